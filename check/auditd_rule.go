@@ -0,0 +1,212 @@
+package check
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/registry"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	name := "auditd-rule"
+	registry.AddJobType(name, func() amboy.Job {
+		return &auditdRule{
+			Base:      NewBase(name, 0),
+			listRules: listAuditRules,
+		}
+	})
+}
+
+// auditdRule checks that an audit rule is loaded (or, when present
+// is false, that it is not). A rule matches if rule_contains (e.g.
+// "-w /etc/passwd -p wa") appears in it as a sequence of whole
+// arguments, so that rules with additional arguments, such as keys,
+// still match. The loaded rules come from "auditctl -l", or, when
+// rules_file is set, from a rules file such as
+// /etc/audit/audit.rules.
+type auditdRule struct {
+	RuleContains string `bson:"rule_contains" json:"rule_contains" yaml:"rule_contains"`
+	Present      *bool  `bson:"present" json:"present" yaml:"present"`
+	RulesFile    string `bson:"rules_file" json:"rules_file" yaml:"rules_file"`
+	*Base        `bson:"metadata" json:"metadata" yaml:"metadata"`
+	fileReadLimit
+
+	listRules func() ([]byte, error)
+}
+
+// auditRuleMaxNearby is the number of similar rules that the
+// auditd-rule check reports when it does not find a match.
+const auditRuleMaxNearby = 3
+
+func (c *auditdRule) validate() error {
+	if strings.TrimSpace(c.RuleContains) == "" {
+		return errors.Errorf("no rule specified for '%s' (%s) check", c.ID(), c.Name())
+	}
+
+	return nil
+}
+
+func listAuditRules() ([]byte, error) {
+	out, err := exec.Command("auditctl", "-l").CombinedOutput()
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem running 'auditctl -l': %s", strings.TrimSpace(string(out)))
+	}
+
+	return out, nil
+}
+
+func (c *auditdRule) Run() {
+	c.startTask()
+	defer c.MarkComplete()
+
+	if err := c.validate(); err != nil {
+		c.setState(false)
+		c.setReason(ReasonInvalidConfig)
+		c.AddError(err)
+		return
+	}
+
+	var data []byte
+	var err error
+	source := "auditctl -l"
+	if c.RulesFile != "" {
+		source = c.RulesFile
+		data, err = c.readFile(c.RulesFile)
+	} else {
+		data, err = c.listRules()
+	}
+	if err != nil {
+		c.setState(false)
+		c.AddError(errors.Wrap(err, "problem listing audit rules"))
+		return
+	}
+
+	rules := parseAuditRules(data)
+	expected := strings.Fields(c.RuleContains)
+	shouldBePresent := c.Present == nil || *c.Present
+
+	var matches []string
+	for _, rule := range rules {
+		if containsArgs(strings.Fields(rule), expected) {
+			matches = append(matches, rule)
+		}
+	}
+
+	msg := []string{fmt.Sprintf("%d audit rules loaded (from %s)", len(rules), source)}
+	for _, match := range matches {
+		msg = append(msg, fmt.Sprintf("matched: %s", match))
+	}
+	c.setMessage(msg)
+
+	switch {
+	case shouldBePresent && len(matches) == 0:
+		c.setState(false)
+		nearby := nearbyAuditRules(rules, expected)
+		if len(nearby) == 0 {
+			c.AddError(errors.Errorf("no audit rule contains '%s'", c.RuleContains))
+			return
+		}
+		c.AddError(errors.Errorf("no audit rule contains '%s', similar rules are [%s]",
+			c.RuleContains, strings.Join(nearby, "; ")))
+	case !shouldBePresent && len(matches) > 0:
+		c.setState(false)
+		c.AddError(errors.Errorf("%d audit rule(s) contain '%s', which should not be loaded",
+			len(matches), c.RuleContains))
+	default:
+		c.setState(true)
+	}
+}
+
+// parseAuditRules returns the rules from the output of "auditctl -l"
+// or from a rules file, without comments and control lines (e.g. "-D"
+// or "-b 8192").
+func parseAuditRules(data []byte) []string {
+	var rules []string
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "-a ") || strings.HasPrefix(line, "-A ") || strings.HasPrefix(line, "-w ") {
+			rules = append(rules, strings.Join(strings.Fields(line), " "))
+		}
+	}
+
+	return rules
+}
+
+// containsArgs reports if the sequence of arguments appears in the
+// rule.
+func containsArgs(rule, args []string) bool {
+	for i := 0; i+len(args) <= len(rule); i++ {
+		match := true
+		for j := range args {
+			if rule[i+j] != args[j] {
+				match = false
+				break
+			}
+		}
+
+		if match {
+			return true
+		}
+	}
+
+	return false
+}
+
+// nearbyAuditRules returns the rules that share the most arguments,
+// other than flags, with the expected rule. Earlier arguments, such
+// as the path of a watch, count for more than later ones.
+func nearbyAuditRules(rules []string, expected []string) []string {
+	var args []string
+	for _, arg := range expected {
+		if !strings.HasPrefix(arg, "-") {
+			args = append(args, arg)
+		}
+	}
+
+	values := make(map[string]int)
+	for idx, arg := range args {
+		if _, ok := values[arg]; !ok {
+			values[arg] = len(args) - idx
+		}
+	}
+
+	scores := make(map[string]int)
+	var candidates []string
+	for _, rule := range rules {
+		score := 0
+		for _, arg := range strings.Fields(rule) {
+			score += values[arg]
+		}
+
+		if score > 0 {
+			scores[rule] = score
+			candidates = append(candidates, rule)
+		}
+	}
+
+	sort.Stable(byAuditRuleScore{rules: candidates, scores: scores})
+	if len(candidates) > auditRuleMaxNearby {
+		candidates = candidates[:auditRuleMaxNearby]
+	}
+
+	return candidates
+}
+
+type byAuditRuleScore struct {
+	rules  []string
+	scores map[string]int
+}
+
+func (s byAuditRuleScore) Len() int      { return len(s.rules) }
+func (s byAuditRuleScore) Swap(i, j int) { s.rules[i], s.rules[j] = s.rules[j], s.rules[i] }
+func (s byAuditRuleScore) Less(i, j int) bool {
+	return s.scores[s.rules[i]] > s.scores[s.rules[j]]
+}
@@ -0,0 +1,134 @@
+package check
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type AuditdRuleSuite struct {
+	tmpDir  string
+	check   *auditdRule
+	require *require.Assertions
+	suite.Suite
+}
+
+func TestAuditdRuleSuite(t *testing.T) {
+	suite.Run(t, new(AuditdRuleSuite))
+}
+
+const auditctlOutput = `-w /etc/passwd -p wa -k identity
+-w /etc/group -p wa -k identity
+-w /etc/shadow -p r -k identity
+-a always,exit -F arch=b64 -S adjtimex,settimeofday -F key=time-change
+-w /var/log/sudo.log -p wa -k actions
+`
+
+func (s *AuditdRuleSuite) SetupSuite() {
+	s.require = s.Require()
+
+	dir, err := ioutil.TempDir("", uuid.NewV4().String())
+	s.require.NoError(err)
+	s.tmpDir = dir
+
+	rules := []byte(`## managed by configuration management
+-D
+-b 8192
+-f 1
+
+-w /etc/sudoers -p wa -k scope
+-a always,exit  -F arch=b64 -S mount -F auid>=1000 -k mounts
+-e 2
+`)
+	s.require.NoError(ioutil.WriteFile(filepath.Join(dir, "audit.rules"), rules, 0640))
+}
+
+func (s *AuditdRuleSuite) TearDownSuite() {
+	s.require.NoError(os.RemoveAll(s.tmpDir))
+}
+
+func (s *AuditdRuleSuite) SetupTest() {
+	s.check = &auditdRule{
+		Base: NewBase("auditd-rule", 0),
+		listRules: func() ([]byte, error) {
+			return []byte(auditctlOutput), nil
+		},
+	}
+}
+
+func (s *AuditdRuleSuite) TestValidation() {
+	s.Error(s.check.validate())
+
+	s.check.RuleContains = "  "
+	s.Error(s.check.validate())
+
+	s.check.RuleContains = "-w /etc/passwd -p wa"
+	s.NoError(s.check.validate())
+}
+
+func (s *AuditdRuleSuite) TestLoadedRulePasses() {
+	s.check.RuleContains = "-w /etc/passwd  -p wa"
+	s.check.Run()
+	s.True(s.check.Output().Passed)
+	s.NoError(s.check.Error())
+	s.Contains(s.check.Output().Message, "5 audit rules loaded")
+	s.Contains(s.check.Output().Message, "matched: -w /etc/passwd -p wa -k identity")
+
+	s.SetupTest()
+	s.check.RuleContains = "-w /etc/sudoers -p wa"
+	s.check.RulesFile = filepath.Join(s.tmpDir, "audit.rules")
+	s.check.Run()
+	s.True(s.check.Output().Passed)
+	s.NoError(s.check.Error())
+	s.Contains(s.check.Output().Message, "2 audit rules loaded")
+}
+
+func (s *AuditdRuleSuite) TestMissingRuleReportsNearbyRules() {
+	s.check.RuleContains = "-w /etc/shadow -p wa"
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "similar rules are [-w /etc/shadow -p r -k identity; ")
+
+	s.SetupTest()
+	// arguments must match whole, so "/etc/pass" does not match
+	s.check.RuleContains = "-w /etc/pass"
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "no audit rule contains '-w /etc/pass'")
+}
+
+func (s *AuditdRuleSuite) TestAbsentRule() {
+	absent := false
+	s.check.Present = &absent
+	s.check.RuleContains = "-w /etc/gshadow"
+	s.check.Run()
+	s.True(s.check.Output().Passed)
+	s.NoError(s.check.Error())
+
+	s.SetupTest()
+	s.check.Present = &absent
+	s.check.RuleContains = "-k identity"
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "3 audit rule(s) contain '-k identity'")
+}
+
+func (s *AuditdRuleSuite) TestListErrorFails() {
+	s.check.RuleContains = "-w /etc/passwd"
+	s.check.listRules = func() ([]byte, error) {
+		return nil, errors.New("auditctl: not found")
+	}
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "problem listing audit rules")
+}
@@ -0,0 +1,134 @@
+package check
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/registry"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	name := "backup-fresh"
+	registry.AddJobType(name, func() amboy.Job {
+		return &backupFresh{
+			Base: NewBase(name, 0),
+		}
+	})
+}
+
+// backupFresh checks that a backup file was written within max_age,
+// and is larger than min_size bytes, so that empty backups fail even
+// without a min_size. The path may be a glob (e.g.
+// "/backups/db-*.tar.gz"), in which case the check inspects the most
+// recently modified file that matches.
+type backupFresh struct {
+	Path    string `bson:"path" json:"path" yaml:"path"`
+	MaxAge  string `bson:"max_age" json:"max_age" yaml:"max_age"`
+	MinSize int64  `bson:"min_size" json:"min_size" yaml:"min_size"`
+	*Base   `bson:"metadata" json:"metadata" yaml:"metadata"`
+
+	maxAge time.Duration
+}
+
+func (c *backupFresh) validate() error {
+	if c.Path == "" {
+		return errors.Errorf("no path specified for '%s' (%s) check", c.ID(), c.Name())
+	}
+
+	if _, err := filepath.Match(c.Path, ""); err != nil {
+		return errors.Wrapf(err, "invalid path pattern for '%s' check", c.ID())
+	}
+
+	if c.MaxAge == "" {
+		return errors.Errorf("no max age specified for '%s' (%s) check", c.ID(), c.Name())
+	}
+
+	age, err := time.ParseDuration(c.MaxAge)
+	if err != nil {
+		return errors.Wrapf(err, "problem parsing max age for '%s' check", c.ID())
+	}
+
+	if age <= 0 {
+		return errors.Errorf("max age for '%s' check must be positive", c.ID())
+	}
+	c.maxAge = age
+
+	if c.MinSize < 0 {
+		return errors.Errorf("min size for '%s' check cannot be negative", c.ID())
+	}
+
+	return nil
+}
+
+func (c *backupFresh) Run() {
+	c.startTask()
+	defer c.MarkComplete()
+
+	if err := c.validate(); err != nil {
+		c.setState(false)
+		c.setReason(ReasonInvalidConfig)
+		c.AddError(err)
+		return
+	}
+
+	fn, info, err := c.findBackup()
+	if err != nil {
+		c.setState(false)
+		c.AddError(err)
+		return
+	}
+
+	age := time.Since(info.ModTime())
+	c.setMessage(fmt.Sprintf("backup '%s' is %d bytes, written %s ago", fn, info.Size(), age))
+	c.setState(true)
+
+	if age > c.maxAge {
+		c.setState(false)
+		c.AddError(errors.Errorf("backup '%s' was written %s ago, which is older than %s",
+			fn, age, c.maxAge))
+	}
+
+	if info.Size() <= c.MinSize {
+		c.setState(false)
+		c.AddError(errors.Errorf("backup '%s' is %d bytes, which is not larger than %d bytes",
+			fn, info.Size(), c.MinSize))
+	}
+}
+
+// findBackup returns the most recently modified file that matches
+// the path.
+func (c *backupFresh) findBackup() (string, os.FileInfo, error) {
+	matches, err := filepath.Glob(c.Path)
+	if err != nil {
+		return "", nil, errors.Wrapf(err, "problem finding backups matching '%s'", c.Path)
+	}
+
+	var newest string
+	var newestInfo os.FileInfo
+	for _, fn := range matches {
+		info, err := os.Stat(fn)
+		if err != nil {
+			return "", nil, errors.Wrapf(err, "problem inspecting backup '%s'", fn)
+		}
+
+		if info.IsDir() {
+			continue
+		}
+
+		if newestInfo == nil || info.ModTime().After(newestInfo.ModTime()) {
+			newest = fn
+			newestInfo = info
+		}
+	}
+
+	if newestInfo == nil {
+		c.setReason(ReasonFileMissing)
+		return "", nil, errors.Errorf("no backup files match '%s'", c.Path)
+	}
+
+	return newest, newestInfo, nil
+}
@@ -0,0 +1,135 @@
+package check
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type BackupFreshSuite struct {
+	tmpDir  string
+	check   *backupFresh
+	require *require.Assertions
+	suite.Suite
+}
+
+func TestBackupFreshSuite(t *testing.T) {
+	suite.Run(t, new(BackupFreshSuite))
+}
+
+func (s *BackupFreshSuite) SetupTest() {
+	s.require = s.Require()
+
+	dir, err := ioutil.TempDir("", uuid.NewV4().String())
+	s.require.NoError(err)
+	s.tmpDir = dir
+
+	s.check = &backupFresh{
+		Base:   NewBase("backup-fresh", 0),
+		MaxAge: "24h",
+	}
+}
+
+func (s *BackupFreshSuite) TearDownTest() {
+	s.require.NoError(os.RemoveAll(s.tmpDir))
+}
+
+func (s *BackupFreshSuite) addBackup(name, content string, age time.Duration) string {
+	fn := filepath.Join(s.tmpDir, name)
+	s.require.NoError(ioutil.WriteFile(fn, []byte(content), 0644))
+
+	mtime := time.Now().Add(-age)
+	s.require.NoError(os.Chtimes(fn, mtime, mtime))
+
+	return fn
+}
+
+func (s *BackupFreshSuite) TestValidation() {
+	s.Error(s.check.validate())
+
+	s.check.Path = filepath.Join(s.tmpDir, "db-[.tar")
+	s.Error(s.check.validate())
+
+	s.check.Path = filepath.Join(s.tmpDir, "db-*.tar")
+	s.NoError(s.check.validate())
+	s.Equal(24*time.Hour, s.check.maxAge)
+
+	s.check.MaxAge = ""
+	s.Error(s.check.validate())
+
+	s.check.MaxAge = "yesterday"
+	s.Error(s.check.validate())
+
+	s.check.MaxAge = "-1h"
+	s.Error(s.check.validate())
+
+	s.check.MaxAge = "1h"
+	s.check.MinSize = -1
+	s.Error(s.check.validate())
+}
+
+func (s *BackupFreshSuite) TestRecentBackup() {
+	s.check.Path = s.addBackup("db.tar", "backup data", time.Hour)
+	s.check.MinSize = 4
+	s.check.Run()
+
+	s.True(s.check.Output().Passed)
+	s.NoError(s.check.Error())
+	s.Contains(s.check.Output().Message, "db.tar' is 11 bytes, written 1h")
+}
+
+func (s *BackupFreshSuite) TestGlobUsesNewestBackup() {
+	s.addBackup("db-1.tar", "old backup data", 72*time.Hour)
+	newest := s.addBackup("db-3.tar", "new backup data", time.Hour)
+	s.addBackup("db-2.tar", "older backup data", 48*time.Hour)
+	s.require.NoError(os.Mkdir(filepath.Join(s.tmpDir, "db-4.tar"), 0755))
+
+	s.check.Path = filepath.Join(s.tmpDir, "db-*.tar")
+	s.check.Run()
+	s.True(s.check.Output().Passed)
+	s.Contains(s.check.Output().Message, newest)
+
+	s.check = &backupFresh{
+		Base:   NewBase("backup-fresh", 0),
+		Path:   filepath.Join(s.tmpDir, "db-[12].tar"),
+		MaxAge: "24h",
+	}
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "db-2.tar' was written 48h")
+	s.Contains(s.check.Error().Error(), "which is older than 24h0m0s")
+}
+
+func (s *BackupFreshSuite) TestEmptyOrSmallBackupFails() {
+	s.check.Path = s.addBackup("empty.tar", "", time.Minute)
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "is 0 bytes, which is not larger than 0 bytes")
+
+	s.check = &backupFresh{
+		Base:    NewBase("backup-fresh", 0),
+		Path:    s.addBackup("small.tar", "tiny", time.Minute),
+		MaxAge:  "24h",
+		MinSize: 1024,
+	}
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "is 4 bytes, which is not larger than 1024 bytes")
+}
+
+func (s *BackupFreshSuite) TestMissingBackupFails() {
+	s.check.Path = filepath.Join(s.tmpDir, "db-*.tar")
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "no backup files match")
+}
@@ -30,11 +30,14 @@ import (
 // Base is a type that all new checks should compose, and provides an
 // implementation of most common amboy.Job and greenbay.Check methods.
 type Base struct {
-	WasSuccessful bool                `bson:"passed" json:"passed" yaml:"passed"`
-	Message       string              `bson:"message" json:"message" yaml:"message"`
-	TestSuites    []string            `bson:"suites" json:"suites" yaml:"suites"`
-	Timing        greenbay.TimingInfo `bson:"timing" json:"timing" yaml:"timing"`
-	*job.Base     `bson:"metadata" json:"metadata" yaml:"metadata"`
+	WasSuccessful    bool                `bson:"passed" json:"passed" yaml:"passed"`
+	WasSkipped       bool                `bson:"skipped" json:"skipped" yaml:"skipped"`
+	Message          string              `bson:"message" json:"message" yaml:"message"`
+	TestSuites       []string            `bson:"suites" json:"suites" yaml:"suites"`
+	CheckAnnotations map[string]string   `bson:"annotations" json:"annotations" yaml:"annotations"`
+	Timing           greenbay.TimingInfo `bson:"timing" json:"timing" yaml:"timing"`
+	ReasonCode       string              `bson:"reason_code" json:"reason_code" yaml:"reason_code"`
+	*job.Base        `bson:"metadata" json:"metadata" yaml:"metadata"`
 
 	mutex sync.RWMutex
 }
@@ -72,12 +75,14 @@ func (b *Base) Output() greenbay.CheckOutput {
 	defer b.mutex.RUnlock()
 
 	out := greenbay.CheckOutput{
-		Name:      b.ID(),
-		Check:     b.Type().Name,
-		Suites:    b.Suites(),
-		Completed: b.IsComplete,
-		Passed:    b.WasSuccessful,
-		Message:   b.Message,
+		Name:        b.ID(),
+		Check:       b.Type().Name,
+		Suites:      b.Suites(),
+		Annotations: b.CheckAnnotations,
+		Completed:   b.IsComplete,
+		Passed:      b.WasSuccessful && !b.WasSkipped,
+		Skipped:     b.WasSkipped,
+		Message:     b.Message,
 		Timing: greenbay.TimingInfo{
 			Start: b.Timing.Start,
 			End:   b.Timing.End,
@@ -88,9 +93,41 @@ func (b *Base) Output() greenbay.CheckOutput {
 		out.Error = err.Error()
 	}
 
+	if !out.Passed && !out.Skipped {
+		out.ReasonCode = b.ReasonCode
+		if out.ReasonCode == "" {
+			out.ReasonCode = ReasonCheckFailed
+		}
+	}
+
 	return out
 }
 
+// AddError records an error, and, if the check has not set a reason
+// code, the reason code for the cause of the error.
+func (b *Base) AddError(err error) {
+	if err == nil {
+		return
+	}
+
+	b.mutex.Lock()
+	if b.ReasonCode == "" {
+		b.ReasonCode = reasonForError(err)
+	}
+	b.mutex.Unlock()
+
+	b.Base.AddError(err)
+}
+
+// setReason records the reason code for the failure of the check,
+// which takes precedence over the code for the cause of any error.
+func (b *Base) setReason(code string) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.ReasonCode = code
+}
+
 func (b *Base) setState(result bool) {
 	b.mutex.Lock()
 	defer b.mutex.Unlock()
@@ -98,6 +135,17 @@ func (b *Base) setState(result bool) {
 	b.WasSuccessful = result
 }
 
+// setSkipped records that the check does not apply to the host,
+// e.g. because the host does not have the feature that the check
+// inspects, with the reason as the message of the check.
+func (b *Base) setSkipped(reason string) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.WasSkipped = true
+	b.Message = reason
+}
+
 func (b *Base) getState() bool {
 	b.mutex.RLock()
 	defer b.mutex.RUnlock()
@@ -140,6 +188,24 @@ func (b *Base) SetSuites(suites []string) {
 	b.TestSuites = suites
 }
 
+// Annotations returns the annotations of the check.
+func (b *Base) Annotations() map[string]string {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	return b.CheckAnnotations
+}
+
+// SetAnnotations allows callers, typically the configuration parser,
+// to set the annotations that are included in the output of the
+// check.
+func (b *Base) SetAnnotations(annotations map[string]string) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.CheckAnnotations = annotations
+}
+
 // Name returns the name of the *check* rather than the name of the
 // task.
 func (b *Base) Name() string {
@@ -155,3 +221,13 @@ func (b *Base) startTask() {
 
 	b.Timing.Start = time.Now()
 }
+
+// MarkComplete records the end time of the check, and marks the job
+// as complete.
+func (b *Base) MarkComplete() {
+	b.mutex.Lock()
+	b.Timing.End = time.Now()
+	b.mutex.Unlock()
+
+	b.Base.MarkComplete()
+}
@@ -2,6 +2,7 @@ package check
 
 import (
 	"errors"
+	"os"
 	"strings"
 	"testing"
 
@@ -138,3 +139,29 @@ func (s *BaseCheckSuite) TestSetSuitesOverridesExistingSuites() {
 		s.Equal(suites, s.base.Suites())
 	}
 }
+
+func (s *BaseCheckSuite) TestReasonCodeOnlyAppliesToFailedChecks() {
+	s.base.WasSuccessful = true
+	s.Equal("", s.base.Output().ReasonCode)
+
+	s.base.WasSuccessful = false
+	s.Equal(ReasonCheckFailed, s.base.Output().ReasonCode)
+
+	s.base.AddError(errors.New("foo"))
+	s.Equal(ReasonCheckFailed, s.base.Output().ReasonCode)
+
+	s.base.WasSkipped = true
+	s.Equal("", s.base.Output().ReasonCode)
+}
+
+func (s *BaseCheckSuite) TestReasonCodeComesFromFirstKnownCause() {
+	s.base.AddError(&os.PathError{Op: "open", Path: "/etc/foo", Err: os.ErrNotExist})
+	s.base.AddError(&os.PathError{Op: "open", Path: "/etc/bar", Err: os.ErrPermission})
+	s.Equal(ReasonFileMissing, s.base.Output().ReasonCode)
+
+	s.base.setReason(ReasonPermMismatch)
+	s.Equal(ReasonPermMismatch, s.base.Output().ReasonCode)
+
+	s.base.AddError(&os.PathError{Op: "open", Path: "/etc/baz", Err: os.ErrNotExist})
+	s.Equal(ReasonPermMismatch, s.base.Output().ReasonCode)
+}
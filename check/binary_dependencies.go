@@ -0,0 +1,125 @@
+package check
+
+import (
+	"debug/elf"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/registry"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	name := "binary-dependencies"
+	registry.AddJobType(name, func() amboy.Job {
+		return &binaryDependencies{
+			Base: NewBase(name, 0),
+		}
+	})
+}
+
+// binaryDependencies checks the shared libraries that an ELF binary
+// depends on, as listed in its dynamic section. When expect_static
+// is set, the binary must not have an interpreter or depend on any
+// shared libraries. Otherwise, every library the binary depends on
+// must match one of the names or glob patterns (e.g. "libc.so.*") in
+// allowed_libraries.
+type binaryDependencies struct {
+	Path             string   `bson:"path" json:"path" yaml:"path"`
+	ExpectStatic     bool     `bson:"expect_static" json:"expect_static" yaml:"expect_static"`
+	AllowedLibraries []string `bson:"allowed_libraries" json:"allowed_libraries" yaml:"allowed_libraries"`
+	*Base            `bson:"metadata" json:"metadata" yaml:"metadata"`
+}
+
+func (c *binaryDependencies) validate() error {
+	if c.Path == "" {
+		return errors.Errorf("no path specified for '%s' (%s) check", c.ID(), c.Name())
+	}
+
+	if !c.ExpectStatic && len(c.AllowedLibraries) == 0 {
+		return errors.Errorf("must specify expect static or allowed libraries for '%s' check", c.ID())
+	}
+
+	for _, pattern := range c.AllowedLibraries {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return errors.Wrapf(err, "allowed library pattern '%s' for '%s' check is not valid",
+				pattern, c.ID())
+		}
+	}
+
+	return nil
+}
+
+func (c *binaryDependencies) allowed(lib string) bool {
+	for _, pattern := range c.AllowedLibraries {
+		if ok, _ := filepath.Match(pattern, lib); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *binaryDependencies) Run() {
+	c.startTask()
+	defer c.MarkComplete()
+
+	if err := c.validate(); err != nil {
+		c.setState(false)
+		c.setReason(ReasonInvalidConfig)
+		c.AddError(err)
+		return
+	}
+
+	f, err := elf.Open(c.Path)
+	if err != nil {
+		c.setState(false)
+		c.AddError(errors.Wrapf(err, "problem reading '%s' as an ELF binary", c.Path))
+		return
+	}
+	defer f.Close()
+
+	libs, err := f.ImportedLibraries()
+	if err != nil {
+		c.setState(false)
+		c.AddError(errors.Wrapf(err, "problem reading the dynamic section of '%s'", c.Path))
+		return
+	}
+
+	var interp bool
+	for _, prog := range f.Progs {
+		if prog.Type == elf.PT_INTERP {
+			interp = true
+			break
+		}
+	}
+
+	c.setMessage(fmt.Sprintf("'%s' depends on %d shared libraries: [%s]",
+		c.Path, len(libs), strings.Join(libs, ", ")))
+
+	if c.ExpectStatic && (interp || len(libs) > 0) {
+		c.setState(false)
+		c.AddError(errors.Errorf("'%s' is dynamically linked, expected a static binary", c.Path))
+		return
+	}
+
+	if len(c.AllowedLibraries) > 0 {
+		var disallowed []string
+		for _, lib := range libs {
+			if !c.allowed(lib) {
+				disallowed = append(disallowed, lib)
+			}
+		}
+
+		if len(disallowed) > 0 {
+			c.setState(false)
+			c.AddError(errors.Errorf("'%s' depends on libraries that are not allowed: [%s]",
+				c.Path, strings.Join(disallowed, ", ")))
+			return
+		}
+	}
+
+	c.setState(true)
+}
@@ -0,0 +1,119 @@
+package check
+
+import (
+	"debug/elf"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type BinaryDependenciesSuite struct {
+	tmpDir  string
+	binary  string
+	libs    []string
+	check   *binaryDependencies
+	require *require.Assertions
+	suite.Suite
+}
+
+func TestBinaryDependenciesSuite(t *testing.T) {
+	suite.Run(t, new(BinaryDependenciesSuite))
+}
+
+func (s *BinaryDependenciesSuite) SetupSuite() {
+	s.require = s.Require()
+
+	dir, err := ioutil.TempDir("", uuid.NewV4().String())
+	s.require.NoError(err)
+	s.tmpDir = dir
+
+	// use a binary from the system that is dynamically linked, if
+	// one exists.
+	for _, fn := range []string{"/bin/sh", "/bin/ls", "/usr/bin/env"} {
+		f, err := elf.Open(fn)
+		if err != nil {
+			continue
+		}
+
+		libs, err := f.ImportedLibraries()
+		f.Close()
+		if err == nil && len(libs) > 0 {
+			s.binary = fn
+			s.libs = libs
+			break
+		}
+	}
+}
+
+func (s *BinaryDependenciesSuite) TearDownSuite() {
+	s.require.NoError(os.RemoveAll(s.tmpDir))
+}
+
+func (s *BinaryDependenciesSuite) SetupTest() {
+	s.check = &binaryDependencies{
+		Base: NewBase("binary-dependencies", 0),
+	}
+}
+
+func (s *BinaryDependenciesSuite) TestValidation() {
+	s.Error(s.check.validate())
+
+	s.check.Path = "/bin/sh"
+	s.Error(s.check.validate())
+
+	s.check.ExpectStatic = true
+	s.NoError(s.check.validate())
+
+	s.check.ExpectStatic = false
+	s.check.AllowedLibraries = []string{"libc.so.*"}
+	s.NoError(s.check.validate())
+
+	s.check.AllowedLibraries = []string{"libc.so.["}
+	s.Error(s.check.validate())
+}
+
+func (s *BinaryDependenciesSuite) TestNonELFFileFails() {
+	fn := filepath.Join(s.tmpDir, "script")
+	s.require.NoError(ioutil.WriteFile(fn, []byte("#!/bin/sh\ntrue\n"), 0755))
+
+	s.check.Path = fn
+	s.check.ExpectStatic = true
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "as an ELF binary")
+}
+
+func (s *BinaryDependenciesSuite) TestDynamicBinary() {
+	if s.binary == "" {
+		s.T().Skip("no dynamically linked binary available")
+	}
+
+	s.check.Path = s.binary
+	s.check.ExpectStatic = true
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "expected a static binary")
+	s.Contains(s.check.Output().Message, s.libs[0])
+
+	s.SetupTest()
+	s.check.Path = s.binary
+	s.check.AllowedLibraries = s.libs
+	s.check.Run()
+	s.True(s.check.Output().Passed)
+	s.NoError(s.check.Error())
+
+	s.SetupTest()
+	s.check.Path = s.binary
+	s.check.AllowedLibraries = []string{"libnothing.so"}
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), s.libs[0])
+}
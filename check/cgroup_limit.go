@@ -0,0 +1,358 @@
+package check
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/registry"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	name := "cgroup-limit"
+	registry.AddJobType(name, func() amboy.Job {
+		return &cgroupLimit{
+			Base:       NewBase(name, 0),
+			cgroupRoot: "/sys/fs/cgroup",
+		}
+	})
+}
+
+// cgroupV1Unlimited is the smallest memory limit that cgroup v1
+// reports for cgroups without a limit, which is the largest page
+// aligned int64 on most systems.
+const cgroupV1Unlimited = int64(1) << 62
+
+// cgroupLimit checks the memory and CPU limits of a cgroup, which is
+// either the cgroup_path, relative to the root of the cgroup
+// hierarchy (e.g. "system.slice/nginx.service"), or the cgroup of a
+// systemd service in the system slice. The memory_max is a size in
+// bytes, with an optional K, M, G, or T suffix, and the cpu_quota is
+// a percentage of one CPU, as in systemd's CPUQuota (e.g. "200%" for
+// two CPUs). Either may be "max" (or "infinity") to require no
+// limit. The check reads cgroup v2 controller files, and falls back
+// to the cgroup v1 memory and cpu hierarchies on hosts without
+// cgroup v2.
+type cgroupLimit struct {
+	CgroupPath string `bson:"cgroup_path" json:"cgroup_path" yaml:"cgroup_path"`
+	Service    string `bson:"service" json:"service" yaml:"service"`
+	MemoryMax  string `bson:"memory_max" json:"memory_max" yaml:"memory_max"`
+	CPUQuota   string `bson:"cpu_quota" json:"cpu_quota" yaml:"cpu_quota"`
+	*Base      `bson:"metadata" json:"metadata" yaml:"metadata"`
+	fileReadLimit
+
+	cgroupRoot string
+}
+
+func (c *cgroupLimit) validate() error {
+	if c.CgroupPath == "" && c.Service == "" {
+		return errors.Errorf("no cgroup path or service specified for '%s' (%s) check",
+			c.ID(), c.Name())
+	}
+
+	if c.CgroupPath != "" && c.Service != "" {
+		return errors.Errorf("cannot specify both a cgroup path and a service for '%s' (%s) check",
+			c.ID(), c.Name())
+	}
+
+	if c.MemoryMax == "" && c.CPUQuota == "" {
+		return errors.Errorf("no expected memory or cpu limit specified for '%s' (%s) check",
+			c.ID(), c.Name())
+	}
+
+	if c.MemoryMax != "" {
+		if _, err := parseCgroupBytes(c.MemoryMax); err != nil {
+			return errors.Wrapf(err, "invalid memory limit for '%s' check", c.ID())
+		}
+	}
+
+	if c.CPUQuota != "" {
+		if _, err := parseCPUQuota(c.CPUQuota); err != nil {
+			return errors.Wrapf(err, "invalid cpu quota for '%s' check", c.ID())
+		}
+	}
+
+	return nil
+}
+
+func (c *cgroupLimit) Run() {
+	c.startTask()
+	defer c.MarkComplete()
+
+	if err := c.validate(); err != nil {
+		c.setState(false)
+		c.setReason(ReasonInvalidConfig)
+		c.AddError(err)
+		return
+	}
+
+	path := c.path()
+	v2 := c.isV2()
+
+	var msg []string
+	var problems []string
+
+	if c.MemoryMax != "" {
+		expected, _ := parseCgroupBytes(c.MemoryMax)
+		actual, err := c.memoryMax(path, v2)
+		if err != nil {
+			c.setState(false)
+			c.AddError(err)
+			return
+		}
+
+		msg = append(msg, fmt.Sprintf("memory max=%s", formatCgroupBytes(actual)))
+		if actual != expected {
+			problems = append(problems, fmt.Sprintf("memory limit of cgroup '%s' is %s, expected %s",
+				path, formatCgroupBytes(actual), formatCgroupBytes(expected)))
+		}
+	}
+
+	if c.CPUQuota != "" {
+		expected, _ := parseCPUQuota(c.CPUQuota)
+		actual, err := c.cpuQuota(path, v2)
+		if err != nil {
+			c.setState(false)
+			c.AddError(err)
+			return
+		}
+
+		msg = append(msg, fmt.Sprintf("cpu quota=%s", formatCPUQuota(actual)))
+		if math.Abs(actual-expected) > 0.01 {
+			problems = append(problems, fmt.Sprintf("cpu quota of cgroup '%s' is %s, expected %s",
+				path, formatCPUQuota(actual), formatCPUQuota(expected)))
+		}
+	}
+
+	c.setMessage(msg)
+
+	if len(problems) > 0 {
+		c.setState(false)
+		c.AddError(errors.New(strings.Join(problems, "; ")))
+		return
+	}
+
+	c.setState(true)
+}
+
+// path returns the path of the cgroup, relative to the root of each
+// hierarchy.
+func (c *cgroupLimit) path() string {
+	if c.CgroupPath != "" {
+		return strings.Trim(filepath.Clean("/"+c.CgroupPath), "/")
+	}
+
+	unit := c.Service
+	if !strings.Contains(unit, ".") {
+		unit += ".service"
+	}
+
+	return "system.slice/" + unit
+}
+
+// isV2 reports if the cgroup root is a cgroup v2 (unified)
+// hierarchy, which has a cgroup.controllers file at its root.
+func (c *cgroupLimit) isV2() bool {
+	_, err := os.Stat(filepath.Join(c.cgroupRoot, "cgroup.controllers"))
+	return err == nil
+}
+
+// controllerFile returns the path of a file in the cgroup, for
+// cgroup v2, or in the cgroup in the first of the v1 controller
+// hierarchies that contains it.
+func (c *cgroupLimit) controllerFile(path string, v2 bool, controllers []string, name string) (string, error) {
+	if v2 {
+		dir := filepath.Join(c.cgroupRoot, path)
+		if _, err := os.Stat(dir); err != nil {
+			return "", errors.Errorf("cgroup '%s' does not exist", path)
+		}
+
+		return filepath.Join(dir, name), nil
+	}
+
+	for _, controller := range controllers {
+		dir := filepath.Join(c.cgroupRoot, controller, path)
+		if _, err := os.Stat(dir); err == nil {
+			return filepath.Join(dir, name), nil
+		}
+	}
+
+	return "", errors.Errorf("cgroup '%s' does not exist in the cgroup v1 %s hierarchy",
+		path, controllers[0])
+}
+
+func (c *cgroupLimit) readValue(fn string) (string, error) {
+	data, err := c.readFile(fn)
+	if err != nil {
+		return "", errors.Wrapf(err, "problem reading '%s'", fn)
+	}
+
+	return strings.TrimSpace(string(data)), nil
+}
+
+// memoryMax returns the memory limit of the cgroup in bytes, or -1
+// for no limit.
+func (c *cgroupLimit) memoryMax(path string, v2 bool) (int64, error) {
+	name := "memory.max"
+	if !v2 {
+		name = "memory.limit_in_bytes"
+	}
+
+	fn, err := c.controllerFile(path, v2, []string{"memory"}, name)
+	if err != nil {
+		return 0, err
+	}
+
+	value, err := c.readValue(fn)
+	if err != nil {
+		return 0, err
+	}
+
+	if value == "max" {
+		return -1, nil
+	}
+
+	limit, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "problem parsing memory limit '%s' in '%s'", value, fn)
+	}
+
+	if !v2 && limit >= cgroupV1Unlimited {
+		return -1, nil
+	}
+
+	return limit, nil
+}
+
+// cpuQuota returns the cpu quota of the cgroup as a percentage of
+// one CPU, or -1 for no limit.
+func (c *cgroupLimit) cpuQuota(path string, v2 bool) (float64, error) {
+	controllers := []string{"cpu", "cpu,cpuacct", "cpuacct,cpu"}
+
+	var quota, period string
+	if v2 {
+		fn, err := c.controllerFile(path, v2, controllers, "cpu.max")
+		if err != nil {
+			return 0, err
+		}
+
+		value, err := c.readValue(fn)
+		if err != nil {
+			return 0, err
+		}
+
+		fields := strings.Fields(value)
+		if len(fields) != 2 {
+			return 0, errors.Errorf("problem parsing cpu limit '%s' in '%s'", value, fn)
+		}
+		quota, period = fields[0], fields[1]
+	} else {
+		fn, err := c.controllerFile(path, v2, controllers, "cpu.cfs_quota_us")
+		if err != nil {
+			return 0, err
+		}
+
+		if quota, err = c.readValue(fn); err != nil {
+			return 0, err
+		}
+
+		if period, err = c.readValue(filepath.Join(filepath.Dir(fn), "cpu.cfs_period_us")); err != nil {
+			return 0, err
+		}
+	}
+
+	if quota == "max" || quota == "-1" {
+		return -1, nil
+	}
+
+	q, err := strconv.ParseFloat(quota, 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "problem parsing cpu quota '%s' of cgroup '%s'", quota, path)
+	}
+
+	p, err := strconv.ParseFloat(period, 64)
+	if err != nil || p <= 0 {
+		return 0, errors.Errorf("invalid cpu period '%s' of cgroup '%s'", period, path)
+	}
+
+	return 100 * q / p, nil
+}
+
+var cgroupByteSuffixes = map[string]int64{
+	"K": 1 << 10,
+	"M": 1 << 20,
+	"G": 1 << 30,
+	"T": 1 << 40,
+}
+
+// parseCgroupBytes parses a memory limit, in bytes with an optional
+// K, M, G, or T suffix, and returns -1 for "max" or "infinity".
+func parseCgroupBytes(value string) (int64, error) {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	if value == "MAX" || value == "INFINITY" {
+		return -1, nil
+	}
+
+	multiplier := int64(1)
+	if len(value) > 0 {
+		if m, ok := cgroupByteSuffixes[value[len(value)-1:]]; ok {
+			multiplier = m
+			value = value[:len(value)-1]
+		}
+	}
+
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || n < 0 {
+		return 0, errors.Errorf("'%s' is not a valid size", value)
+	}
+
+	return n * multiplier, nil
+}
+
+func formatCgroupBytes(n int64) string {
+	if n < 0 {
+		return "max"
+	}
+
+	for _, suffix := range []string{"T", "G", "M", "K"} {
+		m := cgroupByteSuffixes[suffix]
+		if n >= m && n%m == 0 {
+			return fmt.Sprintf("%d%s", n/m, suffix)
+		}
+	}
+
+	return strconv.FormatInt(n, 10)
+}
+
+// parseCPUQuota parses a cpu quota, as a percentage of one CPU, and
+// returns -1 for "max" or "infinity".
+func parseCPUQuota(value string) (float64, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "max" || value == "infinity" {
+		return -1, nil
+	}
+
+	if !strings.HasSuffix(value, "%") {
+		return 0, errors.Errorf("cpu quota '%s' is not a percentage", value)
+	}
+
+	n, err := strconv.ParseFloat(strings.TrimSuffix(value, "%"), 64)
+	if err != nil || n <= 0 {
+		return 0, errors.Errorf("'%s' is not a valid cpu quota", value)
+	}
+
+	return n, nil
+}
+
+func formatCPUQuota(quota float64) string {
+	if quota < 0 {
+		return "max"
+	}
+
+	return strconv.FormatFloat(quota, 'f', -1, 64) + "%"
+}
@@ -0,0 +1,170 @@
+package check
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type CgroupLimitSuite struct {
+	tmpDir  string
+	check   *cgroupLimit
+	require *require.Assertions
+	suite.Suite
+}
+
+func TestCgroupLimitSuite(t *testing.T) {
+	suite.Run(t, new(CgroupLimitSuite))
+}
+
+func (s *CgroupLimitSuite) SetupSuite() {
+	s.require = s.Require()
+
+	dir, err := ioutil.TempDir("", uuid.NewV4().String())
+	s.require.NoError(err)
+	s.tmpDir = dir
+
+	files := map[string]string{
+		"v2/cgroup.controllers":                                       "cpu memory io\n",
+		"v2/system.slice/nginx.service/memory.max":                    "536870912\n",
+		"v2/system.slice/nginx.service/cpu.max":                       "50000 100000\n",
+		"v2/system.slice/cron.service/memory.max":                     "max\n",
+		"v2/system.slice/cron.service/cpu.max":                        "max 100000\n",
+		"v1/memory/system.slice/nginx.service/memory.limit_in_bytes":  "1073741824\n",
+		"v1/cpu,cpuacct/system.slice/nginx.service/cpu.cfs_quota_us":  "200000\n",
+		"v1/cpu,cpuacct/system.slice/nginx.service/cpu.cfs_period_us": "100000\n",
+		"v1/memory/system.slice/cron.service/memory.limit_in_bytes":   "9223372036854771712\n",
+	}
+
+	for fn, content := range files {
+		path := filepath.Join(dir, fn)
+		s.require.NoError(os.MkdirAll(filepath.Dir(path), 0755))
+		s.require.NoError(ioutil.WriteFile(path, []byte(content), 0644))
+	}
+}
+
+func (s *CgroupLimitSuite) TearDownSuite() {
+	s.require.NoError(os.RemoveAll(s.tmpDir))
+}
+
+func (s *CgroupLimitSuite) SetupTest() {
+	s.check = &cgroupLimit{
+		Base:       NewBase("cgroup-limit", 0),
+		cgroupRoot: filepath.Join(s.tmpDir, "v2"),
+	}
+}
+
+func (s *CgroupLimitSuite) TestValidation() {
+	s.Error(s.check.validate())
+
+	s.check.Service = "nginx"
+	s.Error(s.check.validate())
+
+	s.check.MemoryMax = "512M"
+	s.NoError(s.check.validate())
+
+	s.check.CgroupPath = "system.slice/nginx.service"
+	s.Error(s.check.validate())
+
+	s.check.CgroupPath = ""
+	s.check.CPUQuota = "50"
+	s.Error(s.check.validate())
+
+	s.check.CPUQuota = "50%"
+	s.check.MemoryMax = "lots"
+	s.Error(s.check.validate())
+}
+
+func (s *CgroupLimitSuite) TestParseSizes() {
+	for value, expected := range map[string]int64{
+		"512M":       512 << 20,
+		"1g":         1 << 30,
+		"4096":       4096,
+		"max":        -1,
+		"infinity":   -1,
+		" 2T ":       2 << 40,
+		"1073741824": 1 << 30,
+	} {
+		n, err := parseCgroupBytes(value)
+		s.NoError(err, value)
+		s.Equal(expected, n, value)
+	}
+
+	for _, value := range []string{"", "M", "-1", "1.5G", "lots"} {
+		_, err := parseCgroupBytes(value)
+		s.Error(err, value)
+	}
+
+	s.Equal("512M", formatCgroupBytes(512<<20))
+	s.Equal("1G", formatCgroupBytes(1<<30))
+	s.Equal("1000", formatCgroupBytes(1000))
+	s.Equal("max", formatCgroupBytes(-1))
+}
+
+func (s *CgroupLimitSuite) TestV2LimitsMatch() {
+	s.check.Service = "nginx"
+	s.check.MemoryMax = "512M"
+	s.check.CPUQuota = "50%"
+	s.check.Run()
+
+	s.True(s.check.Output().Passed)
+	s.NoError(s.check.Error())
+	s.Equal("memory max=512M\ncpu quota=50%", s.check.Output().Message)
+}
+
+func (s *CgroupLimitSuite) TestV2Unlimited() {
+	s.check.CgroupPath = "/system.slice/cron.service"
+	s.check.MemoryMax = "infinity"
+	s.check.CPUQuota = "max"
+	s.check.Run()
+
+	s.True(s.check.Output().Passed)
+	s.NoError(s.check.Error())
+}
+
+func (s *CgroupLimitSuite) TestV2MismatchReportsActualLimits() {
+	s.check.Service = "cron.service"
+	s.check.MemoryMax = "1G"
+	s.check.CPUQuota = "100%"
+	s.check.Run()
+
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "memory limit of cgroup 'system.slice/cron.service' is max, expected 1G")
+	s.Contains(s.check.Error().Error(), "cpu quota of cgroup 'system.slice/cron.service' is max, expected 100%")
+}
+
+func (s *CgroupLimitSuite) TestV1Limits() {
+	s.check.cgroupRoot = filepath.Join(s.tmpDir, "v1")
+	s.check.Service = "nginx"
+	s.check.MemoryMax = "1G"
+	s.check.CPUQuota = "200%"
+	s.check.Run()
+
+	s.True(s.check.Output().Passed)
+	s.NoError(s.check.Error())
+
+	s.SetupTest()
+	s.check.cgroupRoot = filepath.Join(s.tmpDir, "v1")
+	s.check.Service = "cron"
+	s.check.MemoryMax = "max"
+	s.check.Run()
+
+	s.True(s.check.Output().Passed)
+	s.NoError(s.check.Error())
+}
+
+func (s *CgroupLimitSuite) TestMissingCgroup() {
+	s.check.Service = "postgresql"
+	s.check.MemoryMax = "1G"
+	s.check.Run()
+
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "cgroup 'system.slice/postgresql.service' does not exist")
+}
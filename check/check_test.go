@@ -82,7 +82,7 @@ func (s *CheckSuite) TestFailedChecksShouldReturnErrors() {
 	err := s.check.Error()
 
 	msg := fmt.Sprintf("%T: %+v", s.check, output)
-	if output.Passed {
+	if output.Passed || output.Skipped {
 		s.NoError(err, msg)
 	} else {
 		s.Error(err, msg)
@@ -36,6 +36,7 @@ type shellOperation struct {
 	Command          string            `bson:"command" json:"command" yaml:"command"`
 	WorkingDirectory string            `bson:"working_directory" json:"working_directory" yaml:"working_directory"`
 	Environment      map[string]string `bson:"environment" json:"environment" yaml:"environment"`
+	RunAsUser        string            `bson:"run_as_user" json:"run_as_user" yaml:"run_as_user"`
 	*Base            `bson:"metadata" json:"metadata,omitempty" yaml:"metadata,omitempty"`
 
 	shouldFail bool
@@ -66,6 +67,15 @@ func (c *shellOperation) Run() {
 		logMsg = append(logMsg, fmt.Sprintf("env='%s'", strings.Join(env, " ")))
 	}
 
+	if c.RunAsUser != "" {
+		if err := setCommandUser(cmd, c.RunAsUser); err != nil {
+			c.setState(false)
+			c.AddError(errors.Wrapf(err, "problem running command as user '%s'", c.RunAsUser))
+			return
+		}
+		logMsg = append(logMsg, fmt.Sprintf("user='%s'", c.RunAsUser))
+	}
+
 	c.setState(true) // default to pass
 	out, err := cmd.CombinedOutput()
 	if err != nil {
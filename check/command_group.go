@@ -28,9 +28,12 @@ func registerCommandGroupChecks() {
 	}
 }
 
+// shellGroup runs a group of shell commands. RunAsUser, if set, is
+// the user for the commands that do not set their own run_as_user.
 type shellGroup struct {
 	Commands     []*shellOperation `bson:"commands" json:"commands" yaml:"commands"`
 	Requirements GroupRequirements `bson:"requirements" json:"requirements" yaml:"requirements"`
+	RunAsUser    string            `bson:"run_as_user" json:"run_as_user" yaml:"run_as_user"`
 	*Base        `bson:"metadata" json:"metadata" yaml:"metadata"`
 }
 
@@ -59,6 +62,10 @@ func (c *shellGroup) Run() {
 			cmd.Base = NewBase(fmt.Sprintf("%s-%d", c.ID(), idx), c.Type().Version)
 		}
 
+		if cmd.RunAsUser == "" {
+			cmd.RunAsUser = c.RunAsUser
+		}
+
 		cmd.Run()
 
 		result := cmd.Output()
@@ -0,0 +1,93 @@
+package check
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"runtime"
+	"testing"
+
+	"github.com/mongodb/greenbay"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommandWithUnknownUserFails(t *testing.T) {
+	assert := assert.New(t)
+
+	check := &shellOperation{
+		Command:   "true",
+		RunAsUser: "greenbay-user-does-not-exist",
+		Base:      NewBase("cmd", 0),
+	}
+	check.Run()
+
+	output := check.Output()
+	assert.True(output.Completed)
+	assert.False(output.Passed)
+	assert.Error(check.Error())
+}
+
+func TestCommandRunsAsUser(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("running as another user is not supported on windows")
+	}
+
+	assert := assert.New(t)
+
+	u, err := user.Lookup("nobody")
+	if err != nil {
+		t.Skip("no 'nobody' user on this system")
+	}
+
+	expected := u.Uid
+	if os.Geteuid() != 0 {
+		// without root, running as another user is a no-op.
+		expected = fmt.Sprint(os.Geteuid())
+	}
+
+	check := &shellOperation{
+		Command:   fmt.Sprintf(`test "$(id -u)" = "%s"`, expected),
+		RunAsUser: "nobody",
+		Base:      NewBase("cmd", 0),
+	}
+	check.Run()
+
+	assert.True(check.Output().Passed)
+	assert.NoError(check.Error())
+}
+
+func TestCommandGroupRunsCommandsAsUser(t *testing.T) {
+	assert := assert.New(t)
+
+	check := &shellGroup{
+		Commands: []*shellOperation{
+			{Command: "true"},
+			{Command: "true", RunAsUser: "greenbay-other-user-does-not-exist"},
+		},
+		Requirements: GroupRequirements{Name: "all", All: true},
+		RunAsUser:    "greenbay-user-does-not-exist",
+		Base:         NewBase("cmd-group", 0),
+	}
+	check.Run()
+
+	assert.False(check.Output().Passed)
+	assert.Equal("greenbay-user-does-not-exist", check.Commands[0].RunAsUser)
+	assert.Equal("greenbay-other-user-does-not-exist", check.Commands[1].RunAsUser)
+	assert.Contains(check.Error().Error(), "greenbay-user-does-not-exist")
+}
+
+func TestCompileChecksRejectRunAsUser(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, check := range []greenbay.Checker{
+		&compileCheck{RunAsUser: "nobody", Base: NewBase("compile-gcc-auto", 0)},
+		&programOutputCheck{RunAsUser: "nobody", Base: NewBase("run-program-gcc-auto", 0)},
+	} {
+		check.Run()
+
+		output := check.Output()
+		assert.False(output.Passed)
+		assert.Equal(ReasonInvalidConfig, output.ReasonCode)
+		assert.Contains(check.Error().Error(), "run_as_user")
+	}
+}
@@ -0,0 +1,67 @@
+//go:build linux || freebsd || solaris || darwin
+// +build linux freebsd solaris darwin
+
+package check
+
+import (
+	"os"
+	"os/exec"
+	"os/user"
+	"strconv"
+	"syscall"
+
+	"github.com/pkg/errors"
+	"github.com/tychoish/grip"
+)
+
+// setCommandUser configures the command to run with the uid, gid,
+// and supplementary groups of the named user. Only root can switch
+// users, so when greenbay is not running as root, this logs a
+// warning and leaves the command unchanged.
+func setCommandUser(cmd *exec.Cmd, username string) error {
+	u, err := user.Lookup(username)
+	if err != nil {
+		return errors.Wrapf(err, "problem finding user '%s'", username)
+	}
+
+	if os.Geteuid() != 0 {
+		grip.Warningf("greenbay is not running as root, cannot run command as user '%s'", username)
+		return nil
+	}
+
+	uid, err := strconv.ParseUint(u.Uid, 10, 32)
+	if err != nil {
+		return errors.Wrapf(err, "problem parsing uid '%s' for user '%s'", u.Uid, username)
+	}
+
+	gid, err := strconv.ParseUint(u.Gid, 10, 32)
+	if err != nil {
+		return errors.Wrapf(err, "problem parsing gid '%s' for user '%s'", u.Gid, username)
+	}
+
+	cred := &syscall.Credential{
+		Uid: uint32(uid),
+		Gid: uint32(gid),
+	}
+
+	groups, err := u.GroupIds()
+	if err != nil {
+		grip.Warningf("problem finding groups for user '%s', running without "+
+			"supplementary groups: %+v", username, err)
+	}
+
+	for _, g := range groups {
+		id, err := strconv.ParseUint(g, 10, 32)
+		if err != nil {
+			continue
+		}
+		cred.Groups = append(cred.Groups, uint32(id))
+	}
+
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Credential = cred
+
+	return nil
+}
@@ -0,0 +1,14 @@
+//go:build windows
+// +build windows
+
+package check
+
+import (
+	"os/exec"
+
+	"github.com/pkg/errors"
+)
+
+func setCommandUser(cmd *exec.Cmd, username string) error {
+	return errors.New("running commands as another user is not supported on windows")
+}
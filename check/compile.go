@@ -78,17 +78,35 @@ func registerCompileChecks() {
 	registrar(goCompilerIterfaceFactoryTable())
 }
 
+// compileCheck compiles, and optionally runs, a test program. The
+// compilers write temporary files that only greenbay can read, so
+// compile checks cannot run as another user, and reject the
+// run_as_user argument of command checks.
 type compileCheck struct {
 	Source        string `bson:"source" json:"source" yaml:"source"`
+	RunAsUser     string `bson:"run_as_user" json:"run_as_user" yaml:"run_as_user"`
 	*Base         `bson:"metadata" json:"metadata" yaml:"metadata"`
 	shouldRunCode bool
 	compiler      compiler
 }
 
+// errRunAsUserUnsupported is the error for checks that run commands,
+// but cannot run them as another user.
+func errRunAsUserUnsupported(name string) error {
+	return errors.Errorf("'%s' checks cannot run as another user, and do not support run_as_user", name)
+}
+
 func (c *compileCheck) Run() {
 	c.startTask()
 	defer c.MarkComplete()
 
+	if c.RunAsUser != "" {
+		c.setState(false)
+		c.setReason(ReasonInvalidConfig)
+		c.AddError(errRunAsUserUnsupported(c.Name()))
+		return
+	}
+
 	c.setState(true)
 
 	if err := c.compiler.Validate(); err != nil {
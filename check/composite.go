@@ -0,0 +1,147 @@
+package check
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/registry"
+	"github.com/pkg/errors"
+	"github.com/tychoish/grip"
+)
+
+func init() {
+	name := "composite"
+	registry.AddJobType(name, func() amboy.Job {
+		return &compositeCheck{
+			Base: NewBase(name, 0),
+		}
+	})
+}
+
+// compositeCheck runs a list of inline check definitions and combines
+// their results using a logical operator: "and" passes if all
+// children pass, "or" passes if any child passes, and "not" (which
+// takes exactly one child) passes if its child fails. Skipped
+// children neither pass nor fail, and when every child is skipped,
+// the composite check is skipped.
+type compositeCheck struct {
+	Operator string            `bson:"operator" json:"operator" yaml:"operator"`
+	Checks   []*compositeChild `bson:"checks" json:"checks" yaml:"checks"`
+	*Base    `bson:"metadata" json:"metadata" yaml:"metadata"`
+}
+
+// compositeChild is an inline check definition, with the same form
+// as a check in the greenbay config file.
+type compositeChild struct {
+	Name      string          `bson:"name" json:"name" yaml:"name"`
+	Operation string          `bson:"type" json:"type" yaml:"type"`
+	RawArgs   json.RawMessage `bson:"args" json:"args" yaml:"args"`
+}
+
+func (c *compositeCheck) validate() error {
+	if len(c.Checks) == 0 {
+		return errors.Errorf("no checks specified for '%s' (%s) check", c.ID(), c.Name())
+	}
+
+	switch c.Operator {
+	case "and", "or":
+		return nil
+	case "not":
+		if len(c.Checks) != 1 {
+			return errors.Errorf("'not' operator for '%s' check requires exactly one check, "+
+				"but %d were specified", c.ID(), len(c.Checks))
+		}
+		return nil
+	default:
+		return errors.Errorf("operator '%s' for '%s' check is not valid (and, or, not)",
+			c.Operator, c.ID())
+	}
+}
+
+func (c *compositeCheck) Run() {
+	c.startTask()
+	defer c.MarkComplete()
+
+	if err := c.validate(); err != nil {
+		c.setState(false)
+		c.setReason(ReasonInvalidConfig)
+		c.AddError(err)
+		return
+	}
+
+	var passed []string
+	var failed []string
+	var skipped []string
+	var errs []string
+
+	for idx, child := range c.Checks {
+		name := child.Name
+		if name == "" {
+			name = fmt.Sprintf("%s-%d", c.ID(), idx)
+		}
+
+		args := child.RawArgs
+		if len(args) == 0 {
+			args = json.RawMessage("{}")
+		}
+
+		check, err := NewCheckFromJSON(child.Operation, args)
+		if err != nil {
+			c.setState(false)
+			c.AddError(errors.Wrapf(err, "problem building check '%s' in '%s'",
+				name, c.ID()))
+			return
+		}
+		check.SetID(name)
+
+		check.Run()
+
+		result := check.Output()
+		if result.Skipped {
+			skipped = append(skipped, name)
+		} else if result.Passed {
+			passed = append(passed, name)
+		} else {
+			failed = append(failed, name)
+			if result.Error != "" {
+				errs = append(errs, fmt.Sprintf("%s: %s", name, result.Error))
+			}
+		}
+	}
+
+	if len(skipped) == len(c.Checks) {
+		c.setSkipped(fmt.Sprintf("skipped: [%s]", strings.Join(skipped, ", ")))
+		return
+	}
+
+	var result bool
+	switch c.Operator {
+	case "and":
+		result = len(failed) == 0
+	case "or":
+		result = len(passed) > 0
+	case "not":
+		result = len(passed) == 0
+	}
+
+	grip.Debugf("task '%s' received result %t (%s), with %d successes, %d failures, and %d skipped",
+		c.ID(), result, c.Operator, len(passed), len(failed), len(skipped))
+
+	c.setState(result)
+	msg := fmt.Sprintf("passed: [%s]; failed: [%s]",
+		strings.Join(passed, ", "), strings.Join(failed, ", "))
+	if len(skipped) > 0 {
+		msg = fmt.Sprintf("%s; skipped: [%s]", msg, strings.Join(skipped, ", "))
+	}
+	c.setMessage(msg)
+
+	if !result {
+		msg := fmt.Sprintf("'%s' check did not satisfy the '%s' operator", c.ID(), c.Operator)
+		if len(errs) > 0 {
+			msg = fmt.Sprintf("%s:\n%s", msg, strings.Join(errs, "\n"))
+		}
+		c.AddError(errors.New(msg))
+	}
+}
@@ -0,0 +1,196 @@
+package check
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/registry"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type CompositeCheckSuite struct {
+	existing string
+	missing  string
+	check    *compositeCheck
+	require  *require.Assertions
+	suite.Suite
+}
+
+const mockSkippedCheckName = "mock-skipped-check"
+
+func init() {
+	registry.AddJobType(mockSkippedCheckName, func() amboy.Job {
+		return &mockSkippedCheck{Base: NewBase(mockSkippedCheckName, 0)}
+	})
+}
+
+// mockSkippedCheck is a check that never applies to the host.
+type mockSkippedCheck struct {
+	*Base
+}
+
+func (c *mockSkippedCheck) Run() {
+	c.startTask()
+	defer c.MarkComplete()
+
+	c.setSkipped("does not apply")
+}
+
+func TestCompositeCheckSuite(t *testing.T) {
+	suite.Run(t, new(CompositeCheckSuite))
+}
+
+func (s *CompositeCheckSuite) SetupSuite() {
+	s.require = s.Require()
+
+	f, err := ioutil.TempFile("", "composite")
+	s.require.NoError(err)
+	s.require.NoError(f.Close())
+	s.existing = f.Name()
+	s.missing = f.Name() + ".missing"
+}
+
+func (s *CompositeCheckSuite) TearDownSuite() {
+	s.require.NoError(os.Remove(s.existing))
+}
+
+func (s *CompositeCheckSuite) SetupTest() {
+	s.check = &compositeCheck{Base: NewBase("composite", 0)}
+	s.check.SetID("composite-test")
+}
+
+func (s *CompositeCheckSuite) child(name, fn string) *compositeChild {
+	return &compositeChild{
+		Name:      name,
+		Operation: "file-exists",
+		RawArgs:   json.RawMessage(fmt.Sprintf(`{"name": %q}`, fn)),
+	}
+}
+
+func (s *CompositeCheckSuite) TestValidation() {
+	s.Error(s.check.validate())
+
+	s.check.Checks = []*compositeChild{s.child("one", s.existing)}
+	s.Error(s.check.validate())
+
+	for _, op := range []string{"and", "or", "not"} {
+		s.check.Operator = op
+		s.NoError(s.check.validate())
+	}
+
+	s.check.Operator = "xor"
+	s.Error(s.check.validate())
+
+	s.check.Operator = "not"
+	s.check.Checks = append(s.check.Checks, s.child("two", s.existing))
+	s.Error(s.check.validate())
+}
+
+func (s *CompositeCheckSuite) TestAndRequiresAllChildren() {
+	s.check.Operator = "and"
+	s.check.Checks = []*compositeChild{s.child("present", s.existing), s.child("absent", s.missing)}
+	s.check.Run()
+
+	output := s.check.Output()
+	s.True(output.Completed)
+	s.False(output.Passed)
+	s.Contains(output.Message, "passed: [present]")
+	s.Contains(output.Message, "failed: [absent]")
+	s.Error(s.check.Error())
+
+	s.SetupTest()
+	s.check.Operator = "and"
+	s.check.Checks = []*compositeChild{s.child("present", s.existing), s.child("again", s.existing)}
+	s.check.Run()
+	s.True(s.check.Output().Passed)
+	s.NoError(s.check.Error())
+}
+
+func (s *CompositeCheckSuite) TestOrRequiresAnyChild() {
+	s.check.Operator = "or"
+	s.check.Checks = []*compositeChild{s.child("absent", s.missing), s.child("present", s.existing)}
+	s.check.Run()
+	s.True(s.check.Output().Passed)
+	s.NoError(s.check.Error())
+
+	s.SetupTest()
+	s.check.Operator = "or"
+	s.check.Checks = []*compositeChild{s.child("absent", s.missing)}
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.Error(s.check.Error())
+}
+
+func (s *CompositeCheckSuite) TestNotInvertsChild() {
+	s.check.Operator = "not"
+	s.check.Checks = []*compositeChild{s.child("absent", s.missing)}
+	s.check.Run()
+	s.True(s.check.Output().Passed)
+
+	s.SetupTest()
+	s.check.Operator = "not"
+	s.check.Checks = []*compositeChild{s.child("present", s.existing)}
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.Error(s.check.Error())
+}
+
+func (s *CompositeCheckSuite) TestSkippedChildren() {
+	skipped := &compositeChild{Name: "skipped", Operation: mockSkippedCheckName}
+
+	for _, op := range []string{"and", "or", "not"} {
+		s.SetupTest()
+		s.check.Operator = op
+		s.check.Checks = []*compositeChild{skipped}
+		s.check.Run()
+
+		output := s.check.Output()
+		s.True(output.Skipped, op)
+		s.False(output.Passed, op)
+		s.NoError(s.check.Error(), op)
+	}
+
+	s.SetupTest()
+	s.check.Operator = "and"
+	s.check.Checks = []*compositeChild{s.child("present", s.existing), skipped}
+	s.check.Run()
+	s.True(s.check.Output().Passed)
+	s.Contains(s.check.Output().Message, "skipped: [skipped]")
+
+	s.SetupTest()
+	s.check.Operator = "or"
+	s.check.Checks = []*compositeChild{s.child("absent", s.missing), skipped}
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.False(s.check.Output().Skipped)
+}
+
+func (s *CompositeCheckSuite) TestUnknownChildTypeFails() {
+	s.check.Operator = "and"
+	s.check.Checks = []*compositeChild{{Name: "bad", Operation: "not-a-check"}}
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.Error(s.check.Error())
+}
+
+func (s *CompositeCheckSuite) TestChecksCanBeNested() {
+	inner, err := json.Marshal(map[string]interface{}{
+		"operator": "not",
+		"checks":   []*compositeChild{s.child("absent", s.missing)},
+	})
+	s.require.NoError(err)
+
+	s.check.Operator = "and"
+	s.check.Checks = []*compositeChild{
+		s.child("present", s.existing),
+		{Name: "inner", Operation: "composite", RawArgs: inner},
+	}
+	s.check.Run()
+	s.True(s.check.Output().Passed)
+	s.NoError(s.check.Error())
+}
@@ -101,6 +101,7 @@ func (c *containerCheck) Run() {
 
 	if err := c.validate(); err != nil {
 		c.setState(false)
+		c.setReason(ReasonInvalidConfig)
 		c.AddError(err)
 		return
 	}
@@ -0,0 +1,344 @@
+package check
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/registry"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	name := "crontab-valid"
+	registry.AddJobType(name, func() amboy.Job {
+		return &crontabValid{
+			Base:          NewBase(name, 0),
+			Scope:         "all",
+			systemCrontab: "/etc/crontab",
+			systemDir:     "/etc/cron.d",
+			spoolDirs:     []string{"/var/spool/cron/crontabs", "/var/spool/cron"},
+		}
+	})
+}
+
+// crontabValid checks the syntax of cron entries, and reports lines
+// with invalid schedules or without a command. The scope is
+// "system", which checks /etc/crontab and the files in /etc/cron.d,
+// "user", which checks the crontabs of users in the cron spool
+// directory, or "all" (the default). Set user to only check the
+// crontab of one user, rather than all user crontabs. That user must
+// have a crontab.
+type crontabValid struct {
+	Scope string `bson:"scope" json:"scope" yaml:"scope"`
+	User  string `bson:"user" json:"user" yaml:"user"`
+	*Base `bson:"metadata" json:"metadata" yaml:"metadata"`
+	fileReadLimit
+
+	systemCrontab string
+	systemDir     string
+	spoolDirs     []string
+}
+
+// cronField describes the range of values, and the names, that a
+// schedule field accepts.
+type cronField struct {
+	name  string
+	min   int
+	max   int
+	names []string
+}
+
+var cronFields = []cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12,
+		names: []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}},
+	{name: "day of week", min: 0, max: 7,
+		names: []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}},
+}
+
+var cronShortcuts = map[string]struct{}{
+	"@reboot": {}, "@yearly": {}, "@annually": {}, "@monthly": {},
+	"@weekly": {}, "@daily": {}, "@midnight": {}, "@hourly": {},
+}
+
+func (c *crontabValid) validate() error {
+	switch c.Scope {
+	case "all", "system", "user":
+	default:
+		return errors.Errorf("'%s' is not a valid scope for '%s' (%s) check, "+
+			"must be 'all', 'system', or 'user'", c.Scope, c.ID(), c.Name())
+	}
+
+	if c.User != "" && c.Scope == "system" {
+		return errors.Errorf("cannot specify a user for '%s' check with the system scope", c.ID())
+	}
+
+	return nil
+}
+
+func (c *crontabValid) Run() {
+	c.startTask()
+	defer c.MarkComplete()
+
+	if err := c.validate(); err != nil {
+		c.setState(false)
+		c.setReason(ReasonInvalidConfig)
+		c.AddError(err)
+		return
+	}
+
+	// system crontabs have a user field before the command.
+	files := make(map[string]bool)
+	var order []string
+
+	if c.Scope != "user" {
+		if _, err := os.Stat(c.systemCrontab); err == nil {
+			files[c.systemCrontab] = true
+			order = append(order, c.systemCrontab)
+		}
+
+		fns, err := listCronFiles(c.systemDir)
+		if err != nil {
+			c.setState(false)
+			c.AddError(err)
+			return
+		}
+		for _, fn := range fns {
+			files[fn] = true
+			order = append(order, fn)
+		}
+	}
+
+	if c.Scope != "system" {
+		fns, err := c.userCrontabs()
+		if err != nil {
+			c.setState(false)
+			c.AddError(err)
+			return
+		}
+		for _, fn := range fns {
+			files[fn] = false
+			order = append(order, fn)
+		}
+	}
+
+	var problems []string
+	numEntries := 0
+	for _, fn := range order {
+		data, err := c.readFile(fn)
+		if err != nil {
+			c.setState(false)
+			c.AddError(errors.Wrap(err, "problem reading crontab"))
+			return
+		}
+
+		n, errs := validateCrontab(fn, data, files[fn])
+		numEntries += n
+		problems = append(problems, errs...)
+	}
+
+	if len(problems) > 0 {
+		c.setState(false)
+		c.setMessage(problems)
+		c.AddError(errors.Errorf("found %d invalid cron entries in %d crontabs",
+			len(problems), len(order)))
+		return
+	}
+
+	c.setState(true)
+	c.setMessage(fmt.Sprintf("%d cron entries in %d crontabs are valid", numEntries, len(order)))
+}
+
+// listCronFiles returns the regular files in a cron directory,
+// except hidden files, which cron ignores. A directory that does not
+// exist has no files.
+func listCronFiles(dir string) ([]string, error) {
+	infos, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrapf(err, "problem listing cron directory '%s'", dir)
+	}
+
+	var fns []string
+	for _, info := range infos {
+		if !info.Mode().IsRegular() || strings.HasPrefix(info.Name(), ".") {
+			continue
+		}
+
+		fns = append(fns, filepath.Join(dir, info.Name()))
+	}
+
+	return fns, nil
+}
+
+// userCrontabs returns the user crontabs in the first spool
+// directory that exists. Distributions differ in where they keep
+// user crontabs.
+func (c *crontabValid) userCrontabs() ([]string, error) {
+	for _, dir := range c.spoolDirs {
+		if _, err := os.Stat(dir); os.IsNotExist(err) {
+			continue
+		}
+
+		fns, err := listCronFiles(dir)
+		if err != nil {
+			return nil, err
+		}
+
+		if c.User == "" {
+			return fns, nil
+		}
+
+		for _, fn := range fns {
+			if filepath.Base(fn) == c.User {
+				return []string{fn}, nil
+			}
+		}
+
+		return nil, errors.Errorf("user '%s' does not have a crontab in '%s'", c.User, dir)
+	}
+
+	if c.User != "" {
+		return nil, errors.Errorf("user '%s' does not have a crontab", c.User)
+	}
+
+	return nil, nil
+}
+
+// validateCrontab returns the number of entries in the crontab, and a
+// description of each invalid entry.
+func validateCrontab(fn string, data []byte, system bool) (int, []string) {
+	var problems []string
+	numEntries := 0
+	lineNum := 0
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+
+		// environment settings (e.g. MAILTO=root) are not
+		// entries.
+		if strings.Index(fields[0], "=") > 0 || (len(fields) > 1 && strings.HasPrefix(fields[1], "=")) {
+			continue
+		}
+
+		numEntries++
+		if err := validateCronEntry(fields, system); err != nil {
+			problems = append(problems, fmt.Sprintf("%s:%d: %s", fn, lineNum, err.Error()))
+		}
+	}
+
+	return numEntries, problems
+}
+
+func validateCronEntry(fields []string, system bool) error {
+	numSchedule := len(cronFields)
+	if strings.HasPrefix(fields[0], "@") {
+		if _, ok := cronShortcuts[strings.ToLower(fields[0])]; !ok {
+			return errors.Errorf("'%s' is not a valid schedule", fields[0])
+		}
+		numSchedule = 1
+	} else {
+		if len(fields) < numSchedule {
+			return errors.Errorf("entry has %d fields, expected a schedule of %d fields and a command",
+				len(fields), numSchedule)
+		}
+
+		for idx, field := range cronFields {
+			if err := field.validate(fields[idx]); err != nil {
+				return err
+			}
+		}
+	}
+
+	rest := fields[numSchedule:]
+	if system {
+		if len(rest) == 0 {
+			return errors.New("entry does not have a user")
+		}
+		rest = rest[1:]
+	}
+
+	if len(rest) == 0 {
+		return errors.New("entry does not have a command")
+	}
+
+	return nil
+}
+
+// validate checks a schedule field, which is a comma separated list
+// of "*", values, or ranges, each with an optional "/step".
+func (f cronField) validate(value string) error {
+	for _, item := range strings.Split(value, ",") {
+		if err := f.validateItem(item); err != nil {
+			return errors.Wrapf(err, "%s '%s' is not valid", f.name, value)
+		}
+	}
+
+	return nil
+}
+
+func (f cronField) validateItem(item string) error {
+	if idx := strings.Index(item, "/"); idx >= 0 {
+		step, err := strconv.Atoi(item[idx+1:])
+		if err != nil || step <= 0 {
+			return errors.Errorf("invalid step '%s'", item[idx+1:])
+		}
+		item = item[:idx]
+	}
+
+	if item == "*" {
+		return nil
+	}
+
+	bounds := strings.SplitN(item, "-", 2)
+	values := make([]int, 0, len(bounds))
+	for _, b := range bounds {
+		v, err := f.parseValue(b)
+		if err != nil {
+			return err
+		}
+		values = append(values, v)
+	}
+
+	if len(values) == 2 && values[0] > values[1] {
+		return errors.Errorf("range '%s' is backwards", item)
+	}
+
+	return nil
+}
+
+func (f cronField) parseValue(value string) (int, error) {
+	for idx, name := range f.names {
+		if strings.ToLower(value) == name {
+			return f.min + idx, nil
+		}
+	}
+
+	v, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, errors.Errorf("'%s' is not a number", value)
+	}
+
+	if v < f.min || v > f.max {
+		return 0, errors.Errorf("%d is not between %d and %d", v, f.min, f.max)
+	}
+
+	return v, nil
+}
@@ -0,0 +1,149 @@
+package check
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type CrontabValidSuite struct {
+	tmpDir  string
+	check   *crontabValid
+	require *require.Assertions
+	suite.Suite
+}
+
+func TestCrontabValidSuite(t *testing.T) {
+	suite.Run(t, new(CrontabValidSuite))
+}
+
+func (s *CrontabValidSuite) SetupSuite() {
+	s.require = s.Require()
+}
+
+func (s *CrontabValidSuite) SetupTest() {
+	if s.tmpDir != "" {
+		s.require.NoError(os.RemoveAll(s.tmpDir))
+	}
+
+	dir, err := ioutil.TempDir("", uuid.NewV4().String())
+	s.require.NoError(err)
+	s.tmpDir = dir
+
+	s.require.NoError(os.MkdirAll(filepath.Join(dir, "cron.d"), 0755))
+	s.require.NoError(os.MkdirAll(filepath.Join(dir, "spool", "crontabs"), 0755))
+
+	s.require.NoError(ioutil.WriteFile(filepath.Join(dir, "crontab"), []byte(`
+SHELL=/bin/sh
+PATH = /usr/bin:/bin
+# m h dom mon dow user command
+17 *	* * *	root    cd / && run-parts --report /etc/cron.hourly
+25 6	* * 1-5	root	test -x /usr/sbin/anacron
+`), 0644))
+
+	s.require.NoError(ioutil.WriteFile(filepath.Join(dir, "cron.d", "backup"), []byte(`
+MAILTO=ops@example.net
+*/15 0-6,22-23 * jan-mar,dec sun root /usr/local/bin/backup --incremental
+@reboot root /usr/local/bin/backup --verify
+`), 0644))
+
+	s.require.NoError(ioutil.WriteFile(filepath.Join(dir, "spool", "crontabs", "alice"), []byte(`
+0 2 * * * /home/alice/bin/report
+@daily /home/alice/bin/cleanup
+`), 0600))
+
+	s.check = &crontabValid{
+		Base:          NewBase("crontab-valid", 0),
+		Scope:         "all",
+		systemCrontab: filepath.Join(dir, "crontab"),
+		systemDir:     filepath.Join(dir, "cron.d"),
+		spoolDirs:     []string{filepath.Join(dir, "spool", "crontabs"), filepath.Join(dir, "spool")},
+	}
+}
+
+func (s *CrontabValidSuite) TearDownSuite() {
+	s.require.NoError(os.RemoveAll(s.tmpDir))
+}
+
+func (s *CrontabValidSuite) TestValidation() {
+	s.NoError(s.check.validate())
+
+	s.check.User = "alice"
+	s.NoError(s.check.validate())
+
+	s.check.Scope = "system"
+	s.Error(s.check.validate())
+
+	s.check.Scope = "everything"
+	s.Error(s.check.validate())
+}
+
+func (s *CrontabValidSuite) TestValidCrontabsPass() {
+	s.check.Run()
+	s.True(s.check.Output().Passed)
+	s.NoError(s.check.Error())
+	s.Contains(s.check.Output().Message, "6 cron entries in 3 crontabs")
+}
+
+func (s *CrontabValidSuite) TestInvalidEntries() {
+	for entry, reason := range map[string]string{
+		"60 * * * * /bin/true":          "minute '60' is not valid",
+		"* 24 * * * /bin/true":          "hour '24' is not valid",
+		"* * 0 * * /bin/true":           "day of month '0' is not valid",
+		"* * * smarch * /bin/true":      "month 'smarch' is not valid",
+		"* * * * 8 /bin/true":           "day of week '8' is not valid",
+		"*/0 * * * * /bin/true":         "invalid step",
+		"30-10 * * * * /bin/true":       "backwards",
+		"* * * * *":                     "does not have a command",
+		"* * * *":                       "entry has 4 fields",
+		"@fortnightly /bin/true":        "not a valid schedule",
+		"1,2,x * * * * /bin/true":       "'x' is not a number",
+		"5 4 * * sun,mon-fri /bin/true": "",
+	} {
+		s.SetupTest()
+		s.check.Scope = "user"
+		s.require.NoError(ioutil.WriteFile(filepath.Join(s.tmpDir, "spool", "crontabs", "alice"),
+			[]byte(entry+"\n"), 0600))
+		s.check.Run()
+
+		if reason == "" {
+			s.True(s.check.Output().Passed, entry)
+			continue
+		}
+
+		s.False(s.check.Output().Passed, entry)
+		s.require.Error(s.check.Error(), entry)
+		s.Contains(s.check.Output().Message, "alice:1: ", entry)
+		s.Contains(s.check.Output().Message, reason, entry)
+	}
+}
+
+func (s *CrontabValidSuite) TestSystemEntriesRequireUser() {
+	s.require.NoError(ioutil.WriteFile(filepath.Join(s.tmpDir, "cron.d", "broken"),
+		[]byte("@hourly\n"), 0644))
+	s.check.Scope = "system"
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Output().Message, "does not have a user")
+}
+
+func (s *CrontabValidSuite) TestUserScope() {
+	s.check.Scope = "user"
+	s.check.User = "alice"
+	s.check.Run()
+	s.True(s.check.Output().Passed)
+	s.Contains(s.check.Output().Message, "2 cron entries in 1 crontabs")
+
+	s.SetupTest()
+	s.check.User = "bob"
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "user 'bob' does not have a crontab")
+}
@@ -0,0 +1,146 @@
+package check
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/registry"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	name := "default-umask"
+	registry.AddJobType(name, func() amboy.Job {
+		return &defaultUmask{
+			Base:      NewBase(name, 0),
+			Scope:     "login",
+			loginDefs: "/etc/login.defs",
+			run: func(command string, args ...string) ([]byte, error) {
+				return exec.Command(command, args...).Output()
+			},
+		}
+	})
+}
+
+// defaultUmask checks that the default umask is the expected octal
+// value (e.g. "027"). The scope is "login" (the default), which is
+// the umask of a login shell, after /etc/profile and the files it
+// sources, or "system", which is the UMASK in /etc/login.defs, which
+// pam_umask and useradd use. The login shell runs as the user that
+// runs greenbay.
+type defaultUmask struct {
+	Expected string `bson:"expected" json:"expected" yaml:"expected"`
+	Scope    string `bson:"scope" json:"scope" yaml:"scope"`
+	*Base    `bson:"metadata" json:"metadata" yaml:"metadata"`
+	fileReadLimit
+
+	loginDefs string
+	run       func(command string, args ...string) ([]byte, error)
+}
+
+func (c *defaultUmask) validate() error {
+	if c.Expected == "" {
+		return errors.Errorf("no expected umask specified for '%s' (%s) check", c.ID(), c.Name())
+	}
+
+	if _, err := parseUmask(c.Expected); err != nil {
+		return errors.Wrapf(err, "invalid expected umask for '%s' check", c.ID())
+	}
+
+	if c.Scope != "login" && c.Scope != "system" {
+		return errors.Errorf("scope '%s' for '%s' check must be 'login' or 'system'", c.Scope, c.ID())
+	}
+
+	return nil
+}
+
+func (c *defaultUmask) Run() {
+	c.startTask()
+	defer c.MarkComplete()
+
+	if err := c.validate(); err != nil {
+		c.setState(false)
+		c.setReason(ReasonInvalidConfig)
+		c.AddError(err)
+		return
+	}
+
+	var observed uint64
+	var source string
+	var err error
+	if c.Scope == "login" {
+		source = "login shell"
+		observed, err = c.loginUmask()
+	} else {
+		source = c.loginDefs
+		observed, err = c.systemUmask()
+	}
+
+	if err != nil {
+		c.setState(false)
+		c.AddError(err)
+		return
+	}
+
+	expected, _ := parseUmask(c.Expected)
+	c.setMessage(fmt.Sprintf("umask from %s is %04o, expected %04o", source, observed, expected))
+
+	if observed != expected {
+		c.setState(false)
+		c.setReason(ReasonValueMismatch)
+		c.AddError(errors.Errorf("umask from %s is %04o, not %04o", source, observed, expected))
+		return
+	}
+
+	c.setState(true)
+}
+
+// loginUmask runs "umask" in a login shell.
+func (c *defaultUmask) loginUmask() (uint64, error) {
+	out, err := c.run("sh", "-l", "-c", "umask")
+	if err != nil {
+		return 0, errors.Wrap(err, "problem running umask in a login shell")
+	}
+
+	// profile scripts may print other output first.
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+
+	return parseUmask(lines[len(lines)-1])
+}
+
+// systemUmask reads the UMASK from login.defs, which defaults to 022
+// when unset.
+func (c *defaultUmask) systemUmask() (uint64, error) {
+	data, err := c.readFile(c.loginDefs)
+	if err != nil {
+		return 0, err
+	}
+
+	value := "022"
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "UMASK" {
+			value = fields[1]
+		}
+	}
+
+	return parseUmask(value)
+}
+
+// parseUmask parses an octal umask, such as "027" or "0027".
+func parseUmask(value string) (uint64, error) {
+	value = strings.TrimSpace(value)
+
+	mask, err := strconv.ParseUint(value, 8, 32)
+	if err != nil || mask > 0777 {
+		return 0, errors.Errorf("'%s' is not an octal umask", value)
+	}
+
+	return mask, nil
+}
@@ -0,0 +1,119 @@
+package check
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type DefaultUmaskSuite struct {
+	tmpDir  string
+	output  string
+	ran     []string
+	check   *defaultUmask
+	require *require.Assertions
+	suite.Suite
+}
+
+func TestDefaultUmaskSuite(t *testing.T) {
+	suite.Run(t, new(DefaultUmaskSuite))
+}
+
+func (s *DefaultUmaskSuite) SetupSuite() {
+	s.require = s.Require()
+
+	dir, err := ioutil.TempDir("", uuid.NewV4().String())
+	s.require.NoError(err)
+	s.tmpDir = dir
+
+	s.require.NoError(ioutil.WriteFile(filepath.Join(dir, "login.defs"),
+		[]byte("# login.defs\nMAIL_DIR /var/mail\n#UMASK 077\nUMASK\t\t027\nUSERGROUPS_ENAB yes\n"), 0644))
+	s.require.NoError(ioutil.WriteFile(filepath.Join(dir, "empty.defs"), []byte("MAIL_DIR /var/mail\n"), 0644))
+}
+
+func (s *DefaultUmaskSuite) TearDownSuite() {
+	s.require.NoError(os.RemoveAll(s.tmpDir))
+}
+
+func (s *DefaultUmaskSuite) SetupTest() {
+	s.output = ""
+	s.ran = nil
+	s.check = &defaultUmask{
+		Base:      NewBase("default-umask", 0),
+		Scope:     "login",
+		loginDefs: filepath.Join(s.tmpDir, "login.defs"),
+		run: func(command string, args ...string) ([]byte, error) {
+			s.ran = append([]string{command}, args...)
+			return []byte(s.output), nil
+		},
+	}
+}
+
+func (s *DefaultUmaskSuite) TestValidation() {
+	s.Error(s.check.validate())
+
+	s.check.Expected = "089"
+	s.Error(s.check.validate())
+
+	s.check.Expected = "7777"
+	s.Error(s.check.validate())
+
+	s.check.Expected = "027"
+	s.NoError(s.check.validate())
+
+	s.check.Scope = "service"
+	s.Error(s.check.validate())
+}
+
+func (s *DefaultUmaskSuite) TestLoginShell() {
+	s.output = "Welcome!\n0027\n"
+	s.check.Expected = "027"
+	s.check.Run()
+
+	s.True(s.check.Output().Passed)
+	s.NoError(s.check.Error())
+	s.Equal([]string{"sh", "-l", "-c", "umask"}, s.ran)
+	s.Equal("umask from login shell is 0027, expected 0027", s.check.Output().Message)
+
+	s.SetupTest()
+	s.output = "0022\n"
+	s.check.Expected = "077"
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "umask from login shell is 0022, not 0077")
+
+	s.SetupTest()
+	s.check.Expected = "077"
+	s.check.run = func(command string, args ...string) ([]byte, error) {
+		return nil, errors.New("exit status 127")
+	}
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.Error(s.check.Error())
+}
+
+func (s *DefaultUmaskSuite) TestSystem() {
+	s.check.Scope = "system"
+	s.check.Expected = "0027"
+	s.check.Run()
+	s.True(s.check.Output().Passed)
+	s.NoError(s.check.Error())
+	s.Nil(s.ran)
+
+	// login.defs defaults to 022
+	s.SetupTest()
+	s.check.Scope = "system"
+	s.check.loginDefs = filepath.Join(s.tmpDir, "empty.defs")
+	s.check.Expected = "027"
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "is 0022, not 0027")
+}
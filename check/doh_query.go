@@ -0,0 +1,233 @@
+package check
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/registry"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	name := "doh-query"
+	registry.AddJobType(name, func() amboy.Job {
+		return &dohQuery{
+			Base:       NewBase(name, 0),
+			RecordType: "A",
+			client:     &http.Client{Timeout: time.Minute},
+		}
+	})
+}
+
+// dohQuery checks that a DNS-over-HTTPS resolver (RFC 8484) returns
+// the expected records for a hostname. The record_type is one of A
+// (the default), AAAA, CNAME, MX, NS, or TXT, and the records must
+// match the expected_values exactly, in any order. Names are not case
+// sensitive and do not need a trailing dot, MX records have the form
+// "<preference> <host>", and the strings of a TXT record are joined.
+type dohQuery struct {
+	ResolverURL    string   `bson:"resolver_url" json:"resolver_url" yaml:"resolver_url"`
+	Hostname       string   `bson:"hostname" json:"hostname" yaml:"hostname"`
+	RecordType     string   `bson:"record_type" json:"record_type" yaml:"record_type"`
+	ExpectedValues []string `bson:"expected_values" json:"expected_values" yaml:"expected_values"`
+	*Base          `bson:"metadata" json:"metadata" yaml:"metadata"`
+
+	client *http.Client
+}
+
+// dnsRecordTypes maps the record types that the doh-query check
+// supports to their numeric values.
+var dnsRecordTypes = map[string]uint16{
+	"A":     dns.TypeA,
+	"NS":    dns.TypeNS,
+	"CNAME": dns.TypeCNAME,
+	"MX":    dns.TypeMX,
+	"TXT":   dns.TypeTXT,
+	"AAAA":  dns.TypeAAAA,
+}
+
+// dnsResponseLimit is the maximum size of a DNS message.
+const dnsResponseLimit = 65535
+
+func (c *dohQuery) validate() error {
+	if c.ResolverURL == "" {
+		return errors.Errorf("no resolver url specified for '%s' (%s) check", c.ID(), c.Name())
+	}
+
+	if _, err := url.Parse(c.ResolverURL); err != nil {
+		return errors.Wrapf(err, "resolver url for '%s' check is not valid", c.ID())
+	}
+
+	if c.Hostname == "" {
+		return errors.Errorf("no hostname specified for '%s' check", c.ID())
+	}
+
+	if _, ok := dns.IsDomainName(c.Hostname); !ok {
+		return errors.Errorf("hostname '%s' for '%s' check is not valid", c.Hostname, c.ID())
+	}
+
+	if _, ok := dnsRecordTypes[strings.ToUpper(c.RecordType)]; !ok {
+		return errors.Errorf("record type '%s' for '%s' check is not supported", c.RecordType, c.ID())
+	}
+
+	if len(c.ExpectedValues) == 0 {
+		return errors.Errorf("no expected values specified for '%s' check", c.ID())
+	}
+
+	return nil
+}
+
+func (c *dohQuery) Run() {
+	c.startTask()
+	defer c.MarkComplete()
+
+	if err := c.validate(); err != nil {
+		c.setState(false)
+		c.setReason(ReasonInvalidConfig)
+		c.AddError(err)
+		return
+	}
+
+	recordType := strings.ToUpper(c.RecordType)
+
+	// the ID is 0, as RFC 8484 recommends, so that responses are
+	// cacheable.
+	msg := &dns.Msg{}
+	msg.SetQuestion(dns.Fqdn(c.Hostname), dnsRecordTypes[recordType])
+	msg.Id = 0
+
+	query, err := msg.Pack()
+	if err != nil {
+		c.setState(false)
+		c.AddError(errors.Wrapf(err, "problem building query for %s", c.Hostname))
+		return
+	}
+
+	req, err := http.NewRequest("GET", c.ResolverURL, nil)
+	if err != nil {
+		c.setState(false)
+		c.AddError(errors.Wrapf(err, "problem building request for %s", c.ResolverURL))
+		return
+	}
+	params := req.URL.Query()
+	params.Set("dns", base64.RawURLEncoding.EncodeToString(query))
+	req.URL.RawQuery = params.Encode()
+	req.Header.Set("Accept", "application/dns-message")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		c.setState(false)
+		c.AddError(errors.Wrapf(err, "problem requesting %s", c.ResolverURL))
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		c.setState(false)
+		c.AddError(errors.Errorf("resolver %s returned HTTP status %s", c.ResolverURL, resp.Status))
+		return
+	}
+
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, dnsResponseLimit))
+	if err != nil {
+		c.setState(false)
+		c.AddError(errors.Wrapf(err, "problem reading response from %s", c.ResolverURL))
+		return
+	}
+
+	values, err := parseDNSResponse(data, dnsRecordTypes[recordType])
+	if err != nil {
+		c.setState(false)
+		c.AddError(errors.Wrapf(err, "problem with DNS response for %s %s from %s",
+			c.Hostname, recordType, c.ResolverURL))
+		return
+	}
+
+	expected := make([]string, 0, len(c.ExpectedValues))
+	for _, v := range c.ExpectedValues {
+		expected = append(expected, normalizeDNSValue(recordType, v))
+	}
+	sort.Strings(expected)
+
+	actual := make([]string, 0, len(values))
+	for _, v := range values {
+		actual = append(actual, normalizeDNSValue(recordType, v))
+	}
+	sort.Strings(actual)
+
+	c.setMessage(fmt.Sprintf("%s %s records from %s: [%s]",
+		c.Hostname, recordType, c.ResolverURL, strings.Join(actual, ", ")))
+
+	if strings.Join(actual, "\n") != strings.Join(expected, "\n") {
+		c.setState(false)
+		c.AddError(errors.Errorf("%s %s records [%s] do not match expected [%s]",
+			c.Hostname, recordType, strings.Join(actual, ", "), strings.Join(expected, ", ")))
+		return
+	}
+
+	c.setState(true)
+}
+
+func normalizeDNSValue(recordType, value string) string {
+	switch recordType {
+	case "CNAME", "NS", "MX":
+		return strings.ToLower(strings.TrimSuffix(strings.TrimSpace(value), "."))
+	case "AAAA":
+		if ip := net.ParseIP(value); ip != nil {
+			return ip.String()
+		}
+	}
+
+	return value
+}
+
+// parseDNSResponse returns the values of the answers of the record
+// type in a DNS response message.
+func parseDNSResponse(data []byte, recordType uint16) ([]string, error) {
+	msg := &dns.Msg{}
+	if err := msg.Unpack(data); err != nil {
+		return nil, errors.Wrap(err, "problem parsing response")
+	}
+
+	if msg.Rcode != dns.RcodeSuccess {
+		name, ok := dns.RcodeToString[msg.Rcode]
+		if !ok {
+			name = fmt.Sprintf("RCODE %d", msg.Rcode)
+		}
+		return nil, errors.Errorf("resolver returned %s", name)
+	}
+
+	var values []string
+	for _, rr := range msg.Answer {
+		if rr.Header().Rrtype != recordType {
+			continue
+		}
+
+		switch rr := rr.(type) {
+		case *dns.A:
+			values = append(values, rr.A.String())
+		case *dns.AAAA:
+			values = append(values, rr.AAAA.String())
+		case *dns.CNAME:
+			values = append(values, rr.Target)
+		case *dns.NS:
+			values = append(values, rr.Ns)
+		case *dns.MX:
+			values = append(values, fmt.Sprintf("%d %s", rr.Preference, rr.Mx))
+		case *dns.TXT:
+			values = append(values, strings.Join(rr.Txt, ""))
+		}
+	}
+
+	return values, nil
+}
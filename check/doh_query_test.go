@@ -0,0 +1,234 @@
+package check
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type DoHQuerySuite struct {
+	check   *dohQuery
+	server  *httptest.Server
+	require *require.Assertions
+	suite.Suite
+}
+
+func TestDoHQuerySuite(t *testing.T) {
+	suite.Run(t, new(DoHQuerySuite))
+}
+
+func (s *DoHQuerySuite) SetupSuite() {
+	s.require = s.Require()
+	s.server = httptest.NewServer(http.HandlerFunc(s.resolve))
+}
+
+func (s *DoHQuerySuite) TearDownSuite() {
+	s.server.Close()
+}
+
+func (s *DoHQuerySuite) SetupTest() {
+	s.check = &dohQuery{
+		Base:        NewBase("doh-query", 0),
+		ResolverURL: s.server.URL + "/dns-query",
+		RecordType:  "A",
+		client:      http.DefaultClient,
+	}
+}
+
+// resolve answers DNS-over-HTTPS queries for a few names. Responses
+// are compressed, so answers refer to the question name with a
+// compression pointer.
+func (s *DoHQuerySuite) resolve(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/dns-query" || r.Header.Get("Accept") != "application/dns-message" {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(r.URL.Query().Get("dns"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	query := &dns.Msg{}
+	if err = query.Unpack(data); err != nil || len(query.Question) != 1 {
+		http.Error(w, "bad query", http.StatusBadRequest)
+		return
+	}
+	q := query.Question[0]
+
+	resp := &dns.Msg{}
+	resp.SetReply(query)
+	resp.Compress = true
+	answer := func(record string) {
+		rr, err := dns.NewRR(q.Name + " 300 IN " + record)
+		s.require.NoError(err)
+		resp.Answer = append(resp.Answer, rr)
+	}
+
+	switch q.Name {
+	case "www.example.net.":
+		answer("CNAME web.example.net.")
+		switch q.Qtype {
+		case dns.TypeA:
+			answer("A 192.0.2.20")
+			answer("A 192.0.2.21")
+		case dns.TypeAAAA:
+			answer("AAAA 2001:db8::20")
+		}
+	case "example.net.":
+		switch q.Qtype {
+		case dns.TypeMX:
+			answer("MX 10 Mail.Example.net.")
+		case dns.TypeTXT:
+			answer(`TXT "v=spf1 " "-all"`)
+		}
+	case "broken.example.net.":
+		http.Error(w, "upstream failure", http.StatusBadGateway)
+		return
+	default:
+		resp.Rcode = dns.RcodeNameError
+	}
+
+	out, err := resp.Pack()
+	s.require.NoError(err)
+
+	w.Header().Set("Content-Type", "application/dns-message")
+	_, _ = w.Write(out)
+}
+
+func (s *DoHQuerySuite) TestValidation() {
+	s.check.ResolverURL = ""
+	s.Error(s.check.validate())
+
+	s.check.ResolverURL = s.server.URL
+	s.Error(s.check.validate())
+
+	s.check.Hostname = "www.example.net"
+	s.Error(s.check.validate())
+
+	s.check.ExpectedValues = []string{"192.0.2.20"}
+	s.NoError(s.check.validate())
+
+	s.check.RecordType = "srv"
+	s.Error(s.check.validate())
+
+	s.check.RecordType = "aaaa"
+	s.NoError(s.check.validate())
+
+	s.check.Hostname = "www..example.net"
+	s.Error(s.check.validate())
+}
+
+func (s *DoHQuerySuite) TestMatchingRecordsPass() {
+	for recordType, expected := range map[string][]string{
+		"A":    {"192.0.2.21", "192.0.2.20"},
+		"AAAA": {"2001:0db8::0020"},
+	} {
+		s.SetupTest()
+		s.check.Hostname = "www.example.net"
+		s.check.RecordType = recordType
+		s.check.ExpectedValues = expected
+		s.check.Run()
+		s.True(s.check.Output().Passed, recordType)
+		s.NoError(s.check.Error(), recordType)
+	}
+
+	for recordType, expected := range map[string]string{
+		"CNAME": "web.example.net.",
+		"MX":    "10 mail.example.net",
+		"TXT":   "v=spf1 -all",
+	} {
+		s.SetupTest()
+		s.check.Hostname = "example.net"
+		if recordType == "CNAME" {
+			s.check.Hostname = "www.example.net"
+		}
+		s.check.RecordType = recordType
+		s.check.ExpectedValues = []string{expected}
+		s.check.Run()
+		s.True(s.check.Output().Passed, recordType)
+		s.NoError(s.check.Error(), recordType)
+	}
+}
+
+func (s *DoHQuerySuite) TestMismatchReportsResolvedValues() {
+	s.check.Hostname = "www.example.net"
+	s.check.ExpectedValues = []string{"192.0.2.20"}
+	s.check.Run()
+
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "[192.0.2.20, 192.0.2.21] do not match")
+}
+
+func (s *DoHQuerySuite) TestResolverErrorsAreDistinct() {
+	s.check.Hostname = "missing.example.net"
+	s.check.ExpectedValues = []string{"192.0.2.20"}
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "NXDOMAIN")
+
+	s.SetupTest()
+	s.check.Hostname = "broken.example.net"
+	s.check.ExpectedValues = []string{"192.0.2.20"}
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "returned HTTP status 502")
+
+	s.SetupTest()
+	s.check.ResolverURL = s.server.URL + "/other"
+	s.check.Hostname = "www.example.net"
+	s.check.ExpectedValues = []string{"192.0.2.20"}
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "returned HTTP status 404")
+}
+
+func (s *DoHQuerySuite) TestMalformedResponsesFail() {
+	valid := &dns.Msg{}
+	valid.SetQuestion("www.example.net.", dns.TypeA)
+	valid.Response = true
+	rr, err := dns.NewRR("www.example.net. 300 IN A 192.0.2.20")
+	s.require.NoError(err)
+	valid.Answer = append(valid.Answer, rr)
+	packed, err := valid.Pack()
+	s.require.NoError(err)
+
+	responses := [][]byte{
+		{},
+		{0x00, 0x00, 0x81},
+		// a header that claims more answers than the message has.
+		{0x00, 0x00, 0x81, 0x80, 0x00, 0x00, 0x00, 0x05, 0x00, 0x00, 0x00, 0x00},
+		// a question name with a compression pointer to itself.
+		{0x00, 0x00, 0x81, 0x80, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xC0, 0x0C, 0x00, 0x01, 0x00, 0x01},
+	}
+	for i := 1; i < len(packed); i++ {
+		responses = append(responses, packed[:i])
+	}
+
+	for _, response := range responses {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/dns-message")
+			_, _ = w.Write(response)
+		}))
+
+		s.SetupTest()
+		s.check.ResolverURL = srv.URL
+		s.check.Hostname = "www.example.net"
+		s.check.ExpectedValues = []string{"192.0.2.20"}
+		s.check.Run()
+		srv.Close()
+
+		s.False(s.check.Output().Passed, "%x", response)
+		s.Error(s.check.Error(), "%x", response)
+	}
+}
@@ -0,0 +1,71 @@
+package check
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/registry"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	name := "entropy-available"
+	registry.AddJobType(name, func() amboy.Job {
+		return &entropyAvailable{
+			Base:        NewBase(name, 0),
+			entropyFile: "/proc/sys/kernel/random/entropy_avail",
+		}
+	})
+}
+
+// entropyAvailable checks that the kernel's entropy pool has at least
+// the specified number of bits available. Only supported on Linux.
+type entropyAvailable struct {
+	MinEntropy int `bson:"min_entropy" json:"min_entropy" yaml:"min_entropy"`
+	*Base      `bson:"metadata" json:"metadata" yaml:"metadata"`
+	fileReadLimit
+
+	entropyFile string
+}
+
+func (c *entropyAvailable) Run() {
+	c.startTask()
+	defer c.MarkComplete()
+
+	if c.MinEntropy <= 0 {
+		c.setState(false)
+		c.AddError(errors.Errorf("minimum entropy for '%s' (%s) check must be greater than 0",
+			c.ID(), c.Name()))
+		return
+	}
+
+	data, err := c.readFile(c.entropyFile)
+	if err != nil {
+		c.setState(false)
+		c.AddError(errors.Wrapf(err, "problem reading available entropy from '%s'",
+			c.entropyFile))
+		return
+	}
+
+	available, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		c.setState(false)
+		c.AddError(errors.Wrapf(err, "problem parsing available entropy from '%s'",
+			c.entropyFile))
+		return
+	}
+
+	c.setMessage(fmt.Sprintf("%d bits of entropy available (minimum %d)",
+		available, c.MinEntropy))
+
+	if available < c.MinEntropy {
+		c.setState(false)
+		c.AddError(errors.Errorf("only %d bits of entropy available, which is less than %d",
+			available, c.MinEntropy))
+		return
+	}
+
+	c.setState(true)
+}
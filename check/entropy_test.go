@@ -0,0 +1,91 @@
+package check
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type EntropyAvailableSuite struct {
+	tmpDir  string
+	check   *entropyAvailable
+	require *require.Assertions
+	suite.Suite
+}
+
+func TestEntropyAvailableSuite(t *testing.T) {
+	suite.Run(t, new(EntropyAvailableSuite))
+}
+
+func (s *EntropyAvailableSuite) SetupSuite() {
+	s.require = s.Require()
+
+	dir, err := ioutil.TempDir("", uuid.NewV4().String())
+	s.require.NoError(err)
+	s.tmpDir = dir
+}
+
+func (s *EntropyAvailableSuite) TearDownSuite() {
+	s.require.NoError(os.RemoveAll(s.tmpDir))
+}
+
+func (s *EntropyAvailableSuite) SetupTest() {
+	s.check = &entropyAvailable{
+		Base:        NewBase("entropy-available", 0),
+		entropyFile: filepath.Join(s.tmpDir, "entropy_avail"),
+	}
+}
+
+func (s *EntropyAvailableSuite) writeEntropy(value string) {
+	s.require.NoError(ioutil.WriteFile(s.check.entropyFile, []byte(value), 0644))
+}
+
+func (s *EntropyAvailableSuite) TestUnconfiguredCheckFails() {
+	s.writeEntropy("3000\n")
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.Error(s.check.Error())
+}
+
+func (s *EntropyAvailableSuite) TestSufficientEntropyPasses() {
+	s.writeEntropy("3000\n")
+	s.check.MinEntropy = 1000
+	s.check.Run()
+
+	output := s.check.Output()
+	s.True(output.Passed)
+	s.NoError(s.check.Error())
+	s.Contains(output.Message, "3000")
+}
+
+func (s *EntropyAvailableSuite) TestInsufficientEntropyFails() {
+	s.writeEntropy("128\n")
+	s.check.MinEntropy = 1000
+	s.check.Run()
+
+	output := s.check.Output()
+	s.False(output.Passed)
+	s.Error(s.check.Error())
+	s.Contains(output.Message, "128")
+}
+
+func (s *EntropyAvailableSuite) TestMalformedValueFails() {
+	s.writeEntropy("lots")
+	s.check.MinEntropy = 1000
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.Error(s.check.Error())
+}
+
+func (s *EntropyAvailableSuite) TestMissingFileFails() {
+	s.check.entropyFile = filepath.Join(s.tmpDir, "does-not-exist")
+	s.check.MinEntropy = 1000
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.Error(s.check.Error())
+}
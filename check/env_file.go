@@ -0,0 +1,185 @@
+package check
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/registry"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	name := "env-file"
+	registry.AddJobType(name, func() amboy.Job {
+		return &envFile{
+			Base: NewBase(name, 0),
+		}
+	})
+}
+
+// envFile checks the variables in an environment file, in the format
+// of a systemd EnvironmentFile: KEY=VALUE lines, where lines that
+// begin with "#" or ";" are comments, values may be quoted, and a
+// trailing backslash continues the value on the next line. The
+// variables document maps names to the exact expected value, and the
+// patterns document maps names to regular expressions that the value
+// must match. Variables in forbidden must not be set. As with
+// systemd, the last assignment of a variable wins.
+type envFile struct {
+	Path      string            `bson:"path" json:"path" yaml:"path"`
+	Variables map[string]string `bson:"variables" json:"variables" yaml:"variables"`
+	Patterns  map[string]string `bson:"patterns" json:"patterns" yaml:"patterns"`
+	Forbidden []string          `bson:"forbidden" json:"forbidden" yaml:"forbidden"`
+	*Base     `bson:"metadata" json:"metadata" yaml:"metadata"`
+	fileReadLimit
+
+	patterns map[string]*regexp.Regexp
+}
+
+func (c *envFile) validate() error {
+	if c.Path == "" {
+		return errors.Errorf("no path specified for '%s' (%s) check", c.ID(), c.Name())
+	}
+
+	if len(c.Variables) == 0 && len(c.Patterns) == 0 && len(c.Forbidden) == 0 {
+		return errors.Errorf("no variables, patterns, or forbidden variables specified for '%s' check", c.ID())
+	}
+
+	c.patterns = make(map[string]*regexp.Regexp, len(c.Patterns))
+	for name, expr := range c.Patterns {
+		if _, ok := c.Variables[name]; ok {
+			return errors.Errorf("variable '%s' has both a value and a pattern in '%s' check", name, c.ID())
+		}
+
+		pattern, err := regexp.Compile(expr)
+		if err != nil {
+			return errors.Wrapf(err, "problem compiling pattern for variable '%s' in '%s' check", name, c.ID())
+		}
+		c.patterns[name] = pattern
+	}
+
+	return nil
+}
+
+func (c *envFile) Run() {
+	c.startTask()
+	defer c.MarkComplete()
+
+	if err := c.validate(); err != nil {
+		c.setState(false)
+		c.setReason(ReasonInvalidConfig)
+		c.AddError(err)
+		return
+	}
+
+	data, err := c.readFile(c.Path)
+	if err != nil {
+		c.setState(false)
+		c.AddError(errors.Wrap(err, "problem reading environment file"))
+		return
+	}
+
+	env := parseEnvFile(data)
+
+	var names []string
+	for name := range c.Variables {
+		names = append(names, name)
+	}
+	for name := range c.Patterns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var missing, wrong, forbidden []string
+	for _, name := range names {
+		value, ok := env[name]
+		if !ok {
+			missing = append(missing, name)
+			continue
+		}
+
+		if pattern, ok := c.patterns[name]; ok {
+			if !pattern.MatchString(value) {
+				wrong = append(wrong, fmt.Sprintf("%s is '%s', which does not match '%s'",
+					name, value, c.Patterns[name]))
+			}
+			continue
+		}
+
+		if value != c.Variables[name] {
+			wrong = append(wrong, fmt.Sprintf("%s is '%s', not '%s'", name, value, c.Variables[name]))
+		}
+	}
+
+	for _, name := range c.Forbidden {
+		if _, ok := env[name]; ok {
+			forbidden = append(forbidden, name)
+		}
+	}
+
+	if len(missing) == 0 && len(wrong) == 0 && len(forbidden) == 0 {
+		c.setState(true)
+		c.setMessage(fmt.Sprintf("%d variables in %s have the expected values", len(names), c.Path))
+		return
+	}
+
+	c.setState(false)
+
+	var msgs []string
+	if len(missing) > 0 {
+		msgs = append(msgs, "missing: ["+strings.Join(missing, ", ")+"]")
+	}
+	if len(wrong) > 0 {
+		msgs = append(msgs, "wrong: ["+strings.Join(wrong, "; ")+"]")
+	}
+	if len(forbidden) > 0 {
+		msgs = append(msgs, "forbidden: ["+strings.Join(forbidden, ", ")+"]")
+	}
+	c.setMessage(strings.Join(msgs, "; "))
+
+	c.AddError(errors.Errorf("%s has %d missing, %d wrong, and %d forbidden variables",
+		c.Path, len(missing), len(wrong), len(forbidden)))
+}
+
+// parseEnvFile returns the variables set in an environment file.
+func parseEnvFile(data []byte) map[string]string {
+	env := make(map[string]string)
+
+	var logical string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if strings.HasSuffix(line, "\\") {
+			logical += strings.TrimSuffix(line, "\\")
+			continue
+		}
+		line = logical + line
+		logical = ""
+
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		idx := strings.Index(line, "=")
+		if idx <= 0 {
+			continue
+		}
+
+		name := strings.TrimSpace(line[:idx])
+		value := strings.TrimSpace(line[idx+1:])
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+
+		env[name] = value
+	}
+
+	return env
+}
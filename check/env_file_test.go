@@ -0,0 +1,124 @@
+package check
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type EnvFileSuite struct {
+	tmpDir  string
+	fn      string
+	check   *envFile
+	require *require.Assertions
+	suite.Suite
+}
+
+func TestEnvFileSuite(t *testing.T) {
+	suite.Run(t, new(EnvFileSuite))
+}
+
+func (s *EnvFileSuite) SetupSuite() {
+	s.require = s.Require()
+
+	dir, err := ioutil.TempDir("", uuid.NewV4().String())
+	s.require.NoError(err)
+	s.tmpDir = dir
+
+	s.fn = filepath.Join(dir, "service.env")
+	s.require.NoError(ioutil.WriteFile(s.fn, []byte(`# service environment
+; another comment
+LOG_LEVEL=debug
+LOG_LEVEL=info
+DATA_DIR = "/var/lib/service"
+OPTS='--port 8080 \
+--bind 0.0.0.0'
+DEBUG_TOKEN=secret
+`), 0644))
+}
+
+func (s *EnvFileSuite) TearDownSuite() {
+	s.require.NoError(os.RemoveAll(s.tmpDir))
+}
+
+func (s *EnvFileSuite) SetupTest() {
+	s.check = &envFile{
+		Base: NewBase("env-file", 0),
+		Path: s.fn,
+	}
+}
+
+func (s *EnvFileSuite) TestParsing() {
+	data, err := ioutil.ReadFile(s.fn)
+	s.require.NoError(err)
+
+	env := parseEnvFile(data)
+	s.Len(env, 4)
+	s.Equal("info", env["LOG_LEVEL"])
+	s.Equal("/var/lib/service", env["DATA_DIR"])
+	s.Equal("--port 8080 --bind 0.0.0.0", env["OPTS"])
+}
+
+func (s *EnvFileSuite) TestValidation() {
+	s.Error(s.check.validate())
+
+	s.check.Forbidden = []string{"DEBUG_TOKEN"}
+	s.NoError(s.check.validate())
+
+	s.check.Variables = map[string]string{"LOG_LEVEL": "info"}
+	s.check.Patterns = map[string]string{"LOG_LEVEL": "info"}
+	s.Error(s.check.validate())
+
+	s.check.Patterns = map[string]string{"OPTS": "(["}
+	s.Error(s.check.validate())
+
+	s.check.Path = ""
+	s.check.Patterns = nil
+	s.Error(s.check.validate())
+}
+
+func (s *EnvFileSuite) TestExpectedVariablesPass() {
+	s.check.Variables = map[string]string{
+		"LOG_LEVEL": "info",
+		"DATA_DIR":  "/var/lib/service",
+	}
+	s.check.Patterns = map[string]string{"OPTS": `--port \d+`}
+	s.check.Forbidden = []string{"HTTP_PROXY"}
+	s.check.Run()
+
+	s.True(s.check.Output().Passed)
+	s.NoError(s.check.Error())
+}
+
+func (s *EnvFileSuite) TestProblemsAreReportedSeparately() {
+	s.check.Variables = map[string]string{
+		"LOG_LEVEL": "debug",
+		"PORT":      "8080",
+	}
+	s.check.Patterns = map[string]string{"DATA_DIR": "^/srv/"}
+	s.check.Forbidden = []string{"DEBUG_TOKEN"}
+	s.check.Run()
+
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "1 missing, 2 wrong, and 1 forbidden")
+
+	msg := s.check.Output().Message
+	s.Contains(msg, "missing: [PORT]")
+	s.Contains(msg, "LOG_LEVEL is 'info', not 'debug'")
+	s.Contains(msg, "DATA_DIR is '/var/lib/service', which does not match '^/srv/'")
+	s.Contains(msg, "forbidden: [DEBUG_TOKEN]")
+}
+
+func (s *EnvFileSuite) TestMissingFileFails() {
+	s.check.Path = filepath.Join(s.tmpDir, "DOES-NOT-EXIST")
+	s.check.Forbidden = []string{"DEBUG_TOKEN"}
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.Error(s.check.Error())
+}
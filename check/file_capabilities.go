@@ -0,0 +1,257 @@
+package check
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/registry"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	name := "file-capabilities"
+	registry.AddJobType(name, func() amboy.Job {
+		return &fileCapabilities{
+			Base:     NewBase(name, 0),
+			getXattr: getCapabilityXattr,
+		}
+	})
+}
+
+// fileCapabilities checks that the file capabilities of a file, which
+// are stored in the security.capability extended attribute, match
+// the expected capabilities. Specify capabilities in the format that
+// getcap and setcap use, e.g. "cap_net_bind_service+ep" or
+// "cap_net_admin,cap_net_raw=eip cap_chown+i". Use "none" to check
+// that a file does not have capabilities. Only supported on Linux.
+type fileCapabilities struct {
+	Path         string `bson:"path" json:"path" yaml:"path"`
+	Capabilities string `bson:"capabilities" json:"capabilities" yaml:"capabilities"`
+	*Base        `bson:"metadata" json:"metadata" yaml:"metadata"`
+
+	expected map[string]string
+	getXattr func(string) ([]byte, error)
+}
+
+// capabilityNames are the names of the Linux capabilities, indexed by
+// capability number.
+var capabilityNames = []string{
+	"cap_chown", "cap_dac_override", "cap_dac_read_search", "cap_fowner",
+	"cap_fsetid", "cap_kill", "cap_setgid", "cap_setuid", "cap_setpcap",
+	"cap_linux_immutable", "cap_net_bind_service", "cap_net_broadcast",
+	"cap_net_admin", "cap_net_raw", "cap_ipc_lock", "cap_ipc_owner",
+	"cap_sys_module", "cap_sys_rawio", "cap_sys_chroot", "cap_sys_ptrace",
+	"cap_sys_pacct", "cap_sys_admin", "cap_sys_boot", "cap_sys_nice",
+	"cap_sys_resource", "cap_sys_time", "cap_sys_tty_config", "cap_mknod",
+	"cap_lease", "cap_audit_write", "cap_audit_control", "cap_setfcap",
+	"cap_mac_override", "cap_mac_admin", "cap_syslog", "cap_wake_alarm",
+	"cap_block_suspend", "cap_audit_read", "cap_perfmon", "cap_bpf",
+	"cap_checkpoint_restore",
+}
+
+// Constants from linux/capability.h for the format of the
+// security.capability extended attribute.
+const (
+	vfsCapRevisionMask   = 0xFF000000
+	vfsCapRevision1      = 0x01000000
+	vfsCapRevision2      = 0x02000000
+	vfsCapRevision3      = 0x03000000
+	vfsCapFlagsEffective = 0x000001
+)
+
+func (c *fileCapabilities) validate() error {
+	if c.Path == "" {
+		return errors.Errorf("no path specified for '%s' (%s) check", c.ID(), c.Name())
+	}
+
+	if c.Capabilities == "" {
+		return errors.Errorf("no capabilities specified for '%s' check", c.ID())
+	}
+
+	expected, err := parseCapabilities(c.Capabilities)
+	if err != nil {
+		return errors.Wrapf(err, "problem parsing capabilities for '%s' check", c.ID())
+	}
+	c.expected = expected
+
+	return nil
+}
+
+func (c *fileCapabilities) Run() {
+	c.startTask()
+	defer c.MarkComplete()
+
+	if err := c.validate(); err != nil {
+		c.setState(false)
+		c.setReason(ReasonInvalidConfig)
+		c.AddError(err)
+		return
+	}
+
+	data, err := c.getXattr(c.Path)
+	if err != nil {
+		c.setState(false)
+		c.AddError(err)
+		return
+	}
+
+	actual, err := decodeCapabilities(data)
+	if err != nil {
+		c.setState(false)
+		c.AddError(errors.Wrapf(err, "problem decoding capabilities of '%s'", c.Path))
+		return
+	}
+
+	c.setMessage(fmt.Sprintf("'%s' has capabilities '%s'", c.Path, formatCapabilities(actual)))
+
+	if formatCapabilities(actual) != formatCapabilities(c.expected) {
+		c.setState(false)
+		c.AddError(errors.Errorf("'%s' has capabilities '%s', expected '%s'",
+			c.Path, formatCapabilities(actual), formatCapabilities(c.expected)))
+		return
+	}
+
+	c.setState(true)
+}
+
+// parseCapabilities parses capabilities in the text format of
+// getcap, and returns a map of capability names to their flags.
+func parseCapabilities(text string) (map[string]string, error) {
+	caps := make(map[string]string)
+	if strings.TrimSpace(text) == "none" {
+		return caps, nil
+	}
+
+	for _, clause := range strings.Fields(text) {
+		idx := strings.IndexAny(clause, "+=")
+		if idx <= 0 {
+			return nil, errors.Errorf("'%s' does not have capability flags", clause)
+		}
+
+		flags := clause[idx+1:]
+		for _, f := range flags {
+			if !strings.ContainsRune("eip", f) {
+				return nil, errors.Errorf("'%c' in '%s' is not a capability flag", f, clause)
+			}
+		}
+
+		for _, name := range strings.Split(strings.ToLower(clause[:idx]), ",") {
+			if !isCapabilityName(name) {
+				return nil, errors.Errorf("'%s' is not a capability", name)
+			}
+
+			caps[name] = normalizeCapabilityFlags(caps[name] + flags)
+		}
+	}
+
+	return caps, nil
+}
+
+func isCapabilityName(name string) bool {
+	for _, n := range capabilityNames {
+		if n == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func normalizeCapabilityFlags(flags string) string {
+	out := ""
+	for _, f := range "eip" {
+		if strings.ContainsRune(flags, f) {
+			out += string(f)
+		}
+	}
+
+	return out
+}
+
+// decodeCapabilities decodes the value of a security.capability
+// extended attribute. Files without the attribute have no
+// capabilities.
+func decodeCapabilities(data []byte) (map[string]string, error) {
+	caps := make(map[string]string)
+	if len(data) == 0 {
+		return caps, nil
+	}
+
+	if len(data) < 4 {
+		return nil, errors.Errorf("capability data is too short (%d bytes)", len(data))
+	}
+
+	magic := binary.LittleEndian.Uint32(data)
+
+	var numSets int
+	switch magic & vfsCapRevisionMask {
+	case vfsCapRevision1:
+		numSets = 1
+	case vfsCapRevision2, vfsCapRevision3:
+		numSets = 2
+	default:
+		return nil, errors.Errorf("unknown capability revision 0x%x", magic&vfsCapRevisionMask)
+	}
+
+	if len(data) < 4+numSets*8 {
+		return nil, errors.Errorf("capability data is too short (%d bytes)", len(data))
+	}
+
+	effective := magic&vfsCapFlagsEffective != 0
+
+	for set := 0; set < numSets; set++ {
+		permitted := binary.LittleEndian.Uint32(data[4+set*8:])
+		inheritable := binary.LittleEndian.Uint32(data[8+set*8:])
+
+		for bit := uint(0); bit < 32; bit++ {
+			flags := ""
+			if permitted&(1<<bit) != 0 {
+				flags += "p"
+			}
+			if inheritable&(1<<bit) != 0 {
+				flags += "i"
+			}
+			if flags == "" {
+				continue
+			}
+			if effective {
+				flags += "e"
+			}
+
+			num := set*32 + int(bit)
+			name := fmt.Sprintf("cap_%d", num)
+			if num < len(capabilityNames) {
+				name = capabilityNames[num]
+			}
+
+			caps[name] = normalizeCapabilityFlags(flags)
+		}
+	}
+
+	return caps, nil
+}
+
+// formatCapabilities renders capabilities in the text format of
+// getcap, grouping capabilities with the same flags.
+func formatCapabilities(caps map[string]string) string {
+	if len(caps) == 0 {
+		return "none"
+	}
+
+	byFlags := make(map[string][]string)
+	for name, flags := range caps {
+		byFlags[flags] = append(byFlags[flags], name)
+	}
+
+	clauses := make([]string, 0, len(byFlags))
+	for flags, names := range byFlags {
+		sort.Strings(names)
+		clauses = append(clauses, strings.Join(names, ",")+"="+flags)
+	}
+	sort.Strings(clauses)
+
+	return strings.Join(clauses, " ")
+}
@@ -0,0 +1,32 @@
+//go:build linux
+// +build linux
+
+package check
+
+import (
+	"syscall"
+
+	"github.com/pkg/errors"
+)
+
+// getCapabilityXattr returns the security.capability extended
+// attribute of a file, or no data if the file does not have
+// capabilities.
+func getCapabilityXattr(path string) ([]byte, error) {
+	buf := make([]byte, 64)
+
+	for {
+		size, err := syscall.Getxattr(path, "security.capability", buf)
+		switch err {
+		case nil:
+			return buf[:size], nil
+		case syscall.ERANGE:
+			buf = make([]byte, len(buf)*2)
+			continue
+		case syscall.ENODATA, syscall.ENOTSUP:
+			return nil, nil
+		default:
+			return nil, errors.Wrapf(err, "problem reading capabilities of '%s'", path)
+		}
+	}
+}
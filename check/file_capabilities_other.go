@@ -0,0 +1,10 @@
+//go:build !linux
+// +build !linux
+
+package check
+
+import "github.com/pkg/errors"
+
+func getCapabilityXattr(path string) ([]byte, error) {
+	return nil, errors.Errorf("cannot read capabilities of '%s': file capabilities are only supported on linux", path)
+}
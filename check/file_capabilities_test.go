@@ -0,0 +1,157 @@
+package check
+
+import (
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type FileCapabilitiesSuite struct {
+	data    []byte
+	check   *fileCapabilities
+	require *require.Assertions
+	suite.Suite
+}
+
+func TestFileCapabilitiesSuite(t *testing.T) {
+	suite.Run(t, new(FileCapabilitiesSuite))
+}
+
+// encodeCapabilities builds a revision 2 security.capability value.
+func encodeCapabilities(effective bool, permitted, inheritable [2]uint32) []byte {
+	data := make([]byte, 20)
+
+	magic := uint32(vfsCapRevision2)
+	if effective {
+		magic |= vfsCapFlagsEffective
+	}
+	binary.LittleEndian.PutUint32(data, magic)
+
+	for set := 0; set < 2; set++ {
+		binary.LittleEndian.PutUint32(data[4+set*8:], permitted[set])
+		binary.LittleEndian.PutUint32(data[8+set*8:], inheritable[set])
+	}
+
+	return data
+}
+
+func (s *FileCapabilitiesSuite) SetupSuite() {
+	s.require = s.Require()
+}
+
+func (s *FileCapabilitiesSuite) SetupTest() {
+	// cap_net_bind_service (10) is permitted and effective.
+	s.data = encodeCapabilities(true, [2]uint32{1 << 10, 0}, [2]uint32{0, 0})
+	s.check = &fileCapabilities{
+		Base: NewBase("file-capabilities", 0),
+		Path: "/usr/sbin/nginx",
+		getXattr: func(string) ([]byte, error) {
+			return s.data, nil
+		},
+	}
+}
+
+func (s *FileCapabilitiesSuite) TestValidation() {
+	s.check.Path = ""
+	s.Error(s.check.validate())
+
+	s.check.Path = "/usr/sbin/nginx"
+	s.Error(s.check.validate())
+
+	s.check.Capabilities = "cap_net_bind_service+ep"
+	s.NoError(s.check.validate())
+
+	for _, invalid := range []string{"cap_net_bind_service", "cap_bind_port+ep", "cap_chown+x"} {
+		s.check.Capabilities = invalid
+		s.Error(s.check.validate(), invalid)
+	}
+}
+
+func (s *FileCapabilitiesSuite) TestParsingAndFormatting() {
+	caps, err := parseCapabilities("cap_net_raw,CAP_NET_ADMIN=pe cap_chown+i cap_chown+p")
+	s.require.NoError(err)
+	s.Equal(map[string]string{
+		"cap_net_raw":   "ep",
+		"cap_net_admin": "ep",
+		"cap_chown":     "ip",
+	}, caps)
+	s.Equal("cap_chown=ip cap_net_admin,cap_net_raw=ep", formatCapabilities(caps))
+
+	caps, err = parseCapabilities("none")
+	s.require.NoError(err)
+	s.Equal("none", formatCapabilities(caps))
+}
+
+func (s *FileCapabilitiesSuite) TestDecoding() {
+	caps, err := decodeCapabilities(s.data)
+	s.require.NoError(err)
+	s.Equal(map[string]string{"cap_net_bind_service": "ep"}, caps)
+
+	// cap_chown (0) is inheritable, and cap_bpf (39) is permitted,
+	// without the effective flag.
+	caps, err = decodeCapabilities(encodeCapabilities(false, [2]uint32{0, 1 << 7}, [2]uint32{1, 0}))
+	s.require.NoError(err)
+	s.Equal("cap_bpf=p cap_chown=i", formatCapabilities(caps))
+
+	caps, err = decodeCapabilities(nil)
+	s.require.NoError(err)
+	s.Len(caps, 0)
+
+	_, err = decodeCapabilities([]byte{1, 2})
+	s.Error(err)
+
+	_, err = decodeCapabilities(encodeCapabilities(false, [2]uint32{}, [2]uint32{})[:8])
+	s.Error(err)
+}
+
+func (s *FileCapabilitiesSuite) TestMatchingCapabilitiesPass() {
+	s.check.Capabilities = "cap_net_bind_service=ep"
+	s.check.Run()
+	s.True(s.check.Output().Passed)
+	s.NoError(s.check.Error())
+}
+
+func (s *FileCapabilitiesSuite) TestMismatchReportsActualCapabilities() {
+	s.check.Capabilities = "cap_net_bind_service,cap_net_raw+ep"
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "has capabilities 'cap_net_bind_service=ep'")
+
+	s.SetupTest()
+	s.data = nil
+	s.check.Capabilities = "cap_net_bind_service+ep"
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "has capabilities 'none'")
+}
+
+func (s *FileCapabilitiesSuite) TestFileWithoutCapabilities() {
+	if runtime.GOOS != "linux" {
+		s.T().Skip("file capabilities are only supported on linux")
+	}
+
+	dir, err := ioutil.TempDir("", uuid.NewV4().String())
+	s.require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	fn := filepath.Join(dir, "binary")
+	s.require.NoError(ioutil.WriteFile(fn, []byte("#!/bin/sh\n"), 0755))
+
+	factory, err := GetChecker("file-capabilities")
+	s.require.NoError(err)
+	c := factory.(*fileCapabilities)
+	c.Path = fn
+	c.Capabilities = "none"
+	c.Run()
+	s.NoError(c.Error())
+	s.True(c.Output().Passed)
+}
@@ -46,6 +46,11 @@ func (c *fileExistance) Run() {
 
 	c.setState(fileExists == c.ShouldExist)
 	if fileExists != c.ShouldExist {
+		if c.ShouldExist {
+			c.setReason(ReasonFileMissing)
+		} else {
+			c.setReason(ReasonFilePresent)
+		}
 		c.AddError(errors.New("file existence check did not detect expected state"))
 	}
 
@@ -0,0 +1,111 @@
+package check
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/registry"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	name := "file-immutable"
+	registry.AddJobType(name, func() amboy.Job {
+		return &fileImmutable{
+			Expected: true,
+			Base:     NewBase(name, 0),
+		}
+	})
+}
+
+// fileImmutable checks that the immutable inode attribute (i.e. the
+// attribute set with "chattr +i") of a file matches the expected
+// value. Reading inode attributes is only supported on Linux, and
+// only on filesystems that support the FS_IOC_GETFLAGS ioctl.
+type fileImmutable struct {
+	Path     string `bson:"path" json:"path" yaml:"path"`
+	Expected bool   `bson:"expected" json:"expected" yaml:"expected"`
+	*Base    `bson:"metadata" json:"metadata" yaml:"metadata"`
+}
+
+// errInodeFlagsUnsupported is returned by getInodeFlags when the
+// platform or filesystem does not support reading inode attributes.
+var errInodeFlagsUnsupported = errors.New("reading inode attributes is not supported")
+
+// these are the inode attribute flags from linux/fs.h, using the
+// letters that lsattr uses to display them.
+const fsImmutableFlag = 0x00000010
+
+var inodeFlagNames = []struct {
+	flag   uint32
+	letter string
+}{
+	{0x00000001, "s"},
+	{0x00000002, "u"},
+	{0x00000004, "c"},
+	{0x00000008, "S"},
+	{fsImmutableFlag, "i"},
+	{0x00000020, "a"},
+	{0x00000040, "d"},
+	{0x00000080, "A"},
+	{0x00001000, "I"},
+	{0x00004000, "j"},
+	{0x00008000, "t"},
+	{0x00010000, "D"},
+	{0x00020000, "T"},
+	{0x00080000, "e"},
+	{0x00800000, "C"},
+	{0x20000000, "P"},
+}
+
+func formatInodeFlags(flags uint32) string {
+	var out []string
+	for _, f := range inodeFlagNames {
+		if flags&f.flag != 0 {
+			out = append(out, f.letter)
+		}
+	}
+
+	if len(out) == 0 {
+		return "(none)"
+	}
+
+	return strings.Join(out, "")
+}
+
+func (c *fileImmutable) Run() {
+	c.startTask()
+	defer c.MarkComplete()
+
+	if c.Path == "" {
+		c.setState(false)
+		c.AddError(errors.Errorf("no path specified for '%s' (%s) check", c.ID(), c.Name()))
+		return
+	}
+
+	flags, err := getInodeFlags(c.Path)
+	if errors.Cause(err) == errInodeFlagsUnsupported {
+		c.setState(false)
+		c.setMessage(fmt.Sprintf("cannot determine attributes of '%s': the platform or "+
+			"filesystem does not support inode attributes", c.Path))
+		c.AddError(err)
+		return
+	} else if err != nil {
+		c.setState(false)
+		c.AddError(err)
+		return
+	}
+
+	immutable := flags&fsImmutableFlag != 0
+	c.setMessage(fmt.Sprintf("'%s' has attributes: %s", c.Path, formatInodeFlags(flags)))
+
+	if immutable != c.Expected {
+		c.setState(false)
+		c.AddError(errors.Errorf("'%s' immutable attribute is %t, expected %t",
+			c.Path, immutable, c.Expected))
+		return
+	}
+
+	c.setState(true)
+}
@@ -0,0 +1,39 @@
+//go:build linux
+// +build linux
+
+package check
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+
+	"github.com/pkg/errors"
+)
+
+// fsIocGetFlags is FS_IOC_GETFLAGS, which is defined as
+// _IOR('f', 1, long), and so depends on the size of a long.
+const fsIocGetFlags = 0x80006601 | (unsafe.Sizeof(uintptr(0)) << 16)
+
+func getInodeFlags(path string) (uint32, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, errors.Wrapf(err, "problem opening '%s'", path)
+	}
+	defer f.Close()
+
+	// the kernel writes an int, despite the size in the ioctl
+	// number, so we read into a 32 bit value.
+	var flags uint32
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), fsIocGetFlags,
+		uintptr(unsafe.Pointer(&flags)))
+
+	switch errno {
+	case 0:
+		return flags, nil
+	case syscall.ENOTTY, syscall.ENOTSUP, syscall.ENOSYS, syscall.EINVAL:
+		return 0, errors.Wrapf(errInodeFlagsUnsupported, "for '%s' (%s)", path, errno)
+	default:
+		return 0, errors.Wrapf(errno, "problem reading attributes of '%s'", path)
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+// +build !linux
+
+package check
+
+import "github.com/pkg/errors"
+
+func getInodeFlags(path string) (uint32, error) {
+	return 0, errors.Wrapf(errInodeFlagsUnsupported, "for '%s' on this platform", path)
+}
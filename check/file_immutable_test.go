@@ -0,0 +1,91 @@
+package check
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type FileImmutableSuite struct {
+	tmpDir  string
+	fn      string
+	check   *fileImmutable
+	require *require.Assertions
+	suite.Suite
+}
+
+func TestFileImmutableSuite(t *testing.T) {
+	suite.Run(t, new(FileImmutableSuite))
+}
+
+func (s *FileImmutableSuite) SetupSuite() {
+	s.require = s.Require()
+
+	dir, err := ioutil.TempDir("", uuid.NewV4().String())
+	s.require.NoError(err)
+	s.tmpDir = dir
+
+	s.fn = filepath.Join(dir, "mutable")
+	s.require.NoError(ioutil.WriteFile(s.fn, []byte("greenbay"), 0644))
+}
+
+func (s *FileImmutableSuite) TearDownSuite() {
+	s.require.NoError(os.RemoveAll(s.tmpDir))
+}
+
+func (s *FileImmutableSuite) SetupTest() {
+	s.check = &fileImmutable{
+		Expected: true,
+		Base:     NewBase("file-immutable", 0),
+	}
+}
+
+func (s *FileImmutableSuite) TestFormattingFlags() {
+	s.Equal("(none)", formatInodeFlags(0))
+	s.Equal("i", formatInodeFlags(fsImmutableFlag))
+	s.Equal("iae", formatInodeFlags(fsImmutableFlag|0x20|0x80000))
+}
+
+func (s *FileImmutableSuite) TestCheckWithoutPathFails() {
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.Error(s.check.Error())
+}
+
+func (s *FileImmutableSuite) TestCheckOfMissingFileFails() {
+	s.check.Path = filepath.Join(s.tmpDir, "DOES-NOT-EXIST")
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.Error(s.check.Error())
+}
+
+func (s *FileImmutableSuite) TestMutableFile() {
+	_, err := getInodeFlags(s.fn)
+	if errors.Cause(err) == errInodeFlagsUnsupported {
+		s.check.Path = s.fn
+		s.check.Run()
+		s.False(s.check.Output().Passed)
+		s.Contains(s.check.Output().Message, "does not support inode attributes")
+		return
+	}
+	s.require.NoError(err)
+
+	s.check.Path = s.fn
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.Error(s.check.Error())
+
+	s.SetupTest()
+	s.check.Path = s.fn
+	s.check.Expected = false
+	s.check.Run()
+	s.True(s.check.Output().Passed)
+	s.NoError(s.check.Error())
+	s.Contains(s.check.Output().Message, "has attributes")
+}
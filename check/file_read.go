@@ -0,0 +1,65 @@
+package check
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultMaxReadBytes is the largest file, in bytes, that checks will
+// read, for checks that do not specify a max_read_bytes value. Set
+// max_read_bytes in the "defaults" section of the config file to
+// change the limit for all checks.
+var DefaultMaxReadBytes int64 = 64 * 1024 * 1024
+
+// fileReadLimit provides a size limit for checks that read files, so
+// that a check pointed at a very large file fails rather than
+// exhausting the memory of the host. Checks that read files should
+// compose fileReadLimit and use its readFile method.
+type fileReadLimit struct {
+	MaxReadBytes int64 `bson:"max_read_bytes" json:"max_read_bytes" yaml:"max_read_bytes"`
+}
+
+func (l fileReadLimit) limit() int64 {
+	if l.MaxReadBytes > 0 {
+		return l.MaxReadBytes
+	}
+
+	return DefaultMaxReadBytes
+}
+
+// readFile returns the contents of the file, or an error if the file
+// is larger than the limit. Files that do not report their size,
+// such as files in /proc, are read up to the limit.
+func (l fileReadLimit) readFile(fn string) ([]byte, error) {
+	max := l.limit()
+
+	f, err := os.Open(fn)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem opening '%s'", fn)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem finding size of '%s'", fn)
+	}
+
+	if info.Size() > max {
+		return nil, errors.Errorf("file '%s' too large: %d bytes is larger than the %d byte limit",
+			fn, info.Size(), max)
+	}
+
+	data, err := ioutil.ReadAll(io.LimitReader(f, max+1))
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem reading '%s'", fn)
+	}
+
+	if int64(len(data)) > max {
+		return nil, errors.Errorf("file '%s' too large: more than the %d byte limit", fn, max)
+	}
+
+	return data, nil
+}
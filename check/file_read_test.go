@@ -0,0 +1,55 @@
+package check
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileReadLimit(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", uuid.NewV4().String())
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	fn := filepath.Join(dir, "file")
+	assert.NoError(ioutil.WriteFile(fn, []byte("0123456789"), 0644))
+
+	l := fileReadLimit{}
+	assert.Equal(DefaultMaxReadBytes, l.limit())
+	data, err := l.readFile(fn)
+	assert.NoError(err)
+	assert.Equal("0123456789", string(data))
+
+	l.MaxReadBytes = 10
+	_, err = l.readFile(fn)
+	assert.NoError(err)
+
+	l.MaxReadBytes = 9
+	_, err = l.readFile(fn)
+	if assert.Error(err) {
+		assert.Contains(err.Error(), "too large")
+	}
+
+	_, err = l.readFile(filepath.Join(dir, "DOES-NOT-EXIST"))
+	assert.Error(err)
+}
+
+func TestFileReadLimitWithFilesWithoutSize(t *testing.T) {
+	assert := assert.New(t)
+
+	// files in /proc report a size of zero.
+	fn := "/proc/self/status"
+	if _, err := os.Stat(fn); os.IsNotExist(err) {
+		t.Skip("procfs not available")
+	}
+
+	l := fileReadLimit{MaxReadBytes: 8}
+	_, err := l.readFile(fn)
+	assert.Error(err)
+}
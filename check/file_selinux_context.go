@@ -0,0 +1,91 @@
+package check
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/registry"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	name := "file-selinux-context"
+	registry.AddJobType(name, func() amboy.Job {
+		return &fileSELinuxContext{
+			Base:       NewBase(name, 0),
+			selinuxDir: "/sys/fs/selinux",
+			getContext: getSELinuxContext,
+		}
+	})
+}
+
+// fileSELinuxContext checks that the SELinux security context of a
+// file (e.g. "system_u:object_r:httpd_config_t:s0") matches the
+// expected context. The check is skipped on hosts that do not have
+// SELinux enabled.
+type fileSELinuxContext struct {
+	Path            string `bson:"path" json:"path" yaml:"path"`
+	ExpectedContext string `bson:"expected_context" json:"expected_context" yaml:"expected_context"`
+	*Base           `bson:"metadata" json:"metadata" yaml:"metadata"`
+
+	selinuxDir string
+	getContext func(string) (string, error)
+}
+
+// errSELinuxUnsupported is returned by getSELinuxContext when the
+// platform or filesystem does not support security labels.
+var errSELinuxUnsupported = errors.New("selinux labels are not supported")
+
+func (c *fileSELinuxContext) validate() error {
+	if c.Path == "" {
+		return errors.Errorf("no path specified for '%s' (%s) check", c.ID(), c.Name())
+	}
+
+	if c.ExpectedContext == "" {
+		return errors.Errorf("no expected context specified for '%s' check", c.ID())
+	}
+
+	return nil
+}
+
+func (c *fileSELinuxContext) Run() {
+	c.startTask()
+	defer c.MarkComplete()
+
+	if err := c.validate(); err != nil {
+		c.setState(false)
+		c.setReason(ReasonInvalidConfig)
+		c.AddError(err)
+		return
+	}
+
+	// selinuxfs has an "enforce" file when SELinux is enabled,
+	// in either permissive or enforcing mode.
+	if _, err := os.Stat(filepath.Join(c.selinuxDir, "enforce")); os.IsNotExist(err) {
+		c.setSkipped("selinux is not enabled on this host")
+		return
+	}
+
+	context, err := c.getContext(c.Path)
+	if errors.Cause(err) == errSELinuxUnsupported {
+		c.setSkipped(fmt.Sprintf("'%s' does not support selinux labels", c.Path))
+		return
+	} else if err != nil {
+		c.setState(false)
+		c.AddError(err)
+		return
+	}
+
+	c.setMessage(fmt.Sprintf("'%s' has context '%s'", c.Path, context))
+
+	if context != c.ExpectedContext {
+		c.setState(false)
+		c.AddError(errors.Errorf("'%s' has context '%s', expected '%s'",
+			c.Path, context, c.ExpectedContext))
+		return
+	}
+
+	c.setState(true)
+}
@@ -0,0 +1,30 @@
+//go:build linux
+// +build linux
+
+package check
+
+import (
+	"strings"
+	"syscall"
+
+	"github.com/pkg/errors"
+)
+
+func getSELinuxContext(path string) (string, error) {
+	buf := make([]byte, 256)
+
+	for {
+		size, err := syscall.Getxattr(path, "security.selinux", buf)
+		switch err {
+		case nil:
+			return strings.TrimRight(string(buf[:size]), "\x00"), nil
+		case syscall.ERANGE:
+			buf = make([]byte, len(buf)*2)
+			continue
+		case syscall.ENOTSUP, syscall.ENODATA:
+			return "", errors.Wrapf(errSELinuxUnsupported, "for '%s' (%s)", path, err)
+		default:
+			return "", errors.Wrapf(err, "problem reading selinux context of '%s'", path)
+		}
+	}
+}
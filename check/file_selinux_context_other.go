@@ -0,0 +1,10 @@
+//go:build !linux
+// +build !linux
+
+package check
+
+import "github.com/pkg/errors"
+
+func getSELinuxContext(path string) (string, error) {
+	return "", errors.Wrapf(errSELinuxUnsupported, "for '%s' on this platform", path)
+}
@@ -0,0 +1,109 @@
+package check
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type FileSELinuxContextSuite struct {
+	tmpDir  string
+	check   *fileSELinuxContext
+	require *require.Assertions
+	suite.Suite
+}
+
+func TestFileSELinuxContextSuite(t *testing.T) {
+	suite.Run(t, new(FileSELinuxContextSuite))
+}
+
+func (s *FileSELinuxContextSuite) SetupSuite() {
+	s.require = s.Require()
+
+	dir, err := ioutil.TempDir("", uuid.NewV4().String())
+	s.require.NoError(err)
+	s.tmpDir = dir
+
+	s.require.NoError(os.MkdirAll(filepath.Join(dir, "selinux"), 0755))
+	s.require.NoError(ioutil.WriteFile(filepath.Join(dir, "selinux", "enforce"), []byte("1"), 0644))
+}
+
+func (s *FileSELinuxContextSuite) TearDownSuite() {
+	s.require.NoError(os.RemoveAll(s.tmpDir))
+}
+
+func (s *FileSELinuxContextSuite) SetupTest() {
+	s.check = &fileSELinuxContext{
+		Base:       NewBase("file-selinux-context", 0),
+		selinuxDir: filepath.Join(s.tmpDir, "selinux"),
+		getContext: func(string) (string, error) {
+			return "system_u:object_r:httpd_config_t:s0", nil
+		},
+	}
+}
+
+func (s *FileSELinuxContextSuite) TestUnconfiguredCheckFails() {
+	s.check.Run()
+	output := s.check.Output()
+	s.False(output.Passed)
+	s.False(output.Skipped)
+	s.Error(s.check.Error())
+}
+
+func (s *FileSELinuxContextSuite) TestMatchingContextPasses() {
+	s.check.Path = "/etc/httpd/conf/httpd.conf"
+	s.check.ExpectedContext = "system_u:object_r:httpd_config_t:s0"
+	s.check.Run()
+
+	s.True(s.check.Output().Passed)
+	s.NoError(s.check.Error())
+}
+
+func (s *FileSELinuxContextSuite) TestMismatchedContextReportsActualContext() {
+	s.check.Path = "/etc/httpd/conf/httpd.conf"
+	s.check.ExpectedContext = "system_u:object_r:etc_t:s0"
+	s.check.Run()
+
+	output := s.check.Output()
+	s.False(output.Passed)
+	s.Error(s.check.Error())
+	s.Contains(output.Message, "httpd_config_t")
+}
+
+func (s *FileSELinuxContextSuite) TestHostsWithoutSELinuxAreSkipped() {
+	s.check.selinuxDir = filepath.Join(s.tmpDir, "DOES-NOT-EXIST")
+	s.check.Path = "/etc/httpd/conf/httpd.conf"
+	s.check.ExpectedContext = "system_u:object_r:etc_t:s0"
+	s.check.Run()
+
+	output := s.check.Output()
+	s.True(output.Skipped)
+	s.False(output.Passed)
+	s.NoError(s.check.Error())
+
+	s.SetupTest()
+	s.check.getContext = func(path string) (string, error) {
+		return "", errors.Wrap(errSELinuxUnsupported, path)
+	}
+	s.check.Path = "/etc/httpd/conf/httpd.conf"
+	s.check.ExpectedContext = "system_u:object_r:etc_t:s0"
+	s.check.Run()
+	s.True(s.check.Output().Skipped)
+}
+
+func (s *FileSELinuxContextSuite) TestReadingContextOfMissingFileFails() {
+	s.check.getContext = getSELinuxContext
+	s.check.Path = filepath.Join(s.tmpDir, "DOES-NOT-EXIST")
+	s.check.ExpectedContext = "system_u:object_r:etc_t:s0"
+	s.check.Run()
+
+	s.False(s.check.Output().Passed)
+	s.False(s.check.Output().Skipped)
+	s.Error(s.check.Error())
+}
@@ -0,0 +1,196 @@
+package check
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/registry"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	name := "fstab-entry"
+	registry.AddJobType(name, func() amboy.Job {
+		return &fstabEntry{
+			Base:      NewBase(name, 0),
+			fstabFile: "/etc/fstab",
+		}
+	})
+}
+
+// fstabEntry checks that /etc/fstab has an entry that matches all of
+// the specified fields, so that a mount will be configured after a
+// reboot. Options are matched if all of the expected options appear
+// in the entry, in any order.
+type fstabEntry struct {
+	Device     string   `bson:"device" json:"device" yaml:"device"`
+	UUID       string   `bson:"uuid" json:"uuid" yaml:"uuid"`
+	MountPoint string   `bson:"mountpoint" json:"mountpoint" yaml:"mountpoint"`
+	FSType     string   `bson:"fstype" json:"fstype" yaml:"fstype"`
+	Options    []string `bson:"options" json:"options" yaml:"options"`
+	*Base      `bson:"metadata" json:"metadata" yaml:"metadata"`
+	fileReadLimit
+
+	fstabFile string
+}
+
+// fstabLine is a single entry from the fstab file, with the line
+// number that it appears on.
+type fstabLine struct {
+	spec       string
+	mountPoint string
+	fsType     string
+	options    []string
+	line       int
+	text       string
+}
+
+func (c *fstabEntry) validate() error {
+	if c.Device == "" && c.UUID == "" && c.MountPoint == "" {
+		return errors.Errorf("no device, uuid, or mountpoint specified for '%s' (%s) check",
+			c.ID(), c.Name())
+	}
+
+	if c.Device != "" && c.UUID != "" {
+		return errors.Errorf("cannot specify both a device and a uuid for '%s' check", c.ID())
+	}
+
+	return nil
+}
+
+func (c *fstabEntry) Run() {
+	c.startTask()
+	defer c.MarkComplete()
+
+	if err := c.validate(); err != nil {
+		c.setState(false)
+		c.setReason(ReasonInvalidConfig)
+		c.AddError(err)
+		return
+	}
+
+	entries, err := c.readEntries()
+	if err != nil {
+		c.setState(false)
+		c.AddError(err)
+		return
+	}
+
+	var closest *fstabLine
+	var closestMismatches []string
+	for idx := range entries {
+		mismatches := c.compare(entries[idx])
+		if len(mismatches) == 0 {
+			c.setState(true)
+			c.setMessage(fmt.Sprintf("matched line %d of %s: '%s'",
+				entries[idx].line, c.fstabFile, entries[idx].text))
+			return
+		}
+
+		if closest == nil || len(mismatches) < len(closestMismatches) {
+			closest = &entries[idx]
+			closestMismatches = mismatches
+		}
+	}
+
+	c.setState(false)
+
+	if closest == nil {
+		c.AddError(errors.Errorf("%s has no entries", c.fstabFile))
+		return
+	}
+
+	c.setMessage(fmt.Sprintf("closest match is line %d of %s: '%s'",
+		closest.line, c.fstabFile, closest.text))
+	c.AddError(errors.Errorf("no matching entry in %s, closest entry has different [%s]",
+		c.fstabFile, strings.Join(closestMismatches, ", ")))
+}
+
+// compare returns a list of the fields of the entry that do not match
+// the check's expectations.
+func (c *fstabEntry) compare(e fstabLine) []string {
+	var mismatches []string
+
+	if c.Device != "" && e.spec != c.Device {
+		mismatches = append(mismatches, "device")
+	}
+
+	if c.UUID != "" && !strings.EqualFold(e.spec, "UUID="+c.UUID) &&
+		e.spec != "/dev/disk/by-uuid/"+c.UUID {
+		mismatches = append(mismatches, "uuid")
+	}
+
+	if c.MountPoint != "" && e.mountPoint != c.MountPoint {
+		mismatches = append(mismatches, "mountpoint")
+	}
+
+	if c.FSType != "" && e.fsType != c.FSType {
+		mismatches = append(mismatches, "fstype")
+	}
+
+	for _, opt := range c.Options {
+		if !stringSliceContains(e.options, opt) {
+			mismatches = append(mismatches, fmt.Sprintf("options (%s)", opt))
+		}
+	}
+
+	return mismatches
+}
+
+func (c *fstabEntry) readEntries() ([]fstabLine, error) {
+	data, err := c.readFile(c.fstabFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem reading fstab file")
+	}
+
+	var entries []fstabLine
+
+	lineNum := 0
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		lineNum++
+
+		text := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.Fields(text)
+		if len(fields) < 3 {
+			continue
+		}
+
+		e := fstabLine{
+			spec:       fields[0],
+			mountPoint: fields[1],
+			fsType:     fields[2],
+			line:       lineNum,
+			text:       text,
+		}
+
+		if len(fields) > 3 {
+			e.options = strings.Split(fields[3], ",")
+		}
+
+		entries = append(entries, e)
+	}
+
+	if err = scanner.Err(); err != nil {
+		return nil, errors.Wrapf(err, "problem reading fstab file '%s'", c.fstabFile)
+	}
+
+	return entries, nil
+}
+
+func stringSliceContains(slice []string, item string) bool {
+	for _, s := range slice {
+		if s == item {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,112 @@
+package check
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type FstabEntrySuite struct {
+	tmpDir  string
+	check   *fstabEntry
+	require *require.Assertions
+	suite.Suite
+}
+
+func TestFstabEntrySuite(t *testing.T) {
+	suite.Run(t, new(FstabEntrySuite))
+}
+
+func (s *FstabEntrySuite) SetupSuite() {
+	s.require = s.Require()
+
+	dir, err := ioutil.TempDir("", uuid.NewV4().String())
+	s.require.NoError(err)
+	s.tmpDir = dir
+
+	fstab := []byte(`# /etc/fstab: static file system information.
+UUID=0a3407de-014b-458b-b5c1-848e92a327a3  /         ext4  errors=remount-ro  0 1
+/dev/xvdb                                  /data     xfs   noatime,nodev      0 2
+tmpfs                                      /tmp      tmpfs defaults           0 0
+
+/dev/xvdc                                  none      swap  sw                 0 0
+`)
+	s.require.NoError(ioutil.WriteFile(filepath.Join(dir, "fstab"), fstab, 0644))
+}
+
+func (s *FstabEntrySuite) TearDownSuite() {
+	s.require.NoError(os.RemoveAll(s.tmpDir))
+}
+
+func (s *FstabEntrySuite) SetupTest() {
+	s.check = &fstabEntry{
+		Base:      NewBase("fstab-entry", 0),
+		fstabFile: filepath.Join(s.tmpDir, "fstab"),
+	}
+}
+
+func (s *FstabEntrySuite) TestValidation() {
+	s.Error(s.check.validate())
+
+	s.check.MountPoint = "/data"
+	s.NoError(s.check.validate())
+
+	s.check.Device = "/dev/xvdb"
+	s.check.UUID = "0a3407de-014b-458b-b5c1-848e92a327a3"
+	s.Error(s.check.validate())
+}
+
+func (s *FstabEntrySuite) TestMatchingEntryPasses() {
+	s.check.Device = "/dev/xvdb"
+	s.check.MountPoint = "/data"
+	s.check.FSType = "xfs"
+	s.check.Options = []string{"nodev", "noatime"}
+	s.check.Run()
+
+	output := s.check.Output()
+	s.True(output.Passed)
+	s.NoError(s.check.Error())
+	s.Contains(output.Message, "line 3")
+}
+
+func (s *FstabEntrySuite) TestMatchingByUUID() {
+	s.check.UUID = "0A3407DE-014B-458B-B5C1-848E92A327A3"
+	s.check.MountPoint = "/"
+	s.check.Run()
+	s.True(s.check.Output().Passed)
+	s.NoError(s.check.Error())
+}
+
+func (s *FstabEntrySuite) TestMismatchReportsClosestEntry() {
+	s.check.MountPoint = "/data"
+	s.check.FSType = "ext4"
+	s.check.Options = []string{"noatime", "nosuid"}
+	s.check.Run()
+
+	output := s.check.Output()
+	s.False(output.Passed)
+	s.Contains(output.Message, "line 3")
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "fstype")
+	s.Contains(s.check.Error().Error(), "options (nosuid)")
+}
+
+func (s *FstabEntrySuite) TestMissingEntryFails() {
+	s.check.MountPoint = "/srv"
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.Error(s.check.Error())
+}
+
+func (s *FstabEntrySuite) TestMissingFstabFileFails() {
+	s.check.fstabFile = filepath.Join(s.tmpDir, "DOES-NOT-EXIST")
+	s.check.MountPoint = "/data"
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.Error(s.check.Error())
+}
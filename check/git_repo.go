@@ -0,0 +1,156 @@
+package check
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/registry"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	name := "git-repo"
+	registry.AddJobType(name, func() amboy.Job {
+		return &gitRepo{
+			Base:   NewBase(name, 0),
+			Remote: "origin",
+			run: func(command string, args ...string) ([]byte, error) {
+				return exec.Command(command, args...).Output()
+			},
+		}
+	})
+}
+
+// gitRepo checks the state of a git checkout, such as the checkouts
+// of config repositories on GitOps hosts. The check fails if path is
+// not in a git work tree, if expected_branch is set and a different
+// branch is checked out (a detached HEAD is never the expected
+// branch), if expected_remote is set and the URL of the remote
+// (named by remote, "origin" by default) differs, or if clean is set
+// and the work tree has uncommitted changes or untracked files.
+type gitRepo struct {
+	Path           string `bson:"path" json:"path" yaml:"path"`
+	ExpectedBranch string `bson:"expected_branch" json:"expected_branch" yaml:"expected_branch"`
+	ExpectedRemote string `bson:"expected_remote" json:"expected_remote" yaml:"expected_remote"`
+	Remote         string `bson:"remote" json:"remote" yaml:"remote"`
+	Clean          bool   `bson:"clean" json:"clean" yaml:"clean"`
+	*Base          `bson:"metadata" json:"metadata" yaml:"metadata"`
+
+	run func(command string, args ...string) ([]byte, error)
+}
+
+func (c *gitRepo) validate() error {
+	if c.Path == "" {
+		return errors.Errorf("no path specified for '%s' (%s) check", c.ID(), c.Name())
+	}
+
+	if c.ExpectedRemote != "" && c.Remote == "" {
+		return errors.Errorf("no remote name specified for '%s' (%s) check", c.ID(), c.Name())
+	}
+
+	return nil
+}
+
+func (c *gitRepo) Run() {
+	c.startTask()
+	defer c.MarkComplete()
+
+	if err := c.validate(); err != nil {
+		c.setState(false)
+		c.setReason(ReasonInvalidConfig)
+		c.AddError(err)
+		return
+	}
+
+	if _, err := c.git("rev-parse", "--is-inside-work-tree"); err != nil {
+		c.setState(false)
+		c.AddError(errors.Wrapf(err, "'%s' is not a git repository", c.Path))
+		return
+	}
+
+	var messages []string
+	var problems []string
+
+	if c.ExpectedBranch != "" {
+		branch, err := c.git("rev-parse", "--abbrev-ref", "HEAD")
+		if err != nil {
+			c.setState(false)
+			c.AddError(errors.Wrapf(err, "problem finding the branch of '%s'", c.Path))
+			return
+		}
+
+		if branch == "HEAD" {
+			branch = "(detached HEAD)"
+		}
+
+		messages = append(messages, fmt.Sprintf("branch: %s", branch))
+		if branch != c.ExpectedBranch {
+			problems = append(problems, fmt.Sprintf("branch is '%s', not '%s'", branch, c.ExpectedBranch))
+		}
+	}
+
+	if c.ExpectedRemote != "" {
+		// git config exits with an error when the key is not set.
+		url, err := c.git("config", "--get", fmt.Sprintf("remote.%s.url", c.Remote))
+		if err != nil {
+			url = ""
+		}
+
+		messages = append(messages, fmt.Sprintf("remote %s: %s", c.Remote, url))
+		if url == "" {
+			problems = append(problems, fmt.Sprintf("remote '%s' is not configured", c.Remote))
+		} else if url != c.ExpectedRemote {
+			problems = append(problems, fmt.Sprintf("remote '%s' is '%s', not '%s'",
+				c.Remote, url, c.ExpectedRemote))
+		}
+	}
+
+	if c.Clean {
+		status, err := c.git("status", "--porcelain")
+		if err != nil {
+			c.setState(false)
+			c.AddError(errors.Wrapf(err, "problem finding the status of '%s'", c.Path))
+			return
+		}
+
+		var dirty []string
+		for _, line := range strings.Split(status, "\n") {
+			if strings.TrimSpace(line) != "" {
+				dirty = append(dirty, line)
+			}
+		}
+
+		messages = append(messages, fmt.Sprintf("%d dirty file(s)", len(dirty)))
+		messages = append(messages, dirty...)
+		if len(dirty) > 0 {
+			problems = append(problems, fmt.Sprintf("%d file(s) have uncommitted changes", len(dirty)))
+		}
+	}
+
+	if len(messages) == 0 {
+		messages = append(messages, fmt.Sprintf("'%s' is a git repository", c.Path))
+	}
+	c.setMessage(messages)
+
+	if len(problems) > 0 {
+		c.setState(false)
+		c.AddError(errors.Errorf("git repository '%s' is not as expected: %s",
+			c.Path, strings.Join(problems, ", ")))
+		return
+	}
+
+	c.setState(true)
+}
+
+// git runs a git command in the repository, and returns its output
+// without the trailing newline.
+func (c *gitRepo) git(args ...string) (string, error) {
+	out, err := c.run("git", append([]string{"-C", c.Path}, args...)...)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimRight(string(out), "\n"), nil
+}
@@ -0,0 +1,110 @@
+package check
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type GitRepoSuite struct {
+	outputs map[string]string
+	check   *gitRepo
+	require *require.Assertions
+	suite.Suite
+}
+
+func TestGitRepoSuite(t *testing.T) {
+	suite.Run(t, new(GitRepoSuite))
+}
+
+func (s *GitRepoSuite) SetupSuite() {
+	s.require = s.Require()
+}
+
+func (s *GitRepoSuite) SetupTest() {
+	s.outputs = map[string]string{
+		"-C /srv/config rev-parse --is-inside-work-tree": "true\n",
+		"-C /srv/config rev-parse --abbrev-ref HEAD":     "main\n",
+		"-C /srv/config config --get remote.origin.url":  "git@example.com:ops/config.git\n",
+		"-C /srv/config status --porcelain":              "",
+	}
+
+	s.check = &gitRepo{
+		Base:   NewBase("git-repo", 0),
+		Path:   "/srv/config",
+		Remote: "origin",
+		run: func(command string, args ...string) ([]byte, error) {
+			out, ok := s.outputs[strings.Join(args, " ")]
+			if !ok || command != "git" {
+				return nil, errors.New("exit status 128")
+			}
+			return []byte(out), nil
+		},
+	}
+}
+
+func (s *GitRepoSuite) TestValidation() {
+	s.NoError(s.check.validate())
+
+	s.check.ExpectedRemote = "git@example.com:ops/config.git"
+	s.check.Remote = ""
+	s.Error(s.check.validate())
+
+	s.check.Remote = "upstream"
+	s.NoError(s.check.validate())
+
+	s.check.Path = ""
+	s.Error(s.check.validate())
+}
+
+func (s *GitRepoSuite) TestExpectedState() {
+	s.check.ExpectedBranch = "main"
+	s.check.ExpectedRemote = "git@example.com:ops/config.git"
+	s.check.Clean = true
+	s.check.Run()
+
+	s.True(s.check.Output().Passed)
+	s.NoError(s.check.Error())
+	s.Equal("branch: main\nremote origin: git@example.com:ops/config.git\n0 dirty file(s)",
+		s.check.Output().Message)
+}
+
+func (s *GitRepoSuite) TestMismatches() {
+	s.outputs["-C /srv/config rev-parse --abbrev-ref HEAD"] = "HEAD\n"
+	s.outputs["-C /srv/config status --porcelain"] = " M app.yaml\n?? notes.txt\n"
+	s.check.ExpectedBranch = "main"
+	s.check.ExpectedRemote = "git@example.com:ops/other.git"
+	s.check.Clean = true
+	s.check.Run()
+
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	err := s.check.Error().Error()
+	s.Contains(err, "branch is '(detached HEAD)', not 'main'")
+	s.Contains(err, "remote 'origin' is 'git@example.com:ops/config.git', not 'git@example.com:ops/other.git'")
+	s.Contains(err, "2 file(s) have uncommitted changes")
+	s.Contains(s.check.Output().Message, "2 dirty file(s)\n M app.yaml\n?? notes.txt")
+}
+
+func (s *GitRepoSuite) TestMissingRemote() {
+	s.check.ExpectedRemote = "git@example.com:ops/config.git"
+	s.check.Remote = "upstream"
+	s.check.Run()
+
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "remote 'upstream' is not configured")
+}
+
+func (s *GitRepoSuite) TestNotARepository() {
+	s.check.Path = "/srv/other"
+	s.check.Clean = true
+	s.check.Run()
+
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "'/srv/other' is not a git repository")
+}
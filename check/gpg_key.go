@@ -0,0 +1,263 @@
+package check
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/registry"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	name := "gpg-key"
+	registry.AddJobType(name, func() amboy.Job {
+		return &gpgKey{
+			Base: NewBase(name, 0),
+			run: func(args ...string) ([]byte, error) {
+				return exec.Command("gpg", args...).Output()
+			},
+			now: time.Now,
+		}
+	})
+}
+
+// gpgKey checks that a GPG key, identified by its key_id (the long
+// or short key id) or its fingerprint, is present (or, when present
+// is false, absent) in a keyring. The keyring is a key file or
+// keyring file, such as the files in /etc/apt/trusted.gpg.d, or, if
+// unset, the default keyring of the user that runs greenbay. Subkeys
+// match as well as primary keys. When check_expiry is set, a key that
+// is present must also not be expired or revoked.
+type gpgKey struct {
+	KeyID       string `bson:"key_id" json:"key_id" yaml:"key_id"`
+	Fingerprint string `bson:"fingerprint" json:"fingerprint" yaml:"fingerprint"`
+	Keyring     string `bson:"keyring" json:"keyring" yaml:"keyring"`
+	Present     *bool  `bson:"present" json:"present" yaml:"present"`
+	CheckExpiry bool   `bson:"check_expiry" json:"check_expiry" yaml:"check_expiry"`
+	*Base       `bson:"metadata" json:"metadata" yaml:"metadata"`
+
+	run func(args ...string) ([]byte, error)
+	now func() time.Time
+}
+
+// gpgKeyInfo is a primary key or subkey from the colon delimited
+// output of gpg.
+type gpgKeyInfo struct {
+	keyID       string
+	fingerprint string
+	validity    string
+	expires     time.Time
+	uid         string
+}
+
+func (c *gpgKey) validate() error {
+	if c.KeyID == "" && c.Fingerprint == "" {
+		return errors.Errorf("no key id or fingerprint specified for '%s' (%s) check", c.ID(), c.Name())
+	}
+
+	for _, id := range []string{c.KeyID, c.Fingerprint} {
+		if id == "" {
+			continue
+		}
+
+		if n := normalizeGPGKeyID(id); n == "" || strings.Trim(n, "0123456789ABCDEF") != "" {
+			return errors.Errorf("key '%s' for '%s' check is not a hex key id or fingerprint", id, c.ID())
+		}
+	}
+
+	return nil
+}
+
+func (c *gpgKey) Run() {
+	c.startTask()
+	defer c.MarkComplete()
+
+	if err := c.validate(); err != nil {
+		c.setState(false)
+		c.setReason(ReasonInvalidConfig)
+		c.AddError(err)
+		return
+	}
+
+	present := c.Present == nil || *c.Present
+
+	keys, err := c.listKeys()
+	if err != nil {
+		c.setState(false)
+		c.AddError(err)
+		return
+	}
+
+	desc := c.description()
+	key, found := c.findKey(keys)
+
+	if !found {
+		c.setMessage(fmt.Sprintf("%s is not in %s", desc, c.keyringName()))
+		if present {
+			c.setState(false)
+			c.AddError(errors.Errorf("%s is not in %s", desc, c.keyringName()))
+			return
+		}
+
+		c.setState(true)
+		return
+	}
+
+	expiry := "does not expire"
+	if !key.expires.IsZero() {
+		expiry = fmt.Sprintf("expires %s", key.expires.UTC().Format(time.RFC3339))
+	}
+
+	msg := fmt.Sprintf("found key %s (%s) in %s, which %s", key.fingerprint, key.uid, c.keyringName(), expiry)
+	c.setMessage(msg)
+
+	if !present {
+		c.setState(false)
+		c.AddError(errors.Errorf("%s should not be in %s", desc, c.keyringName()))
+		return
+	}
+
+	if c.CheckExpiry {
+		if key.validity == "r" {
+			c.setState(false)
+			c.AddError(errors.Errorf("%s is revoked", desc))
+			return
+		}
+
+		if key.validity == "e" || (!key.expires.IsZero() && !key.expires.After(c.now())) {
+			c.setState(false)
+			c.AddError(errors.Errorf("%s expired at %s", desc, key.expires.UTC().Format(time.RFC3339)))
+			return
+		}
+	}
+
+	c.setState(true)
+}
+
+func (c *gpgKey) description() string {
+	if c.Fingerprint != "" {
+		return fmt.Sprintf("key with fingerprint '%s'", c.Fingerprint)
+	}
+
+	return fmt.Sprintf("key '%s'", c.KeyID)
+}
+
+func (c *gpgKey) keyringName() string {
+	if c.Keyring == "" {
+		return "the default keyring"
+	}
+
+	return fmt.Sprintf("keyring '%s'", c.Keyring)
+}
+
+// listKeys lists the keys in the keyring with gpg. Key files and
+// keyring files use "--show-keys", which does not import the keys.
+func (c *gpgKey) listKeys() ([]gpgKeyInfo, error) {
+	args := []string{"--batch", "--with-colons", "--fixed-list-mode"}
+	if c.Keyring == "" {
+		args = append(args, "--list-keys")
+	} else {
+		args = append(args, "--show-keys", c.Keyring)
+	}
+
+	out, err := c.run(args...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem listing keys in %s", c.keyringName())
+	}
+
+	return parseGPGColons(string(out)), nil
+}
+
+func (c *gpgKey) findKey(keys []gpgKeyInfo) (gpgKeyInfo, bool) {
+	fingerprint := normalizeGPGKeyID(c.Fingerprint)
+	keyID := normalizeGPGKeyID(c.KeyID)
+
+	for _, key := range keys {
+		if fingerprint != "" && key.fingerprint != fingerprint {
+			continue
+		}
+
+		if keyID != "" && !strings.HasSuffix(key.fingerprint, keyID) && !strings.HasSuffix(key.keyID, keyID) {
+			continue
+		}
+
+		return key, true
+	}
+
+	return gpgKeyInfo{}, false
+}
+
+// normalizeGPGKeyID returns a key id or fingerprint in upper case,
+// without spaces or a "0x" prefix.
+func normalizeGPGKeyID(id string) string {
+	id = strings.ToUpper(strings.Replace(id, " ", "", -1))
+	return strings.TrimPrefix(id, "0X")
+}
+
+// parseGPGColons parses the primary keys and subkeys from the
+// "--with-colons" output of gpg. Subkeys have the user id of their
+// primary key.
+func parseGPGColons(out string) []gpgKeyInfo {
+	var keys []gpgKeyInfo
+	var current *gpgKeyInfo
+	uid := ""
+	primary := 0
+
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Split(strings.TrimSpace(line), ":")
+		if len(fields) < 10 {
+			continue
+		}
+
+		switch fields[0] {
+		case "pub", "sub":
+			if fields[0] == "pub" {
+				uid = ""
+				primary = len(keys)
+			}
+
+			keys = append(keys, gpgKeyInfo{
+				keyID:    strings.ToUpper(fields[4]),
+				validity: fields[1],
+				expires:  parseGPGTime(fields[6]),
+				uid:      uid,
+			})
+			current = &keys[len(keys)-1]
+		case "fpr":
+			if current != nil && current.fingerprint == "" {
+				current.fingerprint = strings.ToUpper(fields[9])
+			}
+		case "uid":
+			if uid == "" {
+				uid = fields[9]
+				for i := primary; i < len(keys); i++ {
+					keys[i].uid = uid
+				}
+			}
+		}
+	}
+
+	return keys
+}
+
+// parseGPGTime parses a time from gpg's colon delimited output, which
+// is seconds since the epoch, or an ISO 8601 basic format time.
+func parseGPGTime(value string) time.Time {
+	if value == "" {
+		return time.Time{}
+	}
+
+	if secs, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(secs, 0)
+	}
+
+	if t, err := time.Parse("20060102T150405", value); err == nil {
+		return t
+	}
+
+	return time.Time{}
+}
@@ -0,0 +1,165 @@
+package check
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+// gpgTestKeys is the "--with-colons" output of gpg for a key that
+// expires in 2030 with a subkey, and an expired key.
+const gpgTestKeys = `tru::1:1700000000:0:3:1:5
+pub:-:4096:1:8D81803C0EBFCD88:1487788586:1893456000::-:::scESC::::::23::0:
+fpr:::::::::9DC858229FC7DD38854AE2D88D81803C0EBFCD88:
+uid:-::::1487788586::3123CB1E7A8E1CE2F94A9AC3CBC93DE61A4ED2AC::Docker Release (CE deb) <docker@docker.com>::::::::::0:
+sub:-:4096:1:7EA0A9C3F273FCD8:1487791608:1893456000:::::s::::::23:
+fpr:::::::::D3306A018370199E527AE7317EA0A9C3F273FCD8:
+pub:e:2048:1:1285491434D8786F:1450000000:1600000000::-:::sc::::::23::0:
+fpr:::::::::A15703C6F2B4A0CA7A9EBE081285491434D8786F:
+uid:e::::1450000000::0B6A3A8B4C6E0A5F6E7B8C9D0E1F2A3B4C5D6E7F::Old Repository Key <repo@example.net>::::::::::0:
+`
+
+type GPGKeySuite struct {
+	args    []string
+	check   *gpgKey
+	require *require.Assertions
+	suite.Suite
+}
+
+func TestGPGKeySuite(t *testing.T) {
+	suite.Run(t, new(GPGKeySuite))
+}
+
+func (s *GPGKeySuite) SetupSuite() {
+	s.require = s.Require()
+}
+
+func (s *GPGKeySuite) SetupTest() {
+	s.args = nil
+	s.check = &gpgKey{
+		Base: NewBase("gpg-key", 0),
+		run: func(args ...string) ([]byte, error) {
+			s.args = args
+			return []byte(gpgTestKeys), nil
+		},
+		now: func() time.Time { return time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC) },
+	}
+}
+
+func (s *GPGKeySuite) TestValidation() {
+	s.Error(s.check.validate())
+
+	s.check.KeyID = "0x8D81803C0EBFCD88"
+	s.NoError(s.check.validate())
+
+	s.check.KeyID = "docker"
+	s.Error(s.check.validate())
+
+	s.check.KeyID = ""
+	s.check.Fingerprint = "9DC8 5822 9FC7 DD38 854A  E2D8 8D81 803C 0EBF CD88"
+	s.NoError(s.check.validate())
+}
+
+func (s *GPGKeySuite) TestParseColons() {
+	keys := parseGPGColons(gpgTestKeys)
+	s.require.Len(keys, 3)
+
+	s.Equal("8D81803C0EBFCD88", keys[0].keyID)
+	s.Equal("9DC858229FC7DD38854AE2D88D81803C0EBFCD88", keys[0].fingerprint)
+	s.Equal("Docker Release (CE deb) <docker@docker.com>", keys[0].uid)
+	s.Equal(time.Unix(1893456000, 0), keys[0].expires)
+
+	s.Equal("7EA0A9C3F273FCD8", keys[1].keyID)
+	s.Equal("D3306A018370199E527AE7317EA0A9C3F273FCD8", keys[1].fingerprint)
+	s.Equal("Docker Release (CE deb) <docker@docker.com>", keys[1].uid)
+
+	s.Equal("e", keys[2].validity)
+	s.Equal("Old Repository Key <repo@example.net>", keys[2].uid)
+}
+
+func (s *GPGKeySuite) TestKeyInKeyring() {
+	s.check.Fingerprint = "9DC8 5822 9FC7 DD38 854A  E2D8 8D81 803C 0EBF CD88"
+	s.check.Keyring = "/etc/apt/trusted.gpg.d/docker.gpg"
+	s.check.CheckExpiry = true
+	s.check.Run()
+
+	s.True(s.check.Output().Passed)
+	s.NoError(s.check.Error())
+	s.Equal([]string{"--batch", "--with-colons", "--fixed-list-mode", "--show-keys", "/etc/apt/trusted.gpg.d/docker.gpg"}, s.args)
+	s.Contains(s.check.Output().Message, "expires 2030-01-01T00:00:00Z")
+
+	for _, id := range []string{"0EBFCD88", "0x8d81803c0ebfcd88", "F273FCD8"} {
+		s.SetupTest()
+		s.check.KeyID = id
+		s.check.Run()
+
+		s.True(s.check.Output().Passed, id)
+		s.NoError(s.check.Error(), id)
+		s.Equal([]string{"--batch", "--with-colons", "--fixed-list-mode", "--list-keys"}, s.args)
+	}
+}
+
+func (s *GPGKeySuite) TestMissingKey() {
+	s.check.KeyID = "DEADBEEF"
+	s.check.Run()
+
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "key 'DEADBEEF' is not in the default keyring")
+
+	s.SetupTest()
+	s.check.KeyID = "DEADBEEF"
+	s.check.Present = new(bool)
+	s.check.Run()
+
+	s.True(s.check.Output().Passed)
+	s.NoError(s.check.Error())
+
+	s.SetupTest()
+	s.check.KeyID = "0EBFCD88"
+	s.check.Present = new(bool)
+	s.check.Run()
+
+	s.False(s.check.Output().Passed)
+	s.Error(s.check.Error())
+}
+
+func (s *GPGKeySuite) TestExpiredKey() {
+	s.check.KeyID = "1285491434D8786F"
+	s.check.Run()
+
+	s.True(s.check.Output().Passed)
+	s.NoError(s.check.Error())
+
+	s.check.CheckExpiry = true
+	s.check.Run()
+
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "key '1285491434D8786F' expired at 2020-09-13T12:26:40Z")
+
+	s.SetupTest()
+	s.check.KeyID = "0EBFCD88"
+	s.check.CheckExpiry = true
+	s.check.now = func() time.Time { return time.Date(2031, 1, 1, 0, 0, 0, 0, time.UTC) }
+	s.check.Run()
+
+	s.False(s.check.Output().Passed)
+	s.Error(s.check.Error())
+}
+
+func (s *GPGKeySuite) TestGPGFailure() {
+	s.check.KeyID = "0EBFCD88"
+	s.check.Keyring = "/does/not/exist.gpg"
+	s.check.run = func(args ...string) ([]byte, error) {
+		return nil, errors.New("exit status 2")
+	}
+	s.check.Run()
+
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "problem listing keys in keyring '/does/not/exist.gpg'")
+}
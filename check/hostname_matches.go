@@ -0,0 +1,137 @@
+package check
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/registry"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	name := "hostname-matches"
+	registry.AddJobType(name, func() amboy.Job {
+		return &hostnameMatches{
+			Base:        NewBase(name, 0),
+			Scope:       "short",
+			hostname:    os.Hostname,
+			lookupCNAME: net.LookupCNAME,
+		}
+	})
+}
+
+// hostnameMatches checks that the name of the host follows a naming
+// convention. Specify either expected, which is compared without
+// case or a trailing dot, or pattern, which is a regular expression
+// (use "^" and "$" to match the whole name). The scope is "short"
+// (the default), which compares the hostname up to the first dot,
+// or "fqdn", which compares the fully qualified name, as resolved
+// from the hostname, like "hostname -f".
+type hostnameMatches struct {
+	Expected string `bson:"expected" json:"expected" yaml:"expected"`
+	Pattern  string `bson:"pattern" json:"pattern" yaml:"pattern"`
+	Scope    string `bson:"scope" json:"scope" yaml:"scope"`
+	*Base    `bson:"metadata" json:"metadata" yaml:"metadata"`
+
+	pattern     *regexp.Regexp
+	hostname    func() (string, error)
+	lookupCNAME func(string) (string, error)
+}
+
+func (c *hostnameMatches) validate() error {
+	if (c.Expected == "") == (c.Pattern == "") {
+		return errors.Errorf("must specify exactly one of expected or pattern for '%s' (%s) check",
+			c.ID(), c.Name())
+	}
+
+	if c.Scope != "short" && c.Scope != "fqdn" {
+		return errors.Errorf("scope '%s' for '%s' check must be 'short' or 'fqdn'", c.Scope, c.ID())
+	}
+
+	if c.Pattern != "" {
+		pattern, err := regexp.Compile(c.Pattern)
+		if err != nil {
+			return errors.Wrapf(err, "problem compiling pattern for '%s' check", c.ID())
+		}
+		c.pattern = pattern
+	}
+
+	return nil
+}
+
+func (c *hostnameMatches) Run() {
+	c.startTask()
+	defer c.MarkComplete()
+
+	if err := c.validate(); err != nil {
+		c.setState(false)
+		c.setReason(ReasonInvalidConfig)
+		c.AddError(err)
+		return
+	}
+
+	name, err := c.name()
+	if err != nil {
+		c.setState(false)
+		c.AddError(err)
+		return
+	}
+
+	if c.pattern != nil {
+		c.setMessage(fmt.Sprintf("%s hostname is '%s', expected pattern '%s'", c.Scope, name, c.Pattern))
+		if !c.pattern.MatchString(name) {
+			c.setState(false)
+			c.setReason(ReasonValueMismatch)
+			c.AddError(errors.Errorf("%s hostname '%s' does not match pattern '%s'", c.Scope, name, c.Pattern))
+			return
+		}
+
+		c.setState(true)
+		return
+	}
+
+	expected := normalizeHostname(c.Expected)
+	c.setMessage(fmt.Sprintf("%s hostname is '%s', expected '%s'", c.Scope, name, expected))
+	if name != expected {
+		c.setState(false)
+		c.setReason(ReasonValueMismatch)
+		c.AddError(errors.Errorf("%s hostname '%s' is not '%s'", c.Scope, name, expected))
+		return
+	}
+
+	c.setState(true)
+}
+
+// name returns the hostname in the check's scope. The fully
+// qualified name is the canonical name of the hostname in DNS, or the
+// hostname itself, if it has a domain and does not resolve.
+func (c *hostnameMatches) name() (string, error) {
+	hostname, err := c.hostname()
+	if err != nil {
+		return "", errors.Wrap(err, "problem getting the hostname")
+	}
+	hostname = normalizeHostname(hostname)
+
+	if c.Scope == "short" {
+		return strings.SplitN(hostname, ".", 2)[0], nil
+	}
+
+	cname, err := c.lookupCNAME(hostname)
+	if err == nil && normalizeHostname(cname) != "" {
+		return normalizeHostname(cname), nil
+	}
+
+	if strings.Contains(hostname, ".") {
+		return hostname, nil
+	}
+
+	if err == nil {
+		err = errors.New("no canonical name")
+	}
+
+	return "", errors.Wrapf(err, "problem resolving the fully qualified name of '%s'", hostname)
+}
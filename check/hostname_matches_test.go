@@ -0,0 +1,111 @@
+package check
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type HostnameMatchesSuite struct {
+	hostname string
+	cnames   map[string]string
+	check    *hostnameMatches
+	require  *require.Assertions
+	suite.Suite
+}
+
+func TestHostnameMatchesSuite(t *testing.T) {
+	suite.Run(t, new(HostnameMatchesSuite))
+}
+
+func (s *HostnameMatchesSuite) SetupSuite() {
+	s.require = s.Require()
+}
+
+func (s *HostnameMatchesSuite) SetupTest() {
+	s.hostname = "Web-042"
+	s.cnames = map[string]string{"web-042": "web-042.prod.example.net."}
+	s.check = &hostnameMatches{
+		Base:  NewBase("hostname-matches", 0),
+		Scope: "short",
+		hostname: func() (string, error) {
+			return s.hostname, nil
+		},
+		lookupCNAME: func(name string) (string, error) {
+			if cname, ok := s.cnames[name]; ok {
+				return cname, nil
+			}
+			return "", &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+		},
+	}
+}
+
+func (s *HostnameMatchesSuite) TestValidation() {
+	s.Error(s.check.validate())
+
+	s.check.Expected = "web-042"
+	s.NoError(s.check.validate())
+
+	s.check.Pattern = "^web-"
+	s.Error(s.check.validate())
+
+	s.check.Expected = ""
+	s.NoError(s.check.validate())
+
+	s.check.Pattern = "(["
+	s.Error(s.check.validate())
+
+	s.check.Pattern = "^web-"
+	s.check.Scope = "domain"
+	s.Error(s.check.validate())
+}
+
+func (s *HostnameMatchesSuite) TestShortName() {
+	s.check.Expected = "WEB-042"
+	s.check.Run()
+	s.True(s.check.Output().Passed)
+	s.NoError(s.check.Error())
+
+	s.SetupTest()
+	s.hostname = "web-042.prod.example.net"
+	s.check.Pattern = `^web-\d{3}$`
+	s.check.Run()
+	s.True(s.check.Output().Passed)
+	s.Equal(`short hostname is 'web-042', expected pattern '^web-\d{3}$'`, s.check.Output().Message)
+
+	s.SetupTest()
+	s.hostname = "localhost"
+	s.check.Pattern = `^web-\d{3}$`
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "short hostname 'localhost' does not match pattern")
+}
+
+func (s *HostnameMatchesSuite) TestFullyQualifiedName() {
+	s.check.Scope = "fqdn"
+	s.check.Expected = "web-042.prod.example.net."
+	s.check.Run()
+	s.True(s.check.Output().Passed)
+	s.NoError(s.check.Error())
+
+	s.SetupTest()
+	s.hostname = "db-001.stage.example.net"
+	s.check.Scope = "fqdn"
+	s.check.Expected = "db-001.prod.example.net"
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "fqdn hostname 'db-001.stage.example.net' is not 'db-001.prod.example.net'")
+
+	s.SetupTest()
+	s.hostname = "orphan"
+	s.check.Scope = "fqdn"
+	s.check.Pattern = "example"
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "problem resolving the fully qualified name of 'orphan'")
+}
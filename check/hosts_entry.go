@@ -0,0 +1,187 @@
+package check
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/registry"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	name := "hosts-entry"
+	registry.AddJobType(name, func() amboy.Job {
+		return &hostsEntry{
+			Present:   true,
+			Base:      NewBase(name, 0),
+			hostsFile: "/etc/hosts",
+		}
+	})
+}
+
+type hostsEntry struct {
+	Hostname   string `bson:"hostname" json:"hostname" yaml:"hostname"`
+	ExpectedIP string `bson:"expected_ip" json:"expected_ip" yaml:"expected_ip"`
+	Present    bool   `bson:"present" json:"present" yaml:"present"`
+	*Base      `bson:"metadata" json:"metadata" yaml:"metadata"`
+	fileReadLimit
+
+	hostsFile string
+}
+
+// hostsFileLine is a single address from the hosts file, with the
+// line number that it appears on.
+type hostsFileLine struct {
+	ip   net.IP
+	line int
+}
+
+func (c *hostsEntry) validate() error {
+	if c.Hostname == "" {
+		return errors.Errorf("no hostname specified for '%s' (%s) check", c.ID(), c.Name())
+	}
+
+	if c.ExpectedIP != "" && net.ParseIP(c.ExpectedIP) == nil {
+		return errors.Errorf("expected ip '%s' for '%s' check is not valid",
+			c.ExpectedIP, c.ID())
+	}
+
+	return nil
+}
+
+func (c *hostsEntry) Run() {
+	c.startTask()
+	defer c.MarkComplete()
+
+	if err := c.validate(); err != nil {
+		c.setState(false)
+		c.setReason(ReasonInvalidConfig)
+		c.AddError(err)
+		return
+	}
+
+	entries, err := c.findEntries()
+	if err != nil {
+		c.setState(false)
+		c.AddError(err)
+		return
+	}
+
+	var expected net.IP
+	if c.ExpectedIP != "" {
+		expected = net.ParseIP(c.ExpectedIP)
+	}
+
+	var matching []hostsFileLine
+	var conflicting []hostsFileLine
+	for _, e := range entries {
+		if expected == nil || e.ip.Equal(expected) {
+			matching = append(matching, e)
+		} else {
+			conflicting = append(conflicting, e)
+		}
+	}
+
+	msg := fmt.Sprintf("'%s' has %d entries in %s: [%s]", c.Hostname, len(entries),
+		c.hostsFile, formatHostsLines(entries))
+
+	if !c.Present {
+		c.setState(len(matching) == 0)
+		if len(matching) > 0 {
+			c.setMessage(msg)
+			c.AddError(errors.Errorf("'%s' has an entry in %s and should not",
+				c.Hostname, c.hostsFile))
+		}
+		return
+	}
+
+	if len(matching) == 0 {
+		c.setState(false)
+		c.setMessage(msg)
+		c.AddError(errors.Errorf("'%s' does not map to the expected address in %s",
+			c.Hostname, c.hostsFile))
+		return
+	}
+
+	// the resolver uses the first entry for an address family, so
+	// conflicting entries before the expected entry shadow it.
+	var shadowing []hostsFileLine
+	for _, e := range conflicting {
+		if e.line < matching[0].line && isIPv4(e.ip) == isIPv4(matching[0].ip) {
+			shadowing = append(shadowing, e)
+		}
+	}
+
+	if len(shadowing) > 0 {
+		c.setState(false)
+		c.setMessage(msg)
+		c.AddError(errors.Errorf("'%s' has conflicting entries in %s that precede "+
+			"the expected entry: [%s]", c.Hostname, c.hostsFile, formatHostsLines(shadowing)))
+		return
+	}
+
+	c.setState(true)
+	if len(conflicting) > 0 {
+		c.setMessage(msg)
+	}
+}
+
+func (c *hostsEntry) findEntries() ([]hostsFileLine, error) {
+	data, err := c.readFile(c.hostsFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem reading hosts file")
+	}
+
+	var entries []hostsFileLine
+
+	lineNum := 0
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		lineNum++
+
+		line := scanner.Text()
+		if idx := strings.Index(line, "#"); idx >= 0 {
+			line = line[:idx]
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+
+		ip := net.ParseIP(fields[0])
+		if ip == nil {
+			continue
+		}
+
+		for _, host := range fields[1:] {
+			if strings.EqualFold(host, c.Hostname) {
+				entries = append(entries, hostsFileLine{ip: ip, line: lineNum})
+				break
+			}
+		}
+	}
+
+	if err = scanner.Err(); err != nil {
+		return nil, errors.Wrapf(err, "problem reading hosts file '%s'", c.hostsFile)
+	}
+
+	return entries, nil
+}
+
+func isIPv4(ip net.IP) bool {
+	return ip.To4() != nil
+}
+
+func formatHostsLines(entries []hostsFileLine) string {
+	out := make([]string, 0, len(entries))
+	for _, e := range entries {
+		out = append(out, fmt.Sprintf("%s (line %d)", e.ip, e.line))
+	}
+
+	return strings.Join(out, ", ")
+}
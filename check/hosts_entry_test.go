@@ -0,0 +1,158 @@
+package check
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type HostsEntrySuite struct {
+	tmpDir  string
+	check   *hostsEntry
+	require *require.Assertions
+	suite.Suite
+}
+
+func TestHostsEntrySuite(t *testing.T) {
+	suite.Run(t, new(HostsEntrySuite))
+}
+
+func (s *HostsEntrySuite) SetupSuite() {
+	s.require = s.Require()
+
+	dir, err := ioutil.TempDir("", uuid.NewV4().String())
+	s.require.NoError(err)
+	s.tmpDir = dir
+
+	hosts := []byte(`# a comment line
+127.0.0.1   localhost localhost.localdomain
+::1         localhost
+10.0.0.1    db.example.net db   # trailing comment
+10.0.0.2    cache.example.net
+10.0.0.9    cache.example.net
+not-an-ip   broken.example.net
+`)
+	s.require.NoError(ioutil.WriteFile(filepath.Join(dir, "hosts"), hosts, 0644))
+}
+
+func (s *HostsEntrySuite) TearDownSuite() {
+	s.require.NoError(os.RemoveAll(s.tmpDir))
+}
+
+func (s *HostsEntrySuite) SetupTest() {
+	s.check = &hostsEntry{
+		Present:   true,
+		Base:      NewBase("hosts-entry", 0),
+		hostsFile: filepath.Join(s.tmpDir, "hosts"),
+	}
+}
+
+func (s *HostsEntrySuite) TestValidation() {
+	s.Error(s.check.validate())
+
+	s.check.Hostname = "db"
+	s.NoError(s.check.validate())
+
+	s.check.ExpectedIP = "10.0.0"
+	s.Error(s.check.validate())
+}
+
+func (s *HostsEntrySuite) TestEntryWithExpectedAddressPasses() {
+	s.check.Hostname = "DB"
+	s.check.ExpectedIP = "10.0.0.1"
+	s.check.Run()
+
+	s.NoError(s.check.Error())
+	s.True(s.check.Output().Passed)
+	s.True(s.check.Output().Completed)
+}
+
+func (s *HostsEntrySuite) TestEntryWithoutExpectedAddressPasses() {
+	s.check.Hostname = "localhost"
+	s.check.Run()
+
+	s.NoError(s.check.Error())
+	s.True(s.check.Output().Passed)
+}
+
+func (s *HostsEntrySuite) TestEntryWithDifferentAddressFails() {
+	s.check.Hostname = "db.example.net"
+	s.check.ExpectedIP = "10.0.0.5"
+	s.check.Run()
+
+	s.Error(s.check.Error())
+	s.False(s.check.Output().Passed)
+	s.Contains(s.check.Output().Message, "10.0.0.1")
+}
+
+func (s *HostsEntrySuite) TestShadowedEntryFails() {
+	s.check.Hostname = "cache.example.net"
+	s.check.ExpectedIP = "10.0.0.9"
+	s.check.Run()
+
+	s.Error(s.check.Error())
+	s.False(s.check.Output().Passed)
+}
+
+func (s *HostsEntrySuite) TestLaterConflictingEntryIsReported() {
+	s.check.Hostname = "cache.example.net"
+	s.check.ExpectedIP = "10.0.0.2"
+	s.check.Run()
+
+	s.NoError(s.check.Error())
+	s.True(s.check.Output().Passed)
+	s.Contains(s.check.Output().Message, "10.0.0.9")
+}
+
+func (s *HostsEntrySuite) TestMissingEntry() {
+	s.check.Hostname = "broken.example.net"
+	s.check.Run()
+	s.Error(s.check.Error())
+	s.False(s.check.Output().Passed)
+
+	s.SetupTest()
+	s.check.Hostname = "broken.example.net"
+	s.check.Present = false
+	s.check.Run()
+	s.NoError(s.check.Error())
+	s.True(s.check.Output().Passed)
+}
+
+func (s *HostsEntrySuite) TestAbsentEntryWithAddress() {
+	s.check.Hostname = "db"
+	s.check.ExpectedIP = "10.0.0.2"
+	s.check.Present = false
+	s.check.Run()
+	s.NoError(s.check.Error())
+	s.True(s.check.Output().Passed)
+
+	s.SetupTest()
+	s.check.Hostname = "db"
+	s.check.ExpectedIP = "10.0.0.1"
+	s.check.Present = false
+	s.check.Run()
+	s.Error(s.check.Error())
+	s.False(s.check.Output().Passed)
+}
+
+func (s *HostsEntrySuite) TestMissingHostsFile() {
+	s.check.Hostname = "db"
+	s.check.hostsFile = filepath.Join(s.tmpDir, "DOES-NOT-EXIST")
+	s.check.Run()
+	s.Error(s.check.Error())
+	s.False(s.check.Output().Passed)
+}
+
+func (s *HostsEntrySuite) TestHostsFileLargerThanLimitFails() {
+	s.check.Hostname = "db"
+	s.check.MaxReadBytes = 16
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "too large")
+}
@@ -0,0 +1,114 @@
+package check
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/registry"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	name := "http-clock-skew"
+	registry.AddJobType(name, func() amboy.Job {
+		return &httpClockSkew{
+			Base:   NewBase(name, 0),
+			client: &http.Client{Timeout: time.Minute},
+		}
+	})
+}
+
+// httpClockSkew checks that the local clock is within max_skew
+// (e.g. "5s") of the time reported in the Date header of an HTTP
+// response. Because the Date header has a resolution of one second,
+// skew of less than a second is not detectable.
+type httpClockSkew struct {
+	URL     string `bson:"url" json:"url" yaml:"url"`
+	MaxSkew string `bson:"max_skew" json:"max_skew" yaml:"max_skew"`
+	*Base   `bson:"metadata" json:"metadata" yaml:"metadata"`
+
+	maxSkew time.Duration
+	client  *http.Client
+}
+
+func (c *httpClockSkew) validate() error {
+	if c.URL == "" {
+		return errors.Errorf("no url specified for '%s' (%s) check", c.ID(), c.Name())
+	}
+
+	if c.MaxSkew == "" {
+		return errors.Errorf("no max skew specified for '%s' check", c.ID())
+	}
+
+	skew, err := time.ParseDuration(c.MaxSkew)
+	if err != nil {
+		return errors.Wrapf(err, "problem parsing max skew for '%s' check", c.ID())
+	}
+	c.maxSkew = skew
+
+	return nil
+}
+
+func (c *httpClockSkew) Run() {
+	c.startTask()
+	defer c.MarkComplete()
+
+	if err := c.validate(); err != nil {
+		c.setState(false)
+		c.setReason(ReasonInvalidConfig)
+		c.AddError(err)
+		return
+	}
+
+	req, err := http.NewRequest("HEAD", c.URL, nil)
+	if err != nil {
+		c.setState(false)
+		c.AddError(errors.Wrapf(err, "problem building request for %s", c.URL))
+		return
+	}
+
+	start := time.Now()
+	resp, err := c.client.Do(req)
+	if err != nil {
+		c.setState(false)
+		c.AddError(errors.Wrapf(err, "problem requesting %s", c.URL))
+		return
+	}
+	defer resp.Body.Close()
+
+	// use the midpoint of the request to account for latency.
+	local := start.Add(time.Since(start) / 2)
+
+	header := resp.Header.Get("Date")
+	if header == "" {
+		c.setState(false)
+		c.AddError(errors.Errorf("response from %s has no Date header", c.URL))
+		return
+	}
+
+	remote, err := http.ParseTime(header)
+	if err != nil {
+		c.setState(false)
+		c.setMessage(fmt.Sprintf("Date header: '%s'", header))
+		c.AddError(errors.Wrapf(err, "response from %s has an invalid Date header", c.URL))
+		return
+	}
+
+	skew := local.Sub(remote)
+	if skew < 0 {
+		skew = -skew
+	}
+
+	c.setMessage(fmt.Sprintf("local clock differs from %s by %s (local=%s, remote=%s)",
+		c.URL, skew, local.UTC().Format(time.RFC3339), remote.UTC().Format(time.RFC3339)))
+
+	if skew > c.maxSkew {
+		c.setState(false)
+		c.AddError(errors.Errorf("clock skew of %s is greater than %s", skew, c.maxSkew))
+		return
+	}
+
+	c.setState(true)
+}
@@ -0,0 +1,98 @@
+package check
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type HTTPClockSkewSuite struct {
+	date    string
+	server  *httptest.Server
+	check   *httpClockSkew
+	require *require.Assertions
+	suite.Suite
+}
+
+func TestHTTPClockSkewSuite(t *testing.T) {
+	suite.Run(t, new(HTTPClockSkewSuite))
+}
+
+func (s *HTTPClockSkewSuite) SetupSuite() {
+	s.require = s.Require()
+
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// setting the header to nil prevents the server
+		// from adding a Date header.
+		if s.date == "" {
+			w.Header()["Date"] = nil
+		} else {
+			w.Header().Set("Date", s.date)
+		}
+	}))
+}
+
+func (s *HTTPClockSkewSuite) TearDownSuite() {
+	s.server.Close()
+}
+
+func (s *HTTPClockSkewSuite) SetupTest() {
+	s.date = time.Now().UTC().Format(http.TimeFormat)
+	s.check = &httpClockSkew{
+		URL:     s.server.URL,
+		MaxSkew: "5s",
+		Base:    NewBase("http-clock-skew", 0),
+		client:  &http.Client{},
+	}
+}
+
+func (s *HTTPClockSkewSuite) TestValidation() {
+	s.NoError(s.check.validate())
+	s.Equal(5*time.Second, s.check.maxSkew)
+
+	s.check.MaxSkew = "five seconds"
+	s.Error(s.check.validate())
+
+	s.check.MaxSkew = ""
+	s.Error(s.check.validate())
+
+	s.check.MaxSkew = "5s"
+	s.check.URL = ""
+	s.Error(s.check.validate())
+}
+
+func (s *HTTPClockSkewSuite) TestSynchronizedClockPasses() {
+	s.check.Run()
+	output := s.check.Output()
+	s.True(output.Passed)
+	s.NoError(s.check.Error())
+	s.Contains(output.Message, "local clock differs")
+}
+
+func (s *HTTPClockSkewSuite) TestSkewedClockFails() {
+	s.date = time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat)
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "greater than 5s")
+}
+
+func (s *HTTPClockSkewSuite) TestMissingDateHeaderFails() {
+	s.date = ""
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "no Date header")
+}
+
+func (s *HTTPClockSkewSuite) TestInvalidDateHeaderFails() {
+	s.date = "yesterday"
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "invalid Date header")
+}
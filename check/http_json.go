@@ -0,0 +1,196 @@
+package check
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/registry"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	name := "http-json"
+	registry.AddJobType(name, func() amboy.Job {
+		return &httpJSON{
+			Method: "GET",
+			Base:   NewBase(name, 0),
+			client: &http.Client{Timeout: time.Minute},
+		}
+	})
+}
+
+// httpJSON checks that an HTTP endpoint returns a JSON document with
+// the expected values. Keys in the expected_json map are dot
+// separated paths into the document (e.g. "status.ready" or
+// "members.0.state"), or RFC 6901 JSON Pointers, which start with "/"
+// (e.g. "/members/0/state"), for keys that contain dots. Values are
+// compared to the value in the document at that path.
+type httpJSON struct {
+	URL          string                 `bson:"url" json:"url" yaml:"url"`
+	Method       string                 `bson:"method" json:"method" yaml:"method"`
+	Headers      map[string]string      `bson:"headers" json:"headers" yaml:"headers"`
+	ExpectedJSON map[string]interface{} `bson:"expected_json" json:"expected_json" yaml:"expected_json"`
+	*Base        `bson:"metadata" json:"metadata" yaml:"metadata"`
+
+	client *http.Client
+}
+
+func (c *httpJSON) validate() error {
+	if c.URL == "" {
+		return errors.Errorf("no url specified for '%s' (%s) check", c.ID(), c.Name())
+	}
+
+	if len(c.ExpectedJSON) == 0 {
+		return errors.Errorf("no expected values specified for '%s' check", c.ID())
+	}
+
+	return nil
+}
+
+func (c *httpJSON) Run() {
+	c.startTask()
+	defer c.MarkComplete()
+
+	if err := c.validate(); err != nil {
+		c.setState(false)
+		c.setReason(ReasonInvalidConfig)
+		c.AddError(err)
+		return
+	}
+
+	doc, err := c.fetch()
+	if err != nil {
+		c.setState(false)
+		c.AddError(err)
+		return
+	}
+
+	paths := make([]string, 0, len(c.ExpectedJSON))
+	for path := range c.ExpectedJSON {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var mismatches []string
+	for _, path := range paths {
+		expected := c.ExpectedJSON[path]
+
+		value, ok := lookupJSONPath(doc, path)
+		if !ok {
+			mismatches = append(mismatches, fmt.Sprintf("path '%s' not found", path))
+			continue
+		}
+
+		if !reflect.DeepEqual(value, expected) {
+			mismatches = append(mismatches, fmt.Sprintf("'%s' is %v, expected %v",
+				path, value, expected))
+		}
+	}
+
+	if len(mismatches) > 0 {
+		c.setState(false)
+		c.setMessage(mismatches)
+		c.AddError(errors.Errorf("%d of %d values from %s did not match",
+			len(mismatches), len(paths), c.URL))
+		return
+	}
+
+	c.setState(true)
+	c.setMessage(fmt.Sprintf("%d values from %s matched", len(paths), c.URL))
+}
+
+func (c *httpJSON) fetch() (interface{}, error) {
+	req, err := http.NewRequest(strings.ToUpper(c.Method), c.URL, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem building request for %s", c.URL)
+	}
+
+	for k, v := range c.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem requesting %s", c.URL)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, errors.Errorf("request to %s returned status %s", c.URL, resp.Status)
+	}
+
+	var doc interface{}
+	if err = json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return nil, errors.Wrapf(err, "problem parsing json response from %s", c.URL)
+	}
+
+	return doc, nil
+}
+
+// lookupJSONPath returns the value at the path in a decoded JSON
+// document. Paths that start with "/" are JSON Pointers, and other
+// paths are dot separated. Numeric path elements index into arrays.
+func lookupJSONPath(doc interface{}, path string) (interface{}, bool) {
+	if strings.HasPrefix(path, "/") {
+		return lookupJSONPointer(doc, path)
+	}
+
+	return lookupJSONKeys(doc, strings.Split(path, "."))
+}
+
+// lookupJSONPointer resolves an RFC 6901 JSON Pointer, where "~1"
+// escapes "/" and "~0" escapes "~" in keys. The empty pointer is the
+// whole document.
+func lookupJSONPointer(doc interface{}, pointer string) (interface{}, bool) {
+	if pointer == "" {
+		return doc, true
+	}
+
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, false
+	}
+
+	keys := strings.Split(pointer[1:], "/")
+	for i := range keys {
+		keys[i] = strings.Replace(strings.Replace(keys[i], "~1", "/", -1), "~0", "~", -1)
+	}
+
+	return lookupJSONKeys(doc, keys)
+}
+
+func lookupJSONKeys(doc interface{}, keys []string) (interface{}, bool) {
+	current := doc
+
+	for _, key := range keys {
+		switch v := current.(type) {
+		case map[string]interface{}:
+			next, ok := v[key]
+			if !ok {
+				return nil, false
+			}
+			current = next
+		case []interface{}:
+			// array indexes do not have signs or leading zeros.
+			if key == "" || (len(key) > 1 && key[0] == '0') || strings.Trim(key, "0123456789") != "" {
+				return nil, false
+			}
+
+			idx, err := strconv.Atoi(key)
+			if err != nil || idx >= len(v) {
+				return nil, false
+			}
+			current = v[idx]
+		default:
+			return nil, false
+		}
+	}
+
+	return current, true
+}
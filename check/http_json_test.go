@@ -0,0 +1,202 @@
+package check
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mongodb/greenbay"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/net/context"
+)
+
+type HTTPJSONSuite struct {
+	server  *httptest.Server
+	check   *httpJSON
+	require *require.Assertions
+	suite.Suite
+}
+
+func TestHTTPJSONSuite(t *testing.T) {
+	suite.Run(t, new(HTTPJSONSuite))
+}
+
+func (s *HTTPJSONSuite) SetupSuite() {
+	s.require = s.Require()
+
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+
+		if r.URL.Path == "/slow" {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(time.Minute):
+			}
+			return
+		}
+
+		if r.URL.Path == "/text" {
+			fmt.Fprintln(w, "not json")
+			return
+		}
+
+		fmt.Fprintf(w, `{"status": {"ready": true, "version": "3.4.0"},
+"members": [{"state": 1}, {"state": 2}], "method": %q, "token": %q}`,
+			r.Method, r.Header.Get("X-Token"))
+	}))
+}
+
+func (s *HTTPJSONSuite) TearDownSuite() {
+	s.server.Close()
+}
+
+func (s *HTTPJSONSuite) SetupTest() {
+	s.check = &httpJSON{
+		Method: "GET",
+		Base:   NewBase("http-json", 0),
+		client: &http.Client{},
+	}
+}
+
+// setExpected sets expectations via the JSON parser, as the config
+// parser would.
+func (s *HTTPJSONSuite) setExpected(doc string) {
+	s.require.NoError(json.Unmarshal([]byte(doc), &s.check.ExpectedJSON))
+}
+
+func (s *HTTPJSONSuite) TestValidation() {
+	s.Error(s.check.validate())
+
+	s.check.URL = s.server.URL
+	s.Error(s.check.validate())
+
+	s.setExpected(`{"status.ready": true}`)
+	s.NoError(s.check.validate())
+}
+
+func (s *HTTPJSONSuite) TestMatchingValuesPass() {
+	s.check.URL = s.server.URL
+	s.check.Method = "post"
+	s.check.Headers = map[string]string{"X-Token": "secret"}
+	s.setExpected(`{"status.ready": true, "status.version": "3.4.0",
+"members.1.state": 2, "method": "POST", "token": "secret"}`)
+	s.check.Run()
+
+	output := s.check.Output()
+	s.True(output.Passed)
+	s.NoError(s.check.Error())
+	s.Contains(output.Message, "5 values")
+}
+
+func (s *HTTPJSONSuite) TestMismatchesAreReportedPerPath() {
+	s.check.URL = s.server.URL
+	s.setExpected(`{"status.ready": false, "members.5.state": 1, "status.version": "3.4.0"}`)
+	s.check.Run()
+
+	output := s.check.Output()
+	s.False(output.Passed)
+	s.Error(s.check.Error())
+	s.Contains(output.Message, "path 'members.5.state' not found")
+	s.Contains(output.Message, "'status.ready' is true, expected false")
+	s.NotContains(output.Message, "status.version")
+}
+
+func (s *HTTPJSONSuite) TestJSONPointers() {
+	s.check.URL = s.server.URL
+	s.setExpected(`{"/status/ready": true, "/members/1/state": 2, "members.0.state": 1}`)
+	s.check.Run()
+	s.True(s.check.Output().Passed)
+	s.NoError(s.check.Error())
+
+	s.SetupTest()
+	s.check.URL = s.server.URL
+	s.setExpected(`{"/members/2/state": 3}`)
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.Contains(s.check.Output().Message, "path '/members/2/state' not found")
+}
+
+func (s *HTTPJSONSuite) TestErrorResponsesFail() {
+	for _, path := range []string{"/missing", "/text"} {
+		s.SetupTest()
+		s.check.URL = s.server.URL + path
+		s.setExpected(`{"status.ready": true}`)
+		s.check.Run()
+		s.False(s.check.Output().Passed)
+		s.Error(s.check.Error())
+	}
+}
+
+func TestLookupJSONPath(t *testing.T) {
+	assert := assert.New(t)
+
+	var doc interface{}
+	assert.NoError(json.Unmarshal([]byte(`{"a": {"b": [1, {"c": "d"}]}}`), &doc))
+
+	value, ok := lookupJSONPath(doc, "a.b.1.c")
+	assert.True(ok)
+	assert.Equal("d", value)
+
+	value, ok = lookupJSONPath(doc, "a.b.0")
+	assert.True(ok)
+	assert.Equal(float64(1), value)
+
+	for _, path := range []string{"a.x", "a.b.2", "a.b.-1", "a.b.c", "a.b.0.c"} {
+		_, ok = lookupJSONPath(doc, path)
+		assert.False(ok, path)
+	}
+}
+
+func TestLookupJSONPointer(t *testing.T) {
+	assert := assert.New(t)
+
+	var doc interface{}
+	assert.NoError(json.Unmarshal([]byte(`{
+		"a": {"b": [1, {"c": "d"}]},
+		"net.core.somaxconn": 4096,
+		"paths": {"/var/log": "logs", "~home": "home"},
+		"": "empty"
+	}`), &doc))
+
+	for pointer, expected := range map[string]interface{}{
+		"/a/b/1/c":            "d",
+		"/a/b/0":              float64(1),
+		"/net.core.somaxconn": float64(4096),
+		"/paths/~1var~1log":   "logs",
+		"/paths/~0home":       "home",
+		"/":                   "empty",
+	} {
+		value, ok := lookupJSONPath(doc, pointer)
+		assert.True(ok, pointer)
+		assert.Equal(expected, value, pointer)
+	}
+
+	value, ok := lookupJSONPointer(doc, "")
+	assert.True(ok)
+	assert.Equal(doc, value)
+
+	for _, pointer := range []string{"/a/x", "/a/b/2", "/a/b/-", "/a/b/01", "/a/b/+1", "/a/b/0/c", "/paths/~1var/log"} {
+		_, ok = lookupJSONPath(doc, pointer)
+		assert.False(ok, pointer)
+	}
+}
+
+func (s *HTTPJSONSuite) TestSlowServerTimesOut() {
+	output := assertCheckTimesOut(s.T(), func(ctx context.Context) greenbay.Checker {
+		deadline, _ := ctx.Deadline()
+		s.check.client = &http.Client{Timeout: deadline.Sub(time.Now())}
+		s.check.URL = s.server.URL + "/slow"
+		s.setExpected(`{"status.ready": true}`)
+		return s.check
+	}, 100*time.Millisecond)
+
+	s.Contains(output.Error, "Timeout")
+}
@@ -0,0 +1,178 @@
+package check
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/registry"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	name := "http-mtls"
+	registry.AddJobType(name, func() amboy.Job {
+		return &httpMutualTLS{
+			Base:           NewBase(name, 0),
+			ExpectedStatus: http.StatusOK,
+			timeout:        time.Minute,
+		}
+	})
+}
+
+// httpMutualTLSBodyLimit is the maximum number of bytes of a response
+// body that the http-mtls check reads.
+const httpMutualTLSBodyLimit = 1024 * 1024
+
+// httpMutualTLS checks that an HTTPS endpoint that requires a client
+// certificate returns the expected status. The check presents the
+// certificate and key in client_cert and client_key, and verifies
+// the server against the certificates in ca_bundle, or against the
+// system roots if ca_bundle is not set. Optionally, specify either
+// expected_body, which must match the response body exactly, after
+// trimming whitespace, or body_pattern, which is a regular
+// expression that must match the body.
+type httpMutualTLS struct {
+	URL            string `bson:"url" json:"url" yaml:"url"`
+	ClientCert     string `bson:"client_cert" json:"client_cert" yaml:"client_cert"`
+	ClientKey      string `bson:"client_key" json:"client_key" yaml:"client_key"`
+	CABundle       string `bson:"ca_bundle" json:"ca_bundle" yaml:"ca_bundle"`
+	ExpectedStatus int    `bson:"expected_status" json:"expected_status" yaml:"expected_status"`
+	ExpectedBody   string `bson:"expected_body" json:"expected_body" yaml:"expected_body"`
+	BodyPattern    string `bson:"body_pattern" json:"body_pattern" yaml:"body_pattern"`
+	*Base          `bson:"metadata" json:"metadata" yaml:"metadata"`
+	fileReadLimit
+
+	pattern   *regexp.Regexp
+	tlsConfig *tls.Config
+	timeout   time.Duration
+}
+
+// validate checks the definition and loads the certificates, so that
+// problems with the certificate files fail the check before it makes
+// a request.
+func (c *httpMutualTLS) validate() error {
+	if c.URL == "" {
+		return errors.Errorf("no url specified for '%s' (%s) check", c.ID(), c.Name())
+	}
+
+	if !strings.HasPrefix(c.URL, "https://") {
+		return errors.Errorf("url '%s' for '%s' check must use https", c.URL, c.ID())
+	}
+
+	if c.ClientCert == "" || c.ClientKey == "" {
+		return errors.Errorf("must specify a client cert and key for '%s' check", c.ID())
+	}
+
+	if c.ExpectedBody != "" && c.BodyPattern != "" {
+		return errors.Errorf("cannot specify both expected body and body pattern for '%s' check", c.ID())
+	}
+
+	if c.BodyPattern != "" {
+		pattern, err := regexp.Compile(c.BodyPattern)
+		if err != nil {
+			return errors.Wrapf(err, "problem compiling body pattern for '%s' check", c.ID())
+		}
+		c.pattern = pattern
+	}
+
+	cert, err := tls.LoadX509KeyPair(c.ClientCert, c.ClientKey)
+	if err != nil {
+		return errors.Wrapf(err, "problem loading client certificate '%s' and key '%s' for '%s' check",
+			c.ClientCert, c.ClientKey, c.ID())
+	}
+
+	conf := &tls.Config{Certificates: []tls.Certificate{cert}}
+
+	if c.CABundle != "" {
+		data, err := c.readFile(c.CABundle)
+		if err != nil {
+			return errors.Wrapf(err, "problem reading ca bundle '%s'", c.CABundle)
+		}
+
+		conf.RootCAs = x509.NewCertPool()
+		if !conf.RootCAs.AppendCertsFromPEM(data) {
+			return errors.Errorf("ca bundle '%s' does not contain any certificates", c.CABundle)
+		}
+	}
+
+	c.tlsConfig = conf
+
+	return nil
+}
+
+// isTLSError reports whether an error from a request happened during
+// the TLS handshake, including when the server rejects the client
+// certificate.
+func isTLSError(err error) bool {
+	msg := err.Error()
+	return strings.Contains(msg, "tls:") || strings.Contains(msg, "x509:")
+}
+
+func (c *httpMutualTLS) Run() {
+	c.startTask()
+	defer c.MarkComplete()
+
+	if err := c.validate(); err != nil {
+		c.setState(false)
+		c.setReason(ReasonInvalidConfig)
+		c.AddError(err)
+		return
+	}
+
+	client := &http.Client{
+		Timeout:   c.timeout,
+		Transport: &http.Transport{TLSClientConfig: c.tlsConfig},
+	}
+
+	resp, err := client.Get(c.URL)
+	if err != nil {
+		c.setState(false)
+		if isTLSError(err) {
+			c.AddError(errors.Wrapf(err, "TLS handshake with %s failed, "+
+				"the server may have rejected the client certificate", c.URL))
+		} else {
+			c.AddError(errors.Wrapf(err, "problem requesting %s", c.URL))
+		}
+		return
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, httpMutualTLSBodyLimit))
+	if err != nil {
+		c.setState(false)
+		c.AddError(errors.Wrapf(err, "problem reading response from %s", c.URL))
+		return
+	}
+
+	if resp.StatusCode != c.ExpectedStatus {
+		c.setState(false)
+		c.AddError(errors.Errorf("request to %s returned status %s, expected %d",
+			c.URL, resp.Status, c.ExpectedStatus))
+		return
+	}
+
+	if c.ExpectedBody != "" && strings.TrimSpace(string(body)) != strings.TrimSpace(c.ExpectedBody) {
+		c.setState(false)
+		c.setMessage(fmt.Sprintf("body: '%s'", body))
+		c.AddError(errors.Errorf("response from %s does not match the expected body", c.URL))
+		return
+	}
+
+	if c.pattern != nil && !c.pattern.Match(body) {
+		c.setState(false)
+		c.setMessage(fmt.Sprintf("body: '%s'", body))
+		c.AddError(errors.Errorf("response from %s does not match pattern '%s'", c.URL, c.BodyPattern))
+		return
+	}
+
+	c.setState(true)
+	c.setMessage(fmt.Sprintf("%s returned status %s", c.URL, resp.Status))
+}
@@ -0,0 +1,229 @@
+package check
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mongodb/amboy"
+	"github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type HTTPMutualTLSSuite struct {
+	tempDir   string
+	bundle    string
+	cert      string
+	key       string
+	otherCert string
+	otherKey  string
+	server    *httptest.Server
+	check     *httpMutualTLS
+	require   *require.Assertions
+	suite.Suite
+}
+
+func TestHTTPMutualTLSSuite(t *testing.T) {
+	suite.Run(t, new(HTTPMutualTLSSuite))
+}
+
+// writeClientCert writes a certificate and key, for a client
+// certificate signed by a new CA, to dir, and returns the paths
+// along with the CA certificate.
+func writeClientCert(dir, name string) (string, string, *x509.Certificate, error) {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name + "-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		return "", "", nil, err
+	}
+	ca, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	certFn := filepath.Join(dir, name+".pem")
+	keyFn := filepath.Join(dir, name+".key")
+
+	if err = ioutil.WriteFile(certFn, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0644); err != nil {
+		return "", "", nil, err
+	}
+	if err = ioutil.WriteFile(keyFn, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600); err != nil {
+		return "", "", nil, err
+	}
+
+	return certFn, keyFn, ca, nil
+}
+
+func (s *HTTPMutualTLSSuite) SetupSuite() {
+	s.require = s.Require()
+
+	tempDir, err := ioutil.TempDir("", uuid.NewV4().String())
+	s.require.NoError(err)
+	s.tempDir = tempDir
+
+	var ca *x509.Certificate
+	s.cert, s.key, ca, err = writeClientCert(s.tempDir, "client")
+	s.require.NoError(err)
+	s.otherCert, s.otherKey, _, err = writeClientCert(s.tempDir, "other")
+	s.require.NoError(err)
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca)
+
+	s.server = httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "hello %s\n", r.TLS.PeerCertificates[0].Subject.CommonName)
+	}))
+	s.server.TLS = &tls.Config{
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  clientCAs,
+	}
+	s.server.StartTLS()
+
+	s.bundle = filepath.Join(s.tempDir, "ca.pem")
+	data := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: s.server.Certificate().Raw})
+	s.require.NoError(ioutil.WriteFile(s.bundle, data, 0644))
+}
+
+func (s *HTTPMutualTLSSuite) TearDownSuite() {
+	s.server.Close()
+	s.NoError(os.RemoveAll(s.tempDir))
+}
+
+func (s *HTTPMutualTLSSuite) SetupTest() {
+	factory, err := GetChecker("http-mtls")
+	s.require.NoError(err)
+	s.check = factory.(amboy.Job).(*httpMutualTLS)
+	s.check.URL = s.server.URL
+	s.check.ClientCert = s.cert
+	s.check.ClientKey = s.key
+	s.check.CABundle = s.bundle
+}
+
+func (s *HTTPMutualTLSSuite) TestDefaults() {
+	s.Equal(http.StatusOK, s.check.ExpectedStatus)
+}
+
+func (s *HTTPMutualTLSSuite) TestValidation() {
+	s.NoError(s.check.validate())
+	s.NotNil(s.check.tlsConfig)
+
+	s.check.URL = "http://localhost"
+	s.Error(s.check.validate())
+
+	s.check.URL = s.server.URL
+	s.check.ClientKey = ""
+	s.Error(s.check.validate())
+
+	s.check.ClientKey = s.otherKey
+	err := s.check.validate()
+	s.require.Error(err)
+	s.Contains(err.Error(), "problem loading client certificate")
+
+	s.check.ClientKey = s.key
+	s.check.CABundle = s.key
+	s.Error(s.check.validate())
+
+	s.check.CABundle = s.bundle
+	s.check.ExpectedBody = "hello"
+	s.check.BodyPattern = "hello"
+	s.Error(s.check.validate())
+
+	s.check.ExpectedBody = ""
+	s.check.BodyPattern = "(["
+	s.Error(s.check.validate())
+}
+
+func (s *HTTPMutualTLSSuite) TestTrustedClientPasses() {
+	s.check.ExpectedBody = "hello client"
+	s.check.Run()
+	s.True(s.check.Output().Passed)
+	s.NoError(s.check.Error())
+
+	s.SetupTest()
+	s.check.BodyPattern = "^hello"
+	s.check.Run()
+	s.True(s.check.Output().Passed)
+	s.NoError(s.check.Error())
+}
+
+func (s *HTTPMutualTLSSuite) TestUnexpectedResponseFails() {
+	s.check.ExpectedStatus = http.StatusNoContent
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "returned status")
+
+	s.SetupTest()
+	s.check.BodyPattern = "goodbye"
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "does not match pattern")
+}
+
+func (s *HTTPMutualTLSSuite) TestRejectedClientCertificateFails() {
+	s.check.ClientCert = s.otherCert
+	s.check.ClientKey = s.otherKey
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "TLS handshake")
+}
+
+func (s *HTTPMutualTLSSuite) TestUntrustedServerFails() {
+	s.check.CABundle = ""
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "TLS handshake")
+}
@@ -0,0 +1,133 @@
+package check
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/registry"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	name := "http-redirect"
+	registry.AddJobType(name, func() amboy.Job {
+		return &httpRedirect{
+			Base: NewBase(name, 0),
+			client: &http.Client{
+				Timeout: time.Minute,
+				CheckRedirect: func(*http.Request, []*http.Request) error {
+					return http.ErrUseLastResponse
+				},
+			},
+		}
+	})
+}
+
+// httpRedirect checks that a request to a URL returns a redirect to
+// the expected location. Specify either expected_location, which
+// must match the Location header exactly, or location_pattern, which
+// is a regular expression that must match the Location header. If
+// expected_status is not set, any redirect status passes.
+type httpRedirect struct {
+	URL              string `bson:"url" json:"url" yaml:"url"`
+	ExpectedLocation string `bson:"expected_location" json:"expected_location" yaml:"expected_location"`
+	LocationPattern  string `bson:"location_pattern" json:"location_pattern" yaml:"location_pattern"`
+	ExpectedStatus   int    `bson:"expected_status" json:"expected_status" yaml:"expected_status"`
+	*Base            `bson:"metadata" json:"metadata" yaml:"metadata"`
+
+	pattern *regexp.Regexp
+	client  *http.Client
+}
+
+func isRedirectStatus(status int) bool {
+	switch status {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther,
+		http.StatusTemporaryRedirect, 308:
+		return true
+	default:
+		return false
+	}
+}
+
+func (c *httpRedirect) validate() error {
+	if c.URL == "" {
+		return errors.Errorf("no url specified for '%s' (%s) check", c.ID(), c.Name())
+	}
+
+	if (c.ExpectedLocation == "") == (c.LocationPattern == "") {
+		return errors.Errorf("must specify exactly one of expected location or "+
+			"location pattern for '%s' check", c.ID())
+	}
+
+	if c.ExpectedStatus != 0 && !isRedirectStatus(c.ExpectedStatus) {
+		return errors.Errorf("expected status %d for '%s' check is not a redirect",
+			c.ExpectedStatus, c.ID())
+	}
+
+	if c.LocationPattern != "" {
+		pattern, err := regexp.Compile(c.LocationPattern)
+		if err != nil {
+			return errors.Wrapf(err, "problem compiling location pattern for '%s' check", c.ID())
+		}
+		c.pattern = pattern
+	}
+
+	return nil
+}
+
+func (c *httpRedirect) Run() {
+	c.startTask()
+	defer c.MarkComplete()
+
+	if err := c.validate(); err != nil {
+		c.setState(false)
+		c.setReason(ReasonInvalidConfig)
+		c.AddError(err)
+		return
+	}
+
+	resp, err := c.client.Get(c.URL)
+	if err != nil {
+		c.setState(false)
+		c.AddError(errors.Wrapf(err, "problem requesting %s", c.URL))
+		return
+	}
+	defer resp.Body.Close()
+
+	location := resp.Header.Get("Location")
+	c.setMessage(fmt.Sprintf("%s returned status %d with location '%s'",
+		c.URL, resp.StatusCode, location))
+
+	if c.ExpectedStatus != 0 && resp.StatusCode != c.ExpectedStatus {
+		c.setState(false)
+		c.AddError(errors.Errorf("%s returned status %d, expected %d",
+			c.URL, resp.StatusCode, c.ExpectedStatus))
+		return
+	}
+
+	if !isRedirectStatus(resp.StatusCode) {
+		c.setState(false)
+		c.AddError(errors.Errorf("%s returned status %d, which is not a redirect",
+			c.URL, resp.StatusCode))
+		return
+	}
+
+	if c.pattern != nil && !c.pattern.MatchString(location) {
+		c.setState(false)
+		c.AddError(errors.Errorf("location '%s' does not match pattern '%s'",
+			location, c.LocationPattern))
+		return
+	}
+
+	if c.ExpectedLocation != "" && location != c.ExpectedLocation {
+		c.setState(false)
+		c.AddError(errors.Errorf("location '%s' does not match expected location '%s'",
+			location, c.ExpectedLocation))
+		return
+	}
+
+	c.setState(true)
+}
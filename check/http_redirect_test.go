@@ -0,0 +1,119 @@
+package check
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mongodb/amboy"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type HTTPRedirectSuite struct {
+	server  *httptest.Server
+	check   *httpRedirect
+	require *require.Assertions
+	suite.Suite
+}
+
+func TestHTTPRedirectSuite(t *testing.T) {
+	suite.Run(t, new(HTTPRedirectSuite))
+}
+
+func (s *HTTPRedirectSuite) SetupSuite() {
+	s.require = s.Require()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/permanent", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "https://example.net/permanent", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/temporary", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "https://example.net/temporary", http.StatusFound)
+	})
+	mux.HandleFunc("/ok", func(w http.ResponseWriter, r *http.Request) {})
+	s.server = httptest.NewServer(mux)
+}
+
+func (s *HTTPRedirectSuite) TearDownSuite() {
+	s.server.Close()
+}
+
+func (s *HTTPRedirectSuite) SetupTest() {
+	factory, err := GetChecker("http-redirect")
+	s.require.NoError(err)
+	s.check = factory.(amboy.Job).(*httpRedirect)
+}
+
+func (s *HTTPRedirectSuite) TestValidation() {
+	s.Error(s.check.validate())
+
+	s.check.URL = s.server.URL
+	s.Error(s.check.validate())
+
+	s.check.ExpectedLocation = "https://example.net/"
+	s.NoError(s.check.validate())
+
+	s.check.LocationPattern = "^https://"
+	s.Error(s.check.validate())
+
+	s.check.ExpectedLocation = ""
+	s.NoError(s.check.validate())
+
+	s.check.LocationPattern = "(["
+	s.Error(s.check.validate())
+
+	s.check.LocationPattern = "^https://"
+	s.check.ExpectedStatus = 200
+	s.Error(s.check.validate())
+
+	s.check.ExpectedStatus = 308
+	s.NoError(s.check.validate())
+}
+
+func (s *HTTPRedirectSuite) TestMatchingRedirectPasses() {
+	s.check.URL = s.server.URL + "/permanent"
+	s.check.ExpectedLocation = "https://example.net/permanent"
+	s.check.ExpectedStatus = 301
+	s.check.Run()
+	s.True(s.check.Output().Passed)
+	s.NoError(s.check.Error())
+
+	s.SetupTest()
+	s.check.URL = s.server.URL + "/temporary"
+	s.check.LocationPattern = "^https://example.net/"
+	s.check.Run()
+	s.True(s.check.Output().Passed)
+	s.NoError(s.check.Error())
+}
+
+func (s *HTTPRedirectSuite) TestMismatchedStatusFails() {
+	s.check.URL = s.server.URL + "/temporary"
+	s.check.ExpectedLocation = "https://example.net/temporary"
+	s.check.ExpectedStatus = 301
+	s.check.Run()
+
+	output := s.check.Output()
+	s.False(output.Passed)
+	s.Error(s.check.Error())
+	s.Contains(output.Message, "status 302")
+}
+
+func (s *HTTPRedirectSuite) TestMismatchedLocationFails() {
+	s.check.URL = s.server.URL + "/permanent"
+	s.check.ExpectedLocation = "https://example.net/other"
+	s.check.Run()
+
+	output := s.check.Output()
+	s.False(output.Passed)
+	s.Error(s.check.Error())
+	s.Contains(output.Message, "https://example.net/permanent")
+}
+
+func (s *HTTPRedirectSuite) TestNonRedirectFails() {
+	s.check.URL = s.server.URL + "/ok"
+	s.check.LocationPattern = ".*"
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.Error(s.check.Error())
+}
@@ -0,0 +1,156 @@
+package check
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/registry"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	name := "image-digest"
+	registry.AddJobType(name, func() amboy.Job {
+		return &imageDigest{
+			Base: NewBase(name, 0),
+			run: func(command string, args ...string) ([]byte, error) {
+				return exec.Command(command, args...).Output()
+			},
+		}
+	})
+}
+
+var sha256DigestPattern = regexp.MustCompile("^sha256:[0-9a-f]{64}$")
+
+// imageDigest checks that a running docker container runs the image
+// with the expected sha256 digest, which catches containers that
+// still run a stale image after a failed pull. The expected digest,
+// with or without the "sha256:" prefix, may be either a repository
+// digest of the image (as in "image@sha256:...") or the image ID.
+type imageDigest struct {
+	Container      string `bson:"container" json:"container" yaml:"container"`
+	ExpectedDigest string `bson:"expected_digest" json:"expected_digest" yaml:"expected_digest"`
+	*Base          `bson:"metadata" json:"metadata" yaml:"metadata"`
+
+	run func(command string, args ...string) ([]byte, error)
+}
+
+// containerInspect is the subset of the output of "docker inspect"
+// that the check uses.
+type containerInspect struct {
+	Image string `json:"Image"`
+	State struct {
+		Running bool `json:"Running"`
+	} `json:"State"`
+}
+
+type imageInspect struct {
+	ID          string   `json:"Id"`
+	RepoDigests []string `json:"RepoDigests"`
+}
+
+func normalizeDigest(digest string) string {
+	digest = strings.ToLower(strings.TrimSpace(digest))
+	if !strings.HasPrefix(digest, "sha256:") {
+		digest = "sha256:" + digest
+	}
+
+	return digest
+}
+
+func (c *imageDigest) validate() error {
+	if c.Container == "" {
+		return errors.Errorf("no container specified for '%s' (%s) check", c.ID(), c.Name())
+	}
+
+	if c.ExpectedDigest == "" {
+		return errors.Errorf("no expected digest specified for '%s' (%s) check", c.ID(), c.Name())
+	}
+
+	if !sha256DigestPattern.MatchString(normalizeDigest(c.ExpectedDigest)) {
+		return errors.Errorf("expected digest '%s' for '%s' check is not a sha256 digest",
+			c.ExpectedDigest, c.ID())
+	}
+
+	return nil
+}
+
+func (c *imageDigest) Run() {
+	c.startTask()
+	defer c.MarkComplete()
+
+	if err := c.validate(); err != nil {
+		c.setState(false)
+		c.setReason(ReasonInvalidConfig)
+		c.AddError(err)
+		return
+	}
+
+	container := &containerInspect{}
+	if err := c.inspect(container, "inspect", "--type", "container", c.Container); err != nil {
+		c.setState(false)
+		c.AddError(err)
+		return
+	}
+
+	if !container.State.Running {
+		c.setState(false)
+		c.AddError(errors.Errorf("container '%s' is not running", c.Container))
+		return
+	}
+
+	image := &imageInspect{}
+	if err := c.inspect(image, "inspect", "--type", "image", container.Image); err != nil {
+		c.setState(false)
+		c.AddError(err)
+		return
+	}
+
+	expected := normalizeDigest(c.ExpectedDigest)
+	digests := []string{image.ID}
+	for _, ref := range image.RepoDigests {
+		if idx := strings.LastIndex(ref, "@"); idx >= 0 {
+			digests = append(digests, ref[idx+1:])
+		}
+	}
+
+	c.setMessage(fmt.Sprintf("container '%s' runs image %s (%s)", c.Container, image.ID,
+		strings.Join(image.RepoDigests, ", ")))
+
+	for _, digest := range digests {
+		if digest == expected {
+			c.setState(true)
+			return
+		}
+	}
+
+	c.setState(false)
+	c.setReason(ReasonValueMismatch)
+	c.AddError(errors.Errorf("container '%s' does not run the image with digest %s",
+		c.Container, expected))
+}
+
+// inspect runs "docker inspect", which prints a list of objects, and
+// decodes the first object into out.
+func (c *imageDigest) inspect(out interface{}, args ...string) error {
+	data, err := c.run("docker", args...)
+	if err != nil {
+		return errors.Wrapf(err, "problem running docker %s", strings.Join(args, " "))
+	}
+
+	var objects []json.RawMessage
+	if err = json.Unmarshal(data, &objects); err != nil {
+		return errors.Wrapf(err, "problem parsing output of docker %s", strings.Join(args, " "))
+	}
+
+	if len(objects) == 0 {
+		return errors.Errorf("docker %s returned no objects", strings.Join(args, " "))
+	}
+
+	return errors.Wrapf(json.Unmarshal(objects[0], out),
+		"problem parsing output of docker %s", strings.Join(args, " "))
+}
@@ -0,0 +1,111 @@
+package check
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	testImageID     = "sha256:1111111111111111111111111111111111111111111111111111111111111111"
+	testImageDigest = "sha256:2222222222222222222222222222222222222222222222222222222222222222"
+)
+
+type ImageDigestSuite struct {
+	outputs map[string]string
+	check   *imageDigest
+	require *require.Assertions
+	suite.Suite
+}
+
+func TestImageDigestSuite(t *testing.T) {
+	suite.Run(t, new(ImageDigestSuite))
+}
+
+func (s *ImageDigestSuite) SetupSuite() {
+	s.require = s.Require()
+}
+
+func (s *ImageDigestSuite) SetupTest() {
+	s.outputs = map[string]string{
+		"inspect --type container web": `[{"Id": "abc", "Image": "` + testImageID + `", "State": {"Running": true}}]`,
+		"inspect --type container old": `[{"Id": "def", "Image": "` + testImageID + `", "State": {"Running": false}}]`,
+		"inspect --type image " + testImageID: `[{"Id": "` + testImageID + `",
+			"RepoDigests": ["registry.example.com/web@` + testImageDigest + `"]}]`,
+	}
+
+	s.check = &imageDigest{
+		Base:      NewBase("image-digest", 0),
+		Container: "web",
+		run: func(command string, args ...string) ([]byte, error) {
+			out, ok := s.outputs[strings.Join(args, " ")]
+			if !ok || command != "docker" {
+				return nil, errors.New("exit status 1")
+			}
+			return []byte(out), nil
+		},
+	}
+}
+
+func (s *ImageDigestSuite) TestValidation() {
+	s.Error(s.check.validate())
+
+	s.check.ExpectedDigest = "sha256:1234"
+	s.Error(s.check.validate())
+
+	s.check.ExpectedDigest = strings.TrimPrefix(testImageDigest, "sha256:")
+	s.NoError(s.check.validate())
+
+	s.check.Container = ""
+	s.Error(s.check.validate())
+}
+
+func (s *ImageDigestSuite) TestMatchingRepoDigestPasses() {
+	s.check.ExpectedDigest = testImageDigest
+	s.check.Run()
+
+	s.True(s.check.Output().Passed)
+	s.NoError(s.check.Error())
+	s.Equal("container 'web' runs image "+testImageID+" (registry.example.com/web@"+testImageDigest+")",
+		s.check.Output().Message)
+}
+
+func (s *ImageDigestSuite) TestMatchingImageIDPasses() {
+	s.check.ExpectedDigest = strings.ToUpper(strings.TrimPrefix(testImageID, "sha256:"))
+	s.check.Run()
+
+	s.True(s.check.Output().Passed)
+	s.NoError(s.check.Error())
+}
+
+func (s *ImageDigestSuite) TestStaleImageFails() {
+	s.check.ExpectedDigest = "sha256:3333333333333333333333333333333333333333333333333333333333333333"
+	s.check.Run()
+
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "does not run the image with digest sha256:3333")
+	s.Contains(s.check.Output().Message, testImageDigest)
+}
+
+func (s *ImageDigestSuite) TestStoppedOrMissingContainerFails() {
+	s.check.ExpectedDigest = testImageDigest
+	s.check.Container = "old"
+	s.check.Run()
+
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "container 'old' is not running")
+
+	s.SetupTest()
+	s.check.ExpectedDigest = testImageDigest
+	s.check.Container = "missing"
+	s.check.Run()
+
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "problem running docker inspect --type container missing")
+}
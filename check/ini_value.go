@@ -0,0 +1,167 @@
+package check
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/registry"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	name := "ini-value"
+	registry.AddJobType(name, func() amboy.Job {
+		return &iniValue{
+			Base: NewBase(name, 0),
+		}
+	})
+}
+
+// iniValue checks the value of a key in an INI file. Specify either
+// expected, which must match the value exactly, or pattern, which is
+// a regular expression that must match the value. Keys before the
+// first section header are in the "" section. As in Windows, section
+// and key names are not case sensitive, and if a key appears more
+// than once in a section, the first value is used.
+type iniValue struct {
+	Path     string `bson:"path" json:"path" yaml:"path"`
+	Section  string `bson:"section" json:"section" yaml:"section"`
+	Key      string `bson:"key" json:"key" yaml:"key"`
+	Expected string `bson:"expected" json:"expected" yaml:"expected"`
+	Pattern  string `bson:"pattern" json:"pattern" yaml:"pattern"`
+	*Base    `bson:"metadata" json:"metadata" yaml:"metadata"`
+	fileReadLimit
+
+	pattern *regexp.Regexp
+}
+
+func (c *iniValue) validate() error {
+	if c.Path == "" {
+		return errors.Errorf("no path specified for '%s' (%s) check", c.ID(), c.Name())
+	}
+
+	if c.Key == "" {
+		return errors.Errorf("no key specified for '%s' check", c.ID())
+	}
+
+	if (c.Expected == "") == (c.Pattern == "") {
+		return errors.Errorf("must specify exactly one of expected or pattern for '%s' check", c.ID())
+	}
+
+	if c.Pattern != "" {
+		pattern, err := regexp.Compile(c.Pattern)
+		if err != nil {
+			return errors.Wrapf(err, "problem compiling pattern for '%s' check", c.ID())
+		}
+		c.pattern = pattern
+	}
+
+	return nil
+}
+
+func (c *iniValue) Run() {
+	c.startTask()
+	defer c.MarkComplete()
+
+	if err := c.validate(); err != nil {
+		c.setState(false)
+		c.setReason(ReasonInvalidConfig)
+		c.AddError(err)
+		return
+	}
+
+	value, foundSection, foundKey, err := c.lookup()
+	if err != nil {
+		c.setState(false)
+		c.AddError(err)
+		return
+	}
+
+	if !foundSection {
+		c.setState(false)
+		c.AddError(errors.Errorf("section '[%s]' does not exist in '%s'", c.Section, c.Path))
+		return
+	}
+
+	if !foundKey {
+		c.setState(false)
+		c.AddError(errors.Errorf("key '%s' does not exist in section '[%s]' of '%s'",
+			c.Key, c.Section, c.Path))
+		return
+	}
+
+	c.setMessage(fmt.Sprintf("'[%s] %s' is '%s'", c.Section, c.Key, value))
+
+	if c.pattern != nil && !c.pattern.MatchString(value) {
+		c.setState(false)
+		c.AddError(errors.Errorf("value '%s' of '[%s] %s' does not match pattern '%s'",
+			value, c.Section, c.Key, c.Pattern))
+		return
+	}
+
+	if c.Expected != "" && value != c.Expected {
+		c.setState(false)
+		c.AddError(errors.Errorf("value '%s' of '[%s] %s' is not '%s'",
+			value, c.Section, c.Key, c.Expected))
+		return
+	}
+
+	c.setState(true)
+}
+
+// lookup returns the value of the key, and reports whether the
+// section and the key exist.
+func (c *iniValue) lookup() (string, bool, bool, error) {
+	data, err := c.readFile(c.Path)
+	if err != nil {
+		return "", false, false, errors.Wrap(err, "problem reading ini file")
+	}
+
+	section := ""
+	foundSection := c.Section == ""
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, ";") || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			if strings.EqualFold(section, c.Section) {
+				foundSection = true
+			}
+			continue
+		}
+
+		if !strings.EqualFold(section, c.Section) {
+			continue
+		}
+
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 || !strings.EqualFold(strings.TrimSpace(parts[0]), c.Key) {
+			continue
+		}
+
+		return unquoteINIValue(strings.TrimSpace(parts[1])), true, true, nil
+	}
+
+	if err = scanner.Err(); err != nil {
+		return "", false, false, errors.Wrapf(err, "problem reading ini file '%s'", c.Path)
+	}
+
+	return "", foundSection, false, nil
+}
+
+func unquoteINIValue(value string) string {
+	if len(value) >= 2 && value[0] == value[len(value)-1] && (value[0] == '"' || value[0] == '\'') {
+		return value[1 : len(value)-1]
+	}
+
+	return value
+}
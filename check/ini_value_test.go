@@ -0,0 +1,142 @@
+package check
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type INIValueSuite struct {
+	tmpDir  string
+	fn      string
+	check   *iniValue
+	require *require.Assertions
+	suite.Suite
+}
+
+func TestINIValueSuite(t *testing.T) {
+	suite.Run(t, new(INIValueSuite))
+}
+
+func (s *INIValueSuite) SetupSuite() {
+	s.require = s.Require()
+
+	dir, err := ioutil.TempDir("", uuid.NewV4().String())
+	s.require.NoError(err)
+	s.tmpDir = dir
+
+	s.fn = filepath.Join(dir, "service.ini")
+	s.require.NoError(ioutil.WriteFile(s.fn, []byte(`
+; global settings
+LogLevel = info
+
+[Database]
+Host = db.example.net
+Port=5432
+# later values are ignored
+port = 6543
+Name = "inventory"
+
+[Empty]
+`), 0644))
+}
+
+func (s *INIValueSuite) TearDownSuite() {
+	s.require.NoError(os.RemoveAll(s.tmpDir))
+}
+
+func (s *INIValueSuite) SetupTest() {
+	s.check = &iniValue{
+		Base: NewBase("ini-value", 0),
+		Path: s.fn,
+	}
+}
+
+func (s *INIValueSuite) TestValidation() {
+	s.check.Path = ""
+	s.Error(s.check.validate())
+
+	s.check.Path = s.fn
+	s.Error(s.check.validate())
+
+	s.check.Key = "Host"
+	s.Error(s.check.validate())
+
+	s.check.Expected = "db.example.net"
+	s.NoError(s.check.validate())
+
+	s.check.Pattern = "example"
+	s.Error(s.check.validate())
+
+	s.check.Expected = ""
+	s.NoError(s.check.validate())
+
+	s.check.Pattern = "(["
+	s.Error(s.check.validate())
+}
+
+func (s *INIValueSuite) TestMatchingValuesPass() {
+	for _, c := range []struct{ section, key, expected, pattern string }{
+		{"", "loglevel", "info", ""},
+		{"Database", "Host", "db.example.net", ""},
+		{"database", "PORT", "5432", ""},
+		{"Database", "Name", "inventory", ""},
+		{"Database", "Host", "", `\.example\.net$`},
+	} {
+		s.SetupTest()
+		s.check.Section = c.section
+		s.check.Key = c.key
+		s.check.Expected = c.expected
+		s.check.Pattern = c.pattern
+		s.check.Run()
+
+		s.True(s.check.Output().Passed, c.key)
+		s.NoError(s.check.Error(), c.key)
+	}
+}
+
+func (s *INIValueSuite) TestMismatchedValueFails() {
+	s.check.Section = "Database"
+	s.check.Key = "Port"
+	s.check.Expected = "6543"
+	s.check.Run()
+
+	output := s.check.Output()
+	s.False(output.Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "value '5432'")
+	s.Contains(output.Message, "5432")
+}
+
+func (s *INIValueSuite) TestMissingSectionAndKeyAreReportedDistinctly() {
+	s.check.Section = "Cache"
+	s.check.Key = "Host"
+	s.check.Expected = "localhost"
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "section '[Cache]' does not exist")
+
+	s.SetupTest()
+	s.check.Section = "Empty"
+	s.check.Key = "Host"
+	s.check.Expected = "localhost"
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "key 'Host' does not exist in section '[Empty]'")
+}
+
+func (s *INIValueSuite) TestMissingFileFails() {
+	s.check.Path = filepath.Join(s.tmpDir, "DOES-NOT-EXIST")
+	s.check.Key = "Host"
+	s.check.Expected = "localhost"
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.Error(s.check.Error())
+}
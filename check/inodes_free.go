@@ -0,0 +1,95 @@
+package check
+
+import (
+	"fmt"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/registry"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	name := "inodes-free"
+	registry.AddJobType(name, func() amboy.Job {
+		return &inodesFree{
+			Base:        NewBase(name, 0),
+			inodeCounts: getInodeCounts,
+		}
+	})
+}
+
+// inodesFree checks that the filesystem containing a path has at
+// least the specified number and/or percentage of free inodes.
+type inodesFree struct {
+	Path           string  `bson:"path" json:"path" yaml:"path"`
+	MinInodes      uint64  `bson:"min_inodes" json:"min_inodes" yaml:"min_inodes"`
+	MinPercentFree float64 `bson:"min_percent_free" json:"min_percent_free" yaml:"min_percent_free"`
+	*Base          `bson:"metadata" json:"metadata" yaml:"metadata"`
+
+	// inodeCounts returns the total and free inodes for the
+	// filesystem containing the path.
+	inodeCounts func(string) (uint64, uint64, error)
+}
+
+func (c *inodesFree) validate() error {
+	if c.Path == "" {
+		return errors.Errorf("no path specified for '%s' (%s) check", c.ID(), c.Name())
+	}
+
+	if c.MinInodes == 0 && c.MinPercentFree <= 0 {
+		return errors.Errorf("no minimum inodes or percent free specified for '%s' check", c.ID())
+	}
+
+	if c.MinPercentFree > 100 {
+		return errors.Errorf("minimum percent free for '%s' check cannot be more than 100", c.ID())
+	}
+
+	return nil
+}
+
+func (c *inodesFree) Run() {
+	c.startTask()
+	defer c.MarkComplete()
+
+	if err := c.validate(); err != nil {
+		c.setState(false)
+		c.setReason(ReasonInvalidConfig)
+		c.AddError(err)
+		return
+	}
+
+	total, free, err := c.inodeCounts(c.Path)
+	if err != nil {
+		c.setState(false)
+		c.AddError(err)
+		return
+	}
+
+	if total == 0 {
+		// some filesystems (e.g. btrfs) allocate inodes
+		// dynamically and do not report inode counts.
+		c.setState(true)
+		c.setMessage(fmt.Sprintf("filesystem for '%s' does not have a fixed number of inodes", c.Path))
+		return
+	}
+
+	percent := float64(free) / float64(total) * 100
+	c.setMessage(fmt.Sprintf("'%s' has %d of %d inodes free (%.2f%%), requires %d inodes and %.2f%%",
+		c.Path, free, total, percent, c.MinInodes, c.MinPercentFree))
+
+	if free < c.MinInodes {
+		c.setState(false)
+		c.AddError(errors.Errorf("'%s' has %d free inodes, which is less than %d",
+			c.Path, free, c.MinInodes))
+	}
+
+	if percent < c.MinPercentFree {
+		c.setState(false)
+		c.AddError(errors.Errorf("'%s' has %.2f%% free inodes, which is less than %.2f%%",
+			c.Path, percent, c.MinPercentFree))
+	}
+
+	if c.Error() == nil {
+		c.setState(true)
+	}
+}
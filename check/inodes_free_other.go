@@ -0,0 +1,10 @@
+//go:build !linux && !freebsd && !darwin
+// +build !linux,!freebsd,!darwin
+
+package check
+
+import "github.com/pkg/errors"
+
+func getInodeCounts(path string) (uint64, uint64, error) {
+	return 0, 0, errors.Errorf("checking free inodes for '%s' is not supported on this platform", path)
+}
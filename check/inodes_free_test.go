@@ -0,0 +1,108 @@
+package check
+
+import (
+	"errors"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type InodesFreeSuite struct {
+	total   uint64
+	free    uint64
+	err     error
+	check   *inodesFree
+	require *require.Assertions
+	suite.Suite
+}
+
+func TestInodesFreeSuite(t *testing.T) {
+	suite.Run(t, new(InodesFreeSuite))
+}
+
+func (s *InodesFreeSuite) SetupSuite() {
+	s.require = s.Require()
+}
+
+func (s *InodesFreeSuite) SetupTest() {
+	s.total = 1000
+	s.free = 250
+	s.err = nil
+	s.check = &inodesFree{
+		Path: "/",
+		Base: NewBase("inodes-free", 0),
+		inodeCounts: func(string) (uint64, uint64, error) {
+			return s.total, s.free, s.err
+		},
+	}
+}
+
+func (s *InodesFreeSuite) TestValidation() {
+	s.Error(s.check.validate())
+
+	s.check.MinInodes = 10
+	s.NoError(s.check.validate())
+
+	s.check.MinPercentFree = 101
+	s.Error(s.check.validate())
+
+	s.check.MinPercentFree = 10
+	s.check.Path = ""
+	s.Error(s.check.validate())
+}
+
+func (s *InodesFreeSuite) TestSufficientInodesPasses() {
+	s.check.MinInodes = 100
+	s.check.MinPercentFree = 20
+	s.check.Run()
+
+	output := s.check.Output()
+	s.True(output.Passed)
+	s.NoError(s.check.Error())
+	s.Contains(output.Message, "250 of 1000 inodes free (25.00%)")
+}
+
+func (s *InodesFreeSuite) TestTooFewInodesFails() {
+	s.check.MinInodes = 500
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.Error(s.check.Error())
+
+	s.SetupTest()
+	s.check.MinPercentFree = 30
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.Error(s.check.Error())
+}
+
+func (s *InodesFreeSuite) TestFilesystemsWithoutInodeCountsPass() {
+	s.total = 0
+	s.free = 0
+	s.check.MinInodes = 100
+	s.check.Run()
+	s.True(s.check.Output().Passed)
+	s.Contains(s.check.Output().Message, "does not have a fixed number")
+}
+
+func (s *InodesFreeSuite) TestErrorsFromStatFail() {
+	s.err = errors.New("statfs failed")
+	s.check.MinInodes = 100
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.Error(s.check.Error())
+}
+
+func (s *InodesFreeSuite) TestInodeCountsForRootFilesystem() {
+	if runtime.GOOS == "windows" {
+		s.T().Skip("inode counts are not supported on windows")
+	}
+
+	total, free, err := getInodeCounts("/")
+	s.NoError(err)
+	s.True(free <= total)
+
+	_, _, err = getInodeCounts("/DOES-NOT-EXIST")
+	s.Error(err)
+}
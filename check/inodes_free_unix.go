@@ -0,0 +1,19 @@
+//go:build linux || freebsd || darwin
+// +build linux freebsd darwin
+
+package check
+
+import (
+	"syscall"
+
+	"github.com/pkg/errors"
+)
+
+func getInodeCounts(path string) (uint64, uint64, error) {
+	stat := &syscall.Statfs_t{}
+	if err := syscall.Statfs(path, stat); err != nil {
+		return 0, 0, errors.Wrapf(err, "problem getting filesystem stats for '%s'", path)
+	}
+
+	return uint64(stat.Files), uint64(stat.Ffree), nil
+}
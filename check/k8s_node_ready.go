@@ -0,0 +1,368 @@
+package check
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/registry"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	name := "k8s-node-ready"
+	registry.AddJobType(name, func() amboy.Job {
+		return &k8sNodeReady{
+			Base:              NewBase(name, 0),
+			serviceAccountDir: "/var/run/secrets/kubernetes.io/serviceaccount",
+			timeout:           30 * time.Second,
+		}
+	})
+}
+
+// k8sNodeReady checks the conditions of a Kubernetes node, using the
+// API server from the current context of the kubeconfig file or,
+// when kubeconfig is not set, the in-cluster service account. The
+// node_name defaults to the hostname, and conditions maps condition
+// types to their expected status, and defaults to requiring that the
+// node is Ready (i.e. {Ready: "True"}).
+type k8sNodeReady struct {
+	Kubeconfig string            `bson:"kubeconfig" json:"kubeconfig" yaml:"kubeconfig"`
+	NodeName   string            `bson:"node_name" json:"node_name" yaml:"node_name"`
+	Conditions map[string]string `bson:"conditions" json:"conditions" yaml:"conditions"`
+	*Base      `bson:"metadata" json:"metadata" yaml:"metadata"`
+	fileReadLimit
+
+	serviceAccountDir string
+	timeout           time.Duration
+}
+
+// k8sAPI is the connection information for a Kubernetes API server.
+type k8sAPI struct {
+	server string
+	token  string
+	tls    *tls.Config
+}
+
+// kubeconfig is the subset of the kubeconfig file format that the
+// k8s-node-ready check supports.
+type kubeconfig struct {
+	CurrentContext string `json:"current-context"`
+	Contexts       []struct {
+		Name    string `json:"name"`
+		Context struct {
+			Cluster string `json:"cluster"`
+			User    string `json:"user"`
+		} `json:"context"`
+	} `json:"contexts"`
+	Clusters []struct {
+		Name    string `json:"name"`
+		Cluster struct {
+			Server                   string `json:"server"`
+			CertificateAuthority     string `json:"certificate-authority"`
+			CertificateAuthorityData string `json:"certificate-authority-data"`
+			InsecureSkipTLSVerify    bool   `json:"insecure-skip-tls-verify"`
+		} `json:"cluster"`
+	} `json:"clusters"`
+	Users []struct {
+		Name string `json:"name"`
+		User struct {
+			Token                 string `json:"token"`
+			TokenFile             string `json:"tokenFile"`
+			ClientCertificate     string `json:"client-certificate"`
+			ClientCertificateData string `json:"client-certificate-data"`
+			ClientKey             string `json:"client-key"`
+			ClientKeyData         string `json:"client-key-data"`
+		} `json:"user"`
+	} `json:"users"`
+}
+
+func (c *k8sNodeReady) validate() error {
+	if c.NodeName == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return errors.Wrapf(err, "problem finding node name for '%s' (%s) check", c.ID(), c.Name())
+		}
+		c.NodeName = hostname
+	}
+
+	if len(c.Conditions) == 0 {
+		c.Conditions = map[string]string{"Ready": "True"}
+	}
+
+	for condition, status := range c.Conditions {
+		switch status {
+		case "True", "False", "Unknown":
+		default:
+			return errors.Errorf("status '%s' for condition '%s' in '%s' check must be True, False, or Unknown",
+				status, condition, c.ID())
+		}
+	}
+
+	return nil
+}
+
+// api returns the connection information for the API server.
+func (c *k8sNodeReady) api() (*k8sAPI, error) {
+	if c.Kubeconfig == "" {
+		return c.inClusterAPI()
+	}
+
+	data, err := c.readFile(c.Kubeconfig)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem reading kubeconfig '%s'", c.Kubeconfig)
+	}
+
+	conf := kubeconfig{}
+	if err = yaml.Unmarshal(data, &conf); err != nil {
+		return nil, errors.Wrapf(err, "problem parsing kubeconfig '%s'", c.Kubeconfig)
+	}
+
+	var clusterName, userName string
+	for _, ctx := range conf.Contexts {
+		if ctx.Name == conf.CurrentContext {
+			clusterName, userName = ctx.Context.Cluster, ctx.Context.User
+		}
+	}
+	if clusterName == "" {
+		return nil, errors.Errorf("kubeconfig '%s' does not define the current context '%s'",
+			c.Kubeconfig, conf.CurrentContext)
+	}
+
+	// relative paths in a kubeconfig are relative to its directory
+	dir := filepath.Dir(c.Kubeconfig)
+	resolve := func(fn string) string {
+		if fn == "" || filepath.IsAbs(fn) {
+			return fn
+		}
+		return filepath.Join(dir, fn)
+	}
+
+	api := &k8sAPI{tls: &tls.Config{}}
+	for _, cluster := range conf.Clusters {
+		if cluster.Name != clusterName {
+			continue
+		}
+
+		api.server = cluster.Cluster.Server
+		api.tls.InsecureSkipVerify = cluster.Cluster.InsecureSkipTLSVerify
+
+		ca, err := c.kubeconfigData(cluster.Cluster.CertificateAuthorityData, resolve(cluster.Cluster.CertificateAuthority))
+		if err != nil {
+			return nil, errors.Wrap(err, "problem loading certificate authority")
+		}
+		if ca != nil {
+			api.tls.RootCAs = x509.NewCertPool()
+			if !api.tls.RootCAs.AppendCertsFromPEM(ca) {
+				return nil, errors.Errorf("certificate authority for cluster '%s' has no certificates", clusterName)
+			}
+		}
+	}
+	if api.server == "" {
+		return nil, errors.Errorf("kubeconfig '%s' does not define a server for cluster '%s'",
+			c.Kubeconfig, clusterName)
+	}
+
+	for _, user := range conf.Users {
+		if user.Name != userName {
+			continue
+		}
+
+		api.token = user.User.Token
+		if user.User.TokenFile != "" {
+			token, err := c.readFile(resolve(user.User.TokenFile))
+			if err != nil {
+				return nil, errors.Wrap(err, "problem reading token file")
+			}
+			api.token = strings.TrimSpace(string(token))
+		}
+
+		cert, err := c.kubeconfigData(user.User.ClientCertificateData, resolve(user.User.ClientCertificate))
+		if err != nil {
+			return nil, errors.Wrap(err, "problem loading client certificate")
+		}
+		key, err := c.kubeconfigData(user.User.ClientKeyData, resolve(user.User.ClientKey))
+		if err != nil {
+			return nil, errors.Wrap(err, "problem loading client key")
+		}
+		if cert != nil || key != nil {
+			pair, err := tls.X509KeyPair(cert, key)
+			if err != nil {
+				return nil, errors.Wrapf(err, "problem loading client certificate for user '%s'", userName)
+			}
+			api.tls.Certificates = []tls.Certificate{pair}
+		}
+	}
+
+	return api, nil
+}
+
+// kubeconfigData returns the base64-encoded inline data, or the
+// content of the file, or nil if neither is set.
+func (c *k8sNodeReady) kubeconfigData(data, fn string) ([]byte, error) {
+	if data != "" {
+		return base64.StdEncoding.DecodeString(data)
+	}
+
+	if fn != "" {
+		return c.readFile(fn)
+	}
+
+	return nil, nil
+}
+
+// inClusterAPI returns the connection information for the API server
+// from the environment and the service account of a pod.
+func (c *k8sNodeReady) inClusterAPI() (*k8sAPI, error) {
+	host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+	if host == "" || port == "" {
+		return nil, errors.New("no kubeconfig specified, and not running in a cluster")
+	}
+
+	token, err := c.readFile(filepath.Join(c.serviceAccountDir, "token"))
+	if err != nil {
+		return nil, errors.Wrap(err, "problem reading service account token")
+	}
+
+	ca, err := c.readFile(filepath.Join(c.serviceAccountDir, "ca.crt"))
+	if err != nil {
+		return nil, errors.Wrap(err, "problem reading service account certificate authority")
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return nil, errors.New("service account certificate authority has no certificates")
+	}
+
+	return &k8sAPI{
+		server: "https://" + net.JoinHostPort(host, port),
+		token:  strings.TrimSpace(string(token)),
+		tls:    &tls.Config{RootCAs: pool},
+	}, nil
+}
+
+// k8sNode is the subset of the Kubernetes Node resource that the
+// k8s-node-ready check uses.
+type k8sNode struct {
+	Status struct {
+		Conditions []struct {
+			Type    string `json:"type"`
+			Status  string `json:"status"`
+			Reason  string `json:"reason"`
+			Message string `json:"message"`
+		} `json:"conditions"`
+	} `json:"status"`
+}
+
+func (c *k8sNodeReady) Run() {
+	c.startTask()
+	defer c.MarkComplete()
+
+	if err := c.validate(); err != nil {
+		c.setState(false)
+		c.setReason(ReasonInvalidConfig)
+		c.AddError(err)
+		return
+	}
+
+	api, err := c.api()
+	if err != nil {
+		c.setState(false)
+		c.AddError(errors.Wrapf(err, "problem configuring kubernetes API access for '%s' check", c.ID()))
+		return
+	}
+
+	nodeURL := strings.TrimSuffix(api.server, "/") + "/api/v1/nodes/" + url.PathEscape(c.NodeName)
+	req, err := http.NewRequest("GET", nodeURL, nil)
+	if err != nil {
+		c.setState(false)
+		c.AddError(errors.Wrapf(err, "problem building request for %s", nodeURL))
+		return
+	}
+	req.Header.Set("Accept", "application/json")
+	if api.token != "" {
+		req.Header.Set("Authorization", "Bearer "+api.token)
+	}
+
+	client := &http.Client{
+		Timeout:   c.timeout,
+		Transport: &http.Transport{TLSClientConfig: api.tls},
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		c.setState(false)
+		c.AddError(errors.Wrapf(err, "kubernetes API at %s is unreachable", api.server))
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		c.setState(false)
+		c.AddError(errors.Errorf("node '%s' was not found", c.NodeName))
+		return
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		c.setState(false)
+		c.AddError(errors.Errorf("kubernetes API at %s returned %s for node '%s'",
+			api.server, resp.Status, c.NodeName))
+		return
+	}
+
+	node := k8sNode{}
+	if err = json.NewDecoder(resp.Body).Decode(&node); err != nil {
+		c.setState(false)
+		c.AddError(errors.Wrapf(err, "problem decoding node '%s'", c.NodeName))
+		return
+	}
+
+	actual := make(map[string]string)
+	var states []string
+	for _, condition := range node.Status.Conditions {
+		actual[condition.Type] = condition.Status
+		states = append(states, fmt.Sprintf("%s=%s", condition.Type, condition.Status))
+	}
+	c.setMessage(fmt.Sprintf("node '%s' conditions: %s", c.NodeName, strings.Join(states, ", ")))
+
+	types := make([]string, 0, len(c.Conditions))
+	for condition := range c.Conditions {
+		types = append(types, condition)
+	}
+	sort.Strings(types)
+
+	var problems []string
+	for _, condition := range types {
+		status, ok := actual[condition]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("node does not report the %s condition", condition))
+			continue
+		}
+
+		if status != c.Conditions[condition] {
+			problems = append(problems, fmt.Sprintf("%s is %s, expected %s",
+				condition, status, c.Conditions[condition]))
+		}
+	}
+
+	if len(problems) > 0 {
+		c.setState(false)
+		c.AddError(errors.Errorf("node '%s' conditions do not match: %s",
+			c.NodeName, strings.Join(problems, "; ")))
+		return
+	}
+
+	c.setState(true)
+}
@@ -0,0 +1,186 @@
+package check
+
+import (
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type K8sNodeReadySuite struct {
+	check   *k8sNodeReady
+	server  *httptest.Server
+	tmpDir  string
+	require *require.Assertions
+	suite.Suite
+}
+
+func TestK8sNodeReadySuite(t *testing.T) {
+	suite.Run(t, new(K8sNodeReadySuite))
+}
+
+const k8sTestNode = `{
+  "kind": "Node",
+  "metadata": {"name": "%s"},
+  "status": {
+    "conditions": [
+      {"type": "MemoryPressure", "status": "False", "reason": "KubeletHasSufficientMemory"},
+      {"type": "DiskPressure", "status": "True", "reason": "KubeletHasDiskPressure"},
+      {"type": "Ready", "status": "True", "reason": "KubeletReady"}
+    ]
+  }
+}`
+
+func (s *K8sNodeReadySuite) SetupSuite() {
+	s.require = s.Require()
+
+	tmpDir, err := ioutil.TempDir("", uuid.NewV4().String())
+	s.require.NoError(err)
+	s.tmpDir = tmpDir
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/nodes/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer node-reader" {
+			http.Error(w, `{"kind": "Status", "code": 401}`, http.StatusUnauthorized)
+			return
+		}
+
+		name := filepath.Base(r.URL.Path)
+		if name != "worker-1" {
+			http.Error(w, `{"kind": "Status", "code": 404}`, http.StatusNotFound)
+			return
+		}
+
+		fmt.Fprintf(w, k8sTestNode, name)
+	})
+	s.server = httptest.NewTLSServer(mux)
+
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: s.server.Certificate().Raw})
+	s.require.NoError(ioutil.WriteFile(filepath.Join(tmpDir, "ca.crt"), ca, 0644))
+	s.require.NoError(ioutil.WriteFile(filepath.Join(tmpDir, "token"), []byte("node-reader\n"), 0600))
+
+	kubeconfig := fmt.Sprintf(`
+apiVersion: v1
+kind: Config
+current-context: fleet
+contexts:
+- name: other
+  context: {cluster: other, user: other}
+- name: fleet
+  context: {cluster: fleet, user: checker}
+clusters:
+- name: fleet
+  cluster:
+    server: %s
+    certificate-authority: ca.crt
+users:
+- name: checker
+  user:
+    tokenFile: token
+`, s.server.URL)
+	s.require.NoError(ioutil.WriteFile(filepath.Join(tmpDir, "kubeconfig"), []byte(kubeconfig), 0600))
+}
+
+func (s *K8sNodeReadySuite) TearDownSuite() {
+	s.server.Close()
+	s.NoError(os.RemoveAll(s.tmpDir))
+}
+
+func (s *K8sNodeReadySuite) SetupTest() {
+	s.check = &k8sNodeReady{
+		Base:              NewBase("k8s-node-ready", 0),
+		Kubeconfig:        filepath.Join(s.tmpDir, "kubeconfig"),
+		NodeName:          "worker-1",
+		serviceAccountDir: s.tmpDir,
+	}
+}
+
+func (s *K8sNodeReadySuite) TestDefaults() {
+	s.check.NodeName = ""
+	s.NoError(s.check.validate())
+
+	hostname, err := os.Hostname()
+	s.require.NoError(err)
+	s.Equal(hostname, s.check.NodeName)
+	s.Equal(map[string]string{"Ready": "True"}, s.check.Conditions)
+
+	s.check.Conditions = map[string]string{"Ready": "yes"}
+	s.Error(s.check.validate())
+}
+
+func (s *K8sNodeReadySuite) TestMatchingConditionsPass() {
+	s.check.Conditions = map[string]string{"Ready": "True", "MemoryPressure": "False"}
+	s.check.Run()
+	s.True(s.check.Output().Passed)
+	s.NoError(s.check.Error())
+	s.Contains(s.check.Output().Message, "MemoryPressure=False, DiskPressure=True, Ready=True")
+}
+
+func (s *K8sNodeReadySuite) TestMismatchedConditionsFail() {
+	s.check.Conditions = map[string]string{"DiskPressure": "False", "PIDPressure": "False", "Ready": "True"}
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "DiskPressure is True, expected False")
+	s.Contains(s.check.Error().Error(), "does not report the PIDPressure condition")
+	s.Contains(s.check.Output().Message, "DiskPressure=True")
+}
+
+func (s *K8sNodeReadySuite) TestInClusterConfig() {
+	host, port, err := net.SplitHostPort(s.server.Listener.Addr().String())
+	s.require.NoError(err)
+
+	s.require.NoError(os.Setenv("KUBERNETES_SERVICE_HOST", host))
+	s.require.NoError(os.Setenv("KUBERNETES_SERVICE_PORT", port))
+	defer os.Unsetenv("KUBERNETES_SERVICE_HOST")
+	defer os.Unsetenv("KUBERNETES_SERVICE_PORT")
+
+	s.check.Kubeconfig = ""
+	s.check.Run()
+	s.True(s.check.Output().Passed)
+	s.NoError(s.check.Error())
+}
+
+func (s *K8sNodeReadySuite) TestAPIFailuresAreDistinct() {
+	s.check.NodeName = "worker-2"
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "node 'worker-2' was not found")
+
+	s.SetupTest()
+	s.require.NoError(os.Unsetenv("KUBERNETES_SERVICE_HOST"))
+	s.check.Kubeconfig = ""
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "not running in a cluster")
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	s.require.NoError(err)
+	addr := l.Addr().String()
+	s.require.NoError(l.Close())
+
+	kubeconfig := filepath.Join(s.tmpDir, "unreachable")
+	s.require.NoError(ioutil.WriteFile(kubeconfig, []byte(`
+current-context: fleet
+contexts: [{name: fleet, context: {cluster: fleet, user: anonymous}}]
+clusters: [{name: fleet, cluster: {server: "https://`+addr+`"}}]
+`), 0600))
+
+	s.SetupTest()
+	s.check.Kubeconfig = kubeconfig
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "is unreachable")
+}
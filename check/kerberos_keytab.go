@@ -0,0 +1,315 @@
+package check
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/registry"
+	"github.com/pkg/errors"
+	"github.com/satori/go.uuid"
+)
+
+func init() {
+	name := "kerberos-keytab"
+	registry.AddJobType(name, func() amboy.Job {
+		return &kerberosKeytab{
+			Base:  NewBase(name, 0),
+			kinit: runKinit,
+		}
+	})
+}
+
+// kerberosKeytab checks that a Kerberos keytab file exists and has
+// keys for a principal (e.g. "HTTP/www.example.com"). The realm is
+// added to principals that do not specify one; without a realm, a
+// principal without a realm matches keys in any realm. When
+// acquire_ticket is set, the check also uses kinit to obtain a
+// ticket-granting ticket with the keytab, in a temporary credential
+// cache.
+type kerberosKeytab struct {
+	KeytabPath    string `bson:"keytab_path" json:"keytab_path" yaml:"keytab_path"`
+	Principal     string `bson:"principal" json:"principal" yaml:"principal"`
+	Realm         string `bson:"realm" json:"realm" yaml:"realm"`
+	AcquireTicket bool   `bson:"acquire_ticket" json:"acquire_ticket" yaml:"acquire_ticket"`
+	*Base         `bson:"metadata" json:"metadata" yaml:"metadata"`
+	fileReadLimit
+
+	kinit func(keytab, principal, cache string) ([]byte, error)
+}
+
+// keytabEntry is a key for a principal in a keytab.
+type keytabEntry struct {
+	principal string
+	kvno      uint32
+	enctype   uint16
+}
+
+// kerberosEnctypes are the names of common Kerberos encryption types.
+var kerberosEnctypes = map[uint16]string{
+	16: "des3-cbc-sha1",
+	17: "aes128-cts-hmac-sha1-96",
+	18: "aes256-cts-hmac-sha1-96",
+	19: "aes128-cts-hmac-sha256-128",
+	20: "aes256-cts-hmac-sha384-192",
+	23: "arcfour-hmac",
+}
+
+func (c *kerberosKeytab) validate() error {
+	if c.KeytabPath == "" {
+		return errors.Errorf("no keytab path specified for '%s' (%s) check", c.ID(), c.Name())
+	}
+
+	if c.Principal == "" {
+		return errors.Errorf("no principal specified for '%s' check", c.ID())
+	}
+
+	if i := strings.LastIndex(c.Principal, "@"); i >= 0 && c.Realm != "" && c.Principal[i+1:] != c.Realm {
+		return errors.Errorf("principal '%s' for '%s' check is not in the realm '%s'",
+			c.Principal, c.ID(), c.Realm)
+	}
+
+	return nil
+}
+
+// principalName returns the principal with the realm, if the check
+// specifies one.
+func (c *kerberosKeytab) principalName() string {
+	if c.Realm != "" && !strings.Contains(c.Principal, "@") {
+		return c.Principal + "@" + c.Realm
+	}
+
+	return c.Principal
+}
+
+func (c *kerberosKeytab) matches(principal string) bool {
+	expected := c.principalName()
+	if strings.Contains(expected, "@") {
+		return principal == expected
+	}
+
+	return strings.HasPrefix(principal, expected+"@")
+}
+
+func (c *kerberosKeytab) Run() {
+	c.startTask()
+	defer c.MarkComplete()
+
+	if err := c.validate(); err != nil {
+		c.setState(false)
+		c.setReason(ReasonInvalidConfig)
+		c.AddError(err)
+		return
+	}
+
+	data, err := c.readFile(c.KeytabPath)
+	if err != nil {
+		c.setState(false)
+		c.AddError(errors.Wrapf(err, "problem reading keytab '%s'", c.KeytabPath))
+		return
+	}
+
+	entries, err := parseKeytab(data)
+	if err != nil {
+		c.setState(false)
+		c.AddError(errors.Wrapf(err, "keytab '%s' is not valid", c.KeytabPath))
+		return
+	}
+
+	var found []keytabEntry
+	principals := make(map[string]struct{})
+	for _, entry := range entries {
+		principals[entry.principal] = struct{}{}
+		if c.matches(entry.principal) {
+			found = append(found, entry)
+		}
+	}
+
+	if len(found) == 0 {
+		names := make([]string, 0, len(principals))
+		for name := range principals {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		c.setState(false)
+		c.AddError(errors.Errorf("principal '%s' was not found in keytab '%s', which has keys for [%s]",
+			c.principalName(), c.KeytabPath, strings.Join(names, ", ")))
+		return
+	}
+
+	msg := []string{fmt.Sprintf("principal '%s' was found in keytab '%s'", c.principalName(), c.KeytabPath)}
+	for _, entry := range found {
+		enctype, ok := kerberosEnctypes[entry.enctype]
+		if !ok {
+			enctype = fmt.Sprintf("enctype-%d", entry.enctype)
+		}
+		msg = append(msg, fmt.Sprintf("%s kvno=%d %s", entry.principal, entry.kvno, enctype))
+	}
+
+	if !c.AcquireTicket {
+		c.setMessage(msg)
+		c.setState(true)
+		return
+	}
+
+	cacheDir, err := ioutil.TempDir("", uuid.NewV4().String())
+	if err != nil {
+		c.setMessage(msg)
+		c.setState(false)
+		c.AddError(errors.Wrap(err, "problem creating temporary credential cache"))
+		return
+	}
+	defer os.RemoveAll(cacheDir)
+
+	out, err := c.kinit(c.KeytabPath, found[0].principal, filepath.Join(cacheDir, "krb5cc"))
+	if err != nil {
+		c.setMessage(msg)
+		c.setState(false)
+		c.AddError(errors.Wrapf(err, "problem acquiring a ticket for '%s' with keytab '%s': %s",
+			found[0].principal, c.KeytabPath, strings.TrimSpace(string(out))))
+		return
+	}
+
+	msg = append(msg, fmt.Sprintf("acquired a ticket-granting ticket for '%s'", found[0].principal))
+	c.setMessage(msg)
+	c.setState(true)
+}
+
+// runKinit obtains a ticket-granting ticket for the principal with the
+// keytab, and stores it in the credential cache file.
+func runKinit(keytab, principal, cache string) ([]byte, error) {
+	cmd := exec.Command("kinit", "-k", "-t", keytab, principal)
+	cmd.Env = append(os.Environ(), "KRB5CCNAME=FILE:"+cache)
+
+	return cmd.CombinedOutput()
+}
+
+// parseKeytab returns the entries in a keytab file, in the format
+// that MIT Kerberos and Heimdal use (versions 0x501 and 0x502).
+func parseKeytab(data []byte) ([]keytabEntry, error) {
+	if len(data) < 2 || data[0] != 5 || (data[1] != 1 && data[1] != 2) {
+		return nil, errors.New("file does not start with a supported keytab version")
+	}
+
+	var order binary.ByteOrder = binary.BigEndian
+	if data[1] == 1 {
+		order = binary.LittleEndian
+	}
+
+	var entries []keytabEntry
+	r := bytes.NewReader(data[2:])
+	for r.Len() > 0 {
+		var size int32
+		if err := binary.Read(r, order, &size); err != nil {
+			return nil, errors.Wrap(err, "problem reading entry size")
+		}
+
+		if size < 0 {
+			// holes from deleted entries
+			if _, err := r.Seek(int64(-size), io.SeekCurrent); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if int(size) > r.Len() {
+			return nil, errors.New("entry is truncated")
+		}
+
+		record := make([]byte, size)
+		if _, err := io.ReadFull(r, record); err != nil {
+			return nil, err
+		}
+
+		entry, err := parseKeytabEntry(record, order, data[1])
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+func parseKeytabEntry(record []byte, order binary.ByteOrder, version byte) (keytabEntry, error) {
+	entry := keytabEntry{}
+	r := bytes.NewReader(record)
+
+	readString := func() (string, error) {
+		var size uint16
+		if err := binary.Read(r, order, &size); err != nil {
+			return "", err
+		}
+		if int(size) > r.Len() {
+			return "", errors.New("string is truncated")
+		}
+
+		buf := make([]byte, size)
+		_, err := io.ReadFull(r, buf)
+		return string(buf), err
+	}
+
+	var numComponents uint16
+	if err := binary.Read(r, order, &numComponents); err != nil {
+		return entry, errors.Wrap(err, "problem reading principal")
+	}
+	if version == 1 {
+		// version 1 counts the realm as a component
+		numComponents--
+	}
+
+	realm, err := readString()
+	if err != nil {
+		return entry, errors.Wrap(err, "problem reading realm")
+	}
+
+	components := make([]string, 0, numComponents)
+	for i := 0; i < int(numComponents); i++ {
+		component, err := readString()
+		if err != nil {
+			return entry, errors.Wrap(err, "problem reading principal")
+		}
+		components = append(components, component)
+	}
+	entry.principal = strings.Join(components, "/") + "@" + realm
+
+	// name type (version 2 only), timestamp, and 8-bit key version
+	skip := 5
+	if version == 2 {
+		skip += 4
+	}
+	if r.Len() < skip {
+		return entry, errors.New("entry is truncated")
+	}
+	if _, err = r.Seek(int64(skip-1), io.SeekCurrent); err != nil {
+		return entry, err
+	}
+	kvno8, _ := r.ReadByte()
+	entry.kvno = uint32(kvno8)
+
+	if err = binary.Read(r, order, &entry.enctype); err != nil {
+		return entry, errors.Wrap(err, "problem reading key type")
+	}
+	if _, err = readString(); err != nil {
+		return entry, errors.Wrap(err, "problem reading key")
+	}
+
+	// newer implementations add a 32-bit key version.
+	if r.Len() >= 4 {
+		var kvno uint32
+		if err = binary.Read(r, order, &kvno); err == nil && kvno != 0 {
+			entry.kvno = kvno
+		}
+	}
+
+	return entry, nil
+}
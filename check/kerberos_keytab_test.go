@@ -0,0 +1,175 @@
+package check
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type KerberosKeytabSuite struct {
+	check   *kerberosKeytab
+	tmpDir  string
+	keytab  string
+	require *require.Assertions
+	suite.Suite
+}
+
+func TestKerberosKeytabSuite(t *testing.T) {
+	suite.Run(t, new(KerberosKeytabSuite))
+}
+
+// keytabRecord encodes a version 0x502 keytab entry for the
+// principal, with a 32-bit key version.
+func keytabRecord(realm string, components []string, kvno uint32, enctype uint16) []byte {
+	record := &bytes.Buffer{}
+	writeString := func(s string) {
+		_ = binary.Write(record, binary.BigEndian, uint16(len(s)))
+		record.WriteString(s)
+	}
+
+	_ = binary.Write(record, binary.BigEndian, uint16(len(components)))
+	writeString(realm)
+	for _, component := range components {
+		writeString(component)
+	}
+	_ = binary.Write(record, binary.BigEndian, uint32(1))          // name type
+	_ = binary.Write(record, binary.BigEndian, uint32(1500000000)) // timestamp
+	record.WriteByte(byte(kvno))
+	_ = binary.Write(record, binary.BigEndian, enctype)
+	writeString(strings.Repeat("k", 32))
+	_ = binary.Write(record, binary.BigEndian, kvno)
+
+	out := &bytes.Buffer{}
+	_ = binary.Write(out, binary.BigEndian, int32(record.Len()))
+	out.Write(record.Bytes())
+	return out.Bytes()
+}
+
+func (s *KerberosKeytabSuite) SetupSuite() {
+	s.require = s.Require()
+
+	tmpDir, err := ioutil.TempDir("", uuid.NewV4().String())
+	s.require.NoError(err)
+	s.tmpDir = tmpDir
+
+	keytab := &bytes.Buffer{}
+	keytab.Write([]byte{5, 2})
+	keytab.Write(keytabRecord("EXAMPLE.COM", []string{"HTTP", "www.example.com"}, 3, 18))
+	// a hole left by a deleted entry
+	_ = binary.Write(keytab, binary.BigEndian, int32(-4))
+	keytab.Write([]byte{0, 0, 0, 0})
+	keytab.Write(keytabRecord("EXAMPLE.COM", []string{"HTTP", "www.example.com"}, 300, 17))
+	keytab.Write(keytabRecord("EXAMPLE.COM", []string{"host", "www.example.com"}, 2, 18))
+
+	s.keytab = filepath.Join(tmpDir, "krb5.keytab")
+	s.require.NoError(ioutil.WriteFile(s.keytab, keytab.Bytes(), 0600))
+}
+
+func (s *KerberosKeytabSuite) TearDownSuite() {
+	s.NoError(os.RemoveAll(s.tmpDir))
+}
+
+func (s *KerberosKeytabSuite) SetupTest() {
+	s.check = &kerberosKeytab{
+		Base:       NewBase("kerberos-keytab", 0),
+		KeytabPath: s.keytab,
+		Principal:  "HTTP/www.example.com",
+		kinit: func(keytab, principal, cache string) ([]byte, error) {
+			if principal != "HTTP/www.example.com@EXAMPLE.COM" {
+				return []byte("kinit: Client not found in Kerberos database"), errors.New("exit status 1")
+			}
+			return nil, ioutil.WriteFile(cache, []byte("ticket"), 0600)
+		},
+	}
+}
+
+func (s *KerberosKeytabSuite) TestValidation() {
+	s.NoError(s.check.validate())
+
+	s.check.Realm = "EXAMPLE.COM"
+	s.NoError(s.check.validate())
+
+	s.check.Principal = "HTTP/www.example.com@OTHER.COM"
+	s.Error(s.check.validate())
+
+	s.check.Principal = ""
+	s.Error(s.check.validate())
+
+	s.SetupTest()
+	s.check.KeytabPath = ""
+	s.Error(s.check.validate())
+}
+
+func (s *KerberosKeytabSuite) TestParseKeytab() {
+	data, err := ioutil.ReadFile(s.keytab)
+	s.require.NoError(err)
+
+	entries, err := parseKeytab(data)
+	s.require.NoError(err)
+	s.require.Len(entries, 3)
+	s.Equal("HTTP/www.example.com@EXAMPLE.COM", entries[0].principal)
+	s.Equal(uint32(3), entries[0].kvno)
+	s.Equal(uint16(18), entries[0].enctype)
+	s.Equal(uint32(300), entries[1].kvno)
+	s.Equal("host/www.example.com@EXAMPLE.COM", entries[2].principal)
+
+	_, err = parseKeytab([]byte("not a keytab"))
+	s.Error(err)
+
+	_, err = parseKeytab(data[:len(data)-10])
+	s.Error(err)
+}
+
+func (s *KerberosKeytabSuite) TestPrincipalFound() {
+	for _, realm := range []string{"", "EXAMPLE.COM"} {
+		s.SetupTest()
+		s.check.Realm = realm
+		s.check.Run()
+		s.True(s.check.Output().Passed, realm)
+		s.NoError(s.check.Error(), realm)
+		s.Contains(s.check.Output().Message, "kvno=300 aes128-cts-hmac-sha1-96")
+	}
+}
+
+func (s *KerberosKeytabSuite) TestMissingPrincipalFails() {
+	s.check.Principal = "HTTP/www.example.com"
+	s.check.Realm = "OTHER.COM"
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "was not found")
+	s.Contains(s.check.Error().Error(), "[HTTP/www.example.com@EXAMPLE.COM, host/www.example.com@EXAMPLE.COM]")
+
+	s.SetupTest()
+	s.check.KeytabPath = filepath.Join(s.tmpDir, "does-not-exist")
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "problem reading keytab")
+}
+
+func (s *KerberosKeytabSuite) TestAcquireTicket() {
+	s.check.AcquireTicket = true
+	s.check.Run()
+	s.True(s.check.Output().Passed)
+	s.NoError(s.check.Error())
+	s.Contains(s.check.Output().Message, "acquired a ticket-granting ticket")
+
+	s.SetupTest()
+	s.check.AcquireTicket = true
+	s.check.Principal = "host/www.example.com"
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "Client not found in Kerberos database")
+	s.Contains(s.check.Output().Message, "was found in keytab")
+}
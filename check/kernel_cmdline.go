@@ -0,0 +1,165 @@
+package check
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/registry"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	name := "kernel-cmdline"
+	registry.AddJobType(name, func() amboy.Job {
+		return &kernelCmdline{
+			Base:        NewBase(name, 0),
+			cmdlineFile: "/proc/cmdline",
+		}
+	})
+}
+
+// kernelCmdline checks the parameters that the kernel booted with,
+// from /proc/cmdline. Each of the parameters must be present, and
+// none of the forbidden parameters may be present. A parameter of
+// the form "key" matches the key with any value (or none), and a
+// parameter of the form "key=value" only matches that value (e.g.
+// "audit=1" or "transparent_hugepage=never"). Keys may be given more
+// than once (e.g. "console"), and match if any occurrence matches.
+type kernelCmdline struct {
+	Parameters []string `bson:"parameters" json:"parameters" yaml:"parameters"`
+	Forbidden  []string `bson:"forbidden" json:"forbidden" yaml:"forbidden"`
+	*Base      `bson:"metadata" json:"metadata" yaml:"metadata"`
+	fileReadLimit
+
+	cmdlineFile string
+}
+
+func (c *kernelCmdline) validate() error {
+	if len(c.Parameters) == 0 && len(c.Forbidden) == 0 {
+		return errors.Errorf("no parameters specified for '%s' (%s) check", c.ID(), c.Name())
+	}
+
+	for _, p := range append(append([]string{}, c.Parameters...), c.Forbidden...) {
+		if strings.TrimSpace(p) == "" || strings.HasPrefix(p, "=") {
+			return errors.Errorf("parameter '%s' for '%s' check does not have a key", p, c.ID())
+		}
+	}
+
+	return nil
+}
+
+func (c *kernelCmdline) Run() {
+	c.startTask()
+	defer c.MarkComplete()
+
+	if err := c.validate(); err != nil {
+		c.setState(false)
+		c.setReason(ReasonInvalidConfig)
+		c.AddError(err)
+		return
+	}
+
+	data, err := c.readFile(c.cmdlineFile)
+	if err != nil {
+		c.setState(false)
+		c.AddError(err)
+		return
+	}
+
+	cmdline := strings.TrimSpace(string(data))
+	params := parseKernelCmdline(cmdline)
+
+	var problems []string
+	for _, p := range c.Parameters {
+		if !hasKernelParameter(params, p) {
+			problems = append(problems, fmt.Sprintf("missing '%s'", p))
+		}
+	}
+
+	for _, p := range c.Forbidden {
+		if hasKernelParameter(params, p) {
+			problems = append(problems, fmt.Sprintf("forbidden '%s' is present", p))
+		}
+	}
+
+	c.setMessage(fmt.Sprintf("kernel command line: %s", cmdline))
+
+	if len(problems) > 0 {
+		c.setState(false)
+		c.AddError(errors.Errorf("kernel command line parameters do not match: %s",
+			strings.Join(problems, ", ")))
+		return
+	}
+
+	c.setState(true)
+}
+
+// kernelParameter is a parameter from the kernel command line, which
+// may not have a value.
+type kernelParameter struct {
+	key      string
+	value    string
+	hasValue bool
+}
+
+func splitKernelParameter(param string) kernelParameter {
+	parts := strings.SplitN(param, "=", 2)
+	p := kernelParameter{key: parts[0]}
+	if len(parts) == 2 {
+		p.value = strings.Trim(parts[1], `"`)
+		p.hasValue = true
+	}
+
+	// the kernel treats dashes and underscores in keys as the same.
+	p.key = strings.Replace(p.key, "-", "_", -1)
+
+	return p
+}
+
+// parseKernelCmdline splits a kernel command line into parameters,
+// on whitespace outside of double quotes, which may quote values
+// with spaces (e.g. `dyndbg="file foo.c +p"`).
+func parseKernelCmdline(cmdline string) []kernelParameter {
+	var params []kernelParameter
+	var current []rune
+	quoted := false
+
+	flush := func() {
+		if len(current) > 0 {
+			params = append(params, splitKernelParameter(string(current)))
+			current = nil
+		}
+	}
+
+	for _, r := range cmdline {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			current = append(current, r)
+		case !quoted && (r == ' ' || r == '\t' || r == '\n'):
+			flush()
+		default:
+			current = append(current, r)
+		}
+	}
+	flush()
+
+	return params
+}
+
+func hasKernelParameter(params []kernelParameter, param string) bool {
+	expected := splitKernelParameter(param)
+
+	for _, p := range params {
+		if p.key != expected.key {
+			continue
+		}
+
+		if !expected.hasValue || (p.hasValue && p.value == expected.value) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,100 @@
+package check
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type KernelCmdlineSuite struct {
+	tmpDir  string
+	check   *kernelCmdline
+	require *require.Assertions
+	suite.Suite
+}
+
+func TestKernelCmdlineSuite(t *testing.T) {
+	suite.Run(t, new(KernelCmdlineSuite))
+}
+
+func (s *KernelCmdlineSuite) SetupSuite() {
+	s.require = s.Require()
+
+	dir, err := ioutil.TempDir("", uuid.NewV4().String())
+	s.require.NoError(err)
+	s.tmpDir = dir
+
+	s.require.NoError(ioutil.WriteFile(filepath.Join(dir, "cmdline"),
+		[]byte(`BOOT_IMAGE=/vmlinuz-5.15.0 root=UUID=1234 ro quiet audit=1 transparent_hugepage=never `+
+			`console=tty0 console=ttyS0,115200 dyndbg="file foo.c +p" nmi-watchdog=0`+"\n"), 0644))
+}
+
+func (s *KernelCmdlineSuite) TearDownSuite() {
+	s.require.NoError(os.RemoveAll(s.tmpDir))
+}
+
+func (s *KernelCmdlineSuite) SetupTest() {
+	s.check = &kernelCmdline{
+		Base:        NewBase("kernel-cmdline", 0),
+		cmdlineFile: filepath.Join(s.tmpDir, "cmdline"),
+	}
+}
+
+func (s *KernelCmdlineSuite) TestValidation() {
+	s.Error(s.check.validate())
+
+	s.check.Parameters = []string{"audit=1"}
+	s.NoError(s.check.validate())
+
+	s.check.Forbidden = []string{"=1"}
+	s.Error(s.check.validate())
+
+	s.check.Parameters = nil
+	s.check.Forbidden = []string{"nosmt"}
+	s.NoError(s.check.validate())
+}
+
+func (s *KernelCmdlineSuite) TestParseCmdline() {
+	params := parseKernelCmdline(`ro dyndbg="file foo.c +p"  root=UUID=1234`)
+	s.require.Len(params, 3)
+	s.Equal(kernelParameter{key: "ro"}, params[0])
+	s.Equal(kernelParameter{key: "dyndbg", value: "file foo.c +p", hasValue: true}, params[1])
+	s.Equal(kernelParameter{key: "root", value: "UUID=1234", hasValue: true}, params[2])
+}
+
+func (s *KernelCmdlineSuite) TestExpectedParametersPass() {
+	s.check.Parameters = []string{"audit=1", "transparent_hugepage=never", "ro",
+		"console=ttyS0,115200", "dyndbg=file foo.c +p", "nmi_watchdog=0"}
+	s.check.Forbidden = []string{"nosmt", "audit=0", "mitigations"}
+	s.check.Run()
+
+	s.True(s.check.Output().Passed)
+	s.NoError(s.check.Error())
+	s.Contains(s.check.Output().Message, "kernel command line: BOOT_IMAGE=/vmlinuz-5.15.0")
+}
+
+func (s *KernelCmdlineSuite) TestMismatchesAreReported() {
+	s.check.Parameters = []string{"audit=0", "mitigations=auto", "quiet"}
+	s.check.Forbidden = []string{"transparent_hugepage", "quiet=1"}
+	s.check.Run()
+
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "missing 'audit=0', missing 'mitigations=auto', "+
+		"forbidden 'transparent_hugepage' is present")
+	s.NotContains(s.check.Error().Error(), "quiet")
+}
+
+func (s *KernelCmdlineSuite) TestMissingFileFails() {
+	s.check.cmdlineFile = filepath.Join(s.tmpDir, "DOES-NOT-EXIST")
+	s.check.Parameters = []string{"audit=1"}
+	s.check.Run()
+
+	s.False(s.check.Output().Passed)
+	s.Error(s.check.Error())
+}
@@ -0,0 +1,163 @@
+package check
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/registry"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	name := "kernel-module"
+	registry.AddJobType(name, func() amboy.Job {
+		return &kernelModule{
+			Loaded:      true,
+			Base:        NewBase(name, 0),
+			modulesFile: "/proc/modules",
+			modprobeDir: "/etc/modprobe.d",
+		}
+	})
+}
+
+// kernelModule checks that a kernel module is (or is not) loaded,
+// and optionally that it is (or is not) blacklisted in the modprobe
+// configuration. A module is blacklisted if there is a "blacklist"
+// directive for it, or if its "install" command is /bin/true or
+// /bin/false. Only supported on Linux.
+type kernelModule struct {
+	ModuleName  string `bson:"name" json:"name" yaml:"name"`
+	Loaded      bool   `bson:"loaded" json:"loaded" yaml:"loaded"`
+	Blacklisted *bool  `bson:"blacklisted" json:"blacklisted" yaml:"blacklisted"`
+	*Base       `bson:"metadata" json:"metadata" yaml:"metadata"`
+	fileReadLimit
+
+	modulesFile string
+	modprobeDir string
+}
+
+func (c *kernelModule) Run() {
+	c.startTask()
+	defer c.MarkComplete()
+
+	if c.ModuleName == "" {
+		c.setState(false)
+		c.AddError(errors.Errorf("no module name specified for '%s' (%s) check",
+			c.ID(), c.Name()))
+		return
+	}
+
+	// the kernel reports module names with underscores, but
+	// accepts dashes and underscores interchangeably.
+	name := normalizeModuleName(c.ModuleName)
+
+	loaded, err := c.isLoaded(name)
+	if err != nil {
+		c.setState(false)
+		c.AddError(err)
+		return
+	}
+
+	msg := []string{fmt.Sprintf("module '%s' is loaded: %t", name, loaded)}
+	var errs []string
+
+	if loaded != c.Loaded {
+		errs = append(errs, fmt.Sprintf("module '%s' loaded state is %t, expected %t",
+			name, loaded, c.Loaded))
+	}
+
+	if c.Blacklisted != nil {
+		blacklisted, err := c.isBlacklisted(name)
+		if err != nil {
+			c.setState(false)
+			c.AddError(err)
+			return
+		}
+
+		msg = append(msg, fmt.Sprintf("blacklisted: %t", blacklisted))
+
+		if blacklisted != *c.Blacklisted {
+			errs = append(errs, fmt.Sprintf("module '%s' blacklisted state is %t, expected %t",
+				name, blacklisted, *c.Blacklisted))
+		}
+	}
+
+	c.setMessage(strings.Join(msg, ", "))
+
+	if len(errs) > 0 {
+		c.setState(false)
+		c.AddError(errors.New(strings.Join(errs, "; ")))
+		return
+	}
+
+	c.setState(true)
+}
+
+func normalizeModuleName(name string) string {
+	return strings.Replace(name, "-", "_", -1)
+}
+
+func (c *kernelModule) isLoaded(name string) (bool, error) {
+	data, err := c.readFile(c.modulesFile)
+	if err != nil {
+		return false, errors.Wrap(err, "problem reading module list")
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) > 0 && fields[0] == name {
+			return true, nil
+		}
+	}
+
+	if err = scanner.Err(); err != nil {
+		return false, errors.Wrapf(err, "problem reading module list '%s'", c.modulesFile)
+	}
+
+	return false, nil
+}
+
+func (c *kernelModule) isBlacklisted(name string) (bool, error) {
+	files, err := ioutil.ReadDir(c.modprobeDir)
+	if os.IsNotExist(err) {
+		return false, nil
+	} else if err != nil {
+		return false, errors.Wrapf(err, "problem reading modprobe config '%s'", c.modprobeDir)
+	}
+
+	for _, info := range files {
+		if info.IsDir() || filepath.Ext(info.Name()) != ".conf" {
+			continue
+		}
+
+		data, err := c.readFile(filepath.Join(c.modprobeDir, info.Name()))
+		if err != nil {
+			return false, errors.Wrap(err, "problem reading modprobe config")
+		}
+
+		for _, line := range strings.Split(string(data), "\n") {
+			fields := strings.Fields(line)
+			if len(fields) < 2 || normalizeModuleName(fields[1]) != name {
+				continue
+			}
+
+			if fields[0] == "blacklist" {
+				return true, nil
+			}
+
+			if fields[0] == "install" && len(fields) > 2 &&
+				(fields[2] == "/bin/true" || fields[2] == "/bin/false") {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,114 @@
+package check
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type KernelModuleSuite struct {
+	tmpDir  string
+	check   *kernelModule
+	require *require.Assertions
+	suite.Suite
+}
+
+func TestKernelModuleSuite(t *testing.T) {
+	suite.Run(t, new(KernelModuleSuite))
+}
+
+func (s *KernelModuleSuite) SetupSuite() {
+	s.require = s.Require()
+
+	dir, err := ioutil.TempDir("", uuid.NewV4().String())
+	s.require.NoError(err)
+	s.tmpDir = dir
+
+	modules := []byte(`ext4 577536 2 - Live 0x0000000000000000
+nf_conntrack 139264 1 - Live 0x0000000000000000
+`)
+	s.require.NoError(ioutil.WriteFile(filepath.Join(dir, "modules"), modules, 0644))
+
+	confDir := filepath.Join(dir, "modprobe.d")
+	s.require.NoError(os.MkdirAll(confDir, 0755))
+	s.require.NoError(ioutil.WriteFile(filepath.Join(confDir, "cis.conf"), []byte(`
+# CIS controls
+install cramfs /bin/true
+blacklist usb-storage
+`), 0644))
+	s.require.NoError(ioutil.WriteFile(filepath.Join(confDir, "ignored.txt"),
+		[]byte("blacklist ext4\n"), 0644))
+}
+
+func (s *KernelModuleSuite) TearDownSuite() {
+	s.require.NoError(os.RemoveAll(s.tmpDir))
+}
+
+func (s *KernelModuleSuite) SetupTest() {
+	s.check = &kernelModule{
+		Loaded:      true,
+		Base:        NewBase("kernel-module", 0),
+		modulesFile: filepath.Join(s.tmpDir, "modules"),
+		modprobeDir: filepath.Join(s.tmpDir, "modprobe.d"),
+	}
+}
+
+func boolPtr(b bool) *bool { return &b }
+
+func (s *KernelModuleSuite) TestCheckWithoutNameFails() {
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.Error(s.check.Error())
+}
+
+func (s *KernelModuleSuite) TestLoadedModule() {
+	s.check.ModuleName = "nf-conntrack"
+	s.check.Run()
+	s.True(s.check.Output().Passed)
+	s.NoError(s.check.Error())
+
+	s.SetupTest()
+	s.check.ModuleName = "ext4"
+	s.check.Loaded = false
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.Error(s.check.Error())
+}
+
+func (s *KernelModuleSuite) TestBlacklistedModules() {
+	for _, name := range []string{"cramfs", "usb_storage"} {
+		s.SetupTest()
+		s.check.ModuleName = name
+		s.check.Loaded = false
+		s.check.Blacklisted = boolPtr(true)
+		s.check.Run()
+		s.True(s.check.Output().Passed, name)
+		s.Contains(s.check.Output().Message, "blacklisted: true")
+	}
+
+	s.SetupTest()
+	s.check.ModuleName = "ext4"
+	s.check.Blacklisted = boolPtr(true)
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.Error(s.check.Error())
+
+	s.SetupTest()
+	s.check.ModuleName = "ext4"
+	s.check.Blacklisted = boolPtr(false)
+	s.check.Run()
+	s.True(s.check.Output().Passed)
+}
+
+func (s *KernelModuleSuite) TestMissingModuleListFails() {
+	s.check.ModuleName = "ext4"
+	s.check.modulesFile = filepath.Join(s.tmpDir, "DOES-NOT-EXIST")
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.Error(s.check.Error())
+}
@@ -0,0 +1,244 @@
+package check
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/registry"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	name := "ldap-bind"
+	registry.AddJobType(name, func() amboy.Job {
+		return &ldapBind{
+			Base: NewBase(name, 0),
+		}
+	})
+}
+
+// ldapBind checks that a simple bind to an LDAP server succeeds. The
+// url uses the "ldap" (port 389 by default) or "ldaps" (port 636)
+// scheme, and start_tls upgrades an "ldap" connection with the
+// StartTLS operation. The optional ca_bundle is a PEM file of
+// certificates to verify the server with, instead of the system
+// roots. When base_dn is set, the check also runs a subtree search
+// for search_filter (which defaults to "(objectClass=*)") and
+// requires expected_count entries, or at least one entry if
+// expected_count is not set. The timeout (a duration, e.g. "5s",
+// which defaults to 10 seconds) limits the entire operation.
+//
+// Connection, bind, and search failures are reported separately.
+type ldapBind struct {
+	URL           string `bson:"url" json:"url" yaml:"url"`
+	BindDN        string `bson:"bind_dn" json:"bind_dn" yaml:"bind_dn"`
+	BindPassword  string `bson:"bind_password" json:"bind_password" yaml:"bind_password"`
+	BaseDN        string `bson:"base_dn" json:"base_dn" yaml:"base_dn"`
+	SearchFilter  string `bson:"search_filter" json:"search_filter" yaml:"search_filter"`
+	ExpectedCount *int   `bson:"expected_count" json:"expected_count" yaml:"expected_count"`
+	StartTLS      bool   `bson:"start_tls" json:"start_tls" yaml:"start_tls"`
+	CABundle      string `bson:"ca_bundle" json:"ca_bundle" yaml:"ca_bundle"`
+	Timeout       string `bson:"timeout" json:"timeout" yaml:"timeout"`
+	*Base         `bson:"metadata" json:"metadata" yaml:"metadata"`
+	fileReadLimit
+
+	addr    string
+	useTLS  bool
+	tls     *tls.Config
+	filter  string
+	timeout time.Duration
+}
+
+func (c *ldapBind) validate() error {
+	if c.URL == "" {
+		return errors.Errorf("no url specified for '%s' (%s) check", c.ID(), c.Name())
+	}
+
+	u, err := url.Parse(c.URL)
+	if err != nil {
+		return errors.Wrapf(err, "url for '%s' check is not valid", c.ID())
+	}
+
+	var port string
+	switch u.Scheme {
+	case "ldap":
+		port = "389"
+	case "ldaps":
+		port = "636"
+		c.useTLS = true
+	default:
+		return errors.Errorf("url '%s' for '%s' check must use the ldap or ldaps scheme", c.URL, c.ID())
+	}
+
+	if u.Hostname() == "" {
+		return errors.Errorf("url '%s' for '%s' check does not have a host", c.URL, c.ID())
+	}
+
+	if u.Port() != "" {
+		port = u.Port()
+	}
+	c.addr = net.JoinHostPort(u.Hostname(), port)
+
+	if c.StartTLS && c.useTLS {
+		return errors.Errorf("cannot use start_tls with an ldaps url for '%s' check", c.ID())
+	}
+
+	if c.BindDN == "" {
+		return errors.Errorf("no bind dn specified for '%s' check", c.ID())
+	}
+
+	if c.SearchFilter != "" && c.BaseDN == "" {
+		return errors.Errorf("search filter for '%s' check requires a base dn", c.ID())
+	}
+
+	if c.ExpectedCount != nil && c.BaseDN == "" {
+		return errors.Errorf("expected count for '%s' check requires a base dn", c.ID())
+	}
+
+	if c.ExpectedCount != nil && *c.ExpectedCount < 0 {
+		return errors.Errorf("expected count for '%s' check cannot be negative", c.ID())
+	}
+
+	if c.BaseDN != "" {
+		filter := c.SearchFilter
+		if filter == "" {
+			filter = "(objectClass=*)"
+		}
+
+		if _, err = ldap.CompileFilter(filter); err != nil {
+			return errors.Wrapf(err, "search filter '%s' for '%s' check is not valid", filter, c.ID())
+		}
+		c.filter = filter
+	}
+
+	c.tls = &tls.Config{ServerName: u.Hostname()}
+	if c.CABundle != "" {
+		data, err := c.readFile(c.CABundle)
+		if err != nil {
+			return errors.Wrapf(err, "problem reading ca bundle for '%s' check", c.ID())
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return errors.Errorf("ca bundle '%s' for '%s' check has no certificates", c.CABundle, c.ID())
+		}
+		c.tls.RootCAs = pool
+	}
+
+	c.timeout = 10 * time.Second
+	if c.Timeout != "" {
+		timeout, err := time.ParseDuration(c.Timeout)
+		if err != nil {
+			return errors.Wrapf(err, "problem parsing timeout for '%s' check", c.ID())
+		}
+		c.timeout = timeout
+	}
+
+	return nil
+}
+
+func (c *ldapBind) Run() {
+	c.startTask()
+	defer c.MarkComplete()
+
+	if err := c.validate(); err != nil {
+		c.setState(false)
+		c.setReason(ReasonInvalidConfig)
+		c.AddError(err)
+		return
+	}
+
+	conn, err := c.connect()
+	if err != nil {
+		c.setState(false)
+		c.AddError(errors.Wrapf(err, "problem connecting to %s", c.URL))
+		return
+	}
+	defer conn.Close()
+
+	if err = conn.Bind(c.BindDN, c.BindPassword); err != nil {
+		c.setState(false)
+		c.AddError(errors.Wrapf(err, "bind to %s as '%s' failed", c.URL, c.BindDN))
+		return
+	}
+
+	if c.BaseDN == "" {
+		c.setMessage(fmt.Sprintf("bound to %s as '%s'", c.URL, c.BindDN))
+		c.setState(true)
+		return
+	}
+
+	// request no attributes ("1.1"), since the check only counts
+	// the entries.
+	result, err := conn.Search(ldap.NewSearchRequest(c.BaseDN, ldap.ScopeWholeSubtree,
+		ldap.NeverDerefAliases, 0, 0, false, c.filter, []string{"1.1"}, nil))
+	if err != nil {
+		c.setState(false)
+		c.AddError(errors.Wrapf(err, "search of '%s' on %s failed", c.BaseDN, c.URL))
+		return
+	}
+	count := len(result.Entries)
+
+	c.setMessage(fmt.Sprintf("bound to %s as '%s', search of '%s' returned %d entries",
+		c.URL, c.BindDN, c.BaseDN, count))
+
+	if c.ExpectedCount != nil && count != *c.ExpectedCount {
+		c.setState(false)
+		c.AddError(errors.Errorf("search of '%s' returned %d entries, expected %d",
+			c.BaseDN, count, *c.ExpectedCount))
+		return
+	}
+
+	if c.ExpectedCount == nil && count == 0 {
+		c.setState(false)
+		c.AddError(errors.Errorf("search of '%s' did not return any entries", c.BaseDN))
+		return
+	}
+
+	c.setState(true)
+}
+
+// connect dials the server, establishes TLS as configured, and
+// returns the connection. The deadline on the network connection
+// limits the entire operation, including the bind and search.
+func (c *ldapBind) connect() (*ldap.Conn, error) {
+	deadline := time.Now().Add(c.timeout)
+
+	raw, err := net.DialTimeout("tcp", c.addr, c.timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = raw.SetDeadline(deadline); err != nil {
+		raw.Close()
+		return nil, err
+	}
+
+	if c.useTLS {
+		tlsConn := tls.Client(raw, c.tls)
+		if err = tlsConn.Handshake(); err != nil {
+			raw.Close()
+			return nil, errors.Wrap(err, "TLS handshake failed")
+		}
+		raw = tlsConn
+	}
+
+	conn := ldap.NewConn(raw, c.useTLS)
+	conn.SetTimeout(c.timeout)
+	conn.Start()
+
+	if c.StartTLS {
+		if err = conn.StartTLS(c.tls); err != nil {
+			conn.Close()
+			return nil, errors.Wrap(err, "StartTLS failed")
+		}
+	}
+
+	return conn, nil
+}
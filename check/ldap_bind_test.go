@@ -0,0 +1,319 @@
+package check
+
+import (
+	"crypto/tls"
+	"encoding/pem"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+	"github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type LDAPBindSuite struct {
+	check    *ldapBind
+	listener net.Listener
+	tls      *tls.Config
+	caBundle string
+	tmpDir   string
+	require  *require.Assertions
+	suite.Suite
+}
+
+func TestLDAPBindSuite(t *testing.T) {
+	suite.Run(t, new(LDAPBindSuite))
+}
+
+func (s *LDAPBindSuite) SetupSuite() {
+	s.require = s.Require()
+
+	tmpDir, err := ioutil.TempDir("", uuid.NewV4().String())
+	s.require.NoError(err)
+	s.tmpDir = tmpDir
+
+	// borrow the certificate for 127.0.0.1 that httptest uses.
+	srv := httptest.NewTLSServer(http.NotFoundHandler())
+	s.tls = &tls.Config{Certificates: srv.TLS.Certificates}
+	s.caBundle = filepath.Join(tmpDir, "ca.pem")
+	s.require.NoError(ioutil.WriteFile(s.caBundle,
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}), 0644))
+	srv.Close()
+
+	s.listener, err = net.Listen("tcp", "127.0.0.1:0")
+	s.require.NoError(err)
+	go s.serve()
+}
+
+func (s *LDAPBindSuite) TearDownSuite() {
+	s.listener.Close()
+	s.NoError(os.RemoveAll(s.tmpDir))
+}
+
+func (s *LDAPBindSuite) SetupTest() {
+	s.check = &ldapBind{
+		Base:         NewBase("ldap-bind", 0),
+		URL:          "ldap://" + s.listener.Addr().String(),
+		BindDN:       "cn=admin,dc=example,dc=com",
+		BindPassword: "secret",
+	}
+}
+
+func (s *LDAPBindSuite) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		go s.handle(conn)
+	}
+}
+
+// handle implements a small directory: binds succeed as
+// "cn=admin,dc=example,dc=com" with the password "secret", and
+// subtree searches of "ou=people,dc=example,dc=com" return two
+// entries.
+func (s *LDAPBindSuite) handle(conn net.Conn) {
+	defer conn.Close()
+
+	bound := false
+
+	for {
+		msg, err := ber.ReadPacket(conn)
+		if err != nil || len(msg.Children) < 2 {
+			return
+		}
+
+		id := msg.Children[0].Value
+		op := msg.Children[1]
+
+		respond := func(tag ber.Tag, code int, children ...*ber.Packet) {
+			if children == nil {
+				children = []*ber.Packet{
+					ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, code, ""),
+					ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", ""),
+					ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", ""),
+				}
+			}
+
+			response := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "")
+			for _, child := range children {
+				response.AppendChild(child)
+			}
+
+			envelope := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "")
+			envelope.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, id, ""))
+			envelope.AppendChild(response)
+			_, _ = conn.Write(envelope.Bytes())
+		}
+
+		switch op.Tag {
+		case ldap.ApplicationUnbindRequest:
+			return
+		case ldap.ApplicationExtendedRequest:
+			respond(ldap.ApplicationExtendedResponse, 0)
+			tlsConn := tls.Server(conn, s.tls)
+			if tlsConn.Handshake() != nil {
+				return
+			}
+			conn = tlsConn
+		case ldap.ApplicationBindRequest:
+			if len(op.Children) < 3 {
+				return
+			}
+
+			dn, password := op.Children[1].Data.String(), op.Children[2].Data.String()
+			if dn == "cn=admin,dc=example,dc=com" && password == "secret" {
+				bound = true
+				respond(ldap.ApplicationBindResponse, 0)
+			} else {
+				respond(ldap.ApplicationBindResponse, ldap.LDAPResultInvalidCredentials)
+			}
+		case ldap.ApplicationSearchRequest:
+			if len(op.Children) < 1 {
+				return
+			}
+
+			base := op.Children[0].Data.String()
+			switch {
+			case !bound:
+				respond(ldap.ApplicationSearchResultDone, ldap.LDAPResultInsufficientAccessRights)
+			case base == "ou=people,dc=example,dc=com":
+				for _, dn := range []string{"uid=alice", "uid=bob"} {
+					respond(ldap.ApplicationSearchResultEntry, 0,
+						ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, dn+","+base, ""),
+						ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, ""))
+				}
+				respond(ldap.ApplicationSearchResultDone, 0)
+			default:
+				respond(ldap.ApplicationSearchResultDone, ldap.LDAPResultNoSuchObject)
+			}
+		}
+	}
+}
+
+func (s *LDAPBindSuite) TestValidation() {
+	s.NoError(s.check.validate())
+	s.Equal(s.listener.Addr().String(), s.check.addr)
+
+	s.check.URL = "ldaps://ldap.example.com"
+	s.NoError(s.check.validate())
+	s.Equal("ldap.example.com:636", s.check.addr)
+
+	s.check.StartTLS = true
+	s.Error(s.check.validate())
+
+	s.check.URL = "http://ldap.example.com"
+	s.Error(s.check.validate())
+
+	s.SetupTest()
+	s.check.BindDN = ""
+	s.Error(s.check.validate())
+
+	s.SetupTest()
+	s.check.SearchFilter = "(uid=*)"
+	s.Error(s.check.validate())
+
+	s.check.BaseDN = "dc=example,dc=com"
+	s.NoError(s.check.validate())
+
+	s.check.SearchFilter = "uid=*"
+	s.Error(s.check.validate())
+
+	s.SetupTest()
+	s.check.Timeout = "soon"
+	s.Error(s.check.validate())
+
+	s.SetupTest()
+	s.check.CABundle = filepath.Join(s.tmpDir, "does-not-exist")
+	s.Error(s.check.validate())
+}
+
+func (s *LDAPBindSuite) TestSearchFilterValidation() {
+	s.check.BaseDN = "dc=example,dc=com"
+
+	for _, filter := range []string{
+		"(cn=foo)",
+		"(cn=*)",
+		`(cn=a\2ab)`,
+		"(&(objectClass=person)(|(uid=a*)(mail=*@example.com))(!(uidNumber<=100)))",
+		"(cn~=smith)",
+		"(cn=*mid*dle*)",
+	} {
+		s.check.SearchFilter = filter
+		s.NoError(s.check.validate(), filter)
+		s.Equal(filter, s.check.filter)
+	}
+
+	for _, filter := range []string{"cn=foo", "(cn=foo", "(cn=foo))", `(cn=\4)`} {
+		s.check.SearchFilter = filter
+		s.Error(s.check.validate(), filter)
+	}
+}
+
+func (s *LDAPBindSuite) TestBindSucceeds() {
+	s.check.Run()
+	s.True(s.check.Output().Passed)
+	s.NoError(s.check.Error())
+}
+
+func (s *LDAPBindSuite) TestBindWithStartTLS() {
+	s.check.StartTLS = true
+	s.check.CABundle = s.caBundle
+	s.check.Run()
+	s.True(s.check.Output().Passed)
+	s.NoError(s.check.Error())
+
+	s.SetupTest()
+	s.check.StartTLS = true
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "problem connecting")
+}
+
+func (s *LDAPBindSuite) TestFailuresAreDistinct() {
+	s.check.BindPassword = "wrong"
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "bind to")
+	s.Contains(s.check.Error().Error(), "Invalid Credentials")
+
+	s.SetupTest()
+	s.check.BaseDN = "ou=groups,dc=example,dc=com"
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "search of 'ou=groups,dc=example,dc=com'")
+	s.Contains(s.check.Error().Error(), "No Such Object")
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	s.require.NoError(err)
+	addr := l.Addr().String()
+	s.require.NoError(l.Close())
+
+	s.SetupTest()
+	s.check.URL = "ldap://" + addr
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "problem connecting")
+}
+
+func (s *LDAPBindSuite) TestSearchCount() {
+	s.check.BaseDN = "ou=people,dc=example,dc=com"
+	s.check.SearchFilter = "(objectClass=person)"
+	s.check.Run()
+	s.True(s.check.Output().Passed)
+	s.NoError(s.check.Error())
+	s.True(strings.HasSuffix(s.check.Output().Message, "returned 2 entries"))
+
+	s.SetupTest()
+	s.check.BaseDN = "ou=people,dc=example,dc=com"
+	s.check.ExpectedCount = intPtr(3)
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "returned 2 entries, expected 3")
+}
+
+func (s *LDAPBindSuite) TestMalformedResponsesFail() {
+	for _, response := range [][]byte{
+		{0x30},
+		{0x30, 0x84, 0xff, 0xff, 0xff, 0xff},
+		{0x30, 0x05, 0x02, 0x01, 0x01, 0x61, 0x10},
+		[]byte("not ldap at all"),
+	} {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		s.require.NoError(err)
+
+		go func(response []byte) {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+
+			_, _ = ber.ReadPacket(conn)
+			_, _ = conn.Write(response)
+		}(response)
+
+		s.SetupTest()
+		s.check.URL = "ldap://" + l.Addr().String()
+		s.check.Timeout = "2s"
+		s.check.Run()
+		s.False(s.check.Output().Passed, "%x", response)
+		s.Error(s.check.Error(), "%x", response)
+		s.NoError(l.Close())
+	}
+}
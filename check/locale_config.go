@@ -0,0 +1,176 @@
+package check
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/registry"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	name := "locale-config"
+	registry.AddJobType(name, func() amboy.Job {
+		return &localeConfig{
+			Base:         NewBase(name, 0),
+			timezoneFile: "/etc/timezone",
+			localtime:    "/etc/localtime",
+			localeFiles:  []string{"/etc/default/locale", "/etc/locale.conf"},
+			getenv:       os.Getenv,
+		}
+	})
+}
+
+// localeConfig checks the system timezone and locale. The timezone
+// comes from /etc/timezone or, if that does not exist, from the
+// target of the /etc/localtime symbolic link, and matches the
+// expected_timezone (e.g. "UTC") with or without an "Etc/" prefix.
+// The locale is the LANG setting in /etc/default/locale or
+// /etc/locale.conf or, if neither sets it, the LANG of the greenbay
+// process, and matches the expected_locale (e.g. "en_US.UTF-8")
+// regardless of how the codeset is spelled (e.g. "en_US.utf8").
+type localeConfig struct {
+	ExpectedTimezone string `bson:"expected_timezone" json:"expected_timezone" yaml:"expected_timezone"`
+	ExpectedLocale   string `bson:"expected_locale" json:"expected_locale" yaml:"expected_locale"`
+	*Base            `bson:"metadata" json:"metadata" yaml:"metadata"`
+	fileReadLimit
+
+	timezoneFile string
+	localtime    string
+	localeFiles  []string
+	getenv       func(string) string
+}
+
+func (c *localeConfig) validate() error {
+	if c.ExpectedTimezone == "" && c.ExpectedLocale == "" {
+		return errors.Errorf("no expected timezone or locale specified for '%s' (%s) check",
+			c.ID(), c.Name())
+	}
+
+	return nil
+}
+
+func (c *localeConfig) Run() {
+	c.startTask()
+	defer c.MarkComplete()
+
+	if err := c.validate(); err != nil {
+		c.setState(false)
+		c.setReason(ReasonInvalidConfig)
+		c.AddError(err)
+		return
+	}
+
+	var msg []string
+	var problems []string
+
+	if c.ExpectedTimezone != "" {
+		tz, source, err := c.timezone()
+		if err != nil {
+			c.setState(false)
+			c.AddError(err)
+			return
+		}
+
+		msg = append(msg, fmt.Sprintf("timezone=%s (from %s)", tz, source))
+		if normalizeTimezone(tz) != normalizeTimezone(c.ExpectedTimezone) {
+			problems = append(problems, fmt.Sprintf("timezone is '%s', expected '%s'", tz, c.ExpectedTimezone))
+		}
+	}
+
+	if c.ExpectedLocale != "" {
+		locale, source, err := c.locale()
+		if err != nil {
+			c.setMessage(msg)
+			c.setState(false)
+			c.AddError(err)
+			return
+		}
+
+		msg = append(msg, fmt.Sprintf("locale=%s (from %s)", locale, source))
+		if normalizeLocale(locale) != normalizeLocale(c.ExpectedLocale) {
+			problems = append(problems, fmt.Sprintf("locale is '%s', expected '%s'", locale, c.ExpectedLocale))
+		}
+	}
+
+	c.setMessage(msg)
+
+	if len(problems) > 0 {
+		c.setState(false)
+		c.AddError(errors.New(strings.Join(problems, "; ")))
+		return
+	}
+
+	c.setState(true)
+}
+
+// timezone returns the name of the system timezone and where it was
+// found.
+func (c *localeConfig) timezone() (string, string, error) {
+	if data, err := c.readFile(c.timezoneFile); err == nil {
+		if tz := strings.TrimSpace(strings.SplitN(string(data), "\n", 2)[0]); tz != "" {
+			return tz, c.timezoneFile, nil
+		}
+	}
+
+	target, err := os.Readlink(c.localtime)
+	if err != nil {
+		return "", "", errors.Wrapf(err, "problem finding the timezone from '%s'", c.localtime)
+	}
+
+	idx := strings.LastIndex(target, "zoneinfo/")
+	if idx < 0 {
+		return "", "", errors.Errorf("'%s' links to '%s', which is not in a zoneinfo directory",
+			c.localtime, target)
+	}
+
+	tz := target[idx+len("zoneinfo/"):]
+	for _, prefix := range []string{"posix/", "right/"} {
+		tz = strings.TrimPrefix(tz, prefix)
+	}
+
+	return tz, c.localtime, nil
+}
+
+// locale returns the system locale and where it was found.
+func (c *localeConfig) locale() (string, string, error) {
+	for _, fn := range c.localeFiles {
+		data, err := c.readFile(fn)
+		if err != nil {
+			continue
+		}
+
+		if lang := parseEnvFile(data)["LANG"]; lang != "" {
+			return lang, fn, nil
+		}
+	}
+
+	if lang := c.getenv("LANG"); lang != "" {
+		return lang, "the LANG environment variable", nil
+	}
+
+	return "", "", errors.Errorf("no locale is set in [%s] or the environment",
+		strings.Join(c.localeFiles, ", "))
+}
+
+func normalizeTimezone(tz string) string {
+	return strings.TrimPrefix(tz, "Etc/")
+}
+
+// normalizeLocale returns the locale with a lower case codeset without
+// dashes, so that "en_US.UTF-8" and "en_US.utf8" are the same.
+func normalizeLocale(locale string) string {
+	modifier := ""
+	if idx := strings.Index(locale, "@"); idx >= 0 {
+		locale, modifier = locale[:idx], locale[idx:]
+	}
+
+	parts := strings.SplitN(locale, ".", 2)
+	if len(parts) == 2 {
+		parts[1] = strings.Replace(strings.ToLower(parts[1]), "-", "", -1)
+	}
+
+	return strings.Join(parts, ".") + modifier
+}
@@ -0,0 +1,129 @@
+package check
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type LocaleConfigSuite struct {
+	tmpDir  string
+	env     map[string]string
+	check   *localeConfig
+	require *require.Assertions
+	suite.Suite
+}
+
+func TestLocaleConfigSuite(t *testing.T) {
+	suite.Run(t, new(LocaleConfigSuite))
+}
+
+func (s *LocaleConfigSuite) SetupSuite() {
+	s.require = s.Require()
+
+	dir, err := ioutil.TempDir("", uuid.NewV4().String())
+	s.require.NoError(err)
+	s.tmpDir = dir
+
+	s.require.NoError(ioutil.WriteFile(filepath.Join(dir, "timezone"), []byte("America/New_York\n"), 0644))
+	s.require.NoError(os.Symlink("/usr/share/zoneinfo/Etc/UTC", filepath.Join(dir, "localtime")))
+	s.require.NoError(os.Symlink("/usr/share/zoneinfo/posix/Europe/Berlin", filepath.Join(dir, "localtime-posix")))
+	s.require.NoError(os.Symlink("/opt/zones/UTC", filepath.Join(dir, "localtime-elsewhere")))
+	s.require.NoError(ioutil.WriteFile(filepath.Join(dir, "locale"),
+		[]byte("# generated by localectl\nLANG=\"en_US.utf8\"\nLC_TIME=en_GB.UTF-8\n"), 0644))
+}
+
+func (s *LocaleConfigSuite) TearDownSuite() {
+	s.require.NoError(os.RemoveAll(s.tmpDir))
+}
+
+func (s *LocaleConfigSuite) SetupTest() {
+	s.env = map[string]string{}
+	s.check = &localeConfig{
+		Base:         NewBase("locale-config", 0),
+		timezoneFile: filepath.Join(s.tmpDir, "timezone"),
+		localtime:    filepath.Join(s.tmpDir, "localtime"),
+		localeFiles:  []string{filepath.Join(s.tmpDir, "does-not-exist"), filepath.Join(s.tmpDir, "locale")},
+		getenv:       func(key string) string { return s.env[key] },
+	}
+}
+
+func (s *LocaleConfigSuite) TestValidation() {
+	s.Error(s.check.validate())
+
+	s.check.ExpectedTimezone = "UTC"
+	s.NoError(s.check.validate())
+
+	s.check.ExpectedTimezone = ""
+	s.check.ExpectedLocale = "en_US.UTF-8"
+	s.NoError(s.check.validate())
+}
+
+func (s *LocaleConfigSuite) TestTimezone() {
+	s.check.ExpectedTimezone = "America/New_York"
+	s.check.Run()
+	s.True(s.check.Output().Passed)
+	s.NoError(s.check.Error())
+
+	for localtime, expected := range map[string]string{
+		"localtime":       "UTC",
+		"localtime-posix": "Europe/Berlin",
+	} {
+		s.SetupTest()
+		s.check.timezoneFile = filepath.Join(s.tmpDir, "does-not-exist")
+		s.check.localtime = filepath.Join(s.tmpDir, localtime)
+		s.check.ExpectedTimezone = expected
+		s.check.Run()
+		s.True(s.check.Output().Passed, localtime)
+		s.NoError(s.check.Error(), localtime)
+	}
+
+	s.SetupTest()
+	s.check.timezoneFile = filepath.Join(s.tmpDir, "does-not-exist")
+	s.check.localtime = filepath.Join(s.tmpDir, "localtime-elsewhere")
+	s.check.ExpectedTimezone = "UTC"
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "not in a zoneinfo directory")
+}
+
+func (s *LocaleConfigSuite) TestLocale() {
+	s.check.ExpectedLocale = "en_US.UTF-8"
+	s.check.Run()
+	s.True(s.check.Output().Passed)
+	s.NoError(s.check.Error())
+	s.Contains(s.check.Output().Message, "locale=en_US.utf8")
+
+	s.SetupTest()
+	s.check.localeFiles = nil
+	s.env["LANG"] = "C.UTF-8"
+	s.check.ExpectedLocale = "C.utf8"
+	s.check.Run()
+	s.True(s.check.Output().Passed)
+	s.NoError(s.check.Error())
+	s.Contains(s.check.Output().Message, "LANG environment variable")
+
+	s.SetupTest()
+	s.check.localeFiles = nil
+	s.check.ExpectedLocale = "en_US.UTF-8"
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.Error(s.check.Error())
+}
+
+func (s *LocaleConfigSuite) TestMismatchReportsActualValues() {
+	s.check.ExpectedTimezone = "UTC"
+	s.check.ExpectedLocale = "de_DE.UTF-8"
+	s.check.Run()
+
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "timezone is 'America/New_York', expected 'UTC'")
+	s.Contains(s.check.Error().Error(), "locale is 'en_US.utf8', expected 'de_DE.UTF-8'")
+}
@@ -0,0 +1,155 @@
+package check
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/registry"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	name := "log-rotation"
+	registry.AddJobType(name, func() amboy.Job {
+		return &logRotation{
+			Base: NewBase(name, 0),
+		}
+	})
+}
+
+// logRotation checks that an active log file is not larger than a
+// maximum size, and that it has been rotated recently, as evidenced
+// by the modification time of the most recent rotated archive
+// (e.g. "app.log.1", "app.log.2.gz", or "app.log-20160601") in the
+// same directory. The modification time of the active log only
+// shows when it was last written, so with a max age, the check fails
+// when there are no rotated archives.
+type logRotation struct {
+	LogPath        string `bson:"log_path" json:"log_path" yaml:"log_path"`
+	MaxSizeBytes   int64  `bson:"max_size_bytes" json:"max_size_bytes" yaml:"max_size_bytes"`
+	MaxAge         string `bson:"max_age" json:"max_age" yaml:"max_age"`
+	RequireArchive bool   `bson:"require_archive" json:"require_archive" yaml:"require_archive"`
+	*Base          `bson:"metadata" json:"metadata" yaml:"metadata"`
+
+	maxAge time.Duration
+}
+
+func (c *logRotation) validate() error {
+	if c.LogPath == "" {
+		return errors.Errorf("no log path specified for '%s' (%s) check", c.ID(), c.Name())
+	}
+
+	if c.MaxSizeBytes < 0 {
+		return errors.Errorf("max size for '%s' check cannot be negative", c.ID())
+	}
+
+	if c.MaxAge != "" {
+		age, err := time.ParseDuration(c.MaxAge)
+		if err != nil {
+			return errors.Wrapf(err, "problem parsing max age for '%s' check", c.ID())
+		}
+		c.maxAge = age
+	}
+
+	return nil
+}
+
+func (c *logRotation) Run() {
+	c.startTask()
+	defer c.MarkComplete()
+
+	if err := c.validate(); err != nil {
+		c.setState(false)
+		c.setReason(ReasonInvalidConfig)
+		c.AddError(err)
+		return
+	}
+
+	stat, err := os.Stat(c.LogPath)
+	if err != nil {
+		c.setState(false)
+		c.AddError(errors.Wrapf(err, "problem finding log file '%s'", c.LogPath))
+		return
+	}
+
+	archives, err := c.findArchives()
+	if err != nil {
+		c.setState(false)
+		c.AddError(err)
+		return
+	}
+
+	msg := []string{fmt.Sprintf("log '%s' is %d bytes, with %d rotated archives",
+		c.LogPath, stat.Size(), len(archives))}
+
+	var lastRotation time.Time
+	for _, archive := range archives {
+		if archive.ModTime().After(lastRotation) {
+			lastRotation = archive.ModTime()
+		}
+	}
+
+	if !lastRotation.IsZero() {
+		msg = append(msg, fmt.Sprintf("last rotated at %s (%s ago)", lastRotation,
+			time.Since(lastRotation)))
+	}
+
+	c.setState(true)
+
+	if c.MaxSizeBytes > 0 && stat.Size() > c.MaxSizeBytes {
+		c.setState(false)
+		c.AddError(errors.Errorf("log '%s' is %d bytes, which is larger than the %d byte limit",
+			c.LogPath, stat.Size(), c.MaxSizeBytes))
+	}
+
+	if c.RequireArchive && len(archives) == 0 {
+		c.setState(false)
+		c.AddError(errors.Errorf("no rotated archives of log '%s' exist", c.LogPath))
+	}
+
+	if c.maxAge > 0 && !lastRotation.IsZero() && time.Since(lastRotation) > c.maxAge {
+		c.setState(false)
+		c.AddError(errors.Errorf("log '%s' has not been rotated in the last %s",
+			c.LogPath, c.maxAge))
+	}
+
+	if c.maxAge > 0 && lastRotation.IsZero() {
+		c.setState(false)
+		c.AddError(errors.Errorf("no rotated archive of log '%s' exists to check the max age of %s",
+			c.LogPath, c.maxAge))
+	}
+
+	c.setMessage(strings.Join(msg, "; "))
+}
+
+func (c *logRotation) findArchives() ([]os.FileInfo, error) {
+	dir, base := filepath.Split(c.LogPath)
+	if dir == "" {
+		dir = "."
+	}
+
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem reading directory of log '%s'", c.LogPath)
+	}
+
+	var archives []os.FileInfo
+	for _, info := range files {
+		name := info.Name()
+		if info.IsDir() || name == base || !strings.HasPrefix(name, base) {
+			continue
+		}
+
+		suffix := name[len(base):]
+		if strings.HasPrefix(suffix, ".") || strings.HasPrefix(suffix, "-") {
+			archives = append(archives, info)
+		}
+	}
+
+	return archives, nil
+}
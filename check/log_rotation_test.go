@@ -0,0 +1,135 @@
+package check
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type LogRotationSuite struct {
+	tmpDir  string
+	check   *logRotation
+	require *require.Assertions
+	suite.Suite
+}
+
+func TestLogRotationSuite(t *testing.T) {
+	suite.Run(t, new(LogRotationSuite))
+}
+
+func (s *LogRotationSuite) SetupTest() {
+	s.require = s.Require()
+
+	dir, err := ioutil.TempDir("", uuid.NewV4().String())
+	s.require.NoError(err)
+	s.tmpDir = dir
+
+	s.require.NoError(ioutil.WriteFile(filepath.Join(dir, "app.log"), []byte("0123456789"), 0644))
+
+	s.check = &logRotation{
+		LogPath: filepath.Join(dir, "app.log"),
+		Base:    NewBase("log-rotation", 0),
+	}
+}
+
+func (s *LogRotationSuite) TearDownTest() {
+	s.require.NoError(os.RemoveAll(s.tmpDir))
+}
+
+func (s *LogRotationSuite) addArchive(name string, age time.Duration) {
+	fn := filepath.Join(s.tmpDir, name)
+	s.require.NoError(ioutil.WriteFile(fn, []byte("old"), 0644))
+
+	mtime := time.Now().Add(-age)
+	s.require.NoError(os.Chtimes(fn, mtime, mtime))
+}
+
+func (s *LogRotationSuite) TestValidation() {
+	s.NoError(s.check.validate())
+
+	s.check.MaxAge = "not-a-duration"
+	s.Error(s.check.validate())
+
+	s.check.MaxAge = "24h"
+	s.NoError(s.check.validate())
+	s.Equal(24*time.Hour, s.check.maxAge)
+
+	s.check.MaxSizeBytes = -1
+	s.Error(s.check.validate())
+
+	s.check.MaxSizeBytes = 0
+	s.check.LogPath = ""
+	s.Error(s.check.validate())
+}
+
+func (s *LogRotationSuite) TestMissingLogFails() {
+	s.check.LogPath = filepath.Join(s.tmpDir, "missing.log")
+	s.check.Run()
+	output := s.check.Output()
+	s.False(output.Passed)
+	s.Error(s.check.Error())
+}
+
+func (s *LogRotationSuite) TestLogWithinLimitsPasses() {
+	s.check.MaxSizeBytes = 100
+	s.check.Run()
+	output := s.check.Output()
+	s.True(output.Passed)
+	s.NoError(s.check.Error())
+}
+
+func (s *LogRotationSuite) TestOversizedLogFails() {
+	s.check.MaxSizeBytes = 5
+	s.check.Run()
+	output := s.check.Output()
+	s.False(output.Passed)
+	s.Error(s.check.Error())
+}
+
+func (s *LogRotationSuite) TestRequireArchive() {
+	s.check.RequireArchive = true
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+
+	s.check = &logRotation{
+		LogPath:        s.check.LogPath,
+		RequireArchive: true,
+		Base:           NewBase("log-rotation", 0),
+	}
+	s.addArchive("app.log.1.gz", time.Hour)
+	s.addArchive("app.logger", time.Hour)
+	s.check.Run()
+	s.True(s.check.Output().Passed)
+	s.Contains(s.check.Output().Message, "1 rotated archives")
+}
+
+func (s *LogRotationSuite) TestRecentRotationPasses() {
+	s.check.MaxAge = "24h"
+	s.addArchive("app.log.2", 72*time.Hour)
+	s.addArchive("app.log-20160601", time.Hour)
+	s.check.Run()
+	s.True(s.check.Output().Passed)
+	s.NoError(s.check.Error())
+}
+
+func (s *LogRotationSuite) TestStaleRotationFails() {
+	s.check.MaxAge = "24h"
+	s.addArchive("app.log.1", 72*time.Hour)
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.Error(s.check.Error())
+}
+
+func (s *LogRotationSuite) TestMaxAgeWithoutArchivesFails() {
+	s.check.MaxAge = "24h"
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "no rotated archive")
+}
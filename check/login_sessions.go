@@ -0,0 +1,148 @@
+package check
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/registry"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	name := "login-sessions"
+	registry.AddJobType(name, func() amboy.Job {
+		return &loginSessions{
+			Base: NewBase(name, 0),
+			run: func(command string, args ...string) ([]byte, error) {
+				return exec.Command(command, args...).Output()
+			},
+		}
+	})
+}
+
+// loginSessions checks the interactive login sessions on the host, as
+// reported by "who", which reads utmp. The check fails if there are
+// more than max sessions (e.g. 0 for hosts that should never have
+// interactive logins), or, when allowed_users is set, if any user not
+// in that list is logged in.
+type loginSessions struct {
+	Max          *int     `bson:"max" json:"max" yaml:"max"`
+	AllowedUsers []string `bson:"allowed_users" json:"allowed_users" yaml:"allowed_users"`
+	*Base        `bson:"metadata" json:"metadata" yaml:"metadata"`
+
+	run func(command string, args ...string) ([]byte, error)
+}
+
+// loginSession is a session from the output of "who", which has the
+// user and terminal followed by the login time and, for remote
+// sessions, the host in parentheses.
+type loginSession struct {
+	user    string
+	line    string
+	details string
+}
+
+func (s loginSession) String() string {
+	return strings.TrimSpace(fmt.Sprintf("%s on %s %s", s.user, s.line, s.details))
+}
+
+func (c *loginSessions) validate() error {
+	if c.Max == nil && len(c.AllowedUsers) == 0 {
+		return errors.Errorf("no max or allowed users specified for '%s' (%s) check", c.ID(), c.Name())
+	}
+
+	if c.Max != nil && *c.Max < 0 {
+		return errors.Errorf("max for '%s' check cannot be negative", c.ID())
+	}
+
+	return nil
+}
+
+func (c *loginSessions) Run() {
+	c.startTask()
+	defer c.MarkComplete()
+
+	if err := c.validate(); err != nil {
+		c.setState(false)
+		c.setReason(ReasonInvalidConfig)
+		c.AddError(err)
+		return
+	}
+
+	out, err := c.run("who")
+	if err != nil {
+		c.setState(false)
+		c.AddError(errors.Wrap(err, "problem listing login sessions"))
+		return
+	}
+
+	sessions := parseWho(out)
+
+	messages := []string{fmt.Sprintf("%d login session(s)", len(sessions))}
+	for _, s := range sessions {
+		messages = append(messages, s.String())
+	}
+	c.setMessage(messages)
+
+	var problems []string
+
+	if c.Max != nil && len(sessions) > *c.Max {
+		problems = append(problems, fmt.Sprintf("%d session(s) is more than the max of %d",
+			len(sessions), *c.Max))
+	}
+
+	if len(c.AllowedUsers) > 0 {
+		allowed := make(map[string]struct{}, len(c.AllowedUsers))
+		for _, user := range c.AllowedUsers {
+			allowed[user] = struct{}{}
+		}
+
+		seen := make(map[string]struct{})
+		var users []string
+		for _, s := range sessions {
+			if _, ok := allowed[s.user]; ok {
+				continue
+			}
+
+			if _, ok := seen[s.user]; !ok {
+				seen[s.user] = struct{}{}
+				users = append(users, s.user)
+			}
+		}
+
+		if len(users) > 0 {
+			problems = append(problems, fmt.Sprintf("users that are not allowed are logged in: %s",
+				strings.Join(users, ", ")))
+		}
+	}
+
+	if len(problems) > 0 {
+		c.setState(false)
+		c.AddError(errors.Errorf("login sessions are not as expected: %s", strings.Join(problems, ", ")))
+		return
+	}
+
+	c.setState(true)
+}
+
+// parseWho parses the output of "who".
+func parseWho(out []byte) []loginSession {
+	var sessions []loginSession
+
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+
+		sessions = append(sessions, loginSession{
+			user:    fields[0],
+			line:    fields[1],
+			details: strings.Join(fields[2:], " "),
+		})
+	}
+
+	return sessions
+}
@@ -0,0 +1,108 @@
+package check
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type LoginSessionsSuite struct {
+	output  string
+	check   *loginSessions
+	require *require.Assertions
+	suite.Suite
+}
+
+func TestLoginSessionsSuite(t *testing.T) {
+	suite.Run(t, new(LoginSessionsSuite))
+}
+
+func (s *LoginSessionsSuite) SetupSuite() {
+	s.require = s.Require()
+}
+
+func (s *LoginSessionsSuite) SetupTest() {
+	s.output = `deploy   pts/0        2016-06-01 10:02 (10.0.0.12)
+alice    pts/1        2016-06-01 11:30 (vpn.example.net)
+alice    pts/2        2016-06-01 11:31 (vpn.example.net)
+`
+	s.check = &loginSessions{
+		Base: NewBase("login-sessions", 0),
+		run: func(command string, args ...string) ([]byte, error) {
+			s.require.Equal("who", command)
+			return []byte(s.output), nil
+		},
+	}
+}
+
+func (s *LoginSessionsSuite) TestValidation() {
+	s.Error(s.check.validate())
+
+	max := -1
+	s.check.Max = &max
+	s.Error(s.check.validate())
+
+	max = 0
+	s.NoError(s.check.validate())
+
+	s.check.Max = nil
+	s.check.AllowedUsers = []string{"deploy"}
+	s.NoError(s.check.validate())
+}
+
+func (s *LoginSessionsSuite) TestParseWho() {
+	sessions := parseWho([]byte(s.output + "\n"))
+	s.require.Len(sessions, 3)
+	s.Equal("deploy", sessions[0].user)
+	s.Equal("pts/0", sessions[0].line)
+	s.Equal("deploy on pts/0 2016-06-01 10:02 (10.0.0.12)", sessions[0].String())
+}
+
+func (s *LoginSessionsSuite) TestNoSessionsAllowed() {
+	max := 0
+	s.check.Max = &max
+	s.check.Run()
+
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "3 session(s) is more than the max of 0")
+	s.Contains(s.check.Output().Message, "3 login session(s)\ndeploy on pts/0")
+
+	s.SetupTest()
+	s.output = ""
+	s.check.Max = &max
+	s.check.Run()
+	s.True(s.check.Output().Passed)
+	s.NoError(s.check.Error())
+	s.Equal("0 login session(s)", s.check.Output().Message)
+}
+
+func (s *LoginSessionsSuite) TestAllowedUsers() {
+	s.check.AllowedUsers = []string{"deploy"}
+	s.check.Run()
+
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "users that are not allowed are logged in: alice")
+
+	s.SetupTest()
+	max := 3
+	s.check.Max = &max
+	s.check.AllowedUsers = []string{"deploy", "alice"}
+	s.check.Run()
+	s.True(s.check.Output().Passed)
+}
+
+func (s *LoginSessionsSuite) TestWhoFailureFails() {
+	s.check.AllowedUsers = []string{"deploy"}
+	s.check.run = func(command string, args ...string) ([]byte, error) {
+		return nil, errors.New("exit status 1")
+	}
+	s.check.Run()
+
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "problem listing login sessions")
+}
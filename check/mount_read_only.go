@@ -0,0 +1,177 @@
+package check
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/registry"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	name := "mount-read-only"
+	registry.AddJobType(name, func() amboy.Job {
+		return &mountReadOnly{
+			Base:       NewBase(name, 0),
+			Expected:   "ro",
+			mountsFile: "/proc/mounts",
+		}
+	})
+}
+
+// mountReadOnly checks that the filesystem that contains a path is
+// mounted read-only ("ro", the default) or read-write ("rw"),
+// according to the current mounts in /proc/mounts. The path does not
+// need to be a mount point: the check uses the mount with the longest
+// mount point that contains the path.
+type mountReadOnly struct {
+	Path     string `bson:"path" json:"path" yaml:"path"`
+	Expected string `bson:"expected" json:"expected" yaml:"expected"`
+	*Base    `bson:"metadata" json:"metadata" yaml:"metadata"`
+	fileReadLimit
+
+	mountsFile string
+}
+
+// mountLine is a single entry from /proc/mounts.
+type mountLine struct {
+	device     string
+	mountPoint string
+	fsType     string
+	options    []string
+}
+
+func (c *mountReadOnly) validate() error {
+	if c.Path == "" {
+		return errors.Errorf("no path specified for '%s' (%s) check", c.ID(), c.Name())
+	}
+
+	if c.Expected != "ro" && c.Expected != "rw" {
+		return errors.Errorf("expected state '%s' for '%s' check must be 'ro' or 'rw'", c.Expected, c.ID())
+	}
+
+	return nil
+}
+
+func (c *mountReadOnly) Run() {
+	c.startTask()
+	defer c.MarkComplete()
+
+	if err := c.validate(); err != nil {
+		c.setState(false)
+		c.setReason(ReasonInvalidConfig)
+		c.AddError(err)
+		return
+	}
+
+	path := filepath.Clean(c.Path)
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+
+	mounts, err := c.readMounts()
+	if err != nil {
+		c.setState(false)
+		c.AddError(err)
+		return
+	}
+
+	mount := findMount(mounts, path)
+	if mount == nil {
+		c.setState(false)
+		c.AddError(errors.Errorf("no mount in %s contains '%s'", c.mountsFile, c.Path))
+		return
+	}
+
+	state := "rw"
+	if stringSliceContains(mount.options, "ro") {
+		state = "ro"
+	}
+
+	c.setMessage(fmt.Sprintf("'%s' is on %s (%s, %s), which is mounted %s",
+		c.Path, mount.mountPoint, mount.device, mount.fsType, state))
+
+	if state != c.Expected {
+		c.setState(false)
+		c.AddError(errors.Errorf("'%s' is on %s, which is mounted %s, not %s [options=%s]",
+			c.Path, mount.mountPoint, state, c.Expected, strings.Join(mount.options, ",")))
+		return
+	}
+
+	c.setState(true)
+}
+
+// findMount returns the mount with the longest mount point that
+// contains the path. When several mounts have the same mount point,
+// the last one, which hides the others, wins.
+func findMount(mounts []mountLine, path string) *mountLine {
+	var found *mountLine
+	for idx := range mounts {
+		mp := mounts[idx].mountPoint
+		if mp != "/" && path != mp && !strings.HasPrefix(path, mp+"/") {
+			continue
+		}
+
+		if found == nil || len(mp) >= len(found.mountPoint) {
+			found = &mounts[idx]
+		}
+	}
+
+	return found
+}
+
+func (c *mountReadOnly) readMounts() ([]mountLine, error) {
+	data, err := c.readFile(c.mountsFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem reading mounts")
+	}
+
+	var mounts []mountLine
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 {
+			continue
+		}
+
+		mounts = append(mounts, mountLine{
+			device:     unescapeMountField(fields[0]),
+			mountPoint: unescapeMountField(fields[1]),
+			fsType:     fields[2],
+			options:    strings.Split(fields[3], ","),
+		})
+	}
+
+	if err = scanner.Err(); err != nil {
+		return nil, errors.Wrapf(err, "problem reading mounts file '%s'", c.mountsFile)
+	}
+
+	return mounts, nil
+}
+
+// unescapeMountField replaces the octal escapes (e.g. "\040" for a
+// space) that the kernel uses in /proc/mounts.
+func unescapeMountField(field string) string {
+	if !strings.Contains(field, `\`) {
+		return field
+	}
+
+	buf := &bytes.Buffer{}
+	for i := 0; i < len(field); i++ {
+		if field[i] == '\\' && i+4 <= len(field) {
+			if b, err := strconv.ParseUint(field[i+1:i+4], 8, 8); err == nil {
+				buf.WriteByte(byte(b))
+				i += 3
+				continue
+			}
+		}
+		buf.WriteByte(field[i])
+	}
+
+	return buf.String()
+}
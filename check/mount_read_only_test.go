@@ -0,0 +1,111 @@
+package check
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type MountReadOnlySuite struct {
+	tmpDir  string
+	check   *mountReadOnly
+	require *require.Assertions
+	suite.Suite
+}
+
+func TestMountReadOnlySuite(t *testing.T) {
+	suite.Run(t, new(MountReadOnlySuite))
+}
+
+func (s *MountReadOnlySuite) SetupSuite() {
+	s.require = s.Require()
+
+	dir, err := ioutil.TempDir("", uuid.NewV4().String())
+	s.require.NoError(err)
+	s.tmpDir = dir
+
+	mounts := []byte(`/dev/xvda1 / ext4 rw,relatime,errors=remount-ro 0 0
+proc /proc proc rw,nosuid,nodev,noexec,relatime 0 0
+/dev/xvda2 /greenbay-test/usr ext4 ro,relatime 0 0
+/dev/xvdb /greenbay-test/srv xfs rw,noatime 0 0
+/dev/xvdc /greenbay-test/srv xfs ro,noatime 0 0
+/dev/xvdd /greenbay-test/my\040data ext4 ro 0 0
+`)
+	s.require.NoError(ioutil.WriteFile(filepath.Join(dir, "mounts"), mounts, 0644))
+}
+
+func (s *MountReadOnlySuite) TearDownSuite() {
+	s.require.NoError(os.RemoveAll(s.tmpDir))
+}
+
+func (s *MountReadOnlySuite) SetupTest() {
+	s.check = &mountReadOnly{
+		Base:       NewBase("mount-read-only", 0),
+		Expected:   "ro",
+		mountsFile: filepath.Join(s.tmpDir, "mounts"),
+	}
+}
+
+func (s *MountReadOnlySuite) TestValidation() {
+	s.Error(s.check.validate())
+
+	s.check.Path = "/greenbay-test/usr"
+	s.NoError(s.check.validate())
+
+	s.check.Expected = "rw"
+	s.NoError(s.check.validate())
+
+	s.check.Expected = "readonly"
+	s.Error(s.check.validate())
+}
+
+func (s *MountReadOnlySuite) TestMatchingStatePasses() {
+	for path, expected := range map[string]string{
+		"/greenbay-test/usr":            "ro",
+		"/greenbay-test/usr/lib/x.so":   "ro",
+		"/greenbay-test/usrlocal":       "rw",
+		"/greenbay-test/srv/app":        "ro",
+		"/greenbay-test/my data/file":   "ro",
+		"/greenbay-test/var/lib/docker": "rw",
+	} {
+		s.SetupTest()
+		s.check.Path = path
+		s.check.Expected = expected
+		s.check.Run()
+		s.True(s.check.Output().Passed, path)
+		s.NoError(s.check.Error(), path)
+	}
+}
+
+func (s *MountReadOnlySuite) TestMismatchReportsActualState() {
+	s.check.Path = "/greenbay-test/var/log"
+	s.check.Run()
+
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "is on /, which is mounted rw, not ro")
+	s.Contains(s.check.Output().Message, "/dev/xvda1")
+
+	s.SetupTest()
+	s.check.Path = "/greenbay-test/usr/bin"
+	s.check.Expected = "rw"
+	s.check.Run()
+
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "mounted ro, not rw")
+}
+
+func (s *MountReadOnlySuite) TestMissingMountsFileFails() {
+	s.check.Path = "/greenbay-test/usr"
+	s.check.mountsFile = filepath.Join(s.tmpDir, "does-not-exist")
+	s.check.Run()
+
+	s.False(s.check.Output().Passed)
+	s.Error(s.check.Error())
+}
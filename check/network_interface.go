@@ -0,0 +1,143 @@
+package check
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/registry"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	name := "network-interface"
+	registry.AddJobType(name, func() amboy.Job {
+		return &networkInterface{
+			Base:   NewBase(name, 0),
+			netDir: "/sys/class/net",
+		}
+	})
+}
+
+// networkInterface checks the link state and negotiated speed of a
+// network interface (e.g. "eth0"), as reported in /sys/class/net,
+// which catches interfaces that came up down or negotiated a lower
+// speed than the hardware supports. When speed_mbps is set, the
+// interface must be up, at that speed or faster. When link_up is
+// set, the operational state of the interface must be "up" or, when
+// link_up is false, not "up". Only supported on Linux.
+type networkInterface struct {
+	InterfaceName string `bson:"name" json:"name" yaml:"name"`
+	SpeedMbps     int    `bson:"speed_mbps" json:"speed_mbps" yaml:"speed_mbps"`
+	LinkUp        *bool  `bson:"link_up" json:"link_up" yaml:"link_up"`
+	*Base         `bson:"metadata" json:"metadata" yaml:"metadata"`
+	fileReadLimit
+
+	netDir string
+}
+
+func (c *networkInterface) validate() error {
+	if c.InterfaceName == "" {
+		return errors.Errorf("no interface name specified for '%s' (%s) check", c.ID(), c.Name())
+	}
+
+	if strings.Contains(c.InterfaceName, "/") {
+		return errors.Errorf("interface name '%s' for '%s' check is not valid", c.InterfaceName, c.ID())
+	}
+
+	if c.SpeedMbps < 0 {
+		return errors.Errorf("speed for '%s' check cannot be negative", c.ID())
+	}
+
+	if c.SpeedMbps == 0 && c.LinkUp == nil {
+		return errors.Errorf("no speed or link state specified for '%s' (%s) check", c.ID(), c.Name())
+	}
+
+	if c.SpeedMbps > 0 && c.LinkUp != nil && !*c.LinkUp {
+		return errors.Errorf("cannot check the speed of interface '%s' for '%s' check when the link should be down",
+			c.InterfaceName, c.ID())
+	}
+
+	return nil
+}
+
+func (c *networkInterface) Run() {
+	c.startTask()
+	defer c.MarkComplete()
+
+	if err := c.validate(); err != nil {
+		c.setState(false)
+		c.setReason(ReasonInvalidConfig)
+		c.AddError(err)
+		return
+	}
+
+	dir := filepath.Join(c.netDir, c.InterfaceName)
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		c.setState(false)
+		c.setReason(ReasonFileMissing)
+		c.AddError(errors.Errorf("network interface '%s' does not exist", c.InterfaceName))
+		return
+	} else if err != nil {
+		c.setState(false)
+		c.AddError(errors.Wrapf(err, "problem finding network interface '%s'", c.InterfaceName))
+		return
+	}
+
+	data, err := c.readFile(filepath.Join(dir, "operstate"))
+	if err != nil {
+		c.setState(false)
+		c.AddError(err)
+		return
+	}
+	state := strings.TrimSpace(string(data))
+
+	// reading the speed of an interface without a link fails, or
+	// reports -1, depending on the driver.
+	speed := -1
+	if data, err = c.readFile(filepath.Join(dir, "speed")); err == nil {
+		if value, err := strconv.Atoi(strings.TrimSpace(string(data))); err == nil {
+			speed = value
+		}
+	}
+
+	speedMsg := "unknown"
+	if speed >= 0 {
+		speedMsg = fmt.Sprintf("%d Mbps", speed)
+	}
+	c.setMessage(fmt.Sprintf("interface '%s': state=%s, speed=%s", c.InterfaceName, state, speedMsg))
+
+	var problems []string
+
+	up := state == "up"
+	if c.LinkUp != nil && *c.LinkUp != up {
+		if *c.LinkUp {
+			problems = append(problems, fmt.Sprintf("link is %s, not up", state))
+		} else {
+			problems = append(problems, "link is up")
+		}
+	}
+
+	if c.SpeedMbps > 0 {
+		if !up && c.LinkUp == nil {
+			problems = append(problems, fmt.Sprintf("link is %s, not up", state))
+		}
+
+		if speed < c.SpeedMbps {
+			problems = append(problems, fmt.Sprintf("speed is %s, less than %d Mbps", speedMsg, c.SpeedMbps))
+		}
+	}
+
+	if len(problems) > 0 {
+		c.setState(false)
+		c.setReason(ReasonValueMismatch)
+		c.AddError(errors.Errorf("network interface '%s' is not as expected: %s",
+			c.InterfaceName, strings.Join(problems, ", ")))
+		return
+	}
+
+	c.setState(true)
+}
@@ -0,0 +1,135 @@
+package check
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type NetworkInterfaceSuite struct {
+	tmpDir  string
+	check   *networkInterface
+	require *require.Assertions
+	suite.Suite
+}
+
+func TestNetworkInterfaceSuite(t *testing.T) {
+	suite.Run(t, new(NetworkInterfaceSuite))
+}
+
+func (s *NetworkInterfaceSuite) SetupSuite() {
+	s.require = s.Require()
+
+	dir, err := ioutil.TempDir("", uuid.NewV4().String())
+	s.require.NoError(err)
+	s.tmpDir = dir
+
+	files := map[string]string{
+		"eth0/operstate": "up\n",
+		"eth0/speed":     "10000\n",
+		"eth1/operstate": "up\n",
+		"eth1/speed":     "1000\n",
+		"eth2/operstate": "down\n",
+		"eth2/speed":     "-1\n",
+		"eth3/operstate": "down\n",
+	}
+
+	for fn, content := range files {
+		path := filepath.Join(dir, fn)
+		s.require.NoError(os.MkdirAll(filepath.Dir(path), 0755))
+		s.require.NoError(ioutil.WriteFile(path, []byte(content), 0644))
+	}
+}
+
+func (s *NetworkInterfaceSuite) TearDownSuite() {
+	s.require.NoError(os.RemoveAll(s.tmpDir))
+}
+
+func (s *NetworkInterfaceSuite) SetupTest() {
+	s.check = &networkInterface{
+		Base:          NewBase("network-interface", 0),
+		InterfaceName: "eth0",
+		netDir:        s.tmpDir,
+	}
+}
+
+func (s *NetworkInterfaceSuite) TestValidation() {
+	s.Error(s.check.validate())
+
+	s.check.SpeedMbps = -1
+	s.Error(s.check.validate())
+
+	s.check.SpeedMbps = 1000
+	s.NoError(s.check.validate())
+
+	down := false
+	s.check.LinkUp = &down
+	s.Error(s.check.validate())
+
+	s.check.SpeedMbps = 0
+	s.NoError(s.check.validate())
+
+	s.check.InterfaceName = "../eth0"
+	s.Error(s.check.validate())
+}
+
+func (s *NetworkInterfaceSuite) TestExpectedSpeed() {
+	s.check.SpeedMbps = 10000
+	s.check.Run()
+
+	s.True(s.check.Output().Passed)
+	s.NoError(s.check.Error())
+	s.Equal("interface 'eth0': state=up, speed=10000 Mbps", s.check.Output().Message)
+
+	s.SetupTest()
+	s.check.InterfaceName = "eth1"
+	s.check.SpeedMbps = 10000
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "speed is 1000 Mbps, less than 10000 Mbps")
+}
+
+func (s *NetworkInterfaceSuite) TestLinkDown() {
+	for _, name := range []string{"eth2", "eth3"} {
+		s.SetupTest()
+		s.check.InterfaceName = name
+		s.check.SpeedMbps = 1000
+		s.check.Run()
+
+		s.False(s.check.Output().Passed, name)
+		s.require.Error(s.check.Error())
+		s.Contains(s.check.Error().Error(), "link is down, not up, speed is unknown, less than 1000 Mbps")
+		s.Equal("interface '"+name+"': state=down, speed=unknown", s.check.Output().Message)
+	}
+
+	down := false
+	s.SetupTest()
+	s.check.InterfaceName = "eth2"
+	s.check.LinkUp = &down
+	s.check.Run()
+	s.True(s.check.Output().Passed)
+
+	up := true
+	s.SetupTest()
+	s.check.InterfaceName = "eth2"
+	s.check.LinkUp = &up
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+}
+
+func (s *NetworkInterfaceSuite) TestMissingInterface() {
+	s.check.InterfaceName = "eth9"
+	s.check.SpeedMbps = 1000
+	s.check.Run()
+
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "network interface 'eth9' does not exist")
+	s.Equal(ReasonFileMissing, s.check.Output().ReasonCode)
+}
@@ -0,0 +1,168 @@
+package check
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/registry"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	name := "package-policy"
+	registry.AddJobType(name, func() amboy.Job {
+		return &packagePolicy{
+			Base: NewBase(name, 0),
+			run: func(command string, args ...string) ([]byte, error) {
+				return exec.Command(command, args...).Output()
+			},
+		}
+	})
+}
+
+// packageListCommands are the commands that list the names of the
+// installed packages, one per line, for each package manager that
+// the package-policy check supports. The dpkg command also lists the
+// status of each package, because dpkg knows about packages that
+// are removed, but not purged.
+var packageListCommands = map[string][]string{
+	"dpkg":   {"dpkg-query", "-W", "-f=${db:Status-Status} ${Package}\n"},
+	"rpm":    {"rpm", "-qa", "--queryformat", "%{NAME}\n"},
+	"yum":    {"rpm", "-qa", "--queryformat", "%{NAME}\n"},
+	"pacman": {"pacman", "-Qq"},
+	"brew":   {"brew", "list", "-1"},
+}
+
+// packagePolicy checks the set of installed packages against a
+// policy, e.g. that there are no compilers or network tools on
+// production hosts. Installed packages must not match any of the
+// forbidden_packages, unless they also match allowed_packages. When
+// strict is set, every installed package must match
+// allowed_packages. Packages match on their names, or on shell
+// patterns (e.g. "gcc*"). The manager is dpkg, rpm, yum (which is the
+// same as rpm), pacman, or brew.
+type packagePolicy struct {
+	Manager           string   `bson:"manager" json:"manager" yaml:"manager"`
+	AllowedPackages   []string `bson:"allowed_packages" json:"allowed_packages" yaml:"allowed_packages"`
+	ForbiddenPackages []string `bson:"forbidden_packages" json:"forbidden_packages" yaml:"forbidden_packages"`
+	Strict            bool     `bson:"strict" json:"strict" yaml:"strict"`
+	*Base             `bson:"metadata" json:"metadata" yaml:"metadata"`
+
+	run func(command string, args ...string) ([]byte, error)
+}
+
+func (c *packagePolicy) validate() error {
+	if _, ok := packageListCommands[c.Manager]; !ok {
+		return errors.Errorf("manager '%s' for '%s' (%s) check must be one of dpkg, rpm, yum, pacman, or brew",
+			c.Manager, c.ID(), c.Name())
+	}
+
+	if c.Strict && len(c.AllowedPackages) == 0 {
+		return errors.Errorf("no allowed packages specified for strict '%s' (%s) check", c.ID(), c.Name())
+	}
+
+	if !c.Strict && len(c.ForbiddenPackages) == 0 {
+		return errors.Errorf("no forbidden packages specified for '%s' (%s) check", c.ID(), c.Name())
+	}
+
+	for _, pattern := range append(append([]string{}, c.AllowedPackages...), c.ForbiddenPackages...) {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return errors.Wrapf(err, "package pattern '%s' for '%s' check is not valid", pattern, c.ID())
+		}
+	}
+
+	return nil
+}
+
+func (c *packagePolicy) Run() {
+	c.startTask()
+	defer c.MarkComplete()
+
+	if err := c.validate(); err != nil {
+		c.setState(false)
+		c.setReason(ReasonInvalidConfig)
+		c.AddError(err)
+		return
+	}
+
+	installed, err := c.installedPackages()
+	if err != nil {
+		c.setState(false)
+		c.AddError(err)
+		return
+	}
+
+	var offending []string
+	for _, pkg := range installed {
+		allowed := matchesPackagePattern(c.AllowedPackages, pkg)
+		forbidden := matchesPackagePattern(c.ForbiddenPackages, pkg)
+
+		if (forbidden || c.Strict) && !allowed {
+			offending = append(offending, pkg)
+		}
+	}
+
+	if len(offending) == 0 {
+		c.setState(true)
+		c.setMessage(fmt.Sprintf("%d installed %s packages comply with the policy", len(installed), c.Manager))
+		return
+	}
+
+	c.setState(false)
+	c.setMessage(offending)
+	c.AddError(errors.Errorf("%d of %d installed %s packages are not allowed: %s",
+		len(offending), len(installed), c.Manager, strings.Join(offending, ", ")))
+}
+
+// installedPackages returns the sorted, unique names of the installed
+// packages. Packages with more than one version or architecture
+// installed are only listed once.
+func (c *packagePolicy) installedPackages() ([]string, error) {
+	args := packageListCommands[c.Manager]
+
+	out, err := c.run(args[0], args[1:]...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem listing installed %s packages", c.Manager)
+	}
+
+	seen := make(map[string]struct{})
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		name := fields[0]
+		if c.Manager == "dpkg" {
+			if len(fields) < 2 || fields[0] != "installed" {
+				continue
+			}
+			name = fields[1]
+		}
+
+		seen[name] = struct{}{}
+	}
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+func matchesPackagePattern(patterns []string, pkg string) bool {
+	for _, pattern := range patterns {
+		// validate checks the syntax of the patterns.
+		if ok, _ := filepath.Match(pattern, pkg); ok {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,119 @@
+package check
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type PackagePolicySuite struct {
+	output  string
+	ran     []string
+	check   *packagePolicy
+	require *require.Assertions
+	suite.Suite
+}
+
+func TestPackagePolicySuite(t *testing.T) {
+	suite.Run(t, new(PackagePolicySuite))
+}
+
+func (s *PackagePolicySuite) SetupSuite() {
+	s.require = s.Require()
+}
+
+func (s *PackagePolicySuite) SetupTest() {
+	s.ran = nil
+	s.output = `installed bash
+installed coreutils
+installed gcc-12
+installed libgcc-s1
+installed libgcc-s1
+config-files netcat-openbsd
+installed openssl
+`
+	s.check = &packagePolicy{
+		Base:    NewBase("package-policy", 0),
+		Manager: "dpkg",
+		run: func(command string, args ...string) ([]byte, error) {
+			s.ran = append([]string{command}, args...)
+			return []byte(s.output), nil
+		},
+	}
+}
+
+func (s *PackagePolicySuite) TestValidation() {
+	s.Error(s.check.validate())
+
+	s.check.ForbiddenPackages = []string{"gcc*"}
+	s.NoError(s.check.validate())
+
+	s.check.Strict = true
+	s.Error(s.check.validate())
+
+	s.check.AllowedPackages = []string{"bash"}
+	s.NoError(s.check.validate())
+
+	s.check.AllowedPackages = []string{"[bash"}
+	s.Error(s.check.validate())
+
+	s.check.AllowedPackages = []string{"bash"}
+	s.check.Manager = "apk"
+	s.Error(s.check.validate())
+}
+
+func (s *PackagePolicySuite) TestForbiddenPackagesFail() {
+	s.check.ForbiddenPackages = []string{"gcc*", "netcat*", "tcpdump"}
+	s.check.Run()
+
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Equal("dpkg-query", s.ran[0])
+	s.Equal("gcc-12", s.check.Output().Message)
+	s.Contains(s.check.Error().Error(), "1 of 5 installed dpkg packages are not allowed: gcc-12")
+}
+
+func (s *PackagePolicySuite) TestAllowedPackagesAreExceptions() {
+	s.check.ForbiddenPackages = []string{"*gcc*"}
+	s.check.AllowedPackages = []string{"libgcc*", "gcc-12"}
+	s.check.Run()
+
+	s.True(s.check.Output().Passed)
+	s.NoError(s.check.Error())
+	s.Equal("5 installed dpkg packages comply with the policy", s.check.Output().Message)
+}
+
+func (s *PackagePolicySuite) TestStrictModeRequiresAllowedPackages() {
+	s.check.Manager = "rpm"
+	s.output = "bash\ncoreutils\nopenssl\nnmap\nnmap\n"
+	s.check.Strict = true
+	s.check.AllowedPackages = []string{"bash", "coreutils", "openssl*"}
+	s.check.Run()
+
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Equal([]string{"rpm", "-qa", "--queryformat", "%{NAME}\n"}, s.ran)
+	s.Contains(s.check.Error().Error(), "1 of 4 installed rpm packages are not allowed: nmap")
+
+	s.SetupTest()
+	s.check.Manager = "pacman"
+	s.output = "bash\nopenssl\n"
+	s.check.Strict = true
+	s.check.AllowedPackages = []string{"bash", "openssl"}
+	s.check.Run()
+	s.True(s.check.Output().Passed)
+}
+
+func (s *PackagePolicySuite) TestListFailureFails() {
+	s.check.ForbiddenPackages = []string{"gcc"}
+	s.check.run = func(command string, args ...string) ([]byte, error) {
+		return nil, errors.New("exit status 127")
+	}
+	s.check.Run()
+
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "problem listing installed dpkg packages")
+}
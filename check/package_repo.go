@@ -0,0 +1,387 @@
+package check
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/registry"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	name := "package-repo"
+	registry.AddJobType(name, func() amboy.Job {
+		return &packageRepo{
+			Base:    NewBase(name, 0),
+			aptDir:  "/etc/apt",
+			yumDirs: []string{"/etc/yum.repos.d"},
+		}
+	})
+}
+
+// packageRepo checks that a package repository is configured and
+// enabled, or, when enabled is false, that it is absent or disabled.
+// For apt, the check reads sources.list, and the ".list" and
+// ".sources" (deb822) files in sources.list.d, and commented out
+// entries are disabled. For yum, the check reads the ".repo" files in
+// /etc/yum.repos.d. Repositories match on their url (ignoring a
+// trailing slash), which matches the apt URIs and the yum baseurl,
+// mirrorlist, and metalink, or, for yum, on the repo_id. The check
+// does not expand yum variables such as $releasever.
+type packageRepo struct {
+	Manager string `bson:"manager" json:"manager" yaml:"manager"`
+	URL     string `bson:"url" json:"url" yaml:"url"`
+	RepoID  string `bson:"repo_id" json:"repo_id" yaml:"repo_id"`
+	Enabled *bool  `bson:"enabled" json:"enabled" yaml:"enabled"`
+	*Base   `bson:"metadata" json:"metadata" yaml:"metadata"`
+	fileReadLimit
+
+	aptDir  string
+	yumDirs []string
+}
+
+// packageRepoEntry is a repository definition from a configuration
+// file, with the location of the definition for reporting.
+type packageRepoEntry struct {
+	id      string
+	urls    []string
+	enabled bool
+	source  string
+}
+
+func (c *packageRepo) validate() error {
+	switch c.Manager {
+	case "apt":
+		if c.RepoID != "" {
+			return errors.Errorf("repo ids are only supported for yum, in '%s' (%s) check", c.ID(), c.Name())
+		}
+	case "yum":
+	default:
+		return errors.Errorf("manager '%s' for '%s' (%s) check must be 'apt' or 'yum'",
+			c.Manager, c.ID(), c.Name())
+	}
+
+	if c.URL == "" && c.RepoID == "" {
+		return errors.Errorf("no url or repo id specified for '%s' (%s) check", c.ID(), c.Name())
+	}
+
+	return nil
+}
+
+func (c *packageRepo) Run() {
+	c.startTask()
+	defer c.MarkComplete()
+
+	if err := c.validate(); err != nil {
+		c.setState(false)
+		c.setReason(ReasonInvalidConfig)
+		c.AddError(err)
+		return
+	}
+
+	var entries []packageRepoEntry
+	var err error
+	if c.Manager == "apt" {
+		entries, err = c.aptEntries()
+	} else {
+		entries, err = c.yumEntries()
+	}
+
+	if err != nil {
+		c.setState(false)
+		c.AddError(err)
+		return
+	}
+
+	var matches []string
+	enabled := false
+	for _, e := range entries {
+		if !c.matches(e) {
+			continue
+		}
+
+		state := "disabled"
+		if e.enabled {
+			state = "enabled"
+			enabled = true
+		}
+		matches = append(matches, fmt.Sprintf("%s (%s)", e.source, state))
+	}
+
+	desc := c.description()
+	expected := c.Enabled == nil || *c.Enabled
+
+	if len(matches) == 0 {
+		c.setMessage(fmt.Sprintf("%s is not configured", desc))
+	} else {
+		c.setMessage(matches)
+	}
+
+	switch {
+	case expected && len(matches) == 0:
+		c.setState(false)
+		c.AddError(errors.Errorf("%s is not configured", desc))
+	case expected && !enabled:
+		c.setState(false)
+		c.AddError(errors.Errorf("%s is configured, but disabled", desc))
+	case !expected && enabled:
+		c.setState(false)
+		c.AddError(errors.Errorf("%s is enabled", desc))
+	default:
+		c.setState(true)
+	}
+}
+
+func (c *packageRepo) description() string {
+	if c.RepoID != "" {
+		return fmt.Sprintf("%s repository '%s'", c.Manager, c.RepoID)
+	}
+
+	return fmt.Sprintf("%s repository at '%s'", c.Manager, c.URL)
+}
+
+func (c *packageRepo) matches(e packageRepoEntry) bool {
+	if c.RepoID != "" && e.id != c.RepoID {
+		return false
+	}
+
+	if c.URL == "" {
+		return true
+	}
+
+	for _, u := range e.urls {
+		if strings.TrimRight(u, "/") == strings.TrimRight(c.URL, "/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// aptEntries reads the repositories in sources.list and in the files
+// in sources.list.d.
+func (c *packageRepo) aptEntries() ([]packageRepoEntry, error) {
+	var entries []packageRepoEntry
+
+	fn := filepath.Join(c.aptDir, "sources.list")
+	if data, err := c.readFile(fn); err == nil {
+		entries = append(entries, parseAptList(fn, data)...)
+	}
+
+	for _, ext := range []string{"list", "sources"} {
+		files, err := filepath.Glob(filepath.Join(c.aptDir, "sources.list.d", "*."+ext))
+		if err != nil {
+			return nil, errors.Wrap(err, "problem listing apt sources")
+		}
+		sort.Strings(files)
+
+		for _, fn := range files {
+			data, err := c.readFile(fn)
+			if err != nil {
+				return nil, err
+			}
+
+			if ext == "list" {
+				entries = append(entries, parseAptList(fn, data)...)
+			} else {
+				entries = append(entries, parseAptSources(fn, data)...)
+			}
+		}
+	}
+
+	return entries, nil
+}
+
+// parseAptList parses the one line format of sources.list, where
+// each entry is "deb [options] uri suite [components]". Commented out
+// entries are disabled.
+func parseAptList(fn string, data []byte) []packageRepoEntry {
+	var entries []packageRepoEntry
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	num := 0
+	for scanner.Scan() {
+		num++
+		line := strings.TrimSpace(scanner.Text())
+
+		enabled := true
+		if strings.HasPrefix(line, "#") {
+			enabled = false
+			line = strings.TrimSpace(strings.TrimLeft(line, "#"))
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 3 || (fields[0] != "deb" && fields[0] != "deb-src") {
+			continue
+		}
+
+		fields = fields[1:]
+		if strings.HasPrefix(fields[0], "[") {
+			for len(fields) > 0 && !strings.HasSuffix(fields[0], "]") {
+				fields = fields[1:]
+			}
+			if len(fields) > 0 {
+				fields = fields[1:]
+			}
+		}
+
+		if len(fields) == 0 {
+			continue
+		}
+
+		entries = append(entries, packageRepoEntry{
+			urls:    []string{fields[0]},
+			enabled: enabled,
+			source:  fmt.Sprintf("%s:%d: %s", fn, num, strings.TrimSpace(scanner.Text())),
+		})
+	}
+
+	return entries
+}
+
+// parseAptSources parses the deb822 format of ".sources" files, where
+// each entry is a stanza of "Key: value" fields, separated by blank
+// lines, which is disabled with "Enabled: no".
+func parseAptSources(fn string, data []byte) []packageRepoEntry {
+	var entries []packageRepoEntry
+
+	fields := map[string]string{}
+	start := 0
+	flush := func() {
+		if uris, ok := fields["uris"]; ok {
+			entries = append(entries, packageRepoEntry{
+				urls:    strings.Fields(uris),
+				enabled: fields["enabled"] != "no",
+				source:  fmt.Sprintf("%s:%d: URIs: %s", fn, start, uris),
+			})
+		}
+		fields = map[string]string{}
+		start = 0
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	num := 0
+	key := ""
+	for scanner.Scan() {
+		num++
+		line := scanner.Text()
+
+		if strings.TrimSpace(line) == "" {
+			flush()
+			continue
+		}
+
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if start == 0 {
+			start = num
+		}
+
+		// continuation lines start with whitespace
+		if (line[0] == ' ' || line[0] == '\t') && key != "" {
+			fields[key] += " " + strings.TrimSpace(line)
+			continue
+		}
+
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		key = strings.ToLower(strings.TrimSpace(parts[0]))
+		fields[key] = strings.TrimSpace(parts[1])
+	}
+	flush()
+
+	return entries
+}
+
+// yumEntries reads the repositories in the ".repo" files.
+func (c *packageRepo) yumEntries() ([]packageRepoEntry, error) {
+	var entries []packageRepoEntry
+
+	for _, dir := range c.yumDirs {
+		files, err := filepath.Glob(filepath.Join(dir, "*.repo"))
+		if err != nil {
+			return nil, errors.Wrap(err, "problem listing yum repositories")
+		}
+		sort.Strings(files)
+
+		for _, fn := range files {
+			data, err := c.readFile(fn)
+			if err != nil {
+				return nil, err
+			}
+
+			entries = append(entries, parseYumRepos(fn, data)...)
+		}
+	}
+
+	return entries, nil
+}
+
+// parseYumRepos parses the repositories in a ".repo" file, which is
+// an INI file with a section for each repository. Repositories are
+// enabled unless they set "enabled=0".
+func parseYumRepos(fn string, data []byte) []packageRepoEntry {
+	var entries []packageRepoEntry
+	var current *packageRepoEntry
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	num := 0
+	key := ""
+	for scanner.Scan() {
+		num++
+		raw := scanner.Text()
+		line := strings.TrimSpace(raw)
+
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			entries = append(entries, packageRepoEntry{
+				id:      strings.TrimSpace(line[1 : len(line)-1]),
+				enabled: true,
+				source:  fmt.Sprintf("%s:%d: %s", fn, num, line),
+			})
+			current = &entries[len(entries)-1]
+			key = ""
+			continue
+		}
+
+		if current == nil {
+			continue
+		}
+
+		// baseurl may list more urls on continuation lines
+		if (raw[0] == ' ' || raw[0] == '\t') && key == "baseurl" {
+			current.urls = append(current.urls, strings.Fields(line)...)
+			continue
+		}
+
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		key = strings.ToLower(strings.TrimSpace(parts[0]))
+		value := strings.TrimSpace(parts[1])
+
+		switch key {
+		case "baseurl", "mirrorlist", "metalink":
+			current.urls = append(current.urls, strings.Fields(strings.Replace(value, ",", " ", -1))...)
+		case "enabled":
+			current.enabled = value != "0" && strings.ToLower(value) != "false" && strings.ToLower(value) != "no"
+		}
+	}
+
+	return entries
+}
@@ -0,0 +1,174 @@
+package check
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type PackageRepoSuite struct {
+	tmpDir  string
+	check   *packageRepo
+	require *require.Assertions
+	suite.Suite
+}
+
+func TestPackageRepoSuite(t *testing.T) {
+	suite.Run(t, new(PackageRepoSuite))
+}
+
+func (s *PackageRepoSuite) SetupSuite() {
+	s.require = s.Require()
+
+	dir, err := ioutil.TempDir("", uuid.NewV4().String())
+	s.require.NoError(err)
+	s.tmpDir = dir
+
+	files := map[string]string{
+		"apt/sources.list": `deb http://archive.ubuntu.com/ubuntu/ focal main restricted
+# deb http://archive.canonical.com/ubuntu focal partner
+`,
+		"apt/sources.list.d/mirror.list": `deb [arch=amd64 signed-by=/usr/share/keyrings/mirror.gpg] https://mirror.example.net/apt/ focal main
+`,
+		"apt/sources.list.d/docker.sources": `Types: deb
+URIs: https://download.docker.com/linux/ubuntu
+Suites: focal
+Components: stable
+
+# staging is off for now
+Types: deb
+URIs: https://staging.example.net/apt
+Suites: focal
+Enabled: no
+`,
+		"yum/internal.repo": `[internal-base]
+name=Internal Base
+baseurl=https://mirror.example.net/centos/$releasever/os/
+        https://mirror2.example.net/centos/$releasever/os/
+gpgcheck=1
+
+[internal-testing]
+name=Internal Testing
+baseurl=https://testing.example.net/centos/
+enabled=0
+`,
+		"yum/epel.repo": `[epel]
+name=Extra Packages for Enterprise Linux
+metalink=https://mirrors.fedoraproject.org/metalink?repo=epel-8&arch=$basearch
+enabled=1
+`,
+	}
+
+	for fn, content := range files {
+		path := filepath.Join(dir, fn)
+		s.require.NoError(os.MkdirAll(filepath.Dir(path), 0755))
+		s.require.NoError(ioutil.WriteFile(path, []byte(content), 0644))
+	}
+}
+
+func (s *PackageRepoSuite) TearDownSuite() {
+	s.require.NoError(os.RemoveAll(s.tmpDir))
+}
+
+func (s *PackageRepoSuite) SetupTest() {
+	s.check = &packageRepo{
+		Base:    NewBase("package-repo", 0),
+		aptDir:  filepath.Join(s.tmpDir, "apt"),
+		yumDirs: []string{filepath.Join(s.tmpDir, "yum")},
+	}
+}
+
+func (s *PackageRepoSuite) runCheck(manager, url, id string, enabled *bool) {
+	s.SetupTest()
+	s.check.Manager = manager
+	s.check.URL = url
+	s.check.RepoID = id
+	s.check.Enabled = enabled
+	s.check.Run()
+}
+
+func (s *PackageRepoSuite) TestValidation() {
+	s.Error(s.check.validate())
+
+	s.check.Manager = "apt"
+	s.Error(s.check.validate())
+
+	s.check.URL = "https://mirror.example.net/apt"
+	s.NoError(s.check.validate())
+
+	s.check.RepoID = "mirror"
+	s.Error(s.check.validate())
+
+	s.check.Manager = "yum"
+	s.NoError(s.check.validate())
+
+	s.check.Manager = "zypper"
+	s.Error(s.check.validate())
+}
+
+func (s *PackageRepoSuite) TestAptRepositories() {
+	disabled := false
+
+	for url, passes := range map[string]bool{
+		"http://archive.ubuntu.com/ubuntu":         true,
+		"https://mirror.example.net/apt":           true,
+		"https://download.docker.com/linux/ubuntu": true,
+		"http://archive.canonical.com/ubuntu":      false,
+		"https://staging.example.net/apt/":         false,
+		"https://unknown.example.net/apt":          false,
+	} {
+		s.runCheck("apt", url, "", nil)
+		s.Equal(passes, s.check.Output().Passed, url)
+
+		s.runCheck("apt", url, "", &disabled)
+		s.Equal(!passes, s.check.Output().Passed, url)
+	}
+
+	s.runCheck("apt", "https://mirror.example.net/apt", "", nil)
+	s.Contains(s.check.Output().Message, "mirror.list:1: deb [arch=amd64")
+	s.Contains(s.check.Output().Message, "(enabled)")
+
+	s.runCheck("apt", "https://staging.example.net/apt", "", nil)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "is configured, but disabled")
+	s.Contains(s.check.Output().Message, "docker.sources:7: URIs: https://staging.example.net/apt (disabled)")
+
+	s.runCheck("apt", "https://unknown.example.net/apt", "", nil)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "is not configured")
+}
+
+func (s *PackageRepoSuite) TestYumRepositories() {
+	s.runCheck("yum", "", "internal-base", nil)
+	s.True(s.check.Output().Passed)
+	s.Contains(s.check.Output().Message, "internal.repo:1: [internal-base] (enabled)")
+
+	s.runCheck("yum", "https://mirror2.example.net/centos/$releasever/os", "", nil)
+	s.True(s.check.Output().Passed)
+
+	s.runCheck("yum", "https://mirrors.fedoraproject.org/metalink?repo=epel-8&arch=$basearch", "epel", nil)
+	s.True(s.check.Output().Passed)
+
+	s.runCheck("yum", "https://testing.example.net/centos/", "", nil)
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "is configured, but disabled")
+
+	disabled := false
+	s.runCheck("yum", "", "internal-testing", &disabled)
+	s.True(s.check.Output().Passed)
+
+	s.runCheck("yum", "", "internal-base", &disabled)
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "yum repository 'internal-base' is enabled")
+
+	// the url and repo id must both match
+	s.runCheck("yum", "https://testing.example.net/centos/", "internal-base", nil)
+	s.False(s.check.Output().Passed)
+}
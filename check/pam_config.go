@@ -0,0 +1,167 @@
+package check
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/registry"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	name := "pam-config"
+	registry.AddJobType(name, func() amboy.Job {
+		return &pamConfig{
+			Base:   NewBase(name, 0),
+			pamDir: "/etc/pam.d",
+		}
+	})
+}
+
+// pamMaxIncludeDepth limits the depth of nested includes that the
+// pam-config check follows, which guards against include cycles.
+const pamMaxIncludeDepth = 8
+
+// pamConfig checks the PAM configuration of a service (e.g. "sshd"
+// or "common-auth"), in /etc/pam.d/<service>, for a line that
+// contains module_contains (e.g. "pam_faillock.so" or "auth required
+// pam_pwquality.so"), ignoring differences in whitespace. The line
+// must be present or, when present is false, absent. The check
+// follows "@include" lines and the "include" and "substack"
+// controls, so a module configured in a file that the service
+// includes counts as configured for the service.
+type pamConfig struct {
+	Service        string `bson:"service" json:"service" yaml:"service"`
+	ModuleContains string `bson:"module_contains" json:"module_contains" yaml:"module_contains"`
+	Present        *bool  `bson:"present" json:"present" yaml:"present"`
+	*Base          `bson:"metadata" json:"metadata" yaml:"metadata"`
+	fileReadLimit
+
+	pamDir string
+}
+
+// pamLine is a line from a PAM configuration file, with its location.
+type pamLine struct {
+	source string
+	text   string
+}
+
+func (c *pamConfig) validate() error {
+	if c.Service == "" {
+		return errors.Errorf("no service specified for '%s' (%s) check", c.ID(), c.Name())
+	}
+
+	if strings.Contains(c.Service, "/") {
+		return errors.Errorf("service '%s' for '%s' check is not valid", c.Service, c.ID())
+	}
+
+	if strings.TrimSpace(c.ModuleContains) == "" {
+		return errors.Errorf("no module specified for '%s' (%s) check", c.ID(), c.Name())
+	}
+
+	return nil
+}
+
+func (c *pamConfig) Run() {
+	c.startTask()
+	defer c.MarkComplete()
+
+	if err := c.validate(); err != nil {
+		c.setState(false)
+		c.setReason(ReasonInvalidConfig)
+		c.AddError(err)
+		return
+	}
+
+	lines, err := c.readService(c.Service, 0)
+	if err != nil {
+		c.setState(false)
+		c.AddError(err)
+		return
+	}
+
+	expected := strings.Join(strings.Fields(c.ModuleContains), " ")
+	present := c.Present == nil || *c.Present
+
+	var matches []string
+	for _, l := range lines {
+		if strings.Contains(l.text, expected) {
+			matches = append(matches, fmt.Sprintf("%s: %s", l.source, l.text))
+		}
+	}
+
+	if len(matches) == 0 {
+		c.setMessage(fmt.Sprintf("no matching lines in %d line(s) of the '%s' PAM configuration",
+			len(lines), c.Service))
+	} else {
+		c.setMessage(matches)
+	}
+
+	switch {
+	case present && len(matches) == 0:
+		c.setState(false)
+		c.setReason(ReasonValueMismatch)
+		c.AddError(errors.Errorf("no line in the '%s' PAM configuration contains '%s'", c.Service, expected))
+	case !present && len(matches) > 0:
+		c.setState(false)
+		c.setReason(ReasonValueMismatch)
+		c.AddError(errors.Errorf("%d line(s) in the '%s' PAM configuration contain '%s'",
+			len(matches), c.Service, expected))
+	default:
+		c.setState(true)
+	}
+}
+
+// readService returns the lines of the PAM configuration file for
+// the service, with the lines of the files that it includes in place
+// of the include lines.
+func (c *pamConfig) readService(service string, depth int) ([]pamLine, error) {
+	if depth > pamMaxIncludeDepth {
+		return nil, errors.Errorf("PAM configuration for '%s' has more than %d levels of includes",
+			c.Service, pamMaxIncludeDepth)
+	}
+
+	fn := filepath.Join(c.pamDir, service)
+	data, err := c.readFile(fn)
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []pamLine
+	for idx, raw := range strings.Split(string(data), "\n") {
+		if pos := strings.Index(raw, "#"); pos >= 0 {
+			raw = raw[:pos]
+		}
+
+		fields := strings.Fields(raw)
+		if len(fields) == 0 {
+			continue
+		}
+
+		include := ""
+		switch {
+		case fields[0] == "@include" && len(fields) > 1:
+			include = fields[1]
+		case len(fields) > 2 && (fields[1] == "include" || fields[1] == "substack"):
+			include = fields[2]
+		}
+
+		if include != "" && !strings.Contains(include, "/") {
+			included, err := c.readService(include, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			lines = append(lines, included...)
+			continue
+		}
+
+		lines = append(lines, pamLine{
+			source: fmt.Sprintf("%s:%d", fn, idx+1),
+			text:   strings.Join(fields, " "),
+		})
+	}
+
+	return lines, nil
+}
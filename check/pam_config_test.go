@@ -0,0 +1,141 @@
+package check
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type PAMConfigSuite struct {
+	tmpDir  string
+	check   *pamConfig
+	require *require.Assertions
+	suite.Suite
+}
+
+func TestPAMConfigSuite(t *testing.T) {
+	suite.Run(t, new(PAMConfigSuite))
+}
+
+func (s *PAMConfigSuite) SetupSuite() {
+	s.require = s.Require()
+
+	dir, err := ioutil.TempDir("", uuid.NewV4().String())
+	s.require.NoError(err)
+	s.tmpDir = dir
+
+	files := map[string]string{
+		"sshd": `# PAM configuration for the Secure Shell service
+@include common-auth
+account    required     pam_nologin.so
+session    include      system-session
+`,
+		"common-auth": `auth	required	pam_faillock.so preauth   # lock after failures
+auth	[success=1 default=ignore]	pam_unix.so nullok
+# auth	required	pam_tally2.so
+`,
+		"system-session": "session required pam_limits.so\n-session optional pam_systemd.so\n",
+		"loop":           "auth include loop\n",
+	}
+
+	for fn, content := range files {
+		s.require.NoError(ioutil.WriteFile(filepath.Join(dir, fn), []byte(content), 0644))
+	}
+}
+
+func (s *PAMConfigSuite) TearDownSuite() {
+	s.require.NoError(os.RemoveAll(s.tmpDir))
+}
+
+func (s *PAMConfigSuite) SetupTest() {
+	s.check = &pamConfig{
+		Base:   NewBase("pam-config", 0),
+		pamDir: s.tmpDir,
+	}
+}
+
+func (s *PAMConfigSuite) TestValidation() {
+	s.Error(s.check.validate())
+
+	s.check.Service = "sshd"
+	s.Error(s.check.validate())
+
+	s.check.ModuleContains = "pam_faillock.so"
+	s.NoError(s.check.validate())
+
+	s.check.Service = "../passwd"
+	s.Error(s.check.validate())
+}
+
+func (s *PAMConfigSuite) TestModuleInIncludedFile() {
+	s.check.Service = "sshd"
+	s.check.ModuleContains = "auth  required pam_faillock.so"
+	s.check.Run()
+
+	s.True(s.check.Output().Passed)
+	s.NoError(s.check.Error())
+	s.Equal(filepath.Join(s.tmpDir, "common-auth")+":1: auth required pam_faillock.so preauth",
+		s.check.Output().Message)
+
+	s.SetupTest()
+	s.check.Service = "sshd"
+	s.check.ModuleContains = "pam_limits.so"
+	s.check.Run()
+	s.True(s.check.Output().Passed)
+}
+
+func (s *PAMConfigSuite) TestMissingModule() {
+	s.check.Service = "sshd"
+	s.check.ModuleContains = "pam_tally2.so"
+	s.check.Run()
+
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "no line in the 'sshd' PAM configuration contains 'pam_tally2.so'")
+	s.Equal(ReasonValueMismatch, s.check.Output().ReasonCode)
+
+	absent := false
+	s.SetupTest()
+	s.check.Service = "sshd"
+	s.check.ModuleContains = "pam_tally2.so"
+	s.check.Present = &absent
+	s.check.Run()
+	s.True(s.check.Output().Passed)
+}
+
+func (s *PAMConfigSuite) TestForbiddenModule() {
+	absent := false
+	s.check.Service = "common-auth"
+	s.check.ModuleContains = "nullok"
+	s.check.Present = &absent
+	s.check.Run()
+
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "1 line(s) in the 'common-auth' PAM configuration contain 'nullok'")
+}
+
+func (s *PAMConfigSuite) TestIncludeCycle() {
+	s.check.Service = "loop"
+	s.check.ModuleContains = "pam_unix.so"
+	s.check.Run()
+
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "levels of includes")
+}
+
+func (s *PAMConfigSuite) TestMissingService() {
+	s.check.Service = "DOES-NOT-EXIST"
+	s.check.ModuleContains = "pam_unix.so"
+	s.check.Run()
+
+	s.False(s.check.Output().Passed)
+	s.Error(s.check.Error())
+	s.Equal(ReasonFileMissing, s.check.Output().ReasonCode)
+}
@@ -0,0 +1,134 @@
+package check
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/registry"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	name := "pid-file"
+	registry.AddJobType(name, func() amboy.Job {
+		return &pidFile{
+			Base:    NewBase(name, 0),
+			procDir: "/proc",
+		}
+	})
+}
+
+// maxCommLength is the length at which the kernel truncates the
+// command names in /proc/<pid>/comm and /proc/<pid>/stat.
+const maxCommLength = 15
+
+// pidFile checks that the file at path contains a valid pid. When
+// must_be_running is set, the process with that pid must also be
+// running, and the check reports pid files for processes that are
+// not running (or are zombies) as stale. When command is set, the
+// process must also have that command name, which the kernel
+// truncates to 15 characters. Checking processes is only supported
+// on Linux.
+type pidFile struct {
+	Path          string `bson:"path" json:"path" yaml:"path"`
+	MustBeRunning bool   `bson:"must_be_running" json:"must_be_running" yaml:"must_be_running"`
+	Command       string `bson:"command" json:"command" yaml:"command"`
+	*Base         `bson:"metadata" json:"metadata" yaml:"metadata"`
+	fileReadLimit
+
+	procDir string
+}
+
+func (c *pidFile) validate() error {
+	if c.Path == "" {
+		return errors.Errorf("no path specified for '%s' (%s) check", c.ID(), c.Name())
+	}
+
+	if c.Command != "" && !c.MustBeRunning {
+		return errors.Errorf("command requires must_be_running for '%s' check", c.ID())
+	}
+
+	return nil
+}
+
+func (c *pidFile) Run() {
+	c.startTask()
+	defer c.MarkComplete()
+
+	if err := c.validate(); err != nil {
+		c.setState(false)
+		c.setReason(ReasonInvalidConfig)
+		c.AddError(err)
+		return
+	}
+
+	data, err := c.readFile(c.Path)
+	if os.IsNotExist(errors.Cause(err)) {
+		c.setState(false)
+		c.AddError(errors.Errorf("pid file '%s' does not exist", c.Path))
+		return
+	} else if err != nil {
+		c.setState(false)
+		c.AddError(err)
+		return
+	}
+
+	content := strings.TrimSpace(string(data))
+	pid, err := strconv.Atoi(strings.SplitN(content, "\n", 2)[0])
+	if err != nil || pid <= 0 {
+		c.setState(false)
+		c.setMessage(content)
+		c.AddError(errors.Errorf("pid file '%s' does not contain a valid pid", c.Path))
+		return
+	}
+
+	if !c.MustBeRunning {
+		c.setState(true)
+		c.setMessage(fmt.Sprintf("pid file '%s' contains pid %d", c.Path, pid))
+		return
+	}
+
+	stat, err := c.readFile(filepath.Join(c.procDir, strconv.Itoa(pid), "stat"))
+	if os.IsNotExist(errors.Cause(err)) {
+		c.setState(false)
+		c.AddError(errors.Errorf("stale pid file '%s': process %d is not running", c.Path, pid))
+		return
+	} else if err != nil {
+		c.setState(false)
+		c.AddError(errors.Wrapf(err, "problem reading the status of process %d", pid))
+		return
+	}
+
+	comm, state, _, ok := parseProcStat(string(stat))
+	if !ok {
+		c.setState(false)
+		c.AddError(errors.Errorf("problem parsing the status of process %d", pid))
+		return
+	}
+
+	if state == "Z" {
+		c.setState(false)
+		c.AddError(errors.Errorf("stale pid file '%s': process %d (%s) is a zombie", c.Path, pid, comm))
+		return
+	}
+
+	c.setMessage(fmt.Sprintf("pid file '%s' contains pid %d (%s), which is running", c.Path, pid, comm))
+
+	expected := c.Command
+	if len(expected) > maxCommLength {
+		expected = expected[:maxCommLength]
+	}
+
+	if expected != "" && comm != expected {
+		c.setState(false)
+		c.AddError(errors.Errorf("process %d from pid file '%s' is '%s', expected '%s'",
+			pid, c.Path, comm, c.Command))
+		return
+	}
+
+	c.setState(true)
+}
@@ -0,0 +1,131 @@
+package check
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type PIDFileSuite struct {
+	tmpDir  string
+	check   *pidFile
+	require *require.Assertions
+	suite.Suite
+}
+
+func TestPIDFileSuite(t *testing.T) {
+	suite.Run(t, new(PIDFileSuite))
+}
+
+func (s *PIDFileSuite) SetupSuite() {
+	s.require = s.Require()
+
+	dir, err := ioutil.TempDir("", uuid.NewV4().String())
+	s.require.NoError(err)
+	s.tmpDir = dir
+
+	files := map[string]string{
+		"run/nginx.pid":   "1200\n",
+		"run/stale.pid":   "4242\n",
+		"run/zombie.pid":  "1300",
+		"run/garbage.pid": "not a pid\n",
+		"run/zero.pid":    "0\n",
+		"run/long.pid":    "1400\n",
+		"proc/1200/stat":  "1200 (nginx) S 1 1200 1200 0 -1 4194560",
+		"proc/1300/stat":  "1300 (worker) Z 1200 1200 1200 0 -1 4194564",
+		"proc/1400/stat":  "1400 (prometheus-node) S 1 1400 1400 0 -1 4194560",
+	}
+
+	for fn, content := range files {
+		path := filepath.Join(dir, fn)
+		s.require.NoError(os.MkdirAll(filepath.Dir(path), 0755))
+		s.require.NoError(ioutil.WriteFile(path, []byte(content), 0644))
+	}
+}
+
+func (s *PIDFileSuite) TearDownSuite() {
+	s.require.NoError(os.RemoveAll(s.tmpDir))
+}
+
+func (s *PIDFileSuite) SetupTest() {
+	s.check = &pidFile{
+		Base:    NewBase("pid-file", 0),
+		procDir: filepath.Join(s.tmpDir, "proc"),
+	}
+}
+
+func (s *PIDFileSuite) runCheck(name string, running bool, command string) {
+	s.SetupTest()
+	s.check.Path = filepath.Join(s.tmpDir, "run", name)
+	s.check.MustBeRunning = running
+	s.check.Command = command
+	s.check.Run()
+}
+
+func (s *PIDFileSuite) TestValidation() {
+	s.Error(s.check.validate())
+
+	s.check.Path = "/run/nginx.pid"
+	s.NoError(s.check.validate())
+
+	s.check.Command = "nginx"
+	s.Error(s.check.validate())
+
+	s.check.MustBeRunning = true
+	s.NoError(s.check.validate())
+}
+
+func (s *PIDFileSuite) TestRunningProcessPasses() {
+	s.runCheck("nginx.pid", true, "nginx")
+	s.True(s.check.Output().Passed)
+	s.NoError(s.check.Error())
+	s.Contains(s.check.Output().Message, "contains pid 1200 (nginx), which is running")
+
+	// command names are truncated to 15 characters
+	s.runCheck("long.pid", true, "prometheus-node-exporter")
+	s.True(s.check.Output().Passed)
+	s.NoError(s.check.Error())
+}
+
+func (s *PIDFileSuite) TestWrongCommandFails() {
+	s.runCheck("nginx.pid", true, "httpd")
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "process 1200 from pid file")
+	s.Contains(s.check.Error().Error(), "is 'nginx', expected 'httpd'")
+}
+
+func (s *PIDFileSuite) TestStalePIDFilesFail() {
+	s.runCheck("stale.pid", false, "")
+	s.True(s.check.Output().Passed)
+
+	s.runCheck("stale.pid", true, "")
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "stale pid file")
+	s.Contains(s.check.Error().Error(), "process 4242 is not running")
+
+	s.runCheck("zombie.pid", true, "")
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "process 1300 (worker) is a zombie")
+}
+
+func (s *PIDFileSuite) TestInvalidPIDFilesFail() {
+	for _, name := range []string{"garbage.pid", "zero.pid"} {
+		s.runCheck(name, false, "")
+		s.False(s.check.Output().Passed, name)
+		s.require.Error(s.check.Error(), name)
+		s.Contains(s.check.Error().Error(), "does not contain a valid pid", name)
+	}
+
+	s.runCheck("missing.pid", false, "")
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "does not exist")
+}
@@ -0,0 +1,58 @@
+package check
+
+import (
+	"plugin"
+
+	"github.com/pkg/errors"
+	"github.com/tychoish/grip"
+)
+
+// LoadPlugins opens the Go plugins (shared objects built with "go
+// build -buildmode=plugin") at the specified paths. A plugin adds
+// check types by calling registry.AddJobType in its init() function,
+// the same way that the checks in this package register
+// themselves. LoadPlugins returns the names of the check types that
+// the plugins added, and an error if any plugin could not be loaded.
+//
+// The Go plugin package imposes several constraints on plugins:
+//
+//   - plugins are only supported on linux, darwin, and freebsd, and
+//     both greenbay and the plugin must be built with cgo enabled.
+//
+//   - the plugin must be built with the same version of Go, and the
+//     same build flags, as the greenbay binary that loads it.
+//
+//   - every package that the plugin shares with greenbay, including
+//     amboy and greenbay itself, must be built from identical
+//     sources. Because greenbay vendors its dependencies, build
+//     plugins from within the greenbay source tree, so that the
+//     plugin registers checks with the same registry package that
+//     greenbay uses.
+//
+//   - plugins cannot be unloaded, and loading the same plugin twice
+//     has no effect.
+func LoadPlugins(paths ...string) ([]string, error) {
+	before := make(map[string]struct{})
+	for _, name := range RegisteredChecks() {
+		before[name] = struct{}{}
+	}
+
+	catcher := grip.NewCatcher()
+	for _, fn := range paths {
+		if _, err := plugin.Open(fn); err != nil {
+			catcher.Add(errors.Wrapf(err, "problem loading plugin '%s'", fn))
+			continue
+		}
+
+		grip.Infoln("loaded plugin:", fn)
+	}
+
+	var added []string
+	for _, name := range RegisteredChecks() {
+		if _, ok := before[name]; !ok {
+			added = append(added, name)
+		}
+	}
+
+	return added, catcher.Resolve()
+}
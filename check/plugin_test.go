@@ -0,0 +1,31 @@
+package check
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadPluginsWithoutPathsIsANoop(t *testing.T) {
+	added, err := LoadPlugins()
+	assert.NoError(t, err)
+	assert.Len(t, added, 0)
+}
+
+func TestLoadPluginsErrorsForInvalidPlugins(t *testing.T) {
+	dir, err := ioutil.TempDir("", uuid.NewV4().String())
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	fn := filepath.Join(dir, "invalid.so")
+	require.NoError(t, ioutil.WriteFile(fn, []byte("not a shared object"), 0644))
+
+	added, err := LoadPlugins(fn, filepath.Join(dir, "DOES-NOT-EXIST.so"))
+	assert.Error(t, err)
+	assert.Len(t, added, 0)
+}
@@ -0,0 +1,176 @@
+package check
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/registry"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	name := "process-count"
+	registry.AddJobType(name, func() amboy.Job {
+		return &processCount{
+			Base:    NewBase(name, 0),
+			procDir: "/proc",
+		}
+	})
+}
+
+// processCount checks that the number of processes on the system is
+// within the specified bounds. If a name is specified, only processes
+// with that name (as reported in /proc/<pid>/comm) are counted;
+// otherwise all processes are counted. Only supported on Linux.
+type processCount struct {
+	ProcessName string `bson:"name" json:"name" yaml:"name"`
+	Min         *int   `bson:"min" json:"min" yaml:"min"`
+	Max         *int   `bson:"max" json:"max" yaml:"max"`
+	*Base       `bson:"metadata" json:"metadata" yaml:"metadata"`
+	fileReadLimit
+
+	procDir string
+}
+
+// processCountReportSize is the number of process names to report
+// when a process count check fails.
+const processCountReportSize = 5
+
+func (c *processCount) validate() error {
+	if c.Min == nil && c.Max == nil {
+		return errors.Errorf("no min or max specified for '%s' (%s) check", c.ID(), c.Name())
+	}
+
+	if c.Min != nil && c.Max != nil && *c.Min > *c.Max {
+		return errors.Errorf("min (%d) for '%s' check is greater than max (%d)",
+			*c.Min, c.ID(), *c.Max)
+	}
+
+	return nil
+}
+
+func (c *processCount) Run() {
+	c.startTask()
+	defer c.MarkComplete()
+
+	if err := c.validate(); err != nil {
+		c.setState(false)
+		c.setReason(ReasonInvalidConfig)
+		c.AddError(err)
+		return
+	}
+
+	counts, err := c.countProcesses()
+	if err != nil {
+		c.setState(false)
+		c.AddError(err)
+		return
+	}
+
+	var num int
+	if c.ProcessName == "" {
+		for _, n := range counts {
+			num += n
+		}
+	} else {
+		num = counts[c.ProcessName]
+	}
+
+	desc := "processes"
+	if c.ProcessName != "" {
+		desc = fmt.Sprintf("processes named '%s'", c.ProcessName)
+	}
+
+	msg := fmt.Sprintf("found %d %s", num, desc)
+
+	var errs []string
+	if c.Min != nil && num < *c.Min {
+		errs = append(errs, fmt.Sprintf("fewer than %d %s", *c.Min, desc))
+	}
+
+	if c.Max != nil && num > *c.Max {
+		errs = append(errs, fmt.Sprintf("more than %d %s", *c.Max, desc))
+	}
+
+	if len(errs) == 0 {
+		c.setState(true)
+		c.setMessage(msg)
+		return
+	}
+
+	c.setState(false)
+	c.setMessage(fmt.Sprintf("%s; most common: [%s]", msg, topProcessNames(counts)))
+	c.AddError(errors.Errorf("found %d %s, which is %s", num, desc, strings.Join(errs, " and ")))
+}
+
+func (c *processCount) countProcesses() (map[string]int, error) {
+	dirs, err := ioutil.ReadDir(c.procDir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem reading process table from '%s'", c.procDir)
+	}
+
+	counts := make(map[string]int)
+	for _, info := range dirs {
+		if !info.IsDir() {
+			continue
+		}
+
+		if _, err := strconv.Atoi(info.Name()); err != nil {
+			continue
+		}
+
+		// processes may exit while we're reading the process
+		// table, so we ignore processes we cannot read.
+		comm, err := c.readFile(filepath.Join(c.procDir, info.Name(), "comm"))
+		if err != nil {
+			continue
+		}
+
+		counts[strings.TrimSpace(string(comm))]++
+	}
+
+	return counts, nil
+}
+
+func topProcessNames(counts map[string]int) string {
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+
+	sort.Sort(&processNamesByCount{names: names, counts: counts})
+
+	if len(names) > processCountReportSize {
+		names = names[:processCountReportSize]
+	}
+
+	out := make([]string, 0, len(names))
+	for _, name := range names {
+		out = append(out, fmt.Sprintf("%s=%d", name, counts[name]))
+	}
+
+	return strings.Join(out, ", ")
+}
+
+// processNamesByCount sorts process names by descending count, and
+// then by name.
+type processNamesByCount struct {
+	names  []string
+	counts map[string]int
+}
+
+func (s *processNamesByCount) Len() int      { return len(s.names) }
+func (s *processNamesByCount) Swap(i, j int) { s.names[i], s.names[j] = s.names[j], s.names[i] }
+func (s *processNamesByCount) Less(i, j int) bool {
+	ci, cj := s.counts[s.names[i]], s.counts[s.names[j]]
+	if ci != cj {
+		return ci > cj
+	}
+
+	return s.names[i] < s.names[j]
+}
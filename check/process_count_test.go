@@ -0,0 +1,114 @@
+package check
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type ProcessCountSuite struct {
+	tmpDir  string
+	check   *processCount
+	require *require.Assertions
+	suite.Suite
+}
+
+func TestProcessCountSuite(t *testing.T) {
+	suite.Run(t, new(ProcessCountSuite))
+}
+
+func (s *ProcessCountSuite) SetupSuite() {
+	s.require = s.Require()
+
+	dir, err := ioutil.TempDir("", uuid.NewV4().String())
+	s.require.NoError(err)
+	s.tmpDir = dir
+
+	procs := []string{"init", "sshd", "sshd", "mongod", "nginx", "nginx", "nginx"}
+	for idx, name := range procs {
+		pidDir := filepath.Join(dir, fmt.Sprint(idx+1))
+		s.require.NoError(os.MkdirAll(pidDir, 0755))
+		s.require.NoError(ioutil.WriteFile(filepath.Join(pidDir, "comm"), []byte(name+"\n"), 0644))
+	}
+
+	// non-process entries in the process table are ignored.
+	s.require.NoError(os.MkdirAll(filepath.Join(dir, "sys"), 0755))
+	s.require.NoError(ioutil.WriteFile(filepath.Join(dir, "uptime"), []byte("1 1"), 0644))
+}
+
+func (s *ProcessCountSuite) TearDownSuite() {
+	s.require.NoError(os.RemoveAll(s.tmpDir))
+}
+
+func (s *ProcessCountSuite) SetupTest() {
+	s.check = &processCount{
+		Base:    NewBase("process-count", 0),
+		procDir: s.tmpDir,
+	}
+}
+
+func intPtr(n int) *int { return &n }
+
+func (s *ProcessCountSuite) TestValidation() {
+	s.Error(s.check.validate())
+
+	s.check.Max = intPtr(1)
+	s.NoError(s.check.validate())
+
+	s.check.Min = intPtr(2)
+	s.Error(s.check.validate())
+
+	s.check.Max = nil
+	s.NoError(s.check.validate())
+}
+
+func (s *ProcessCountSuite) TestCountingAllProcesses() {
+	s.check.Max = intPtr(7)
+	s.check.Run()
+	s.True(s.check.Output().Passed)
+	s.Contains(s.check.Output().Message, "found 7 processes")
+
+	s.SetupTest()
+	s.check.Max = intPtr(6)
+	s.check.Run()
+	output := s.check.Output()
+	s.False(output.Passed)
+	s.Error(s.check.Error())
+	s.Contains(output.Message, "nginx=3, sshd=2, init=1, mongod=1")
+}
+
+func (s *ProcessCountSuite) TestCountingNamedProcesses() {
+	s.check.ProcessName = "sshd"
+	s.check.Min = intPtr(1)
+	s.check.Max = intPtr(2)
+	s.check.Run()
+	s.True(s.check.Output().Passed)
+	s.NoError(s.check.Error())
+
+	s.SetupTest()
+	s.check.ProcessName = "postgres"
+	s.check.Min = intPtr(1)
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.Error(s.check.Error())
+
+	s.SetupTest()
+	s.check.ProcessName = "postgres"
+	s.check.Max = intPtr(0)
+	s.check.Run()
+	s.True(s.check.Output().Passed)
+}
+
+func (s *ProcessCountSuite) TestMissingProcessTableFails() {
+	s.check.procDir = filepath.Join(s.tmpDir, "DOES-NOT-EXIST")
+	s.check.Max = intPtr(10)
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.Error(s.check.Error())
+}
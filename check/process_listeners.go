@@ -0,0 +1,257 @@
+package check
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/registry"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	name := "process-listeners"
+	registry.AddJobType(name, func() amboy.Job {
+		return &processListeners{
+			Base:    NewBase(name, 0),
+			procDir: "/proc",
+		}
+	})
+}
+
+// listenerProtocols are the socket tables, in /proc/<pid>/net, that
+// the process-listeners check reads.
+var listenerProtocols = []string{"tcp", "tcp6", "udp", "udp6"}
+
+// tcpListenState is the state of listening TCP sockets in the
+// /proc/net/tcp tables.
+const tcpListenState = "0A"
+
+// processListeners checks that the processes with the specified name
+// (as reported in /proc/<pid>/comm), or the process whose pid is in
+// pid_file, only listen on the ports in allowed_ports, which catches
+// services that open debug or admin ports. Listening sockets are TCP
+// sockets in the LISTEN state and unconnected UDP sockets, in the
+// network namespace of the process, that the process has open, which
+// the check finds by matching the socket inodes in /proc/<pid>/fd to
+// the socket tables in /proc/<pid>/net. Reading the open files of a
+// process requires running as the same user or as root. An empty
+// allowed_ports list requires that the processes not listen on any
+// port. Only supported on Linux.
+type processListeners struct {
+	ProcessName  string `bson:"name" json:"name" yaml:"name"`
+	PIDFile      string `bson:"pid_file" json:"pid_file" yaml:"pid_file"`
+	AllowedPorts []int  `bson:"allowed_ports" json:"allowed_ports" yaml:"allowed_ports"`
+	*Base        `bson:"metadata" json:"metadata" yaml:"metadata"`
+	fileReadLimit
+
+	procDir string
+}
+
+// listener is a listening socket, as "<protocol>:<port>".
+type listener struct {
+	protocol string
+	port     int
+}
+
+func (l listener) String() string { return fmt.Sprintf("%s:%d", l.protocol, l.port) }
+
+// listenersByPort sorts listeners by port, and then by protocol.
+type listenersByPort []listener
+
+func (l listenersByPort) Len() int      { return len(l) }
+func (l listenersByPort) Swap(i, j int) { l[i], l[j] = l[j], l[i] }
+func (l listenersByPort) Less(i, j int) bool {
+	if l[i].port != l[j].port {
+		return l[i].port < l[j].port
+	}
+
+	return l[i].protocol < l[j].protocol
+}
+
+func (c *processListeners) validate() error {
+	if (c.ProcessName == "") == (c.PIDFile == "") {
+		return errors.Errorf("must specify exactly one of name or pid file for '%s' (%s) check",
+			c.ID(), c.Name())
+	}
+
+	for _, port := range c.AllowedPorts {
+		if port <= 0 || port > 65535 {
+			return errors.Errorf("allowed port %d for '%s' check is not a valid port", port, c.ID())
+		}
+	}
+
+	return nil
+}
+
+func (c *processListeners) Run() {
+	c.startTask()
+	defer c.MarkComplete()
+
+	if err := c.validate(); err != nil {
+		c.setState(false)
+		c.setReason(ReasonInvalidConfig)
+		c.AddError(err)
+		return
+	}
+
+	pids, err := findProcesses(c.fileReadLimit, c.procDir, c.ProcessName, c.PIDFile)
+	if err != nil {
+		c.setState(false)
+		c.AddError(err)
+		return
+	}
+
+	desc := fmt.Sprintf("processes named '%s'", c.ProcessName)
+	if c.PIDFile != "" {
+		desc = fmt.Sprintf("process in '%s'", c.PIDFile)
+	}
+
+	if len(pids) == 0 {
+		c.setState(false)
+		c.AddError(errors.Errorf("found no %s", desc))
+		return
+	}
+
+	seen := make(map[listener]struct{})
+	var listeners []listener
+	for _, pid := range pids {
+		found, err := c.processListeners(pid)
+		if err != nil {
+			c.setState(false)
+			c.AddError(err)
+			return
+		}
+
+		for _, l := range found {
+			if _, ok := seen[l]; ok {
+				continue
+			}
+			seen[l] = struct{}{}
+			listeners = append(listeners, l)
+		}
+	}
+	sort.Sort(listenersByPort(listeners))
+
+	allowed := make(map[int]struct{}, len(c.AllowedPorts))
+	for _, port := range c.AllowedPorts {
+		allowed[port] = struct{}{}
+	}
+
+	all := make([]string, 0, len(listeners))
+	var unexpected []string
+	for _, l := range listeners {
+		all = append(all, l.String())
+		if _, ok := allowed[l.port]; !ok {
+			unexpected = append(unexpected, l.String())
+		}
+	}
+
+	c.setMessage(fmt.Sprintf("%s (pids: %s) listening on [%s]",
+		desc, strings.Join(pids, ", "), strings.Join(all, ", ")))
+
+	if len(unexpected) > 0 {
+		c.setState(false)
+		c.setReason(ReasonValueMismatch)
+		c.AddError(errors.Errorf("%s listening on unexpected ports: %s",
+			desc, strings.Join(unexpected, ", ")))
+		return
+	}
+
+	c.setState(true)
+}
+
+// processListeners returns the listening sockets that the process
+// has open.
+func (c *processListeners) processListeners(pid string) ([]listener, error) {
+	fdDir := filepath.Join(c.procDir, pid, "fd")
+	fds, err := ioutil.ReadDir(fdDir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem reading open files of process %s", pid)
+	}
+
+	inodes := make(map[string]struct{})
+	for _, fd := range fds {
+		// files may close while we're reading them, so we
+		// ignore files we cannot read.
+		target, err := os.Readlink(filepath.Join(fdDir, fd.Name()))
+		if err != nil {
+			continue
+		}
+
+		if strings.HasPrefix(target, "socket:[") && strings.HasSuffix(target, "]") {
+			inodes[strings.TrimSuffix(strings.TrimPrefix(target, "socket:["), "]")] = struct{}{}
+		}
+	}
+
+	if len(inodes) == 0 {
+		return nil, nil
+	}
+
+	var listeners []listener
+	for _, protocol := range listenerProtocols {
+		fn := filepath.Join(c.procDir, pid, "net", protocol)
+		data, err := c.readFile(fn)
+		if os.IsNotExist(errors.Cause(err)) {
+			continue
+		} else if err != nil {
+			return nil, errors.Wrapf(err, "problem reading socket table '%s'", fn)
+		}
+
+		for inode, port := range parseListeningSockets(protocol, data) {
+			if _, ok := inodes[inode]; ok {
+				listeners = append(listeners, listener{protocol: protocol, port: port})
+			}
+		}
+	}
+
+	return listeners, nil
+}
+
+// parseListeningSockets returns the ports of the listening sockets in
+// a /proc/net socket table, by inode. Table rows have the local and
+// remote addresses, as hex "<address>:<port>", in the second and
+// third columns, the state in the fourth, and the inode in the
+// tenth.
+func parseListeningSockets(protocol string, data []byte) map[string]int {
+	ports := make(map[string]int)
+	udp := strings.HasPrefix(protocol, "udp")
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 10 || fields[0] == "sl" {
+			continue
+		}
+
+		local := strings.Split(fields[1], ":")
+		remote := strings.Split(fields[2], ":")
+		if len(local) != 2 || len(remote) != 2 {
+			continue
+		}
+
+		if udp {
+			if strings.Trim(remote[1], "0") != "" {
+				continue
+			}
+		} else if fields[3] != tcpListenState {
+			continue
+		}
+
+		port, err := strconv.ParseInt(local[1], 16, 32)
+		if err != nil || port == 0 {
+			continue
+		}
+
+		ports[fields[9]] = int(port)
+	}
+
+	return ports
+}
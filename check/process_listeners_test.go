@@ -0,0 +1,152 @@
+package check
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const processListenersTCPTable = `  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 00000000:0016 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 1001 1 0000000000000000 100 0 0 10 0
+   1: 00000000:1F90 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 1002 1 0000000000000000 100 0 0 10 0
+   2: 00000000:6989 00000000:0000 0A 00000000:00000000 00:00000000 00000000   999        0 2001 1 0000000000000000 100 0 0 10 0
+   3: 0100007F:6D71 00000000:0000 0A 00000000:00000000 00:00000000 00000000   999        0 2002 1 0000000000000000 100 0 0 10 0
+   4: 0A00000F:6989 0A000010:C350 01 00000000:00000000 00:00000000 00000000   999        0 2004 1 0000000000000000 20 4 30 10 -1
+`
+
+const processListenersUDPTable = `  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode ref pointer drops
+  10: 0A00000F:A1B2 0A000001:0035 01 00000000:00000000 00:00000000 00000000   999        0 2003 2 0000000000000000 0
+`
+
+type ProcessListenersSuite struct {
+	tmpDir  string
+	check   *processListeners
+	require *require.Assertions
+	suite.Suite
+}
+
+func TestProcessListenersSuite(t *testing.T) {
+	suite.Run(t, new(ProcessListenersSuite))
+}
+
+func (s *ProcessListenersSuite) SetupSuite() {
+	s.require = s.Require()
+
+	dir, err := ioutil.TempDir("", uuid.NewV4().String())
+	s.require.NoError(err)
+	s.tmpDir = dir
+
+	procs := []struct {
+		name    string
+		sockets []string
+	}{
+		{"sshd", []string{"socket:[1001]"}},
+		{"mongod", []string{"socket:[2001]", "socket:[2002]", "socket:[2003]", "socket:[2004]", "/dev/null"}},
+		{"worker", []string{"pipe:[3001]"}},
+	}
+
+	for idx, proc := range procs {
+		pidDir := filepath.Join(dir, fmt.Sprint(idx+1))
+		s.require.NoError(os.MkdirAll(filepath.Join(pidDir, "fd"), 0755))
+		s.require.NoError(os.MkdirAll(filepath.Join(pidDir, "net"), 0755))
+		s.require.NoError(ioutil.WriteFile(filepath.Join(pidDir, "comm"), []byte(proc.name+"\n"), 0644))
+		s.require.NoError(ioutil.WriteFile(filepath.Join(pidDir, "net", "tcp"), []byte(processListenersTCPTable), 0644))
+		s.require.NoError(ioutil.WriteFile(filepath.Join(pidDir, "net", "udp"), []byte(processListenersUDPTable), 0644))
+
+		for fd, target := range proc.sockets {
+			s.require.NoError(os.Symlink(target, filepath.Join(pidDir, "fd", fmt.Sprint(fd+3))))
+		}
+	}
+
+	s.require.NoError(ioutil.WriteFile(filepath.Join(dir, "mongod.pid"), []byte("2\n"), 0644))
+}
+
+func (s *ProcessListenersSuite) TearDownSuite() {
+	s.require.NoError(os.RemoveAll(s.tmpDir))
+}
+
+func (s *ProcessListenersSuite) SetupTest() {
+	s.check = &processListeners{
+		Base:    NewBase("process-listeners", 0),
+		procDir: s.tmpDir,
+	}
+}
+
+func (s *ProcessListenersSuite) TestValidation() {
+	s.Error(s.check.validate())
+
+	s.check.ProcessName = "mongod"
+	s.NoError(s.check.validate())
+
+	s.check.PIDFile = filepath.Join(s.tmpDir, "mongod.pid")
+	s.Error(s.check.validate())
+
+	s.check.ProcessName = ""
+	s.check.AllowedPorts = []int{27017, 0}
+	s.Error(s.check.validate())
+
+	s.check.AllowedPorts = []int{27017, 70000}
+	s.Error(s.check.validate())
+
+	s.check.AllowedPorts = []int{27017}
+	s.NoError(s.check.validate())
+}
+
+func (s *ProcessListenersSuite) TestParseListeningSockets() {
+	s.Equal(map[string]int{"1001": 22, "1002": 8080, "2001": 27017, "2002": 28017},
+		parseListeningSockets("tcp", []byte(processListenersTCPTable)))
+	s.Len(parseListeningSockets("udp", []byte(processListenersUDPTable)), 0)
+	s.Equal(map[string]int{"4001": 123},
+		parseListeningSockets("udp", []byte("  10: 00000000:007B 00000000:0000 07 00000000:00000000 00:00000000 00000000 0 0 4001 2 0 0\n")))
+}
+
+func (s *ProcessListenersSuite) TestAllowedPorts() {
+	s.check.ProcessName = "mongod"
+	s.check.AllowedPorts = []int{27017, 28017}
+	s.check.Run()
+
+	s.True(s.check.Output().Passed)
+	s.NoError(s.check.Error())
+	s.Equal("processes named 'mongod' (pids: 2) listening on [tcp:27017, tcp:28017]", s.check.Output().Message)
+}
+
+func (s *ProcessListenersSuite) TestUnexpectedPorts() {
+	s.check.PIDFile = filepath.Join(s.tmpDir, "mongod.pid")
+	s.check.AllowedPorts = []int{27017}
+	s.check.Run()
+
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "listening on unexpected ports: tcp:28017")
+	s.Equal(ReasonValueMismatch, s.check.Output().ReasonCode)
+}
+
+func (s *ProcessListenersSuite) TestProcessWithoutSockets() {
+	s.check.ProcessName = "worker"
+	s.check.Run()
+
+	s.True(s.check.Output().Passed)
+	s.NoError(s.check.Error())
+
+	s.SetupTest()
+	s.check.ProcessName = "sshd"
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "unexpected ports: tcp:22")
+}
+
+func (s *ProcessListenersSuite) TestMissingProcess() {
+	s.check.ProcessName = "postgres"
+	s.check.Run()
+
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "found no processes named 'postgres'")
+}
@@ -0,0 +1,222 @@
+package check
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/registry"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	name := "process-threads"
+	registry.AddJobType(name, func() amboy.Job {
+		return &processThreads{
+			Base:     NewBase(name, 0),
+			Multiple: "sum",
+			procDir:  "/proc",
+		}
+	})
+}
+
+// processThreads checks that the number of threads in the processes
+// with the specified name (as reported in /proc/<pid>/comm), or in
+// the process whose pid is in pid_file, is within the specified
+// bounds. When more than one process matches the name, "multiple"
+// determines how the check handles them: "sum" (the default)
+// compares the total number of threads across all processes, and
+// "single" fails unless exactly one process matches. Only supported
+// on Linux.
+type processThreads struct {
+	ProcessName string `bson:"name" json:"name" yaml:"name"`
+	PIDFile     string `bson:"pid_file" json:"pid_file" yaml:"pid_file"`
+	MinThreads  *int   `bson:"min_threads" json:"min_threads" yaml:"min_threads"`
+	MaxThreads  *int   `bson:"max_threads" json:"max_threads" yaml:"max_threads"`
+	Multiple    string `bson:"multiple" json:"multiple" yaml:"multiple"`
+	*Base       `bson:"metadata" json:"metadata" yaml:"metadata"`
+	fileReadLimit
+
+	procDir string
+}
+
+func (c *processThreads) validate() error {
+	if (c.ProcessName == "") == (c.PIDFile == "") {
+		return errors.Errorf("must specify exactly one of name or pid file for '%s' (%s) check",
+			c.ID(), c.Name())
+	}
+
+	if c.MinThreads == nil && c.MaxThreads == nil {
+		return errors.Errorf("no min or max threads specified for '%s' check", c.ID())
+	}
+
+	if c.MinThreads != nil && c.MaxThreads != nil && *c.MinThreads > *c.MaxThreads {
+		return errors.Errorf("min threads (%d) for '%s' check is greater than max threads (%d)",
+			*c.MinThreads, c.ID(), *c.MaxThreads)
+	}
+
+	if c.Multiple != "sum" && c.Multiple != "single" {
+		return errors.Errorf("'%s' is not a valid value of multiple for '%s' check, "+
+			"must be 'sum' or 'single'", c.Multiple, c.ID())
+	}
+
+	return nil
+}
+
+func (c *processThreads) Run() {
+	c.startTask()
+	defer c.MarkComplete()
+
+	if err := c.validate(); err != nil {
+		c.setState(false)
+		c.setReason(ReasonInvalidConfig)
+		c.AddError(err)
+		return
+	}
+
+	pids, err := c.findProcesses()
+	if err != nil {
+		c.setState(false)
+		c.AddError(err)
+		return
+	}
+
+	desc := fmt.Sprintf("processes named '%s'", c.ProcessName)
+	if c.PIDFile != "" {
+		desc = fmt.Sprintf("process in '%s'", c.PIDFile)
+	}
+
+	if len(pids) == 0 {
+		c.setState(false)
+		c.AddError(errors.Errorf("found no %s", desc))
+		return
+	}
+
+	if c.Multiple == "single" && len(pids) > 1 {
+		c.setState(false)
+		c.setMessage(fmt.Sprintf("pids: [%s]", strings.Join(pids, ", ")))
+		c.AddError(errors.Errorf("found %d %s, expected exactly one", len(pids), desc))
+		return
+	}
+
+	var total int
+	counts := make([]string, 0, len(pids))
+	for _, pid := range pids {
+		num, err := c.threadCount(pid)
+		if err != nil {
+			c.setState(false)
+			c.AddError(err)
+			return
+		}
+
+		total += num
+		counts = append(counts, fmt.Sprintf("%s=%d", pid, num))
+	}
+
+	c.setMessage(fmt.Sprintf("found %d threads in %d %s [%s]",
+		total, len(pids), desc, strings.Join(counts, ", ")))
+
+	var errs []string
+	if c.MinThreads != nil && total < *c.MinThreads {
+		errs = append(errs, fmt.Sprintf("fewer than %d", *c.MinThreads))
+	}
+
+	if c.MaxThreads != nil && total > *c.MaxThreads {
+		errs = append(errs, fmt.Sprintf("more than %d", *c.MaxThreads))
+	}
+
+	if len(errs) > 0 {
+		c.setState(false)
+		c.AddError(errors.Errorf("found %d threads in %s, which is %s",
+			total, desc, strings.Join(errs, " and ")))
+		return
+	}
+
+	c.setState(true)
+}
+
+// findProcesses returns the pids of the processes that the check
+// selects, in the order of the process table.
+func (c *processThreads) findProcesses() ([]string, error) {
+	return findProcesses(c.fileReadLimit, c.procDir, c.ProcessName, c.PIDFile)
+}
+
+// findProcesses returns the pid in pidFile, if set, or the pids of
+// the processes named name (as reported in <procDir>/<pid>/comm), in
+// the order of the process table. Files are read within limit.
+func findProcesses(limit fileReadLimit, procDir, name, pidFile string) ([]string, error) {
+	if pidFile != "" {
+		data, err := limit.readFile(pidFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "problem reading pid file '%s'", pidFile)
+		}
+
+		pid := strings.TrimSpace(string(data))
+		if _, err := strconv.Atoi(pid); err != nil {
+			return nil, errors.Errorf("pid file '%s' does not contain a pid", pidFile)
+		}
+
+		return []string{pid}, nil
+	}
+
+	dirs, err := ioutil.ReadDir(procDir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem reading process table from '%s'", procDir)
+	}
+
+	var pids []string
+	for _, info := range dirs {
+		if !info.IsDir() {
+			continue
+		}
+
+		if _, err := strconv.Atoi(info.Name()); err != nil {
+			continue
+		}
+
+		// processes may exit while we're reading the process
+		// table, so we ignore processes we cannot read.
+		comm, err := limit.readFile(filepath.Join(procDir, info.Name(), "comm"))
+		if err != nil {
+			continue
+		}
+
+		if strings.TrimSpace(string(comm)) == name {
+			pids = append(pids, info.Name())
+		}
+	}
+
+	return pids, nil
+}
+
+// threadCount returns the value of the Threads field in
+// /proc/<pid>/status.
+func (c *processThreads) threadCount(pid string) (int, error) {
+	fn := filepath.Join(c.procDir, pid, "status")
+	data, err := c.readFile(fn)
+	if err != nil {
+		return 0, errors.Wrapf(err, "problem reading status of process %s", pid)
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "Threads:") {
+			continue
+		}
+
+		num, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "Threads:")))
+		if err != nil {
+			return 0, errors.Wrapf(err, "problem parsing thread count in '%s'", fn)
+		}
+
+		return num, nil
+	}
+
+	return 0, errors.Errorf("'%s' does not report a thread count", fn)
+}
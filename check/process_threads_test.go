@@ -0,0 +1,147 @@
+package check
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type ProcessThreadsSuite struct {
+	tmpDir  string
+	check   *processThreads
+	require *require.Assertions
+	suite.Suite
+}
+
+func TestProcessThreadsSuite(t *testing.T) {
+	suite.Run(t, new(ProcessThreadsSuite))
+}
+
+func (s *ProcessThreadsSuite) SetupSuite() {
+	s.require = s.Require()
+
+	dir, err := ioutil.TempDir("", uuid.NewV4().String())
+	s.require.NoError(err)
+	s.tmpDir = dir
+
+	procs := []struct {
+		name    string
+		threads int
+	}{
+		{"init", 1},
+		{"mongod", 32},
+		{"nginx", 4},
+		{"nginx", 4},
+	}
+
+	for idx, proc := range procs {
+		pidDir := filepath.Join(dir, fmt.Sprint(idx+1))
+		s.require.NoError(os.MkdirAll(pidDir, 0755))
+		s.require.NoError(ioutil.WriteFile(filepath.Join(pidDir, "comm"), []byte(proc.name+"\n"), 0644))
+		status := fmt.Sprintf("Name:\t%s\nState:\tS (sleeping)\nThreads:\t%d\nVmRSS:\t1024 kB\n",
+			proc.name, proc.threads)
+		s.require.NoError(ioutil.WriteFile(filepath.Join(pidDir, "status"), []byte(status), 0644))
+	}
+
+	s.require.NoError(ioutil.WriteFile(filepath.Join(dir, "mongod.pid"), []byte("2\n"), 0644))
+}
+
+func (s *ProcessThreadsSuite) TearDownSuite() {
+	s.require.NoError(os.RemoveAll(s.tmpDir))
+}
+
+func (s *ProcessThreadsSuite) SetupTest() {
+	s.check = &processThreads{
+		Base:     NewBase("process-threads", 0),
+		Multiple: "sum",
+		procDir:  s.tmpDir,
+	}
+}
+
+func (s *ProcessThreadsSuite) TestValidation() {
+	s.Error(s.check.validate())
+
+	s.check.ProcessName = "mongod"
+	s.Error(s.check.validate())
+
+	s.check.MinThreads = intPtr(10)
+	s.NoError(s.check.validate())
+
+	s.check.PIDFile = "mongod.pid"
+	s.Error(s.check.validate())
+
+	s.check.PIDFile = ""
+	s.check.MaxThreads = intPtr(5)
+	s.Error(s.check.validate())
+
+	s.check.MaxThreads = intPtr(50)
+	s.check.Multiple = "max"
+	s.Error(s.check.validate())
+}
+
+func (s *ProcessThreadsSuite) TestThreadCountWithinBoundsPasses() {
+	s.check.ProcessName = "mongod"
+	s.check.MinThreads = intPtr(16)
+	s.check.MaxThreads = intPtr(64)
+	s.check.Run()
+	s.True(s.check.Output().Passed)
+	s.NoError(s.check.Error())
+	s.Contains(s.check.Output().Message, "2=32")
+
+	s.SetupTest()
+	s.check.PIDFile = filepath.Join(s.tmpDir, "mongod.pid")
+	s.check.MinThreads = intPtr(32)
+	s.check.Run()
+	s.True(s.check.Output().Passed)
+	s.NoError(s.check.Error())
+}
+
+func (s *ProcessThreadsSuite) TestThreadCountOutsideBoundsFails() {
+	s.check.ProcessName = "mongod"
+	s.check.MinThreads = intPtr(64)
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "found 32 threads")
+	s.Contains(s.check.Error().Error(), "fewer than 64")
+}
+
+func (s *ProcessThreadsSuite) TestMultipleProcesses() {
+	s.check.ProcessName = "nginx"
+	s.check.MinThreads = intPtr(8)
+	s.check.Run()
+	s.True(s.check.Output().Passed)
+	s.NoError(s.check.Error())
+	s.Contains(s.check.Output().Message, "3=4, 4=4")
+
+	s.SetupTest()
+	s.check.ProcessName = "nginx"
+	s.check.MinThreads = intPtr(4)
+	s.check.Multiple = "single"
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "expected exactly one")
+}
+
+func (s *ProcessThreadsSuite) TestMissingProcessFails() {
+	s.check.ProcessName = "postgres"
+	s.check.MinThreads = intPtr(1)
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "found no processes")
+
+	s.SetupTest()
+	s.check.PIDFile = filepath.Join(s.tmpDir, "DOES-NOT-EXIST")
+	s.check.MinThreads = intPtr(1)
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.Error(s.check.Error())
+}
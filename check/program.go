@@ -30,9 +30,13 @@ func registerProgramChecks() {
 	registrar(scriptCompilerInterfaceFactoryTable())
 }
 
+// programOutputCheck compiles and runs a test program, and compares
+// its output to the expected output. Like compile checks, it rejects
+// the run_as_user argument.
 type programOutputCheck struct {
 	Source         string `bson:"source" json:"source" yaml:"source"`
 	ExpectedOutput string `bson:"output" json:"output" yaml:"output"`
+	RunAsUser      string `bson:"run_as_user" json:"run_as_user" yaml:"run_as_user"`
 	*Base          `bson:"metadata" json:"metadata" yaml:"metadata"`
 	compiler       compiler
 }
@@ -41,6 +45,13 @@ func (c *programOutputCheck) Run() {
 	c.startTask()
 	defer c.MarkComplete()
 
+	if c.RunAsUser != "" {
+		c.setState(false)
+		c.setReason(ReasonInvalidConfig)
+		c.AddError(errRunAsUserUnsupported(c.Name()))
+		return
+	}
+
 	c.setState(true)
 
 	if err := c.compiler.Validate(); err != nil {
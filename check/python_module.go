@@ -61,6 +61,7 @@ func (c *pythonModuleVersion) Run() {
 
 	if err := c.validate(); err != nil {
 		c.setState(false)
+		c.setReason(ReasonInvalidConfig)
 		c.AddError(err)
 		return
 	}
@@ -0,0 +1,75 @@
+package check
+
+import (
+	"net"
+	"net/url"
+	"os"
+	"os/exec"
+	"syscall"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+)
+
+// Reason codes are stable, machine-readable identifiers for the ways
+// that checks fail, which the output of failed checks includes (as
+// ReasonCode), so that alerting can route on the code rather than on
+// the text of a message. Checks set the code for the failures they
+// understand; otherwise, the code comes from the cause of the first
+// error that the check reports, and is ReasonCheckFailed when the
+// cause is not one of the common failures below. Codes never change
+// once released: add new codes rather than renaming existing ones.
+const (
+	ReasonCheckFailed      = "CHECK_FAILED"
+	ReasonInvalidConfig    = "INVALID_CONFIG"
+	ReasonFileMissing      = "FILE_MISSING"
+	ReasonFilePresent      = "FILE_PRESENT"
+	ReasonPermissionDenied = "PERMISSION_DENIED"
+	ReasonPermMismatch     = "PERM_MISMATCH"
+	ReasonValueMismatch    = "VALUE_MISMATCH"
+	ReasonConnRefused      = "CONN_REFUSED"
+	ReasonTimeout          = "TIMEOUT"
+	ReasonCommandFailed    = "COMMAND_FAILED"
+	ReasonRemoteRunFailed  = "REMOTE_RUN_FAILED"
+)
+
+// reasonForError returns the reason code for a common cause of
+// failure, or an empty string if the cause of the error is not one
+// of them.
+func reasonForError(err error) string {
+	cause := errors.Cause(err)
+
+	if urlErr, ok := cause.(*url.Error); ok {
+		cause = urlErr.Err
+	}
+
+	if netErr, ok := cause.(net.Error); ok && netErr.Timeout() {
+		return ReasonTimeout
+	}
+
+	if opErr, ok := cause.(*net.OpError); ok {
+		cause = opErr.Err
+	}
+
+	if sysErr, ok := cause.(*os.SyscallError); ok {
+		cause = sysErr.Err
+	}
+
+	switch {
+	case cause == syscall.ECONNREFUSED:
+		return ReasonConnRefused
+	case cause == context.DeadlineExceeded:
+		return ReasonTimeout
+	case os.IsNotExist(cause):
+		return ReasonFileMissing
+	case os.IsPermission(cause):
+		return ReasonPermissionDenied
+	}
+
+	switch cause.(type) {
+	case *exec.ExitError, *exec.Error:
+		return ReasonCommandFailed
+	}
+
+	return ""
+}
@@ -0,0 +1,43 @@
+package check
+
+import (
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+)
+
+func TestReasonForError(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("", reasonForError(errors.New("foo")))
+	assert.Equal(ReasonFileMissing, reasonForError(errors.Wrap(&os.PathError{
+		Op: "open", Path: "/etc/foo", Err: os.ErrNotExist}, "problem reading file")))
+	assert.Equal(ReasonPermissionDenied, reasonForError(&os.PathError{
+		Op: "open", Path: "/etc/shadow", Err: os.ErrPermission}))
+	assert.Equal(ReasonTimeout, reasonForError(errors.Wrap(context.DeadlineExceeded, "timed out")))
+
+	_, err := exec.Command("sh", "-c", "exit 3").Output()
+	assert.Equal(ReasonCommandFailed, reasonForError(errors.Wrap(err, "problem running command")))
+
+	_, err = exec.Command("greenbay-does-not-exist").Output()
+	assert.Equal(ReasonCommandFailed, reasonForError(err))
+
+	// find a port that nothing listens on.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(err)
+	addr := listener.Addr().String()
+	assert.NoError(listener.Close())
+
+	_, err = net.DialTimeout("tcp", addr, time.Second)
+	assert.Equal(ReasonConnRefused, reasonForError(err))
+
+	_, err = http.Get("http://" + addr)
+	assert.Equal(ReasonConnRefused, reasonForError(errors.Wrap(err, "problem making request")))
+}
@@ -0,0 +1,80 @@
+package check
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/mongodb/amboy/registry"
+	"github.com/mongodb/greenbay"
+	"github.com/pkg/errors"
+)
+
+// RegisteredChecks returns a sorted list of the names of all check
+// types in the amboy job registry. Registered job types that do not
+// implement the greenbay.Checker interface are not included.
+func RegisteredChecks() []string {
+	var names []string
+	for name := range registry.JobTypeNames() {
+		names = append(names, name)
+	}
+
+	var checks []string
+	for _, name := range names {
+		if _, err := GetChecker(name); err != nil {
+			continue
+		}
+
+		checks = append(checks, name)
+	}
+
+	sort.Strings(checks)
+
+	return checks
+}
+
+// GetChecker returns a new, unconfigured, instance of the named
+// check type. Returns an error if there is no job type registered
+// with that name, or if the job type does not implement the
+// greenbay.Checker interface.
+func GetChecker(typeName string) (greenbay.Checker, error) {
+	factory, err := registry.GetJobFactory(typeName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "no test job named %s defined,", typeName)
+	}
+
+	c, ok := factory().(greenbay.Checker)
+	if !ok {
+		return nil, errors.Errorf("job %s does not implement Checker interface", typeName)
+	}
+
+	return c, nil
+}
+
+// NewCheck builds a check of the named type, using the values in the
+// config map as the arguments to the check. The keys of the map are
+// the same as the keys of the "args" document of a check in a
+// greenbay config file. The ID and suites of the check are not set.
+func NewCheck(typeName string, config map[string]interface{}) (greenbay.Checker, error) {
+	args, err := json.Marshal(config)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem encoding arguments for %s check", typeName)
+	}
+
+	return NewCheckFromJSON(typeName, args)
+}
+
+// NewCheckFromJSON builds a check of the named type from a JSON
+// document of arguments. This is the construction path used by the
+// greenbay config parser.
+func NewCheckFromJSON(typeName string, args []byte) (greenbay.Checker, error) {
+	c, err := GetChecker(typeName)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem determining job type")
+	}
+
+	if err = json.Unmarshal(args, c); err != nil {
+		return nil, errors.Wrapf(err, "problem parsing arguments for %s check", typeName)
+	}
+
+	return c, nil
+}
@@ -0,0 +1,85 @@
+package check
+
+import (
+	"encoding/json"
+	"sort"
+	"testing"
+
+	"github.com/mongodb/greenbay"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisteredChecksOnlyReportsCheckers(t *testing.T) {
+	assert := assert.New(t)
+
+	names := RegisteredChecks()
+	assert.NotEqual(0, len(names))
+	assert.True(sort.StringsAreSorted(names))
+	assert.Contains(names, "file-exists")
+	assert.Contains(names, "mock-check")
+
+	// amboy registers a shell job which is not a greenbay check.
+	assert.NotContains(names, "shell")
+
+	for _, name := range names {
+		c, err := GetChecker(name)
+		assert.NoError(err)
+		assert.Implements((*greenbay.Checker)(nil), c)
+	}
+}
+
+func TestGetCheckerWithInvalidTypes(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, name := range []string{"DOES-NOT-EXIST", "shell", ""} {
+		c, err := GetChecker(name)
+		assert.Error(err)
+		assert.Nil(c)
+	}
+}
+
+func TestNewCheckBuildsConfiguredCheck(t *testing.T) {
+	assert := assert.New(t)
+
+	c, err := NewCheck("file-exists", map[string]interface{}{
+		"name": "../makefile",
+	})
+	assert.NoError(err)
+	if assert.NotNil(c) {
+		assert.Equal("file-exists", c.Name())
+		assert.Equal("../makefile", c.(*fileExistance).FileName)
+
+		c.Run()
+		assert.True(c.Output().Passed)
+	}
+
+	c, err = NewCheck("file-exists", nil)
+	assert.NoError(err)
+	assert.NotNil(c)
+
+	c, err = NewCheck("DOES-NOT-EXIST", map[string]interface{}{})
+	assert.Error(err)
+	assert.Nil(c)
+
+	c, err = NewCheck("file-exists", map[string]interface{}{"name": 42})
+	assert.Error(err)
+	assert.Nil(c)
+}
+
+func TestMaxReadBytesIsAcceptedByFileChecks(t *testing.T) {
+	assert := assert.New(t)
+
+	names := []string{"hosts-entry", "fstab-entry", "kernel-module", "entropy-available", "http-mtls",
+		"k8s-node-ready", "kerberos-keytab", "ldap-bind", "pid-file", "process-count",
+		"process-listeners", "process-threads", "s3-object", "socket-usage", "tls-chain-valid",
+		"zombie-processes"}
+
+	for _, name := range names {
+		c, err := NewCheck(name, map[string]interface{}{"max_read_bytes": 1024})
+		if assert.NoError(err, name) {
+			out, err := json.Marshal(c)
+			assert.NoError(err)
+			assert.Contains(string(out), `"max_read_bytes":1024`, name)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package check
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/registry"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	name := "resolv-conf"
+	registry.AddJobType(name, func() amboy.Job {
+		return &resolvConf{
+			Base:       NewBase(name, 0),
+			resolvConf: "/etc/resolv.conf",
+		}
+	})
+}
+
+// resolvConf checks the DNS client configuration in
+// /etc/resolv.conf. By default, every nameserver in nameservers, and
+// every domain in search_domains, if set, must be in the file, in any
+// order. When strict is set, the nameservers, and the search domains,
+// if set, must be exactly the expected lists, in order, as the
+// resolver tries them in order.
+type resolvConf struct {
+	Nameservers   []string `bson:"nameservers" json:"nameservers" yaml:"nameservers"`
+	SearchDomains []string `bson:"search_domains" json:"search_domains" yaml:"search_domains"`
+	Strict        bool     `bson:"strict" json:"strict" yaml:"strict"`
+	*Base         `bson:"metadata" json:"metadata" yaml:"metadata"`
+	fileReadLimit
+
+	resolvConf string
+}
+
+// resolverConfig is the part of a resolv.conf file that the check
+// inspects.
+type resolverConfig struct {
+	nameservers []string
+	search      []string
+}
+
+func (c *resolvConf) validate() error {
+	if len(c.Nameservers) == 0 {
+		return errors.Errorf("no nameservers specified for '%s' (%s) check", c.ID(), c.Name())
+	}
+
+	return nil
+}
+
+func (c *resolvConf) Run() {
+	c.startTask()
+	defer c.MarkComplete()
+
+	if err := c.validate(); err != nil {
+		c.setState(false)
+		c.setReason(ReasonInvalidConfig)
+		c.AddError(err)
+		return
+	}
+
+	data, err := c.readFile(c.resolvConf)
+	if err != nil {
+		c.setState(false)
+		c.AddError(err)
+		return
+	}
+
+	conf := parseResolvConf(string(data))
+	c.setMessage([]string{
+		fmt.Sprintf("nameservers: %s", strings.Join(conf.nameservers, ", ")),
+		fmt.Sprintf("search: %s", strings.Join(conf.search, ", ")),
+	})
+
+	var problems []string
+	problems = append(problems, compareResolverList("nameservers", conf.nameservers, c.Nameservers, c.Strict)...)
+	if len(c.SearchDomains) > 0 {
+		problems = append(problems, compareResolverList("search domains", conf.search, c.SearchDomains, c.Strict)...)
+	}
+
+	if len(problems) > 0 {
+		c.setState(false)
+		c.setReason(ReasonValueMismatch)
+		c.AddError(errors.Errorf("'%s' is not as expected: %s", c.resolvConf, strings.Join(problems, ", ")))
+		return
+	}
+
+	c.setState(true)
+}
+
+// compareResolverList returns the differences between the actual and
+// expected lists. When strict, the lists must be the same, in the
+// same order; otherwise, the actual list must contain every expected
+// value.
+func compareResolverList(kind string, actual, expected []string, strict bool) []string {
+	if strict {
+		if strings.Join(actual, " ") != strings.Join(expected, " ") {
+			return []string{fmt.Sprintf("%s are [%s], not [%s]", kind,
+				strings.Join(actual, ", "), strings.Join(expected, ", "))}
+		}
+
+		return nil
+	}
+
+	present := make(map[string]struct{}, len(actual))
+	for _, value := range actual {
+		present[value] = struct{}{}
+	}
+
+	var missing []string
+	for _, value := range expected {
+		if _, ok := present[value]; !ok {
+			missing = append(missing, value)
+		}
+	}
+
+	if len(missing) > 0 {
+		return []string{fmt.Sprintf("%s do not include %s", kind, strings.Join(missing, ", "))}
+	}
+
+	return nil
+}
+
+// parseResolvConf parses the nameservers and search domains from the
+// contents of a resolv.conf file. As for the resolver, the last
+// "search" or "domain" line sets the search list.
+func parseResolvConf(data string) resolverConfig {
+	conf := resolverConfig{}
+
+	for _, line := range strings.Split(data, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") || strings.HasPrefix(fields[0], ";") {
+			continue
+		}
+
+		switch fields[0] {
+		case "nameserver":
+			conf.nameservers = append(conf.nameservers, fields[1])
+		case "search":
+			conf.search = fields[1:]
+		case "domain":
+			conf.search = fields[1:2]
+		}
+	}
+
+	return conf
+}
@@ -0,0 +1,112 @@
+package check
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type ResolvConfSuite struct {
+	tmpDir  string
+	check   *resolvConf
+	require *require.Assertions
+	suite.Suite
+}
+
+func TestResolvConfSuite(t *testing.T) {
+	suite.Run(t, new(ResolvConfSuite))
+}
+
+func (s *ResolvConfSuite) SetupSuite() {
+	s.require = s.Require()
+
+	dir, err := ioutil.TempDir("", uuid.NewV4().String())
+	s.require.NoError(err)
+	s.tmpDir = dir
+
+	s.require.NoError(ioutil.WriteFile(filepath.Join(dir, "resolv.conf"), []byte(`# generated by provisioning
+domain example.net
+search prod.example.net example.net
+; nameserver 8.8.8.8
+nameserver 10.0.0.2
+nameserver 10.0.0.3
+options timeout:2 attempts:3
+`), 0644))
+}
+
+func (s *ResolvConfSuite) TearDownSuite() {
+	s.require.NoError(os.RemoveAll(s.tmpDir))
+}
+
+func (s *ResolvConfSuite) SetupTest() {
+	s.check = &resolvConf{
+		Base:       NewBase("resolv-conf", 0),
+		resolvConf: filepath.Join(s.tmpDir, "resolv.conf"),
+	}
+}
+
+func (s *ResolvConfSuite) TestValidation() {
+	s.Error(s.check.validate())
+
+	s.check.Nameservers = []string{"10.0.0.2"}
+	s.NoError(s.check.validate())
+}
+
+func (s *ResolvConfSuite) TestParse() {
+	conf := parseResolvConf("domain a.example\nnameserver 10.0.0.1\nsearch b.example c.example\ndomain d.example\n")
+	s.Equal([]string{"10.0.0.1"}, conf.nameservers)
+	s.Equal([]string{"d.example"}, conf.search)
+}
+
+func (s *ResolvConfSuite) TestExpectedConfiguration() {
+	s.check.Nameservers = []string{"10.0.0.3", "10.0.0.2"}
+	s.check.SearchDomains = []string{"example.net"}
+	s.check.Run()
+
+	s.True(s.check.Output().Passed)
+	s.NoError(s.check.Error())
+	s.Equal("nameservers: 10.0.0.2, 10.0.0.3\nsearch: prod.example.net, example.net", s.check.Output().Message)
+}
+
+func (s *ResolvConfSuite) TestMissingValues() {
+	s.check.Nameservers = []string{"10.0.0.2", "8.8.8.8"}
+	s.check.SearchDomains = []string{"corp.example.net"}
+	s.check.Run()
+
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "nameservers do not include 8.8.8.8, search domains do not include corp.example.net")
+}
+
+func (s *ResolvConfSuite) TestStrictRequiresOrder() {
+	s.check.Nameservers = []string{"10.0.0.3", "10.0.0.2"}
+	s.check.Strict = true
+	s.check.Run()
+
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "nameservers are [10.0.0.2, 10.0.0.3], not [10.0.0.3, 10.0.0.2]")
+
+	s.SetupTest()
+	s.check.Nameservers = []string{"10.0.0.2", "10.0.0.3"}
+	s.check.SearchDomains = []string{"prod.example.net", "example.net"}
+	s.check.Strict = true
+	s.check.Run()
+	s.True(s.check.Output().Passed)
+	s.NoError(s.check.Error())
+}
+
+func (s *ResolvConfSuite) TestMissingFile() {
+	s.check.Nameservers = []string{"10.0.0.2"}
+	s.check.resolvConf = filepath.Join(s.tmpDir, "DOES-NOT-EXIST")
+	s.check.Run()
+
+	s.False(s.check.Output().Passed)
+	s.Error(s.check.Error())
+	s.Equal(ReasonFileMissing, s.check.Output().ReasonCode)
+}
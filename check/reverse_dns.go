@@ -0,0 +1,116 @@
+package check
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/registry"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	name := "reverse-dns"
+	registry.AddJobType(name, func() amboy.Job {
+		return &reverseDNS{
+			Base:       NewBase(name, 0),
+			lookupAddr: net.LookupAddr,
+		}
+	})
+}
+
+// reverseDNS checks that the PTR records for an IP address include
+// the expected hostname. Specify either expected_hostname, which is
+// compared without case or a trailing dot, or hostname_pattern,
+// which is a regular expression. The check passes if any PTR record
+// matches.
+type reverseDNS struct {
+	IP               string `bson:"ip" json:"ip" yaml:"ip"`
+	ExpectedHostname string `bson:"expected_hostname" json:"expected_hostname" yaml:"expected_hostname"`
+	HostnamePattern  string `bson:"hostname_pattern" json:"hostname_pattern" yaml:"hostname_pattern"`
+	*Base            `bson:"metadata" json:"metadata" yaml:"metadata"`
+
+	pattern    *regexp.Regexp
+	lookupAddr func(string) ([]string, error)
+}
+
+func (c *reverseDNS) validate() error {
+	if c.IP == "" {
+		return errors.Errorf("no ip specified for '%s' (%s) check", c.ID(), c.Name())
+	}
+
+	if net.ParseIP(c.IP) == nil {
+		return errors.Errorf("ip '%s' for '%s' check is not valid", c.IP, c.ID())
+	}
+
+	if (c.ExpectedHostname == "") == (c.HostnamePattern == "") {
+		return errors.Errorf("must specify exactly one of expected hostname or "+
+			"hostname pattern for '%s' check", c.ID())
+	}
+
+	if c.HostnamePattern != "" {
+		pattern, err := regexp.Compile(c.HostnamePattern)
+		if err != nil {
+			return errors.Wrapf(err, "problem compiling hostname pattern for '%s' check", c.ID())
+		}
+		c.pattern = pattern
+	}
+
+	return nil
+}
+
+func normalizeHostname(name string) string {
+	return strings.ToLower(strings.TrimSuffix(name, "."))
+}
+
+func (c *reverseDNS) Run() {
+	c.startTask()
+	defer c.MarkComplete()
+
+	if err := c.validate(); err != nil {
+		c.setState(false)
+		c.setReason(ReasonInvalidConfig)
+		c.AddError(err)
+		return
+	}
+
+	names, err := c.lookupAddr(c.IP)
+	if dnsErr, ok := err.(*net.DNSError); (ok && dnsErr.IsNotFound) || (err == nil && len(names) == 0) {
+		c.setState(false)
+		c.AddError(errors.Errorf("%s does not have any PTR records", c.IP))
+		return
+	} else if err != nil {
+		c.setState(false)
+		c.AddError(errors.Wrapf(err, "problem looking up PTR records for %s", c.IP))
+		return
+	}
+
+	hostnames := make([]string, 0, len(names))
+	for _, name := range names {
+		hostnames = append(hostnames, normalizeHostname(name))
+	}
+	c.setMessage(fmt.Sprintf("PTR records for %s: [%s]", c.IP, strings.Join(hostnames, ", ")))
+
+	for _, name := range hostnames {
+		if c.pattern != nil && c.pattern.MatchString(name) {
+			c.setState(true)
+			return
+		}
+
+		if c.ExpectedHostname != "" && name == normalizeHostname(c.ExpectedHostname) {
+			c.setState(true)
+			return
+		}
+	}
+
+	expected := c.ExpectedHostname
+	if c.pattern != nil {
+		expected = fmt.Sprintf("pattern '%s'", c.HostnamePattern)
+	}
+
+	c.setState(false)
+	c.AddError(errors.Errorf("PTR records for %s [%s] do not match %s",
+		c.IP, strings.Join(hostnames, ", "), expected))
+}
@@ -0,0 +1,106 @@
+package check
+
+import (
+	"net"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type ReverseDNSSuite struct {
+	check   *reverseDNS
+	require *require.Assertions
+	suite.Suite
+}
+
+func TestReverseDNSSuite(t *testing.T) {
+	suite.Run(t, new(ReverseDNSSuite))
+}
+
+func (s *ReverseDNSSuite) SetupSuite() {
+	s.require = s.Require()
+}
+
+func (s *ReverseDNSSuite) SetupTest() {
+	s.check = &reverseDNS{
+		Base: NewBase("reverse-dns", 0),
+		lookupAddr: func(addr string) ([]string, error) {
+			switch addr {
+			case "192.0.2.10":
+				return []string{"Mail.Example.net.", "alias.example.net."}, nil
+			case "192.0.2.11":
+				return nil, &net.DNSError{Err: "no such host", Name: addr, IsNotFound: true}
+			default:
+				return nil, errors.New("server misbehaving")
+			}
+		},
+	}
+}
+
+func (s *ReverseDNSSuite) TestValidation() {
+	s.Error(s.check.validate())
+
+	s.check.IP = "not-an-ip"
+	s.Error(s.check.validate())
+
+	s.check.IP = "192.0.2.10"
+	s.Error(s.check.validate())
+
+	s.check.ExpectedHostname = "mail.example.net"
+	s.NoError(s.check.validate())
+
+	s.check.HostnamePattern = "example"
+	s.Error(s.check.validate())
+
+	s.check.ExpectedHostname = ""
+	s.NoError(s.check.validate())
+
+	s.check.HostnamePattern = "(["
+	s.Error(s.check.validate())
+}
+
+func (s *ReverseDNSSuite) TestMatchingPTRRecordPasses() {
+	s.check.IP = "192.0.2.10"
+	s.check.ExpectedHostname = "mail.example.net."
+	s.check.Run()
+	s.True(s.check.Output().Passed)
+	s.NoError(s.check.Error())
+
+	s.SetupTest()
+	s.check.IP = "192.0.2.10"
+	s.check.HostnamePattern = `^alias\.`
+	s.check.Run()
+	s.True(s.check.Output().Passed)
+	s.NoError(s.check.Error())
+}
+
+func (s *ReverseDNSSuite) TestMismatchReportsActualRecords() {
+	s.check.IP = "192.0.2.10"
+	s.check.ExpectedHostname = "smtp.example.net"
+	s.check.Run()
+
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "[mail.example.net, alias.example.net]")
+}
+
+func (s *ReverseDNSSuite) TestMissingPTRRecordIsDistinctFailure() {
+	s.check.IP = "192.0.2.11"
+	s.check.ExpectedHostname = "mail.example.net"
+	s.check.Run()
+
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "does not have any PTR records")
+
+	s.SetupTest()
+	s.check.IP = "192.0.2.12"
+	s.check.ExpectedHostname = "mail.example.net"
+	s.check.Run()
+
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "problem looking up")
+}
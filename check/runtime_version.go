@@ -0,0 +1,170 @@
+package check
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/blang/semver"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/registry"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	name := "runtime-version"
+	registry.AddJobType(name, func() amboy.Job {
+		return &runtimeVersion{
+			Base: NewBase(name, 0),
+			run: func(command string, args ...string) ([]byte, error) {
+				return exec.Command(command, args...).CombinedOutput()
+			},
+		}
+	})
+}
+
+// runtimeCommand is the default command of a runtime, and the
+// arguments that print its version.
+type runtimeCommand struct {
+	command string
+	args    []string
+}
+
+// runtimeCommands are the runtimes that the runtime-version check
+// supports. Python 2 and Java print their versions to standard error,
+// so the check reads both standard output and standard error.
+var runtimeCommands = map[string]runtimeCommand{
+	"python": {command: "python", args: []string{"--version"}},
+	"node":   {command: "node", args: []string{"--version"}},
+	"ruby":   {command: "ruby", args: []string{"--version"}},
+	"java":   {command: "java", args: []string{"-version"}},
+}
+
+// versionPattern matches the first version number in the output of
+// a runtime, which may omit the minor and patch versions
+// (e.g. "openjdk version "17" 2021-09-14").
+var versionPattern = regexp.MustCompile(`(\d+)(?:\.(\d+))?(?:\.(\d+))?`)
+
+// runtimeVersion checks that the version of an interpreter or
+// runtime (python, node, ruby, or java) is between min_version and
+// max_version, inclusive. The command overrides the default command
+// for the runtime (e.g. "python3" or "/opt/node/bin/node"), which
+// runs with the runtime's version flag. Versions may omit the minor
+// and patch versions, which default to 0, so "3.8" is "3.8.0".
+type runtimeVersion struct {
+	Runtime    string `bson:"runtime" json:"runtime" yaml:"runtime"`
+	Command    string `bson:"command" json:"command" yaml:"command"`
+	MinVersion string `bson:"min_version" json:"min_version" yaml:"min_version"`
+	MaxVersion string `bson:"max_version" json:"max_version" yaml:"max_version"`
+	*Base      `bson:"metadata" json:"metadata" yaml:"metadata"`
+
+	run func(command string, args ...string) ([]byte, error)
+}
+
+func (c *runtimeVersion) validate() error {
+	if _, ok := runtimeCommands[c.Runtime]; !ok {
+		return errors.Errorf("runtime '%s' for '%s' (%s) check must be one of python, node, ruby, or java",
+			c.Runtime, c.ID(), c.Name())
+	}
+
+	if c.MinVersion == "" && c.MaxVersion == "" {
+		return errors.Errorf("no min or max version specified for '%s' (%s) check", c.ID(), c.Name())
+	}
+
+	for _, v := range []string{c.MinVersion, c.MaxVersion} {
+		if v == "" {
+			continue
+		}
+
+		if _, err := parseLooseVersion(v); err != nil {
+			return errors.Wrapf(err, "invalid version bound for '%s' check", c.ID())
+		}
+	}
+
+	return nil
+}
+
+func (c *runtimeVersion) Run() {
+	c.startTask()
+	defer c.MarkComplete()
+
+	if err := c.validate(); err != nil {
+		c.setState(false)
+		c.setReason(ReasonInvalidConfig)
+		c.AddError(err)
+		return
+	}
+
+	rc := runtimeCommands[c.Runtime]
+	command := rc.command
+	if c.Command != "" {
+		command = c.Command
+	}
+
+	out, err := c.run(command, rc.args...)
+	output := strings.TrimSpace(string(out))
+	if err != nil {
+		c.setState(false)
+		c.AddError(errors.Wrapf(err, "problem running '%s %s'", command, strings.Join(rc.args, " ")))
+		c.setMessage(output)
+		return
+	}
+
+	version, err := parseLooseVersion(output)
+	if err != nil {
+		c.setState(false)
+		c.AddError(errors.Wrapf(err, "problem finding the %s version in the output of '%s'",
+			c.Runtime, command))
+		c.setMessage(output)
+		return
+	}
+
+	var bounds []string
+	var problems []string
+
+	if c.MinVersion != "" {
+		min, _ := parseLooseVersion(c.MinVersion)
+		bounds = append(bounds, fmt.Sprintf(">= %s", min))
+		if version.LT(min) {
+			problems = append(problems, fmt.Sprintf("is older than the minimum version %s", min))
+		}
+	}
+
+	if c.MaxVersion != "" {
+		max, _ := parseLooseVersion(c.MaxVersion)
+		bounds = append(bounds, fmt.Sprintf("<= %s", max))
+		if version.GT(max) {
+			problems = append(problems, fmt.Sprintf("is newer than the maximum version %s", max))
+		}
+	}
+
+	c.setMessage(fmt.Sprintf("%s version %s (from '%s'), expected %s",
+		c.Runtime, version, command, strings.Join(bounds, ", ")))
+
+	if len(problems) > 0 {
+		c.setState(false)
+		c.AddError(errors.Errorf("%s version %s %s", c.Runtime, version, strings.Join(problems, ", ")))
+		return
+	}
+
+	c.setState(true)
+}
+
+// parseLooseVersion returns the first version number in a string, as
+// a semantic version, with missing minor and patch versions set to 0.
+func parseLooseVersion(value string) (semver.Version, error) {
+	match := versionPattern.FindStringSubmatch(value)
+	if match == nil {
+		return semver.Version{}, errors.Errorf("no version number in '%s'", value)
+	}
+
+	parts := match[1:]
+	for i := range parts {
+		if parts[i] == "" {
+			parts[i] = "0"
+		}
+	}
+
+	return semver.Parse(strings.Join(parts, "."))
+}
@@ -0,0 +1,123 @@
+package check
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type RuntimeVersionSuite struct {
+	output  string
+	ran     []string
+	check   *runtimeVersion
+	require *require.Assertions
+	suite.Suite
+}
+
+func TestRuntimeVersionSuite(t *testing.T) {
+	suite.Run(t, new(RuntimeVersionSuite))
+}
+
+func (s *RuntimeVersionSuite) SetupSuite() {
+	s.require = s.Require()
+}
+
+func (s *RuntimeVersionSuite) SetupTest() {
+	s.output = ""
+	s.ran = nil
+	s.check = &runtimeVersion{
+		Base: NewBase("runtime-version", 0),
+		run: func(command string, args ...string) ([]byte, error) {
+			s.ran = append([]string{command}, args...)
+			return []byte(s.output), nil
+		},
+	}
+}
+
+func (s *RuntimeVersionSuite) TestValidation() {
+	s.Error(s.check.validate())
+
+	s.check.Runtime = "perl"
+	s.check.MinVersion = "5"
+	s.Error(s.check.validate())
+
+	s.check.Runtime = "python"
+	s.NoError(s.check.validate())
+
+	s.check.MinVersion = ""
+	s.Error(s.check.validate())
+
+	s.check.MaxVersion = "latest"
+	s.Error(s.check.validate())
+}
+
+func (s *RuntimeVersionSuite) TestParseVersions() {
+	for output, expected := range map[string]string{
+		"Python 3.8.10": "3.8.10",
+		"v18.17.0":      "18.17.0",
+		"ruby 3.0.2p107 (2021-07-07 revision 0db68f0233) [x86_64-linux]": "3.0.2",
+		`openjdk version "17" 2021-09-14`:                                "17.0.0",
+		`java version "1.8.0_382"`:                                       "1.8.0",
+		"3.8":                                                            "3.8.0",
+	} {
+		v, err := parseLooseVersion(output)
+		s.NoError(err, output)
+		s.Equal(expected, v.String(), output)
+	}
+
+	_, err := parseLooseVersion("command not found")
+	s.Error(err)
+}
+
+func (s *RuntimeVersionSuite) TestVersionWithinBounds() {
+	s.check.Runtime = "node"
+	s.check.MinVersion = "16"
+	s.check.MaxVersion = "20.99"
+	s.output = "v18.17.0\n"
+	s.check.Run()
+
+	s.True(s.check.Output().Passed)
+	s.NoError(s.check.Error())
+	s.Equal([]string{"node", "--version"}, s.ran)
+	s.Equal("node version 18.17.0 (from 'node'), expected >= 16.0.0, <= 20.99.0", s.check.Output().Message)
+}
+
+func (s *RuntimeVersionSuite) TestVersionOutsideBounds() {
+	s.check.Runtime = "python"
+	s.check.Command = "python3"
+	s.check.MinVersion = "3.9"
+	s.output = "Python 3.8.10\n"
+	s.check.Run()
+
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Equal([]string{"python3", "--version"}, s.ran)
+	s.Contains(s.check.Error().Error(), "python version 3.8.10 is older than the minimum version 3.9.0")
+
+	s.SetupTest()
+	s.check.Runtime = "java"
+	s.check.MaxVersion = "11"
+	s.output = `openjdk version "17.0.2" 2022-01-18`
+	s.check.Run()
+
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Equal([]string{"java", "-version"}, s.ran)
+	s.Contains(s.check.Error().Error(), "java version 17.0.2 is newer than the maximum version 11.0.0")
+}
+
+func (s *RuntimeVersionSuite) TestCommandFailure() {
+	s.check.Runtime = "ruby"
+	s.check.MinVersion = "2.7"
+	s.check.run = func(command string, args ...string) ([]byte, error) {
+		return []byte("ruby: command not found"), errors.New("exit status 127")
+	}
+	s.check.Run()
+
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "problem running 'ruby --version'")
+	s.Equal("ruby: command not found", s.check.Output().Message)
+}
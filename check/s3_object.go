@@ -0,0 +1,320 @@
+package check
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/registry"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+)
+
+func init() {
+	name := "s3-object"
+	registry.AddJobType(name, func() amboy.Job {
+		return &s3Object{
+			Base:            NewBase(name, 0),
+			Region:          "us-east-1",
+			client:          &http.Client{Timeout: time.Minute},
+			credentialsFile: awsCredentialsFile(),
+		}
+	})
+}
+
+// s3Object checks that an object in an S3 bucket is accessible, by
+// requesting its metadata (a HEAD request). The object must exist
+// and be at least min_size bytes, unless exists is false, in which
+// case the object must not exist.
+//
+// Requests are signed with the access_key_id and secret_access_key,
+// or the credentials for the profile in the shared credentials file
+// (~/.aws/credentials, or AWS_SHARED_CREDENTIALS_FILE), or the
+// AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY, and AWS_SESSION_TOKEN
+// environment variables, or the "default" profile, in that order.
+// Without credentials, requests are anonymous. The endpoint (e.g.
+// "http://localhost:9000") replaces the AWS endpoint for the region,
+// for S3-compatible storage, and uses path-style requests.
+//
+// Access denied, not found, and connectivity errors are reported
+// separately.
+type s3Object struct {
+	Bucket          string `bson:"bucket" json:"bucket" yaml:"bucket"`
+	Key             string `bson:"key" json:"key" yaml:"key"`
+	Region          string `bson:"region" json:"region" yaml:"region"`
+	Endpoint        string `bson:"endpoint" json:"endpoint" yaml:"endpoint"`
+	AccessKeyID     string `bson:"access_key_id" json:"access_key_id" yaml:"access_key_id"`
+	SecretAccessKey string `bson:"secret_access_key" json:"secret_access_key" yaml:"secret_access_key"`
+	Profile         string `bson:"profile" json:"profile" yaml:"profile"`
+	Exists          *bool  `bson:"exists" json:"exists" yaml:"exists"`
+	MinSize         int64  `bson:"min_size" json:"min_size" yaml:"min_size"`
+	*Base           `bson:"metadata" json:"metadata" yaml:"metadata"`
+	fileReadLimit
+
+	client          *http.Client
+	credentialsFile string
+}
+
+func awsCredentialsFile() string {
+	if fn := os.Getenv("AWS_SHARED_CREDENTIALS_FILE"); fn != "" {
+		return fn
+	}
+
+	return filepath.Join(os.Getenv("HOME"), ".aws", "credentials")
+}
+
+func (c *s3Object) validate() error {
+	if c.Bucket == "" {
+		return errors.Errorf("no bucket specified for '%s' (%s) check", c.ID(), c.Name())
+	}
+
+	if c.Key == "" {
+		return errors.Errorf("no key specified for '%s' check", c.ID())
+	}
+
+	if c.Region == "" {
+		return errors.Errorf("no region specified for '%s' check", c.ID())
+	}
+
+	if (c.AccessKeyID == "") != (c.SecretAccessKey == "") {
+		return errors.Errorf("'%s' check must specify both or neither of access_key_id and secret_access_key", c.ID())
+	}
+
+	if c.AccessKeyID != "" && c.Profile != "" {
+		return errors.Errorf("'%s' check cannot specify both access keys and a profile", c.ID())
+	}
+
+	if c.MinSize < 0 {
+		return errors.Errorf("min size for '%s' check cannot be negative", c.ID())
+	}
+
+	if c.Exists != nil && !*c.Exists && c.MinSize > 0 {
+		return errors.Errorf("'%s' check cannot specify a min size for an object that should not exist", c.ID())
+	}
+
+	return nil
+}
+
+// credentials returns the credentials to sign requests with, or nil
+// for anonymous requests.
+func (c *s3Object) credentials() (*aws.Credentials, error) {
+	if c.AccessKeyID != "" {
+		return &aws.Credentials{AccessKeyID: c.AccessKeyID, SecretAccessKey: c.SecretAccessKey}, nil
+	}
+
+	if c.Profile != "" {
+		creds, err := c.readAWSProfile(c.credentialsFile, c.Profile)
+		if err != nil {
+			return nil, err
+		}
+		if creds == nil {
+			return nil, errors.Errorf("profile '%s' is not in '%s'", c.Profile, c.credentialsFile)
+		}
+		return creds, nil
+	}
+
+	if id, secret := os.Getenv("AWS_ACCESS_KEY_ID"), os.Getenv("AWS_SECRET_ACCESS_KEY"); id != "" && secret != "" {
+		return &aws.Credentials{
+			AccessKeyID:     id,
+			SecretAccessKey: secret,
+			SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+		}, nil
+	}
+
+	creds, err := c.readAWSProfile(c.credentialsFile, "default")
+	if os.IsNotExist(errors.Cause(err)) {
+		return nil, nil
+	}
+
+	return creds, err
+}
+
+// readAWSProfile returns the credentials for the profile from a shared
+// credentials file, or nil if the file does not have the profile.
+func (c *s3Object) readAWSProfile(fn, profile string) (*aws.Credentials, error) {
+	data, err := c.readFile(fn)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem reading credentials file '%s'", fn)
+	}
+
+	var creds *aws.Credentials
+	section := ""
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			if section == profile && creds == nil {
+				creds = &aws.Credentials{}
+			}
+			continue
+		}
+
+		if section != profile {
+			continue
+		}
+
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		value := strings.TrimSpace(parts[1])
+		switch strings.TrimSpace(parts[0]) {
+		case "aws_access_key_id":
+			creds.AccessKeyID = value
+		case "aws_secret_access_key":
+			creds.SecretAccessKey = value
+		case "aws_session_token":
+			creds.SessionToken = value
+		}
+	}
+
+	if creds != nil && (creds.AccessKeyID == "" || creds.SecretAccessKey == "") {
+		return nil, errors.Errorf("profile '%s' in '%s' does not have access keys", profile, fn)
+	}
+
+	return creds, errors.Wrapf(scanner.Err(), "problem reading credentials file '%s'", fn)
+}
+
+// objectURL returns the URL of the object, using a virtual-hosted
+// style URL for AWS and a path-style URL for other endpoints.
+func (c *s3Object) objectURL() string {
+	key := s3Escape(strings.TrimPrefix(c.Key, "/"))
+
+	if c.Endpoint != "" {
+		return fmt.Sprintf("%s/%s/%s", strings.TrimSuffix(c.Endpoint, "/"), c.Bucket, key)
+	}
+
+	return fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", c.Bucket, c.Region, key)
+}
+
+func (c *s3Object) Run() {
+	c.startTask()
+	defer c.MarkComplete()
+
+	if err := c.validate(); err != nil {
+		c.setState(false)
+		c.setReason(ReasonInvalidConfig)
+		c.AddError(err)
+		return
+	}
+
+	creds, err := c.credentials()
+	if err != nil {
+		c.setState(false)
+		c.AddError(errors.Wrapf(err, "problem loading credentials for '%s' check", c.ID()))
+		return
+	}
+
+	object := fmt.Sprintf("s3://%s/%s", c.Bucket, strings.TrimPrefix(c.Key, "/"))
+	req, err := http.NewRequest("HEAD", c.objectURL(), nil)
+	if err != nil {
+		c.setState(false)
+		c.AddError(errors.Wrapf(err, "problem building request for %s", object))
+		return
+	}
+
+	if creds != nil {
+		if err = signS3Request(req, *creds, c.Region, time.Now()); err != nil {
+			c.setState(false)
+			c.AddError(errors.Wrapf(err, "problem signing request for %s", object))
+			return
+		}
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		c.setState(false)
+		c.AddError(errors.Wrapf(err, "problem connecting to S3 for %s", object))
+		return
+	}
+	resp.Body.Close()
+
+	shouldExist := c.Exists == nil || *c.Exists
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		c.setMessage(fmt.Sprintf("%s exists [size=%d, last-modified='%s']",
+			object, resp.ContentLength, resp.Header.Get("Last-Modified")))
+
+		if !shouldExist {
+			c.setState(false)
+			c.AddError(errors.Errorf("%s exists, but should not", object))
+			return
+		}
+
+		if resp.ContentLength < c.MinSize {
+			c.setState(false)
+			c.AddError(errors.Errorf("%s is %d bytes, expected at least %d",
+				object, resp.ContentLength, c.MinSize))
+			return
+		}
+
+		c.setState(true)
+	case http.StatusNotFound:
+		c.setMessage(fmt.Sprintf("%s does not exist", object))
+		if shouldExist {
+			c.setState(false)
+			c.AddError(errors.Errorf("%s was not found", object))
+			return
+		}
+
+		c.setState(true)
+	case http.StatusForbidden:
+		c.setState(false)
+		c.AddError(errors.Errorf("access to %s was denied", object))
+	case http.StatusMovedPermanently, http.StatusBadRequest:
+		if region := resp.Header.Get("X-Amz-Bucket-Region"); region != "" && region != c.Region {
+			c.setState(false)
+			c.AddError(errors.Errorf("bucket '%s' is in region '%s', not '%s'", c.Bucket, region, c.Region))
+			return
+		}
+		fallthrough
+	default:
+		c.setState(false)
+		c.AddError(errors.Errorf("S3 returned %s for %s", resp.Status, object))
+	}
+}
+
+// s3Escape encodes each segment of an object key, as the AWS
+// signature requires.
+func s3Escape(key string) string {
+	buf := &bytes.Buffer{}
+	for i := 0; i < len(key); i++ {
+		b := key[i]
+		if b == '/' || b == '-' || b == '_' || b == '.' || b == '~' ||
+			('a' <= b && b <= 'z') || ('A' <= b && b <= 'Z') || ('0' <= b && b <= '9') {
+			buf.WriteByte(b)
+			continue
+		}
+		fmt.Fprintf(buf, "%%%02X", b)
+	}
+
+	return buf.String()
+}
+
+// signS3Request signs a request without a body for S3, with AWS
+// Signature Version 4. S3 requires the hash of the payload in a
+// header, and object keys that are only escaped once.
+func signS3Request(req *http.Request, creds aws.Credentials, region string, now time.Time) error {
+	payloadHash := hex.EncodeToString(sha256.New().Sum(nil))
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	return v4.NewSigner().SignHTTP(context.Background(), creds, req, payloadHash, "s3", region, now,
+		func(opts *v4.SignerOptions) { opts.DisableURIPathEscaping = true })
+}
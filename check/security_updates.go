@@ -0,0 +1,322 @@
+package check
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/registry"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	name := "security-updates"
+	registry.AddJobType(name, func() amboy.Job {
+		return &securityUpdates{
+			Base: NewBase(name, 0),
+			now:  time.Now,
+			run: func(command string, args ...string) ([]byte, error) {
+				return exec.Command(command, args...).Output()
+			},
+		}
+	})
+}
+
+// securityUpdateCommands are the commands that list the pending
+// security updates, and describe the security advisories, for each
+// package manager that the security-updates check supports. apt does
+// not have advisories.
+var securityUpdateCommands = map[string]struct {
+	list []string
+	info []string
+}{
+	"apt": {list: []string{"apt-get", "--simulate", "dist-upgrade"}},
+	"dnf": {
+		list: []string{"dnf", "updateinfo", "list", "--security"},
+		info: []string{"dnf", "updateinfo", "info", "--security"},
+	},
+	"yum": {
+		list: []string{"yum", "updateinfo", "list", "security"},
+		info: []string{"yum", "updateinfo", "info", "security"},
+	},
+}
+
+// securityUpdates checks the number and age of the security updates
+// that are available, but not installed, as a patch hygiene gate. The
+// manager is apt, which simulates an upgrade and counts the packages
+// from security archives (e.g. "focal-security"), or dnf or yum,
+// which list the packages in security advisories. When
+// max_pending_count is set, there must be at most that many pending
+// packages. When max_pending_days is set, no pending package may be
+// in an advisory issued more than that many days ago, which is only
+// supported with dnf and yum, because apt does not report when
+// updates were released. The package lists are only as current as
+// the package manager's metadata cache.
+type securityUpdates struct {
+	Manager         string `bson:"manager" json:"manager" yaml:"manager"`
+	MaxPendingCount *int   `bson:"max_pending_count" json:"max_pending_count" yaml:"max_pending_count"`
+	MaxPendingDays  *int   `bson:"max_pending_days" json:"max_pending_days" yaml:"max_pending_days"`
+	*Base           `bson:"metadata" json:"metadata" yaml:"metadata"`
+
+	now func() time.Time
+	run func(command string, args ...string) ([]byte, error)
+}
+
+// pendingUpdate is a package with a pending security update, and,
+// with dnf and yum, the oldest advisory for the package.
+type pendingUpdate struct {
+	pkg      string
+	advisory string
+	issued   time.Time
+}
+
+func (p pendingUpdate) String() string {
+	if p.advisory == "" {
+		return p.pkg
+	}
+
+	if p.issued.IsZero() {
+		return fmt.Sprintf("%s (%s)", p.pkg, p.advisory)
+	}
+
+	return fmt.Sprintf("%s (%s, issued %s)", p.pkg, p.advisory, p.issued.Format("2006-01-02"))
+}
+
+// pendingUpdatesByName sorts pending updates by package name.
+type pendingUpdatesByName []pendingUpdate
+
+func (p pendingUpdatesByName) Len() int           { return len(p) }
+func (p pendingUpdatesByName) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
+func (p pendingUpdatesByName) Less(i, j int) bool { return p[i].pkg < p[j].pkg }
+
+func (c *securityUpdates) validate() error {
+	if _, ok := securityUpdateCommands[c.Manager]; !ok {
+		return errors.Errorf("manager '%s' for '%s' (%s) check must be one of apt, dnf, or yum",
+			c.Manager, c.ID(), c.Name())
+	}
+
+	if c.MaxPendingCount == nil && c.MaxPendingDays == nil {
+		return errors.Errorf("no max pending count or days specified for '%s' (%s) check", c.ID(), c.Name())
+	}
+
+	if c.MaxPendingCount != nil && *c.MaxPendingCount < 0 {
+		return errors.Errorf("max pending count for '%s' check cannot be negative", c.ID())
+	}
+
+	if c.MaxPendingDays != nil {
+		if *c.MaxPendingDays < 0 {
+			return errors.Errorf("max pending days for '%s' check cannot be negative", c.ID())
+		}
+
+		if c.Manager == "apt" {
+			return errors.Errorf("max pending days for '%s' check is not supported with apt", c.ID())
+		}
+	}
+
+	return nil
+}
+
+func (c *securityUpdates) Run() {
+	c.startTask()
+	defer c.MarkComplete()
+
+	if err := c.validate(); err != nil {
+		c.setState(false)
+		c.setReason(ReasonInvalidConfig)
+		c.AddError(err)
+		return
+	}
+
+	pending, err := c.pendingUpdates()
+	if err != nil {
+		c.setState(false)
+		c.AddError(err)
+		return
+	}
+
+	msg := []string{fmt.Sprintf("%d pending %s security update(s)", len(pending), c.Manager)}
+	for _, p := range pending {
+		msg = append(msg, p.String())
+	}
+	c.setMessage(msg)
+
+	var problems []string
+	if c.MaxPendingCount != nil && len(pending) > *c.MaxPendingCount {
+		problems = append(problems, fmt.Sprintf("%d pending, more than %d", len(pending), *c.MaxPendingCount))
+	}
+
+	if c.MaxPendingDays != nil {
+		cutoff := c.now().AddDate(0, 0, -*c.MaxPendingDays)
+
+		var old []string
+		for _, p := range pending {
+			if !p.issued.IsZero() && p.issued.Before(cutoff) {
+				old = append(old, p.pkg)
+			}
+		}
+
+		if len(old) > 0 {
+			problems = append(problems, fmt.Sprintf("pending for more than %d days: %s",
+				*c.MaxPendingDays, strings.Join(old, ", ")))
+		}
+	}
+
+	if len(problems) > 0 {
+		c.setState(false)
+		c.setReason(ReasonValueMismatch)
+		c.AddError(errors.Errorf("security updates are outstanding: %s", strings.Join(problems, "; ")))
+		return
+	}
+
+	c.setState(true)
+}
+
+// pendingUpdates returns the packages with pending security updates,
+// sorted by name.
+func (c *securityUpdates) pendingUpdates() ([]pendingUpdate, error) {
+	commands := securityUpdateCommands[c.Manager]
+
+	out, err := c.run(commands.list[0], commands.list[1:]...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem listing pending %s security updates", c.Manager)
+	}
+
+	var pending []pendingUpdate
+	if c.Manager == "apt" {
+		pending = parseAptSecurityUpdates(out)
+	} else {
+		pending = parseUpdateinfoList(out)
+
+		if c.MaxPendingDays != nil && len(pending) > 0 {
+			out, err = c.run(commands.info[0], commands.info[1:]...)
+			if err != nil {
+				return nil, errors.Wrapf(err, "problem describing %s security advisories", c.Manager)
+			}
+
+			issued := parseUpdateinfoDates(out)
+			for idx := range pending {
+				pending[idx].issued = issued[pending[idx].advisory]
+			}
+		}
+	}
+
+	// a package may be in more than one advisory: keep the
+	// oldest.
+	byName := make(map[string]pendingUpdate, len(pending))
+	for _, p := range pending {
+		prev, ok := byName[p.pkg]
+		if !ok || (!p.issued.IsZero() && (prev.issued.IsZero() || p.issued.Before(prev.issued))) {
+			byName[p.pkg] = p
+		}
+	}
+
+	result := make([]pendingUpdate, 0, len(byName))
+	for _, p := range byName {
+		result = append(result, p)
+	}
+	sort.Sort(pendingUpdatesByName(result))
+
+	return result, nil
+}
+
+// parseAptSecurityUpdates returns the packages that a simulated apt
+// upgrade would install from a security archive, from lines like
+// "Inst openssl [1.1.1f-1ubuntu2.16] (1.1.1f-1ubuntu2.17
+// Ubuntu:20.04/focal-security [amd64])".
+func parseAptSecurityUpdates(out []byte) []pendingUpdate {
+	var pending []pendingUpdate
+
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || fields[0] != "Inst" {
+			continue
+		}
+
+		line := scanner.Text()
+		start := strings.Index(line, "(")
+		if start < 0 || !strings.Contains(strings.ToLower(line[start:]), "security") {
+			continue
+		}
+
+		pending = append(pending, pendingUpdate{pkg: fields[1]})
+	}
+
+	return pending
+}
+
+// parseUpdateinfoList returns the packages in the output of
+// "updateinfo list", which has lines like "RHSA-2023:1441
+// Important/Sec. openssl-1:1.1.1k-9.el8_7.x86_64".
+func parseUpdateinfoList(out []byte) []pendingUpdate {
+	var pending []pendingUpdate
+
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 || !strings.Contains(fields[1], "Sec") {
+			continue
+		}
+
+		pending = append(pending, pendingUpdate{
+			pkg:      packageNameFromNEVRA(fields[2]),
+			advisory: fields[0],
+		})
+	}
+
+	return pending
+}
+
+// packageNameFromNEVRA returns the name of a package from a
+// "name-[epoch:]version-release.arch" string.
+func packageNameFromNEVRA(nevra string) string {
+	parts := strings.Split(nevra, "-")
+	if len(parts) < 3 {
+		return nevra
+	}
+
+	return strings.Join(parts[:len(parts)-2], "-")
+}
+
+// parseUpdateinfoDates returns the date of each advisory in the
+// output of "updateinfo info", which describes each advisory in a
+// block of "Name : value" lines. The date is the "Updated" date, or,
+// for advisories that were never updated, the "Issued" date.
+func parseUpdateinfoDates(out []byte) map[string]time.Time {
+	dates := make(map[string]time.Time)
+	var advisory string
+
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		parts := strings.SplitN(scanner.Text(), ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		value := strings.TrimSpace(parts[1])
+		switch strings.TrimSpace(parts[0]) {
+		case "Update ID":
+			advisory = value
+		case "Updated", "Issued":
+			if advisory == "" || len(value) < 10 {
+				continue
+			}
+
+			date, err := time.Parse("2006-01-02", value[:10])
+			if err != nil {
+				continue
+			}
+
+			if prev, ok := dates[advisory]; !ok || date.After(prev) {
+				dates[advisory] = date
+			}
+		}
+	}
+
+	return dates
+}
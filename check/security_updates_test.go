@@ -0,0 +1,175 @@
+package check
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type SecurityUpdatesSuite struct {
+	outputs map[string]string
+	check   *securityUpdates
+	require *require.Assertions
+	suite.Suite
+}
+
+func TestSecurityUpdatesSuite(t *testing.T) {
+	suite.Run(t, new(SecurityUpdatesSuite))
+}
+
+func (s *SecurityUpdatesSuite) SetupSuite() {
+	s.require = s.Require()
+}
+
+func (s *SecurityUpdatesSuite) SetupTest() {
+	s.outputs = map[string]string{
+		"apt-get --simulate dist-upgrade": `NOTE: This is only a simulation!
+Reading package lists...
+Inst libssl1.1 [1.1.1f-1ubuntu2.16] (1.1.1f-1ubuntu2.17 Ubuntu:20.04/focal-updates, Ubuntu:20.04/focal-security [amd64])
+Inst tzdata [2023c-0ubuntu0.20.04.1] (2023c-0ubuntu0.20.04.2 Ubuntu:20.04/focal-updates [all])
+Inst openssl [1.1.1f-1ubuntu2.16] (1.1.1f-1ubuntu2.17 Ubuntu:20.04/focal-security [amd64])
+Conf libssl1.1 (1.1.1f-1ubuntu2.17 Ubuntu:20.04/focal-security [amd64])
+`,
+		"dnf updateinfo list --security": `Last metadata expiration check: 0:12:01 ago on Mon 03 Apr 2023 10:00:00 AM UTC.
+RHSA-2023:1441 Important/Sec. openssl-1:1.1.1k-9.el8_7.x86_64
+RHSA-2023:1441 Important/Sec. openssl-libs-1:1.1.1k-9.el8_7.x86_64
+RHSA-2023:1600 Moderate/Sec.  openssl-libs-1:1.1.1k-10.el8_7.x86_64
+RHSA-2023:1590 Low/Sec.       tzdata-2023c-1.el8.noarch
+`,
+		"dnf updateinfo info --security": `===============================================================================
+  Important: openssl security update
+===============================================================================
+  Update ID: RHSA-2023:1441
+       Type: security
+    Updated: 2023-03-23 09:04:32
+       CVEs: CVE-2023-0286
+   Severity: Important
+===============================================================================
+  Low: tzdata bug fix
+===============================================================================
+  Update ID: RHSA-2023:1590
+       Type: security
+     Issued: 2023-03-30 00:00:00
+===============================================================================
+  Moderate: openssl security update
+===============================================================================
+  Update ID: RHSA-2023:1600
+       Type: security
+     Issued: 2023-03-31 00:00:00
+`,
+	}
+
+	s.check = &securityUpdates{
+		Base: NewBase("security-updates", 0),
+		now: func() time.Time {
+			return time.Date(2023, time.April, 10, 12, 0, 0, 0, time.UTC)
+		},
+		run: func(command string, args ...string) ([]byte, error) {
+			out, ok := s.outputs[command+" "+strings.Join(args, " ")]
+			if !ok {
+				return nil, errors.New("exit status 1")
+			}
+			return []byte(out), nil
+		},
+	}
+}
+
+func (s *SecurityUpdatesSuite) TestValidation() {
+	s.Error(s.check.validate())
+
+	s.check.Manager = "zypper"
+	s.Error(s.check.validate())
+
+	s.check.Manager = "apt"
+	s.Error(s.check.validate())
+
+	count := 0
+	s.check.MaxPendingCount = &count
+	s.NoError(s.check.validate())
+
+	days := 14
+	s.check.MaxPendingDays = &days
+	s.Error(s.check.validate())
+
+	s.check.Manager = "dnf"
+	s.NoError(s.check.validate())
+
+	days = -1
+	s.Error(s.check.validate())
+}
+
+func (s *SecurityUpdatesSuite) TestPackageNameFromNEVRA() {
+	s.Equal("openssl-libs", packageNameFromNEVRA("openssl-libs-1:1.1.1k-9.el8_7.x86_64"))
+	s.Equal("tzdata", packageNameFromNEVRA("tzdata-2023c-1.el8.noarch"))
+	s.Equal("kernel", packageNameFromNEVRA("kernel"))
+}
+
+func (s *SecurityUpdatesSuite) TestAptPendingCount() {
+	count := 2
+	s.check.Manager = "apt"
+	s.check.MaxPendingCount = &count
+	s.check.Run()
+
+	s.True(s.check.Output().Passed)
+	s.NoError(s.check.Error())
+	s.Equal("2 pending apt security update(s)\nlibssl1.1\nopenssl", s.check.Output().Message)
+
+	count = 1
+	s.SetupTest()
+	s.check.Manager = "apt"
+	s.check.MaxPendingCount = &count
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "security updates are outstanding: 2 pending, more than 1")
+	s.Equal(ReasonValueMismatch, s.check.Output().ReasonCode)
+}
+
+func (s *SecurityUpdatesSuite) TestDNFPendingDays() {
+	days := 14
+	s.check.Manager = "dnf"
+	s.check.MaxPendingDays = &days
+	s.check.Run()
+
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "pending for more than 14 days: openssl, openssl-libs")
+	s.Equal("3 pending dnf security update(s)\n"+
+		"openssl (RHSA-2023:1441, issued 2023-03-23)\n"+
+		"openssl-libs (RHSA-2023:1441, issued 2023-03-23)\n"+
+		"tzdata (RHSA-2023:1590, issued 2023-03-30)", s.check.Output().Message)
+
+	days = 30
+	s.SetupTest()
+	s.check.Manager = "dnf"
+	s.check.MaxPendingDays = &days
+	s.check.Run()
+	s.True(s.check.Output().Passed)
+	s.NoError(s.check.Error())
+}
+
+func (s *SecurityUpdatesSuite) TestNoPendingUpdates() {
+	count := 0
+	s.outputs["yum updateinfo list security"] = "Loaded plugins: fastestmirror\nupdateinfo list done\n"
+	s.check.Manager = "yum"
+	s.check.MaxPendingCount = &count
+	s.check.Run()
+
+	s.True(s.check.Output().Passed)
+	s.Equal("0 pending yum security update(s)", s.check.Output().Message)
+}
+
+func (s *SecurityUpdatesSuite) TestCommandFails() {
+	count := 0
+	s.check.Manager = "yum"
+	s.check.MaxPendingCount = &count
+	s.check.Run()
+
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "problem listing pending yum security updates")
+}
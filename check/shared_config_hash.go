@@ -0,0 +1,161 @@
+package check
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/registry"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	name := "shared-config-hash"
+	registry.AddJobType(name, func() amboy.Job {
+		return &sharedConfigHash{
+			Base:      NewBase(name, 0),
+			Algorithm: "sha256",
+			client:    &http.Client{Timeout: time.Minute},
+		}
+	})
+}
+
+// sharedConfigHash checks that the hash of a local file matches the
+// hash that a canonical source publishes at remote_url, to detect
+// configuration drift between hosts. The remote response is a
+// hexadecimal hash, optionally followed by other text (as in the
+// output of sha256sum). The algorithm is one of sha256 (the
+// default), sha512, sha1, or md5.
+type sharedConfigHash struct {
+	Path      string `bson:"path" json:"path" yaml:"path"`
+	RemoteURL string `bson:"remote_url" json:"remote_url" yaml:"remote_url"`
+	Algorithm string `bson:"algorithm" json:"algorithm" yaml:"algorithm"`
+	*Base     `bson:"metadata" json:"metadata" yaml:"metadata"`
+
+	client *http.Client
+}
+
+// hashAlgorithms are the hash functions that the shared-config-hash
+// check supports.
+var hashAlgorithms = map[string]func() hash.Hash{
+	"md5":    md5.New,
+	"sha1":   sha1.New,
+	"sha256": sha256.New,
+	"sha512": sha512.New,
+}
+
+// remoteHashLimit is the most data that the shared-config-hash check
+// reads from the remote url.
+const remoteHashLimit = 64 * 1024
+
+func (c *sharedConfigHash) validate() error {
+	if c.Path == "" {
+		return errors.Errorf("no path specified for '%s' (%s) check", c.ID(), c.Name())
+	}
+
+	if c.RemoteURL == "" {
+		return errors.Errorf("no remote url specified for '%s' check", c.ID())
+	}
+
+	if _, ok := hashAlgorithms[c.Algorithm]; !ok {
+		return errors.Errorf("hash algorithm '%s' for '%s' check is not supported", c.Algorithm, c.ID())
+	}
+
+	return nil
+}
+
+// localHash streams the file into the hash, rather than reading it
+// into memory, so it does not need the max_read_bytes limit.
+func (c *sharedConfigHash) localHash() (string, error) {
+	f, err := os.Open(c.Path)
+	if err != nil {
+		return "", errors.Wrapf(err, "problem opening '%s'", c.Path)
+	}
+	defer f.Close()
+
+	h := hashAlgorithms[c.Algorithm]()
+	if _, err = io.Copy(h, f); err != nil {
+		return "", errors.Wrapf(err, "problem reading '%s'", c.Path)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func (c *sharedConfigHash) remoteHash() (string, error) {
+	resp, err := c.client.Get(c.RemoteURL)
+	if err != nil {
+		return "", errors.Wrapf(err, "problem requesting %s", c.RemoteURL)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Errorf("%s returned %s", c.RemoteURL, resp.Status)
+	}
+
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, remoteHashLimit))
+	if err != nil {
+		return "", errors.Wrapf(err, "problem reading response from %s", c.RemoteURL)
+	}
+
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return "", errors.Errorf("%s did not return a hash", c.RemoteURL)
+	}
+
+	value := strings.ToLower(fields[0])
+	size := hashAlgorithms[c.Algorithm]().Size()
+	if decoded, err := hex.DecodeString(value); err != nil || len(decoded) != size {
+		return "", errors.Errorf("%s returned '%s', which is not a %s hash", c.RemoteURL, fields[0], c.Algorithm)
+	}
+
+	return value, nil
+}
+
+func (c *sharedConfigHash) Run() {
+	c.startTask()
+	defer c.MarkComplete()
+
+	if err := c.validate(); err != nil {
+		c.setState(false)
+		c.setReason(ReasonInvalidConfig)
+		c.AddError(err)
+		return
+	}
+
+	local, err := c.localHash()
+	if err != nil {
+		c.setState(false)
+		c.AddError(err)
+		return
+	}
+
+	remote, err := c.remoteHash()
+	if err != nil {
+		c.setMessage(fmt.Sprintf("local %s=%s", c.Algorithm, local))
+		c.setState(false)
+		c.AddError(err)
+		return
+	}
+
+	c.setMessage(fmt.Sprintf("local %s=%s, remote %s=%s", c.Algorithm, local, c.Algorithm, remote))
+
+	if local != remote {
+		c.setState(false)
+		c.AddError(errors.Errorf("%s hash of '%s' is %s, but %s has %s",
+			c.Algorithm, c.Path, local, c.RemoteURL, remote))
+		return
+	}
+
+	c.setState(true)
+}
@@ -0,0 +1,126 @@
+package check
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type SharedConfigHashSuite struct {
+	check   *sharedConfigHash
+	server  *httptest.Server
+	tmpDir  string
+	hash    string
+	require *require.Assertions
+	suite.Suite
+}
+
+func TestSharedConfigHashSuite(t *testing.T) {
+	suite.Run(t, new(SharedConfigHashSuite))
+}
+
+func (s *SharedConfigHashSuite) SetupSuite() {
+	s.require = s.Require()
+
+	tmpDir, err := ioutil.TempDir("", uuid.NewV4().String())
+	s.require.NoError(err)
+	s.tmpDir = tmpDir
+
+	content := []byte("listen: 0.0.0.0:8080\nworkers: 4\n")
+	s.require.NoError(ioutil.WriteFile(filepath.Join(tmpDir, "app.yaml"), content, 0644))
+	sum := sha256.Sum256(content)
+	s.hash = hex.EncodeToString(sum[:])
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/app.yaml.sha256", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "%s  app.yaml\n", s.hash)
+	})
+	mux.HandleFunc("/stale.sha256", func(w http.ResponseWriter, r *http.Request) {
+		sum := sha256.Sum256([]byte("workers: 2\n"))
+		fmt.Fprintln(w, hex.EncodeToString(sum[:]))
+	})
+	mux.HandleFunc("/garbage", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "<html>maintenance</html>")
+	})
+	s.server = httptest.NewServer(mux)
+}
+
+func (s *SharedConfigHashSuite) TearDownSuite() {
+	s.server.Close()
+	s.NoError(os.RemoveAll(s.tmpDir))
+}
+
+func (s *SharedConfigHashSuite) SetupTest() {
+	s.check = &sharedConfigHash{
+		Base:      NewBase("shared-config-hash", 0),
+		Path:      filepath.Join(s.tmpDir, "app.yaml"),
+		RemoteURL: s.server.URL + "/app.yaml.sha256",
+		Algorithm: "sha256",
+		client:    http.DefaultClient,
+	}
+}
+
+func (s *SharedConfigHashSuite) TestValidation() {
+	s.NoError(s.check.validate())
+
+	s.check.Algorithm = "crc32"
+	s.Error(s.check.validate())
+
+	s.SetupTest()
+	s.check.Path = ""
+	s.Error(s.check.validate())
+
+	s.SetupTest()
+	s.check.RemoteURL = ""
+	s.Error(s.check.validate())
+}
+
+func (s *SharedConfigHashSuite) TestMatchingHashPasses() {
+	s.check.Run()
+	s.True(s.check.Output().Passed)
+	s.NoError(s.check.Error())
+	s.Contains(s.check.Output().Message, s.hash)
+}
+
+func (s *SharedConfigHashSuite) TestMismatchReportsBothHashes() {
+	s.check.RemoteURL = s.server.URL + "/stale.sha256"
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), s.hash)
+
+	sum := sha256.Sum256([]byte("workers: 2\n"))
+	s.Contains(s.check.Error().Error(), hex.EncodeToString(sum[:]))
+}
+
+func (s *SharedConfigHashSuite) TestRemoteErrors() {
+	s.check.RemoteURL = s.server.URL + "/garbage"
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "is not a sha256 hash")
+
+	s.SetupTest()
+	s.check.RemoteURL = s.server.URL + "/missing"
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "404")
+
+	s.SetupTest()
+	s.check.Algorithm = "md5"
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "is not a md5 hash")
+}
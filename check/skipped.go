@@ -0,0 +1,62 @@
+package check
+
+import (
+	"sync"
+	"time"
+
+	"github.com/mongodb/greenbay"
+)
+
+// Skip wraps a check so that running the check does not perform any
+// of the check's work, and the output of the check reports that it
+// was skipped, with the reason as its message.
+func Skip(c greenbay.Checker, reason string) greenbay.Checker {
+	return &skippedCheck{
+		Checker: c,
+		reason:  reason,
+	}
+}
+
+type skippedCheck struct {
+	greenbay.Checker
+	reason   string
+	complete bool
+	timing   greenbay.TimingInfo
+	mutex    sync.RWMutex
+}
+
+func (c *skippedCheck) Run() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.timing.Start = time.Now()
+	c.timing.End = c.timing.Start
+	c.complete = true
+}
+
+func (c *skippedCheck) Completed() bool {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return c.complete
+}
+
+func (c *skippedCheck) Error() error {
+	return nil
+}
+
+func (c *skippedCheck) Output() greenbay.CheckOutput {
+	out := c.Checker.Output()
+
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	out.Completed = c.complete
+	out.Passed = false
+	out.Skipped = true
+	out.Message = c.reason
+	out.Error = ""
+	out.Timing = c.timing
+
+	return out
+}
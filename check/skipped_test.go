@@ -0,0 +1,34 @@
+package check
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSkippedCheckDoesNotRunWrappedCheck(t *testing.T) {
+	assert := assert.New(t)
+
+	mock := &MockCheck{Base: NewBase("mock-check", 0)}
+	mock.SetID("skipped-mock")
+	mock.SetSuites([]string{"one"})
+
+	c := Skip(mock, "unchanged")
+	assert.Equal("skipped-mock", c.ID())
+	assert.False(c.Completed())
+	assert.False(c.Output().Completed)
+
+	c.Run()
+	assert.False(mock.hasRun)
+	assert.True(c.Completed())
+	assert.NoError(c.Error())
+
+	out := c.Output()
+	assert.True(out.Completed)
+	assert.True(out.Skipped)
+	assert.False(out.Passed)
+	assert.Equal("unchanged", out.Message)
+	assert.Equal("skipped-mock", out.Name)
+	assert.Equal("mock-check", out.Check)
+	assert.Equal([]string{"one"}, out.Suites)
+}
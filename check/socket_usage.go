@@ -0,0 +1,207 @@
+package check
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/registry"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	name := "socket-usage"
+	registry.AddJobType(name, func() amboy.Job {
+		return &socketUsage{
+			Base:         NewBase(name, 0),
+			sockstatFile: "/proc/net/sockstat",
+			sysctlDir:    "/proc/sys/net",
+		}
+	})
+}
+
+// socketLimitSysctls maps the socket counters that have a kernel
+// limit to the file, relative to /proc/sys/net, that holds the limit.
+var socketLimitSysctls = map[string]string{
+	"tcp.tw":     "ipv4/tcp_max_tw_buckets",
+	"tcp.orphan": "ipv4/tcp_max_orphans",
+}
+
+// socketUsage checks the socket counters in /proc/net/sockstat
+// against thresholds. Counters are named by their protocol and field
+// in lower case, e.g. "sockets.used", "tcp.inuse", "tcp.tw" (sockets
+// in TIME_WAIT), or "udp.inuse". The max document sets the largest
+// allowed count for a counter, and the max_percent document sets the
+// largest allowed count as a percentage of the kernel limit, which
+// is only supported for "tcp.tw" and "tcp.orphan". Only supported on
+// Linux.
+type socketUsage struct {
+	Max        map[string]int64   `bson:"max" json:"max" yaml:"max"`
+	MaxPercent map[string]float64 `bson:"max_percent" json:"max_percent" yaml:"max_percent"`
+	*Base      `bson:"metadata" json:"metadata" yaml:"metadata"`
+	fileReadLimit
+
+	sockstatFile string
+	sysctlDir    string
+}
+
+func (c *socketUsage) validate() error {
+	if len(c.Max) == 0 && len(c.MaxPercent) == 0 {
+		return errors.Errorf("no thresholds specified for '%s' (%s) check", c.ID(), c.Name())
+	}
+
+	for counter, percent := range c.MaxPercent {
+		if _, ok := socketLimitSysctls[counter]; !ok {
+			return errors.Errorf("counter '%s' for '%s' check does not have a kernel limit",
+				counter, c.ID())
+		}
+
+		if percent <= 0 || percent > 100 {
+			return errors.Errorf("max percent for '%s' in '%s' check must be between 0 and 100",
+				counter, c.ID())
+		}
+	}
+
+	return nil
+}
+
+// parseSockstat returns the counters in a sockstat file, which has
+// lines in the form "TCP: inuse 4 orphan 0 tw 8 alloc 4 mem 0".
+func parseSockstat(data []byte) (map[string]int64, error) {
+	counters := make(map[string]int64)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		parts := strings.SplitN(scanner.Text(), ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		protocol := strings.ToLower(strings.TrimSpace(parts[0]))
+		fields := strings.Fields(parts[1])
+		if len(fields)%2 != 0 {
+			return nil, errors.Errorf("sockstat line for '%s' is not valid", protocol)
+		}
+
+		for i := 0; i < len(fields); i += 2 {
+			value, err := strconv.ParseInt(fields[i+1], 10, 64)
+			if err != nil {
+				return nil, errors.Wrapf(err, "problem parsing '%s' for '%s'", fields[i], protocol)
+			}
+
+			counters[protocol+"."+fields[i]] = value
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "problem reading sockstat")
+	}
+
+	return counters, nil
+}
+
+func (c *socketUsage) kernelLimit(counter string) (int64, error) {
+	fn := filepath.Join(c.sysctlDir, socketLimitSysctls[counter])
+
+	data, err := c.readFile(fn)
+	if err != nil {
+		return 0, errors.Wrapf(err, "problem reading limit for '%s'", counter)
+	}
+
+	limit, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "problem parsing limit for '%s' from '%s'", counter, fn)
+	}
+
+	return limit, nil
+}
+
+func formatSocketCounters(counters map[string]int64) string {
+	names := make([]string, 0, len(counters))
+	for name := range counters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	out := make([]string, 0, len(names))
+	for _, name := range names {
+		out = append(out, fmt.Sprintf("%s=%d", name, counters[name]))
+	}
+
+	return strings.Join(out, ", ")
+}
+
+func (c *socketUsage) Run() {
+	c.startTask()
+	defer c.MarkComplete()
+
+	if err := c.validate(); err != nil {
+		c.setState(false)
+		c.setReason(ReasonInvalidConfig)
+		c.AddError(err)
+		return
+	}
+
+	data, err := c.readFile(c.sockstatFile)
+	if err != nil {
+		c.setState(false)
+		c.AddError(errors.Wrapf(err, "problem reading socket counters from '%s'", c.sockstatFile))
+		return
+	}
+
+	counters, err := parseSockstat(data)
+	if err != nil {
+		c.setState(false)
+		c.AddError(errors.Wrapf(err, "problem parsing socket counters from '%s'", c.sockstatFile))
+		return
+	}
+
+	c.setMessage(formatSocketCounters(counters))
+	c.setState(true)
+
+	for counter, max := range c.Max {
+		value, ok := counters[counter]
+		if !ok {
+			c.setState(false)
+			c.AddError(errors.Errorf("'%s' is not a socket counter", counter))
+			continue
+		}
+
+		if value > max {
+			c.setState(false)
+			c.AddError(errors.Errorf("%s is %d, which is more than %d", counter, value, max))
+		}
+	}
+
+	for counter, percent := range c.MaxPercent {
+		value, ok := counters[counter]
+		if !ok {
+			c.setState(false)
+			c.AddError(errors.Errorf("'%s' is not a socket counter", counter))
+			continue
+		}
+
+		limit, err := c.kernelLimit(counter)
+		if err != nil {
+			c.setState(false)
+			c.AddError(err)
+			continue
+		}
+
+		if limit <= 0 {
+			continue
+		}
+
+		used := 100 * float64(value) / float64(limit)
+		if used > percent {
+			c.setState(false)
+			c.AddError(errors.Errorf("%s is %d, which is %.1f%% of the limit of %d (max %g%%)",
+				counter, value, used, limit, percent))
+		}
+	}
+}
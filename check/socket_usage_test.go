@@ -0,0 +1,130 @@
+package check
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const testSockstat = `sockets: used 160
+TCP: inuse 40 orphan 2 tw 800 alloc 44 mem 3
+UDP: inuse 5 mem 1
+UDPLITE: inuse 0
+RAW: inuse 0
+FRAG: inuse 0 memory 0
+`
+
+type SocketUsageSuite struct {
+	tmpDir  string
+	check   *socketUsage
+	require *require.Assertions
+	suite.Suite
+}
+
+func TestSocketUsageSuite(t *testing.T) {
+	suite.Run(t, new(SocketUsageSuite))
+}
+
+func (s *SocketUsageSuite) SetupSuite() {
+	s.require = s.Require()
+
+	dir, err := ioutil.TempDir("", uuid.NewV4().String())
+	s.require.NoError(err)
+	s.tmpDir = dir
+
+	s.require.NoError(os.MkdirAll(filepath.Join(dir, "sys", "ipv4"), 0755))
+	s.require.NoError(ioutil.WriteFile(filepath.Join(dir, "sockstat"), []byte(testSockstat), 0644))
+	s.require.NoError(ioutil.WriteFile(filepath.Join(dir, "sys", "ipv4", "tcp_max_tw_buckets"),
+		[]byte("1000\n"), 0644))
+}
+
+func (s *SocketUsageSuite) TearDownSuite() {
+	s.require.NoError(os.RemoveAll(s.tmpDir))
+}
+
+func (s *SocketUsageSuite) SetupTest() {
+	s.check = &socketUsage{
+		Base:         NewBase("socket-usage", 0),
+		sockstatFile: filepath.Join(s.tmpDir, "sockstat"),
+		sysctlDir:    filepath.Join(s.tmpDir, "sys"),
+	}
+}
+
+func (s *SocketUsageSuite) TestParseSockstat() {
+	counters, err := parseSockstat([]byte(testSockstat))
+	s.require.NoError(err)
+	s.Equal(int64(160), counters["sockets.used"])
+	s.Equal(int64(40), counters["tcp.inuse"])
+	s.Equal(int64(800), counters["tcp.tw"])
+	s.Equal(int64(5), counters["udp.inuse"])
+
+	_, err = parseSockstat([]byte("TCP: inuse four\n"))
+	s.Error(err)
+
+	_, err = parseSockstat([]byte("TCP: inuse\n"))
+	s.Error(err)
+}
+
+func (s *SocketUsageSuite) TestValidation() {
+	s.Error(s.check.validate())
+
+	s.check.Max = map[string]int64{"tcp.inuse": 100}
+	s.NoError(s.check.validate())
+
+	s.check.MaxPercent = map[string]float64{"tcp.inuse": 50}
+	s.Error(s.check.validate())
+
+	s.check.MaxPercent = map[string]float64{"tcp.tw": 150}
+	s.Error(s.check.validate())
+
+	s.check.MaxPercent = map[string]float64{"tcp.tw": 50}
+	s.NoError(s.check.validate())
+}
+
+func (s *SocketUsageSuite) TestCountsBelowThresholdsPass() {
+	s.check.Max = map[string]int64{"tcp.inuse": 100, "sockets.used": 1000}
+	s.check.MaxPercent = map[string]float64{"tcp.tw": 90}
+	s.check.Run()
+
+	output := s.check.Output()
+	s.True(output.Passed)
+	s.NoError(s.check.Error())
+	s.Contains(output.Message, "tcp.tw=800")
+	s.Contains(output.Message, "udp.inuse=5")
+}
+
+func (s *SocketUsageSuite) TestCountAboveMaxFails() {
+	s.check.Max = map[string]int64{"tcp.inuse": 10}
+	s.check.Run()
+
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "tcp.inuse is 40")
+}
+
+func (s *SocketUsageSuite) TestCountAbovePercentOfLimitFails() {
+	s.check.MaxPercent = map[string]float64{"tcp.tw": 50}
+	s.check.Run()
+
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "80.0%")
+}
+
+func (s *SocketUsageSuite) TestUnknownCounterOrMissingLimitFails() {
+	s.check.Max = map[string]int64{"sctp.inuse": 10}
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.Error(s.check.Error())
+
+	s.SetupTest()
+	s.check.MaxPercent = map[string]float64{"tcp.orphan": 50}
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.Error(s.check.Error())
+}
@@ -0,0 +1,225 @@
+package check
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/registry"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	name := "sshd-config"
+	registry.AddJobType(name, func() amboy.Job {
+		return &sshdConfig{
+			Base:       NewBase(name, 0),
+			configFile: "/etc/ssh/sshd_config",
+		}
+	})
+}
+
+// maxSSHDIncludeDepth limits nested Include directives, to avoid
+// loops in the config.
+const maxSSHDIncludeDepth = 16
+
+// sshdConfig checks that the global settings in sshd_config have the
+// expected values. The directives document maps keywords
+// (e.g. "PermitRootLogin") to the expected value (e.g. "no"). Keywords
+// and values are not case sensitive.
+//
+// Like sshd, the check uses the first value set for each keyword,
+// follows Include directives, and ignores settings in Match blocks,
+// which only apply to some connections. Directives that the config
+// does not set use the sshd default, and fail the check.
+type sshdConfig struct {
+	Directives map[string]string `bson:"directives" json:"directives" yaml:"directives"`
+	*Base      `bson:"metadata" json:"metadata" yaml:"metadata"`
+	fileReadLimit
+
+	configFile string
+}
+
+// sshdSetting is the value of a keyword in sshd_config, with the
+// location where it is set.
+type sshdSetting struct {
+	value string
+	file  string
+	line  int
+}
+
+func (c *sshdConfig) validate() error {
+	if len(c.Directives) == 0 {
+		return errors.Errorf("no directives specified for '%s' (%s) check", c.ID(), c.Name())
+	}
+
+	return nil
+}
+
+func (c *sshdConfig) Run() {
+	c.startTask()
+	defer c.MarkComplete()
+
+	if err := c.validate(); err != nil {
+		c.setState(false)
+		c.setReason(ReasonInvalidConfig)
+		c.AddError(err)
+		return
+	}
+
+	settings := make(map[string]sshdSetting)
+	if err := c.parseFile(c.configFile, 0, settings); err != nil {
+		c.setState(false)
+		c.AddError(err)
+		return
+	}
+
+	keywords := make([]string, 0, len(c.Directives))
+	for keyword := range c.Directives {
+		keywords = append(keywords, keyword)
+	}
+	sort.Strings(keywords)
+
+	var missing []string
+	var wrong []string
+	for _, keyword := range keywords {
+		expected := normalizeSSHDValue(c.Directives[keyword])
+
+		setting, ok := settings[strings.ToLower(keyword)]
+		if !ok {
+			missing = append(missing, keyword)
+			continue
+		}
+
+		if normalizeSSHDValue(setting.value) != expected {
+			wrong = append(wrong, fmt.Sprintf("%s is '%s', not '%s' (%s:%d)",
+				keyword, setting.value, c.Directives[keyword], setting.file, setting.line))
+		}
+	}
+
+	if len(missing) == 0 && len(wrong) == 0 {
+		c.setState(true)
+		return
+	}
+
+	c.setState(false)
+
+	var msgs []string
+	if len(wrong) > 0 {
+		msgs = append(msgs, "wrong: ["+strings.Join(wrong, "; ")+"]")
+	}
+	if len(missing) > 0 {
+		msgs = append(msgs, "not set, using defaults: ["+strings.Join(missing, ", ")+"]")
+	}
+	c.setMessage(strings.Join(msgs, "; "))
+
+	c.AddError(errors.Errorf("%d of %d directives in %s do not have the expected value",
+		len(missing)+len(wrong), len(c.Directives), c.configFile))
+}
+
+func (c *sshdConfig) parseFile(fn string, depth int, settings map[string]sshdSetting) error {
+	if depth > maxSSHDIncludeDepth {
+		return errors.Errorf("too many nested includes at '%s'", fn)
+	}
+
+	data, err := c.readFile(fn)
+	if err != nil {
+		return errors.Wrap(err, "problem reading sshd config")
+	}
+
+	inMatch := false
+	lineNum := 0
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		lineNum++
+
+		keyword, value := splitSSHDLine(scanner.Text())
+		if keyword == "" {
+			continue
+		}
+
+		switch keyword {
+		case "match":
+			// a match block continues until the next match
+			// block or the end of the file.
+			inMatch = true
+			continue
+		case "include":
+			if inMatch {
+				continue
+			}
+
+			if err = c.parseInclude(value, depth, settings); err != nil {
+				return errors.Wrapf(err, "problem processing include on %s:%d", fn, lineNum)
+			}
+			continue
+		}
+
+		if inMatch {
+			continue
+		}
+
+		if _, ok := settings[keyword]; !ok {
+			settings[keyword] = sshdSetting{value: value, file: fn, line: lineNum}
+		}
+	}
+
+	if err = scanner.Err(); err != nil {
+		return errors.Wrapf(err, "problem reading sshd config '%s'", fn)
+	}
+
+	return nil
+}
+
+func (c *sshdConfig) parseInclude(value string, depth int, settings map[string]sshdSetting) error {
+	for _, pattern := range strings.Fields(value) {
+		if !filepath.IsAbs(pattern) {
+			pattern = filepath.Join(filepath.Dir(c.configFile), pattern)
+		}
+
+		// filepath.Glob returns matches in lexical order, which is
+		// the order that sshd uses.
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return errors.Wrapf(err, "include pattern '%s' is not valid", pattern)
+		}
+
+		for _, fn := range matches {
+			if err = c.parseFile(fn, depth+1, settings); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// splitSSHDLine returns the lower case keyword and the value of a
+// line from sshd_config. Keywords and values are separated by
+// whitespace or an equals sign. Returns an empty keyword for blank
+// lines and comments.
+func splitSSHDLine(line string) (string, string) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return "", ""
+	}
+
+	idx := strings.IndexAny(line, " \t=")
+	if idx < 0 {
+		return strings.ToLower(line), ""
+	}
+
+	keyword := strings.ToLower(line[:idx])
+	value := strings.TrimSpace(line[idx:])
+	value = strings.TrimSpace(strings.TrimPrefix(value, "="))
+
+	return keyword, value
+}
+
+func normalizeSSHDValue(value string) string {
+	return strings.ToLower(strings.Join(strings.Fields(value), " "))
+}
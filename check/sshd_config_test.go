@@ -0,0 +1,120 @@
+package check
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type SSHDConfigSuite struct {
+	tmpDir  string
+	check   *sshdConfig
+	require *require.Assertions
+	suite.Suite
+}
+
+func TestSSHDConfigSuite(t *testing.T) {
+	suite.Run(t, new(SSHDConfigSuite))
+}
+
+func (s *SSHDConfigSuite) SetupSuite() {
+	s.require = s.Require()
+
+	dir, err := ioutil.TempDir("", uuid.NewV4().String())
+	s.require.NoError(err)
+	s.tmpDir = dir
+
+	s.require.NoError(os.MkdirAll(filepath.Join(dir, "sshd_config.d"), 0755))
+	s.require.NoError(ioutil.WriteFile(filepath.Join(dir, "sshd_config.d", "10-hardening.conf"), []byte(`
+PasswordAuthentication no
+X11Forwarding no
+`), 0644))
+	s.require.NoError(ioutil.WriteFile(filepath.Join(dir, "sshd_config"), []byte(`
+# hardened configuration
+Include sshd_config.d/*.conf
+PermitRootLogin=no
+PasswordAuthentication yes
+Ciphers  aes256-gcm@openssh.com   aes128-gcm@openssh.com
+PermitRootLogin yes
+
+Match User backup
+    PermitRootLogin yes
+    MaxSessions 2
+`), 0644))
+}
+
+func (s *SSHDConfigSuite) TearDownSuite() {
+	s.require.NoError(os.RemoveAll(s.tmpDir))
+}
+
+func (s *SSHDConfigSuite) SetupTest() {
+	s.check = &sshdConfig{
+		Base:       NewBase("sshd-config", 0),
+		configFile: filepath.Join(s.tmpDir, "sshd_config"),
+	}
+}
+
+func (s *SSHDConfigSuite) TestSplitSSHDLine() {
+	for line, expected := range map[string][2]string{
+		"":                       {"", ""},
+		"   # comment":           {"", ""},
+		"PermitRootLogin no":     {"permitrootlogin", "no"},
+		"PermitRootLogin=no":     {"permitrootlogin", "no"},
+		"PermitRootLogin = no":   {"permitrootlogin", "no"},
+		"\tUsePAM\tyes  ":        {"usepam", "yes"},
+		"AllowUsers alice bob":   {"allowusers", "alice bob"},
+		"Match Address 10.0.0.0": {"match", "Address 10.0.0.0"},
+	} {
+		keyword, value := splitSSHDLine(line)
+		s.Equal(expected[0], keyword, line)
+		s.Equal(expected[1], value, line)
+	}
+}
+
+func (s *SSHDConfigSuite) TestUnconfiguredCheckFails() {
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.Error(s.check.Error())
+}
+
+func (s *SSHDConfigSuite) TestFirstValueAndIncludesWin() {
+	s.check.Directives = map[string]string{
+		"PermitRootLogin":        "no",
+		"passwordauthentication": "No",
+		"X11Forwarding":          "no",
+		"Ciphers":                "aes256-gcm@openssh.com aes128-gcm@openssh.com",
+	}
+	s.check.Run()
+
+	s.True(s.check.Output().Passed, s.check.Output().Message)
+	s.NoError(s.check.Error())
+}
+
+func (s *SSHDConfigSuite) TestWrongAndMissingDirectivesAreReportedSeparately() {
+	s.check.Directives = map[string]string{
+		"PermitRootLogin": "prohibit-password",
+		"MaxSessions":     "2",
+		"X11Forwarding":   "no",
+	}
+	s.check.Run()
+
+	output := s.check.Output()
+	s.False(output.Passed)
+	s.Error(s.check.Error())
+	s.Contains(output.Message, "wrong: [PermitRootLogin is 'no', not 'prohibit-password'")
+	s.Contains(output.Message, "not set, using defaults: [MaxSessions]")
+	s.NotContains(output.Message, "X11Forwarding")
+}
+
+func (s *SSHDConfigSuite) TestMissingConfigFails() {
+	s.check.configFile = filepath.Join(s.tmpDir, "DOES-NOT-EXIST")
+	s.check.Directives = map[string]string{"PermitRootLogin": "no"}
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.Error(s.check.Error())
+}
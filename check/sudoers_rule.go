@@ -0,0 +1,210 @@
+package check
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/registry"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	name := "sudoers-rule"
+	registry.AddJobType(name, func() amboy.Job {
+		return &sudoersRule{
+			Base:        NewBase(name, 0),
+			sudoersFile: "/etc/sudoers",
+			includeDir:  "/etc/sudoers.d",
+		}
+	})
+}
+
+// sudoersRule checks the rules in /etc/sudoers and the files in
+// /etc/sudoers.d. When rule_contains is set, a rule that contains
+// that text (ignoring differences in whitespace) must be present,
+// or, when present is false, absent. When forbid_nopasswd is set, no
+// rule may grant NOPASSWD. Like sudo, the check ignores files in
+// sudoers.d with names that contain a "." or end in "~", and joins
+// lines that end in a backslash. The check does not follow other
+// include directives. Reading the files usually requires root.
+type sudoersRule struct {
+	RuleContains   string `bson:"rule_contains" json:"rule_contains" yaml:"rule_contains"`
+	Present        *bool  `bson:"present" json:"present" yaml:"present"`
+	ForbidNOPASSWD bool   `bson:"forbid_nopasswd" json:"forbid_nopasswd" yaml:"forbid_nopasswd"`
+	*Base          `bson:"metadata" json:"metadata" yaml:"metadata"`
+	fileReadLimit
+
+	sudoersFile string
+	includeDir  string
+}
+
+// sudoersLine is a logical line from a sudoers file, with the
+// location of its first line.
+type sudoersLine struct {
+	source string
+	text   string
+}
+
+func (c *sudoersRule) validate() error {
+	if strings.TrimSpace(c.RuleContains) == "" && !c.ForbidNOPASSWD {
+		return errors.Errorf("no rule or forbid_nopasswd specified for '%s' (%s) check", c.ID(), c.Name())
+	}
+
+	if c.Present != nil && c.RuleContains == "" {
+		return errors.Errorf("present requires rule_contains for '%s' check", c.ID())
+	}
+
+	return nil
+}
+
+func (c *sudoersRule) Run() {
+	c.startTask()
+	defer c.MarkComplete()
+
+	if err := c.validate(); err != nil {
+		c.setState(false)
+		c.setReason(ReasonInvalidConfig)
+		c.AddError(err)
+		return
+	}
+
+	lines, err := c.readRules()
+	if err != nil {
+		c.setState(false)
+		c.AddError(err)
+		return
+	}
+
+	var messages []string
+	var problems []string
+
+	if c.RuleContains != "" {
+		rule := normalizeSudoersText(c.RuleContains)
+		present := c.Present == nil || *c.Present
+
+		var matches []string
+		for _, l := range lines {
+			if strings.Contains(l.text, rule) {
+				matches = append(matches, fmt.Sprintf("%s: %s", l.source, l.text))
+			}
+		}
+		messages = append(messages, matches...)
+
+		if present && len(matches) == 0 {
+			problems = append(problems, fmt.Sprintf("no rule contains '%s'", rule))
+		} else if !present && len(matches) > 0 {
+			problems = append(problems, fmt.Sprintf("%d rule(s) contain '%s'", len(matches), rule))
+		}
+	}
+
+	if c.ForbidNOPASSWD {
+		var grants []string
+		for _, l := range lines {
+			if !strings.HasPrefix(l.text, "Defaults") && strings.Contains(l.text, "NOPASSWD:") {
+				grants = append(grants, fmt.Sprintf("%s: %s", l.source, l.text))
+			}
+		}
+		messages = append(messages, grants...)
+
+		if len(grants) > 0 {
+			problems = append(problems, fmt.Sprintf("%d rule(s) grant NOPASSWD", len(grants)))
+		}
+	}
+
+	if len(messages) == 0 {
+		messages = append(messages, fmt.Sprintf("no matching rules in %d sudoers rule(s)", len(lines)))
+	}
+	c.setMessage(messages)
+
+	if len(problems) > 0 {
+		c.setState(false)
+		c.AddError(errors.Errorf("sudoers rules do not match: %s", strings.Join(problems, ", ")))
+		return
+	}
+
+	c.setState(true)
+}
+
+// readRules returns the logical lines of the sudoers file, and the
+// files in the include directory, in the order that sudo reads them.
+func (c *sudoersRule) readRules() ([]sudoersLine, error) {
+	data, err := c.readFile(c.sudoersFile)
+	if err != nil {
+		return nil, err
+	}
+	lines := parseSudoers(c.sudoersFile, data)
+
+	infos, err := ioutil.ReadDir(c.includeDir)
+	if os.IsNotExist(err) {
+		return lines, nil
+	} else if err != nil {
+		return nil, errors.Wrapf(err, "problem reading '%s'", c.includeDir)
+	}
+
+	var names []string
+	for _, info := range infos {
+		name := info.Name()
+		if info.IsDir() || strings.Contains(name, ".") || strings.HasSuffix(name, "~") {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		fn := filepath.Join(c.includeDir, name)
+		data, err := c.readFile(fn)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, parseSudoers(fn, data)...)
+	}
+
+	return lines, nil
+}
+
+// parseSudoers returns the logical lines of a sudoers file, without
+// comments and blank lines. Lines that end in a backslash continue
+// on the next line. Include directives, which may start with "#",
+// are not comments.
+func parseSudoers(fn string, data []byte) []sudoersLine {
+	var lines []sudoersLine
+	var current []string
+	start := 0
+
+	for idx, raw := range strings.Split(string(data), "\n") {
+		line := strings.TrimSpace(raw)
+
+		if len(current) == 0 {
+			start = idx + 1
+			if line == "" || (strings.HasPrefix(line, "#") && !strings.HasPrefix(line, "#include")) {
+				continue
+			}
+		}
+
+		if strings.HasSuffix(line, "\\") {
+			current = append(current, strings.TrimSuffix(line, "\\"))
+			continue
+		}
+
+		current = append(current, line)
+		lines = append(lines, sudoersLine{
+			source: fmt.Sprintf("%s:%d", fn, start),
+			text:   normalizeSudoersText(strings.Join(current, " ")),
+		})
+		current = nil
+	}
+
+	return lines
+}
+
+// normalizeSudoersText collapses runs of whitespace into single
+// spaces.
+func normalizeSudoersText(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
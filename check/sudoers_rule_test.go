@@ -0,0 +1,166 @@
+package check
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type SudoersRuleSuite struct {
+	tmpDir  string
+	check   *sudoersRule
+	require *require.Assertions
+	suite.Suite
+}
+
+func TestSudoersRuleSuite(t *testing.T) {
+	suite.Run(t, new(SudoersRuleSuite))
+}
+
+func (s *SudoersRuleSuite) SetupSuite() {
+	s.require = s.Require()
+
+	dir, err := ioutil.TempDir("", uuid.NewV4().String())
+	s.require.NoError(err)
+	s.tmpDir = dir
+
+	files := map[string]string{
+		"sudoers": `# sudoers file
+Defaults	env_reset
+Defaults	!authenticate_nopasswd: NOPASSWD: ignored
+
+root	ALL=(ALL:ALL) ALL
+%admin  ALL=(ALL)   ALL
+# %wheel ALL=(ALL) NOPASSWD: ALL
+
+#includedir /etc/sudoers.d
+`,
+		"sudoers.d/deploy": `deploy ALL=(root) NOPASSWD: /usr/bin/systemctl restart app, \
+	/usr/bin/systemctl status app
+`,
+		"sudoers.d/README":    "# notes only\n",
+		"sudoers.d/old.bak":   "backup ALL=(ALL) NOPASSWD: ALL\n",
+		"sudoers.d/monitor~":  "monitor ALL=(ALL) NOPASSWD: ALL\n",
+		"clean/sudoers":       "root ALL=(ALL:ALL) ALL\n",
+		"clean/sudoers.d/ops": "%ops ALL=(ALL) ALL\n",
+	}
+
+	for fn, content := range files {
+		path := filepath.Join(dir, fn)
+		s.require.NoError(os.MkdirAll(filepath.Dir(path), 0755))
+		s.require.NoError(ioutil.WriteFile(path, []byte(content), 0644))
+	}
+}
+
+func (s *SudoersRuleSuite) TearDownSuite() {
+	s.require.NoError(os.RemoveAll(s.tmpDir))
+}
+
+func (s *SudoersRuleSuite) SetupTest() {
+	s.check = &sudoersRule{
+		Base:        NewBase("sudoers-rule", 0),
+		sudoersFile: filepath.Join(s.tmpDir, "sudoers"),
+		includeDir:  filepath.Join(s.tmpDir, "sudoers.d"),
+	}
+}
+
+func (s *SudoersRuleSuite) TestValidation() {
+	s.Error(s.check.validate())
+
+	present := false
+	s.check.Present = &present
+	s.check.ForbidNOPASSWD = true
+	s.Error(s.check.validate())
+
+	s.check.Present = nil
+	s.NoError(s.check.validate())
+
+	s.check.ForbidNOPASSWD = false
+	s.check.RuleContains = "%admin ALL=(ALL) ALL"
+	s.NoError(s.check.validate())
+}
+
+func (s *SudoersRuleSuite) TestParseJoinsContinuationLines() {
+	data, err := ioutil.ReadFile(filepath.Join(s.tmpDir, "sudoers.d", "deploy"))
+	s.require.NoError(err)
+
+	lines := parseSudoers("deploy", data)
+	s.require.Len(lines, 1)
+	s.Equal("deploy:1", lines[0].source)
+	s.Equal("deploy ALL=(root) NOPASSWD: /usr/bin/systemctl restart app, /usr/bin/systemctl status app",
+		lines[0].text)
+}
+
+func (s *SudoersRuleSuite) TestRulePresent() {
+	s.check.RuleContains = "%admin	ALL=(ALL) ALL"
+	s.check.Run()
+
+	s.True(s.check.Output().Passed)
+	s.NoError(s.check.Error())
+	s.Equal(filepath.Join(s.tmpDir, "sudoers")+":6: %admin ALL=(ALL) ALL", s.check.Output().Message)
+
+	// commented out rules do not count.
+	s.SetupTest()
+	s.check.RuleContains = "%wheel"
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "no rule contains '%wheel'")
+}
+
+func (s *SudoersRuleSuite) TestRuleAbsent() {
+	absent := false
+	s.check.RuleContains = "%wheel"
+	s.check.Present = &absent
+	s.check.Run()
+	s.True(s.check.Output().Passed)
+
+	s.SetupTest()
+	s.check.RuleContains = "/usr/bin/systemctl restart app"
+	s.check.Present = &absent
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "1 rule(s) contain")
+	s.Contains(s.check.Output().Message, "deploy:1: deploy ALL=(root)")
+}
+
+func (s *SudoersRuleSuite) TestForbidNOPASSWD() {
+	s.check.ForbidNOPASSWD = true
+	s.check.Run()
+
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "1 rule(s) grant NOPASSWD")
+	s.Contains(s.check.Output().Message, "deploy ALL=(root) NOPASSWD:")
+	s.NotContains(s.check.Output().Message, "backup")
+	s.NotContains(s.check.Output().Message, "monitor")
+
+	s.SetupTest()
+	s.check.sudoersFile = filepath.Join(s.tmpDir, "clean", "sudoers")
+	s.check.includeDir = filepath.Join(s.tmpDir, "clean", "sudoers.d")
+	s.check.ForbidNOPASSWD = true
+	s.check.RuleContains = "%ops ALL"
+	s.check.Run()
+	s.True(s.check.Output().Passed)
+	s.NoError(s.check.Error())
+}
+
+func (s *SudoersRuleSuite) TestMissingFiles() {
+	s.check.includeDir = filepath.Join(s.tmpDir, "DOES-NOT-EXIST")
+	s.check.RuleContains = "root"
+	s.check.Run()
+	s.True(s.check.Output().Passed)
+
+	s.SetupTest()
+	s.check.sudoersFile = filepath.Join(s.tmpDir, "DOES-NOT-EXIST")
+	s.check.RuleContains = "root"
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.Error(s.check.Error())
+}
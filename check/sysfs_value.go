@@ -0,0 +1,194 @@
+package check
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/registry"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	name := "sysfs-value"
+	registry.AddJobType(name, func() amboy.Job {
+		return &sysfsValue{
+			Base:     NewBase(name, 0),
+			Operator: "eq",
+		}
+	})
+}
+
+// sysfsValue checks the value of a kernel tunable in /proc/sys or
+// /sys, such as "/proc/sys/vm/swappiness" or
+// "/sys/kernel/mm/transparent_hugepage/enabled". The path may be a
+// glob (e.g. "/sys/block/*/queue/scheduler"), in which case every
+// matching file must have the expected value, and the check fails if
+// no files match. Values are compared after collapsing whitespace.
+//
+// The operator is one of:
+//   - "eq" (the default) and "ne", which compare the values as
+//     strings,
+//   - "gt", "gte", "lt", and "lte", which compare the values as
+//     numbers,
+//   - "contains", which requires the expected value as one of the
+//     words of the value, and
+//   - "selected", for tunables that list the options and mark the
+//     current one in brackets (e.g. "mq-deadline [none] kyber"),
+//     which requires the expected option to be the current one.
+type sysfsValue struct {
+	Path     string `bson:"path" json:"path" yaml:"path"`
+	Expected string `bson:"expected" json:"expected" yaml:"expected"`
+	Operator string `bson:"operator" json:"operator" yaml:"operator"`
+	*Base    `bson:"metadata" json:"metadata" yaml:"metadata"`
+	fileReadLimit
+}
+
+func (c *sysfsValue) validate() error {
+	if c.Path == "" {
+		return errors.Errorf("no path specified for '%s' (%s) check", c.ID(), c.Name())
+	}
+
+	if _, err := filepath.Match(c.Path, ""); err != nil {
+		return errors.Wrapf(err, "invalid path pattern for '%s' check", c.ID())
+	}
+
+	if c.Operator == "" {
+		c.Operator = "eq"
+	}
+
+	switch c.Operator {
+	case "eq", "ne", "contains", "selected":
+	case "gt", "gte", "lt", "lte":
+		if _, err := strconv.ParseFloat(strings.TrimSpace(c.Expected), 64); err != nil {
+			return errors.Errorf("expected value '%s' for '%s' check must be a number for the '%s' operator",
+				c.Expected, c.ID(), c.Operator)
+		}
+	default:
+		return errors.Errorf("operator '%s' for '%s' check is not valid", c.Operator, c.ID())
+	}
+
+	if c.Expected == "" && c.Operator != "eq" && c.Operator != "ne" {
+		return errors.Errorf("no expected value specified for '%s' (%s) check", c.ID(), c.Name())
+	}
+
+	return nil
+}
+
+func (c *sysfsValue) Run() {
+	c.startTask()
+	defer c.MarkComplete()
+
+	if err := c.validate(); err != nil {
+		c.setState(false)
+		c.setReason(ReasonInvalidConfig)
+		c.AddError(err)
+		return
+	}
+
+	files, err := filepath.Glob(c.Path)
+	if err != nil {
+		c.setState(false)
+		c.AddError(errors.Wrapf(err, "problem finding files matching '%s'", c.Path))
+		return
+	}
+
+	if len(files) == 0 {
+		c.setState(false)
+		c.setReason(ReasonFileMissing)
+		c.AddError(errors.Errorf("no files match '%s'", c.Path))
+		return
+	}
+
+	expected := normalizeSysfsValue(c.Expected)
+
+	var messages []string
+	var mismatched []string
+	for _, fn := range files {
+		data, err := c.readFile(fn)
+		if err != nil {
+			c.setState(false)
+			c.AddError(err)
+			return
+		}
+
+		observed := normalizeSysfsValue(string(data))
+		ok, err := compareSysfsValue(c.Operator, observed, expected)
+		if err != nil {
+			c.setState(false)
+			c.AddError(errors.Wrapf(err, "problem comparing the value of '%s'", fn))
+			return
+		}
+
+		messages = append(messages, fmt.Sprintf("%s: '%s' (expected %s '%s')", fn, observed, c.Operator, expected))
+		if !ok {
+			mismatched = append(mismatched, fn)
+		}
+	}
+
+	c.setMessage(messages)
+
+	if len(mismatched) > 0 {
+		c.setState(false)
+		c.setReason(ReasonValueMismatch)
+		c.AddError(errors.Errorf("%d of %d file(s) do not have the expected value: %s",
+			len(mismatched), len(files), strings.Join(mismatched, ", ")))
+		return
+	}
+
+	c.setState(true)
+}
+
+func normalizeSysfsValue(value string) string {
+	return strings.Join(strings.Fields(value), " ")
+}
+
+// compareSysfsValue reports if the observed value satisfies the
+// operator and the expected value.
+func compareSysfsValue(operator, observed, expected string) (bool, error) {
+	switch operator {
+	case "eq":
+		return observed == expected, nil
+	case "ne":
+		return observed != expected, nil
+	case "contains":
+		for _, word := range strings.Fields(observed) {
+			if word == expected {
+				return true, nil
+			}
+		}
+		return false, nil
+	case "selected":
+		for _, word := range strings.Fields(observed) {
+			if strings.HasPrefix(word, "[") && strings.HasSuffix(word, "]") {
+				return strings.Trim(word, "[]") == expected, nil
+			}
+		}
+		return false, errors.Errorf("value '%s' does not mark a selected option", observed)
+	}
+
+	actual, err := strconv.ParseFloat(observed, 64)
+	if err != nil {
+		return false, errors.Errorf("value '%s' is not a number", observed)
+	}
+
+	limit, err := strconv.ParseFloat(expected, 64)
+	if err != nil {
+		return false, errors.Errorf("expected value '%s' is not a number", expected)
+	}
+
+	switch operator {
+	case "gt":
+		return actual > limit, nil
+	case "gte":
+		return actual >= limit, nil
+	case "lt":
+		return actual < limit, nil
+	case "lte":
+		return actual <= limit, nil
+	default:
+		return false, errors.Errorf("operator '%s' is not valid", operator)
+	}
+}
@@ -0,0 +1,176 @@
+package check
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type SysfsValueSuite struct {
+	tmpDir  string
+	check   *sysfsValue
+	require *require.Assertions
+	suite.Suite
+}
+
+func TestSysfsValueSuite(t *testing.T) {
+	suite.Run(t, new(SysfsValueSuite))
+}
+
+func (s *SysfsValueSuite) SetupSuite() {
+	s.require = s.Require()
+
+	dir, err := ioutil.TempDir("", uuid.NewV4().String())
+	s.require.NoError(err)
+	s.tmpDir = dir
+
+	files := map[string]string{
+		"vm/swappiness":             "10\n",
+		"net/tcp_rmem":              "4096\t87380\t6291456\n",
+		"thp/enabled":               "always madvise [never]\n",
+		"block/sda/queue/scheduler": "mq-deadline [none] kyber\n",
+		"block/sdb/queue/scheduler": "[mq-deadline] none kyber\n",
+	}
+
+	for fn, content := range files {
+		path := filepath.Join(dir, fn)
+		s.require.NoError(os.MkdirAll(filepath.Dir(path), 0755))
+		s.require.NoError(ioutil.WriteFile(path, []byte(content), 0644))
+	}
+}
+
+func (s *SysfsValueSuite) TearDownSuite() {
+	s.require.NoError(os.RemoveAll(s.tmpDir))
+}
+
+func (s *SysfsValueSuite) SetupTest() {
+	s.check = &sysfsValue{
+		Base:     NewBase("sysfs-value", 0),
+		Operator: "eq",
+	}
+}
+
+func (s *SysfsValueSuite) path(fn string) string {
+	return filepath.Join(s.tmpDir, fn)
+}
+
+func (s *SysfsValueSuite) TestValidation() {
+	s.Error(s.check.validate())
+
+	s.check.Path = s.path("vm/[swappiness")
+	s.Error(s.check.validate())
+
+	s.check.Path = s.path("vm/swappiness")
+	s.NoError(s.check.validate())
+
+	s.check.Operator = ""
+	s.NoError(s.check.validate())
+	s.Equal("eq", s.check.Operator)
+
+	s.check.Operator = "lte"
+	s.Error(s.check.validate())
+
+	s.check.Expected = "ten"
+	s.Error(s.check.validate())
+
+	s.check.Expected = "10"
+	s.NoError(s.check.validate())
+
+	s.check.Operator = "matches"
+	s.Error(s.check.validate())
+}
+
+func (s *SysfsValueSuite) TestCompareSysfsValue() {
+	for _, c := range []struct {
+		operator string
+		observed string
+		expected string
+		result   bool
+	}{
+		{"eq", "10", "10", true},
+		{"eq", "10", "1", false},
+		{"ne", "10", "1", true},
+		{"gt", "10", "1", true},
+		{"gte", "10", "10", true},
+		{"lt", "10", "10", false},
+		{"lte", "9.5", "10", true},
+		{"contains", "always madvise [never]", "madvise", true},
+		{"contains", "always madvise [never]", "never", false},
+		{"selected", "always madvise [never]", "never", true},
+		{"selected", "always madvise [never]", "always", false},
+	} {
+		result, err := compareSysfsValue(c.operator, c.observed, c.expected)
+		s.NoError(err)
+		s.Equal(c.result, result, "%+v", c)
+	}
+
+	_, err := compareSysfsValue("gt", "always", "1")
+	s.Error(err)
+
+	_, err = compareSysfsValue("selected", "always never", "never")
+	s.Error(err)
+}
+
+func (s *SysfsValueSuite) TestValueComparedAfterNormalizingWhitespace() {
+	s.check.Path = s.path("net/tcp_rmem")
+	s.check.Expected = "4096 87380  6291456"
+	s.check.Run()
+
+	s.True(s.check.Output().Passed)
+	s.NoError(s.check.Error())
+	s.Equal(s.path("net/tcp_rmem")+": '4096 87380 6291456' (expected eq '4096 87380 6291456')",
+		s.check.Output().Message)
+}
+
+func (s *SysfsValueSuite) TestNumericOperators() {
+	s.check.Path = s.path("vm/swappiness")
+	s.check.Operator = "lte"
+	s.check.Expected = "10"
+	s.check.Run()
+	s.True(s.check.Output().Passed)
+
+	s.SetupTest()
+	s.check.Path = s.path("vm/swappiness")
+	s.check.Operator = "lt"
+	s.check.Expected = "10"
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "1 of 1 file(s) do not have the expected value")
+	s.Equal(ReasonValueMismatch, s.check.Output().ReasonCode)
+}
+
+func (s *SysfsValueSuite) TestGlobRequiresEveryFileToMatch() {
+	s.check.Path = s.path("block/*/queue/scheduler")
+	s.check.Operator = "selected"
+	s.check.Expected = "none"
+	s.check.Run()
+
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "1 of 2 file(s) do not have the expected value: "+s.path("block/sdb/queue/scheduler"))
+	s.Contains(s.check.Output().Message, "sda/queue/scheduler: 'mq-deadline [none] kyber'")
+
+	s.SetupTest()
+	s.check.Path = s.path("block/*/queue/scheduler")
+	s.check.Operator = "contains"
+	s.check.Expected = "kyber"
+	s.check.Run()
+	s.True(s.check.Output().Passed)
+}
+
+func (s *SysfsValueSuite) TestMissingFilesFail() {
+	s.check.Path = s.path("block/*/queue/DOES-NOT-EXIST")
+	s.check.Expected = "none"
+	s.check.Run()
+
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "no files match")
+	s.Equal(ReasonFileMissing, s.check.Output().ReasonCode)
+}
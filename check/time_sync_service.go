@@ -0,0 +1,311 @@
+package check
+
+import (
+	"fmt"
+	"math"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/registry"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	name := "time-sync-service"
+	registry.AddJobType(name, func() amboy.Job {
+		return &timeSyncService{
+			Base:    NewBase(name, 0),
+			Service: "auto",
+			run: func(command string, args ...string) ([]byte, error) {
+				return exec.Command(command, args...).Output()
+			},
+		}
+	})
+}
+
+// timeSyncServices are the supported time synchronization services,
+// in the order that the "auto" service tries them.
+var timeSyncServices = []string{"chrony", "ntpd", "systemd-timesyncd"}
+
+// timeSyncService checks that a time synchronization service is
+// running and reports that the clock is synchronized, which checks
+// the mechanism that keeps the clock correct, rather than the
+// current clock. The service is "chrony", "ntpd",
+// "systemd-timesyncd", or "auto" (the default), which uses the first
+// of those services that answers a query. The check queries the
+// service with chronyc, ntpq, or timedatectl, which fail if the
+// service is not running. When max_offset (e.g. "100ms") is set, the
+// offset of the clock from the service's time must be at most that
+// large.
+type timeSyncService struct {
+	Service   string `bson:"service" json:"service" yaml:"service"`
+	MaxOffset string `bson:"max_offset" json:"max_offset" yaml:"max_offset"`
+	*Base     `bson:"metadata" json:"metadata" yaml:"metadata"`
+
+	maxOffset time.Duration
+	run       func(command string, args ...string) ([]byte, error)
+}
+
+// timeSyncStatus is the synchronization state that a service reports.
+type timeSyncStatus struct {
+	service      string
+	synchronized bool
+	stratum      int
+	offset       time.Duration
+}
+
+func (s timeSyncStatus) String() string {
+	state := "synchronized"
+	if !s.synchronized {
+		state = "not synchronized"
+	}
+
+	return fmt.Sprintf("%s: %s, stratum %d, offset %s", s.service, state, s.stratum, s.offset)
+}
+
+func (c *timeSyncService) validate() error {
+	if c.Service == "" {
+		c.Service = "auto"
+	}
+
+	valid := c.Service == "auto"
+	for _, service := range timeSyncServices {
+		valid = valid || c.Service == service
+	}
+
+	if !valid {
+		return errors.Errorf("service '%s' for '%s' check must be one of auto, %s",
+			c.Service, c.ID(), strings.Join(timeSyncServices, ", "))
+	}
+
+	if c.MaxOffset != "" {
+		offset, err := time.ParseDuration(c.MaxOffset)
+		if err != nil {
+			return errors.Wrapf(err, "problem parsing max offset for '%s' check", c.ID())
+		}
+
+		if offset <= 0 {
+			return errors.Errorf("max offset for '%s' check must be positive", c.ID())
+		}
+		c.maxOffset = offset
+	}
+
+	return nil
+}
+
+func (c *timeSyncService) Run() {
+	c.startTask()
+	defer c.MarkComplete()
+
+	if err := c.validate(); err != nil {
+		c.setState(false)
+		c.setReason(ReasonInvalidConfig)
+		c.AddError(err)
+		return
+	}
+
+	status, err := c.status()
+	if err != nil {
+		c.setState(false)
+		c.AddError(err)
+		return
+	}
+
+	c.setMessage(status.String())
+
+	var problems []string
+	if !status.synchronized {
+		problems = append(problems, "the clock is not synchronized")
+	}
+
+	offset := time.Duration(math.Abs(float64(status.offset)))
+	if c.maxOffset > 0 && offset > c.maxOffset {
+		problems = append(problems, fmt.Sprintf("offset %s is larger than %s", status.offset, c.maxOffset))
+	}
+
+	if len(problems) > 0 {
+		c.setState(false)
+		c.setReason(ReasonValueMismatch)
+		c.AddError(errors.Errorf("%s reports that %s", status.service, strings.Join(problems, ", ")))
+		return
+	}
+
+	c.setState(true)
+}
+
+// status queries the configured service, or, for "auto", the first
+// service that answers.
+func (c *timeSyncService) status() (timeSyncStatus, error) {
+	services := []string{c.Service}
+	if c.Service == "auto" {
+		services = timeSyncServices
+	}
+
+	var errs []string
+	for _, service := range services {
+		status, err := c.query(service)
+		if err == nil {
+			return status, nil
+		}
+
+		errs = append(errs, err.Error())
+	}
+
+	return timeSyncStatus{}, errors.Errorf("no time synchronization service answered: %s", strings.Join(errs, "; "))
+}
+
+func (c *timeSyncService) query(service string) (timeSyncStatus, error) {
+	var status timeSyncStatus
+	var out []byte
+	var err error
+
+	switch service {
+	case "chrony":
+		out, err = c.run("chronyc", "-c", "tracking")
+		if err == nil {
+			status, err = parseChronyTracking(string(out))
+		}
+	case "ntpd":
+		out, err = c.run("ntpq", "-c", "rv")
+		if err == nil {
+			status, err = parseNTPQVariables(string(out))
+		}
+	case "systemd-timesyncd":
+		status, err = c.queryTimesyncd()
+	}
+
+	if err != nil {
+		return status, errors.Wrapf(err, "problem querying %s", service)
+	}
+
+	status.service = service
+	return status, nil
+}
+
+func (c *timeSyncService) queryTimesyncd() (timeSyncStatus, error) {
+	synced, err := c.run("timedatectl", "show", "--property=NTPSynchronized", "--value")
+	if err != nil {
+		return timeSyncStatus{}, err
+	}
+
+	out, err := c.run("timedatectl", "timesync-status")
+	if err != nil {
+		return timeSyncStatus{}, err
+	}
+
+	status, err := parseTimesyncStatus(string(out))
+	status.synchronized = strings.TrimSpace(string(synced)) == "yes"
+
+	return status, err
+}
+
+// parseChronyTracking parses the output of "chronyc -c tracking",
+// which is one line of comma separated values: the stratum is the
+// third field, the offset of the system clock, in seconds, is the
+// fifth, and the leap status is the last.
+func parseChronyTracking(out string) (timeSyncStatus, error) {
+	fields := strings.Split(strings.TrimSpace(out), ",")
+	if len(fields) < 14 {
+		return timeSyncStatus{}, errors.Errorf("unexpected chronyc output '%s'", strings.TrimSpace(out))
+	}
+
+	stratum, err := strconv.Atoi(fields[2])
+	if err != nil {
+		return timeSyncStatus{}, errors.Errorf("invalid stratum '%s'", fields[2])
+	}
+
+	seconds, err := strconv.ParseFloat(fields[4], 64)
+	if err != nil {
+		return timeSyncStatus{}, errors.Errorf("invalid offset '%s'", fields[4])
+	}
+
+	leap := fields[len(fields)-1]
+
+	return timeSyncStatus{
+		synchronized: leap != "Not synchronised" && stratum > 0 && stratum < 16,
+		stratum:      stratum,
+		offset:       time.Duration(seconds * float64(time.Second)),
+	}, nil
+}
+
+// parseNTPQVariables parses the output of "ntpq -c rv", which is a
+// list of comma separated name=value pairs, after a status word that
+// includes the "leap_*" and "sync_*" states. The offset is in
+// milliseconds.
+func parseNTPQVariables(out string) (timeSyncStatus, error) {
+	status := timeSyncStatus{}
+	var hasOffset, hasSync bool
+
+	for _, field := range strings.Split(strings.Replace(out, "\n", ",", -1), ",") {
+		for _, word := range strings.Fields(field) {
+			if strings.HasPrefix(word, "sync_") {
+				hasSync = true
+				status.synchronized = word != "sync_unspec"
+			}
+		}
+
+		parts := strings.SplitN(strings.TrimSpace(field), "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		switch parts[0] {
+		case "stratum":
+			status.stratum, _ = strconv.Atoi(parts[1])
+		case "offset":
+			ms, err := strconv.ParseFloat(parts[1], 64)
+			if err != nil {
+				return status, errors.Errorf("invalid offset '%s'", parts[1])
+			}
+			status.offset = time.Duration(ms * float64(time.Millisecond))
+			hasOffset = true
+		}
+	}
+
+	if !hasSync || !hasOffset {
+		return status, errors.Errorf("unexpected ntpq output '%s'", strings.TrimSpace(out))
+	}
+
+	if strings.Contains(out, "leap_alarm") || status.stratum == 0 || status.stratum >= 16 {
+		status.synchronized = false
+	}
+
+	return status, nil
+}
+
+// parseTimesyncStatus parses the stratum and offset from the output
+// of "timedatectl timesync-status", which has lines like
+// "Offset: +1.234ms".
+func parseTimesyncStatus(out string) (timeSyncStatus, error) {
+	status := timeSyncStatus{}
+	var hasOffset bool
+
+	for _, line := range strings.Split(out, "\n") {
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		value := strings.TrimSpace(parts[1])
+		switch strings.TrimSpace(parts[0]) {
+		case "Stratum":
+			status.stratum, _ = strconv.Atoi(value)
+		case "Offset":
+			offset, err := time.ParseDuration(value)
+			if err != nil {
+				return status, errors.Errorf("invalid offset '%s'", value)
+			}
+			status.offset = offset
+			hasOffset = true
+		}
+	}
+
+	if !hasOffset {
+		return status, errors.New("timedatectl did not report an offset")
+	}
+
+	return status, nil
+}
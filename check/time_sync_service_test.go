@@ -0,0 +1,181 @@
+package check
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mongodb/greenbay"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/net/context"
+)
+
+type TimeSyncServiceSuite struct {
+	outputs map[string]string
+	check   *timeSyncService
+	require *require.Assertions
+	suite.Suite
+}
+
+func TestTimeSyncServiceSuite(t *testing.T) {
+	suite.Run(t, new(TimeSyncServiceSuite))
+}
+
+func (s *TimeSyncServiceSuite) SetupSuite() {
+	s.require = s.Require()
+}
+
+func (s *TimeSyncServiceSuite) SetupTest() {
+	s.outputs = map[string]string{
+		"chronyc -c tracking": "A9FEA97B,169.254.169.123,4,1465574400.123456789,0.000012345,-0.000002,0.000005,-12.5,0.001,0.02,0.0005,0.0003,64.4,Normal\n",
+		"ntpq -c rv": `associd=0 status=0615 leap_none, sync_ntp, 1 event, clock_sync,
+version="ntpd 4.2.8p4@1.3265-o", processor="x86_64", system="Linux/4.4.0",
+leap=00, stratum=3, precision=-23, rootdelay=31.514, rootdisp=42.139,
+refid=10.0.0.5, reftime=dae1a2b1.1c0ad4ab, offset=-0.480, frequency=-12.231
+`,
+		"timedatectl show --property=NTPSynchronized --value": "yes\n",
+		"timedatectl timesync-status": `       Server: 10.0.0.5 (ntp.example.net)
+Poll interval: 34min 8s (min: 32s; max 34min 8s)
+         Leap: normal
+      Version: 4
+      Stratum: 2
+    Reference: C342F10A
+    Precision: 1us (-24)
+Root distance: 3.437ms (max: 5s)
+       Offset: +1.234ms
+        Delay: 10.538ms
+`,
+	}
+
+	s.check = &timeSyncService{
+		Base:    NewBase("time-sync-service", 0),
+		Service: "auto",
+		run: func(command string, args ...string) ([]byte, error) {
+			out, ok := s.outputs[command+" "+strings.Join(args, " ")]
+			if !ok {
+				return nil, errors.New("exit status 1")
+			}
+			return []byte(out), nil
+		},
+	}
+}
+
+func (s *TimeSyncServiceSuite) TestValidation() {
+	s.NoError(s.check.validate())
+
+	s.check.Service = "openntpd"
+	s.Error(s.check.validate())
+
+	s.check.Service = ""
+	s.NoError(s.check.validate())
+	s.Equal("auto", s.check.Service)
+
+	s.check.MaxOffset = "fast"
+	s.Error(s.check.validate())
+
+	s.check.MaxOffset = "-1ms"
+	s.Error(s.check.validate())
+
+	s.check.MaxOffset = "100ms"
+	s.NoError(s.check.validate())
+	s.Equal(100*time.Millisecond, s.check.maxOffset)
+}
+
+func (s *TimeSyncServiceSuite) TestParsers() {
+	status, err := parseChronyTracking(s.outputs["chronyc -c tracking"])
+	s.require.NoError(err)
+	s.True(status.synchronized)
+	s.Equal(4, status.stratum)
+	s.Equal(12345*time.Nanosecond, status.offset)
+
+	status, err = parseNTPQVariables(s.outputs["ntpq -c rv"])
+	s.require.NoError(err)
+	s.True(status.synchronized)
+	s.Equal(3, status.stratum)
+	s.Equal(-480*time.Microsecond, status.offset)
+
+	status, err = parseNTPQVariables("associd=0 status=c016 leap_alarm, sync_unspec, 1 event, restart,\nstratum=16, offset=0.000\n")
+	s.require.NoError(err)
+	s.False(status.synchronized)
+
+	status, err = parseTimesyncStatus(s.outputs["timedatectl timesync-status"])
+	s.require.NoError(err)
+	s.Equal(2, status.stratum)
+	s.Equal(1234*time.Microsecond, status.offset)
+
+	_, err = parseChronyTracking("506 Cannot talk to daemon")
+	s.Error(err)
+}
+
+func (s *TimeSyncServiceSuite) TestEachService() {
+	for service, msg := range map[string]string{
+		"chrony":            "chrony: synchronized, stratum 4, offset 12.345µs",
+		"ntpd":              "ntpd: synchronized, stratum 3, offset -480µs",
+		"systemd-timesyncd": "systemd-timesyncd: synchronized, stratum 2, offset 1.234ms",
+	} {
+		s.SetupTest()
+		s.check.Service = service
+		s.check.MaxOffset = "10ms"
+		s.check.Run()
+
+		s.True(s.check.Output().Passed, service)
+		s.NoError(s.check.Error())
+		s.Equal(msg, s.check.Output().Message)
+	}
+}
+
+func (s *TimeSyncServiceSuite) TestAutoUsesFirstAnsweringService() {
+	delete(s.outputs, "chronyc -c tracking")
+	s.check.Run()
+
+	s.True(s.check.Output().Passed)
+	s.True(strings.HasPrefix(s.check.Output().Message, "ntpd:"))
+}
+
+func (s *TimeSyncServiceSuite) TestNotRunning() {
+	s.outputs = map[string]string{}
+	s.check.Run()
+
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "no time synchronization service answered")
+	s.Contains(s.check.Error().Error(), "problem querying systemd-timesyncd")
+}
+
+func (s *TimeSyncServiceSuite) TestUnsynchronized() {
+	s.check.Service = "systemd-timesyncd"
+	s.outputs["timedatectl show --property=NTPSynchronized --value"] = "no\n"
+	s.check.Run()
+
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "systemd-timesyncd reports that the clock is not synchronized")
+	s.Equal(ReasonValueMismatch, s.check.Output().ReasonCode)
+}
+
+func (s *TimeSyncServiceSuite) TestOffsetTooLarge() {
+	s.check.Service = "ntpd"
+	s.check.MaxOffset = "100us"
+	s.check.Run()
+
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "offset -480µs is larger than 100µs")
+}
+
+func (s *TimeSyncServiceSuite) TestHungServiceQueryTimesOut() {
+	output := assertCheckTimesOut(s.T(), func(ctx context.Context) greenbay.Checker {
+		s.check.Service = "chrony"
+		// commands that run under a context are killed at the
+		// deadline.
+		s.check.run = func(command string, args ...string) ([]byte, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+		return s.check
+	}, 100*time.Millisecond)
+
+	s.Contains(output.Error, "deadline exceeded")
+}
@@ -0,0 +1,102 @@
+package check
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mongodb/greenbay"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+)
+
+// timeoutGracePeriod is how long, after the deadline, a check has to
+// return before assertCheckTimesOut considers it hung.
+const timeoutGracePeriod = 2 * time.Second
+
+// assertCheckTimesOut is a helper for testing the timeout behavior of
+// checks. The constructor should return a check that observes the
+// context it receives. The helper runs the check under a context
+// with the specified deadline, and asserts that the check returns
+// promptly after the deadline, and reports a failure with an error.
+func assertCheckTimesOut(t *testing.T, constructor func(context.Context) greenbay.Checker, timeout time.Duration) greenbay.CheckOutput {
+	assert := assert.New(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	c := constructor(ctx)
+
+	done := make(chan struct{})
+	start := time.Now()
+	go func() {
+		c.Run()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(timeout + timeoutGracePeriod):
+		t.Fatalf("check '%s' did not return within %s of its %s deadline",
+			c.ID(), timeoutGracePeriod, timeout)
+	}
+
+	output := c.Output()
+	assert.True(time.Since(start) >= timeout, "check returned before its deadline")
+	assert.True(output.Completed)
+	assert.False(output.Passed)
+	assert.Error(c.Error())
+	assert.NotEqual("", output.Error)
+
+	return output
+}
+
+// mockSlowCheck is a check that takes a long time to complete unless
+// its context is canceled, for testing timeout handling.
+type mockSlowCheck struct {
+	ctx      context.Context
+	duration time.Duration
+	*Base
+}
+
+func newMockSlowCheck(ctx context.Context, dur time.Duration) *mockSlowCheck {
+	c := &mockSlowCheck{
+		ctx:      ctx,
+		duration: dur,
+		Base:     NewBase("mock-slow-check", 0),
+	}
+	c.SetID(fmt.Sprintf("mock-slow-check-%s", dur))
+
+	return c
+}
+
+func (c *mockSlowCheck) Run() {
+	c.startTask()
+	defer c.MarkComplete()
+
+	select {
+	case <-c.ctx.Done():
+		c.setState(false)
+		c.AddError(errors.Wrapf(c.ctx.Err(), "check '%s' timed out", c.ID()))
+	case <-time.After(c.duration):
+		c.setState(true)
+	}
+}
+
+func TestTimeoutHelperWithSlowCheck(t *testing.T) {
+	output := assertCheckTimesOut(t, func(ctx context.Context) greenbay.Checker {
+		return newMockSlowCheck(ctx, time.Hour)
+	}, 10*time.Millisecond)
+
+	assert.Contains(t, output.Error, "timed out")
+}
+
+func TestSlowCheckPassesWithoutDeadline(t *testing.T) {
+	assert := assert.New(t)
+
+	c := newMockSlowCheck(context.Background(), time.Millisecond)
+	c.Run()
+	assert.True(c.Output().Passed)
+	assert.NoError(c.Error())
+}
@@ -0,0 +1,136 @@
+package check
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/registry"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	name := "tls-chain-valid"
+	registry.AddJobType(name, func() amboy.Job {
+		return &tlsChainValid{
+			Base:           NewBase(name, 0),
+			VerifyHostname: true,
+			dialTimeout:    time.Minute,
+		}
+	})
+}
+
+// tlsChainValid connects to a TLS service and verifies the
+// certificate chain it presents against the certificates in
+// ca_bundle, or against the system roots if ca_bundle is not
+// set. Unless verify_hostname is false, the host portion of the
+// address must also match the leaf certificate.
+type tlsChainValid struct {
+	Address        string `bson:"address" json:"address" yaml:"address"`
+	CABundle       string `bson:"ca_bundle" json:"ca_bundle" yaml:"ca_bundle"`
+	VerifyHostname bool   `bson:"verify_hostname" json:"verify_hostname" yaml:"verify_hostname"`
+	*Base          `bson:"metadata" json:"metadata" yaml:"metadata"`
+	fileReadLimit
+
+	dialTimeout time.Duration
+}
+
+func (c *tlsChainValid) validate() error {
+	if c.Address == "" {
+		return errors.Errorf("no address specified for '%s' (%s) check", c.ID(), c.Name())
+	}
+
+	if _, _, err := net.SplitHostPort(c.Address); err != nil {
+		return errors.Wrapf(err, "address '%s' for '%s' check is not valid", c.Address, c.ID())
+	}
+
+	return nil
+}
+
+func (c *tlsChainValid) roots() (*x509.CertPool, error) {
+	if c.CABundle == "" {
+		return nil, nil
+	}
+
+	data, err := c.readFile(c.CABundle)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem reading ca bundle '%s'", c.CABundle)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, errors.Errorf("ca bundle '%s' does not contain any certificates", c.CABundle)
+	}
+
+	return pool, nil
+}
+
+func (c *tlsChainValid) Run() {
+	c.startTask()
+	defer c.MarkComplete()
+
+	if err := c.validate(); err != nil {
+		c.setState(false)
+		c.setReason(ReasonInvalidConfig)
+		c.AddError(err)
+		return
+	}
+
+	roots, err := c.roots()
+	if err != nil {
+		c.setState(false)
+		c.AddError(err)
+		return
+	}
+
+	host, _, _ := net.SplitHostPort(c.Address)
+
+	// verification happens below, rather than during the
+	// handshake, so that the check can report the chain that the
+	// server presented along with the specific verification error.
+	dialer := &net.Dialer{Timeout: c.dialTimeout}
+	conn, err := tls.DialWithDialer(dialer, "tcp", c.Address, &tls.Config{
+		ServerName:         host,
+		InsecureSkipVerify: true,
+	})
+	if err != nil {
+		c.setState(false)
+		c.AddError(errors.Wrapf(err, "problem connecting to %s", c.Address))
+		return
+	}
+	defer conn.Close()
+
+	certs := conn.ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		c.setState(false)
+		c.AddError(errors.Errorf("%s did not present any certificates", c.Address))
+		return
+	}
+
+	opts := x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: x509.NewCertPool(),
+	}
+	for _, cert := range certs[1:] {
+		opts.Intermediates.AddCert(cert)
+	}
+	if c.VerifyHostname {
+		opts.DNSName = host
+	}
+
+	chains, err := certs[0].Verify(opts)
+	if err != nil {
+		c.setState(false)
+		c.setMessage(fmt.Sprintf("%s presented %d certificate(s) for '%s', issued by '%s'",
+			c.Address, len(certs), certs[0].Subject.CommonName, certs[0].Issuer.CommonName))
+		c.AddError(errors.Wrapf(err, "certificate chain for %s is not valid", c.Address))
+		return
+	}
+
+	c.setState(true)
+	c.setMessage(fmt.Sprintf("%s presented a valid chain of %d certificate(s)",
+		c.Address, len(chains[0])))
+}
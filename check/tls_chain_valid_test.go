@@ -0,0 +1,149 @@
+package check
+
+import (
+	"encoding/pem"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/greenbay"
+	"github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/net/context"
+)
+
+type TLSChainValidSuite struct {
+	tempDir string
+	bundle  string
+	address string
+	server  *httptest.Server
+	check   *tlsChainValid
+	require *require.Assertions
+	suite.Suite
+}
+
+func TestTLSChainValidSuite(t *testing.T) {
+	suite.Run(t, new(TLSChainValidSuite))
+}
+
+func (s *TLSChainValidSuite) SetupSuite() {
+	s.require = s.Require()
+
+	tempDir, err := ioutil.TempDir("", uuid.NewV4().String())
+	s.require.NoError(err)
+	s.tempDir = tempDir
+
+	s.server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	u, err := url.Parse(s.server.URL)
+	s.require.NoError(err)
+	s.address = u.Host
+
+	s.bundle = filepath.Join(s.tempDir, "ca.pem")
+	data := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: s.server.Certificate().Raw})
+	s.require.NoError(ioutil.WriteFile(s.bundle, data, 0644))
+}
+
+func (s *TLSChainValidSuite) TearDownSuite() {
+	s.server.Close()
+	s.NoError(os.RemoveAll(s.tempDir))
+}
+
+func (s *TLSChainValidSuite) SetupTest() {
+	factory, err := GetChecker("tls-chain-valid")
+	s.require.NoError(err)
+	s.check = factory.(amboy.Job).(*tlsChainValid)
+}
+
+func (s *TLSChainValidSuite) TestDefaults() {
+	s.True(s.check.VerifyHostname)
+	s.Equal("", s.check.CABundle)
+}
+
+func (s *TLSChainValidSuite) TestValidation() {
+	s.Error(s.check.validate())
+
+	s.check.Address = "localhost"
+	s.Error(s.check.validate())
+
+	s.check.Address = s.address
+	s.NoError(s.check.validate())
+}
+
+func (s *TLSChainValidSuite) TestChainWithBundlePasses() {
+	s.check.Address = s.address
+	s.check.CABundle = s.bundle
+	s.check.Run()
+
+	s.True(s.check.Output().Passed)
+	s.NoError(s.check.Error())
+}
+
+func (s *TLSChainValidSuite) TestUnknownAuthorityFails() {
+	s.check.Address = s.address
+	s.check.Run()
+
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "unknown authority")
+}
+
+func (s *TLSChainValidSuite) TestHostnameMismatchFails() {
+	s.check.Address = strings.Replace(s.address, "127.0.0.1", "localhost", 1)
+	s.check.CABundle = s.bundle
+	s.check.Run()
+
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "localhost")
+
+	s.SetupTest()
+	s.check.Address = strings.Replace(s.address, "127.0.0.1", "localhost", 1)
+	s.check.CABundle = s.bundle
+	s.check.VerifyHostname = false
+	s.check.Run()
+	s.True(s.check.Output().Passed)
+}
+
+func (s *TLSChainValidSuite) TestInvalidBundleFails() {
+	fn := filepath.Join(s.tempDir, "empty.pem")
+	s.require.NoError(ioutil.WriteFile(fn, []byte("not a cert"), 0644))
+
+	s.check.Address = s.address
+	s.check.CABundle = fn
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.Error(s.check.Error())
+
+	s.SetupTest()
+	s.check.Address = s.address
+	s.check.CABundle = filepath.Join(s.tempDir, "missing.pem")
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.Error(s.check.Error())
+}
+
+func (s *TLSChainValidSuite) TestUnresponsiveServerTimesOut() {
+	// the listener never accepts connections, so the handshake
+	// never completes.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	s.require.NoError(err)
+	defer listener.Close()
+
+	output := assertCheckTimesOut(s.T(), func(ctx context.Context) greenbay.Checker {
+		deadline, _ := ctx.Deadline()
+		s.check.dialTimeout = deadline.Sub(time.Now())
+		s.check.Address = listener.Addr().String()
+		return s.check
+	}, 100*time.Millisecond)
+
+	s.Contains(output.Error, "problem connecting to")
+}
@@ -0,0 +1,259 @@
+package check
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/registry"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	name := "tls-policy"
+	registry.AddJobType(name, func() amboy.Job {
+		return &tlsPolicy{
+			Base:        NewBase(name, 0),
+			dialTimeout: time.Minute,
+		}
+	})
+}
+
+// tlsVersion is a protocol version that the tls-policy check can
+// probe.
+type tlsVersion struct {
+	name    string
+	version uint16
+}
+
+// tlsVersions are the protocol versions that the tls-policy check
+// can probe, in order.
+var tlsVersions = []tlsVersion{
+	{name: "TLS1.0", version: tls.VersionTLS10},
+	{name: "TLS1.1", version: tls.VersionTLS11},
+	{name: "TLS1.2", version: tls.VersionTLS12},
+	{name: "TLS1.3", version: tls.VersionTLS13},
+}
+
+// tlsPolicy connects to a TLS service, and checks that the service
+// only accepts approved protocol versions and cipher suites. The
+// check attempts a handshake with each version older than
+// min_version (e.g. "TLS1.2"), and with each of the
+// forbidden_versions, all of which the server must reject. Each of
+// the forbidden_ciphers must be rejected, and each of the
+// required_ciphers must be accepted, when offered on its own.
+//
+// Ciphers use their IANA names (e.g. "TLS_RSA_WITH_AES_128_CBC_SHA"),
+// and must be TLS 1.0-1.2 cipher suites, because TLS 1.3 clients
+// cannot offer a single cipher suite. The check does not verify the
+// server's certificate: use tls-chain-valid for that.
+type tlsPolicy struct {
+	Address           string   `bson:"address" json:"address" yaml:"address"`
+	MinVersion        string   `bson:"min_version" json:"min_version" yaml:"min_version"`
+	ForbiddenVersions []string `bson:"forbidden_versions" json:"forbidden_versions" yaml:"forbidden_versions"`
+	RequiredCiphers   []string `bson:"required_ciphers" json:"required_ciphers" yaml:"required_ciphers"`
+	ForbiddenCiphers  []string `bson:"forbidden_ciphers" json:"forbidden_ciphers" yaml:"forbidden_ciphers"`
+	*Base             `bson:"metadata" json:"metadata" yaml:"metadata"`
+
+	dialTimeout time.Duration
+}
+
+func (c *tlsPolicy) validate() error {
+	if c.Address == "" {
+		return errors.Errorf("no address specified for '%s' (%s) check", c.ID(), c.Name())
+	}
+
+	if _, _, err := net.SplitHostPort(c.Address); err != nil {
+		return errors.Wrapf(err, "address '%s' for '%s' check is not valid", c.Address, c.ID())
+	}
+
+	if c.MinVersion == "" && len(c.ForbiddenVersions) == 0 &&
+		len(c.RequiredCiphers) == 0 && len(c.ForbiddenCiphers) == 0 {
+		return errors.Errorf("no versions or ciphers specified for '%s' (%s) check", c.ID(), c.Name())
+	}
+
+	for _, v := range append([]string{c.MinVersion}, c.ForbiddenVersions...) {
+		if v == "" {
+			continue
+		}
+
+		if _, ok := tlsVersionByName(v); !ok {
+			return errors.Errorf("'%s' for '%s' check is not a TLS version (TLS1.0 to TLS1.3)", v, c.ID())
+		}
+	}
+
+	for _, name := range append(append([]string{}, c.RequiredCiphers...), c.ForbiddenCiphers...) {
+		if _, ok := tlsCipherByName(name); !ok {
+			return errors.Errorf("'%s' for '%s' check is not a TLS 1.0-1.2 cipher suite", name, c.ID())
+		}
+	}
+
+	return nil
+}
+
+func (c *tlsPolicy) Run() {
+	c.startTask()
+	defer c.MarkComplete()
+
+	if err := c.validate(); err != nil {
+		c.setState(false)
+		c.setReason(ReasonInvalidConfig)
+		c.AddError(err)
+		return
+	}
+
+	var messages []string
+	var problems []string
+
+	for _, v := range c.disallowedVersions() {
+		accepted, err := c.handshake(&tls.Config{MinVersion: v.version, MaxVersion: v.version})
+		if err != nil {
+			c.setState(false)
+			c.AddError(err)
+			return
+		}
+
+		messages = append(messages, fmt.Sprintf("%s: %s", v.name, acceptedState(accepted)))
+		if accepted {
+			problems = append(problems, fmt.Sprintf("accepted forbidden protocol %s", v.name))
+		}
+	}
+
+	for _, name := range c.ForbiddenCiphers {
+		accepted, err := c.handshakeWithCipher(name)
+		if err != nil {
+			c.setState(false)
+			c.AddError(err)
+			return
+		}
+
+		messages = append(messages, fmt.Sprintf("%s: %s", name, acceptedState(accepted)))
+		if accepted {
+			problems = append(problems, fmt.Sprintf("negotiated forbidden cipher %s", name))
+		}
+	}
+
+	for _, name := range c.RequiredCiphers {
+		accepted, err := c.handshakeWithCipher(name)
+		if err != nil {
+			c.setState(false)
+			c.AddError(err)
+			return
+		}
+
+		messages = append(messages, fmt.Sprintf("%s: %s", name, acceptedState(accepted)))
+		if !accepted {
+			problems = append(problems, fmt.Sprintf("does not support required cipher %s", name))
+		}
+	}
+
+	c.setMessage(messages)
+
+	if len(problems) > 0 {
+		c.setState(false)
+		c.AddError(errors.Errorf("%s %s", c.Address, strings.Join(problems, ", ")))
+		return
+	}
+
+	c.setState(true)
+}
+
+// disallowedVersions returns the versions older than the minimum
+// version, and the forbidden versions, in order.
+func (c *tlsPolicy) disallowedVersions() []tlsVersion {
+	forbidden := map[uint16]bool{}
+	for _, v := range c.ForbiddenVersions {
+		version, _ := tlsVersionByName(v)
+		forbidden[version] = true
+	}
+
+	var min uint16
+	if c.MinVersion != "" {
+		min, _ = tlsVersionByName(c.MinVersion)
+	}
+
+	var out []tlsVersion
+	for _, v := range tlsVersions {
+		if v.version < min || forbidden[v.version] {
+			out = append(out, v)
+		}
+	}
+
+	return out
+}
+
+func (c *tlsPolicy) handshakeWithCipher(name string) (bool, error) {
+	id, _ := tlsCipherByName(name)
+
+	return c.handshake(&tls.Config{
+		MinVersion:   tls.VersionTLS10,
+		MaxVersion:   tls.VersionTLS12,
+		CipherSuites: []uint16{id},
+	})
+}
+
+// handshake connects to the server, and reports whether a handshake
+// with the config succeeds. Only connection failures are errors: a
+// failed handshake means that the server rejected the config.
+func (c *tlsPolicy) handshake(conf *tls.Config) (bool, error) {
+	host, _, _ := net.SplitHostPort(c.Address)
+	conf.ServerName = host
+	conf.InsecureSkipVerify = true
+
+	conn, err := net.DialTimeout("tcp", c.Address, c.dialTimeout)
+	if err != nil {
+		return false, errors.Wrapf(err, "problem connecting to %s", c.Address)
+	}
+	defer conn.Close()
+
+	if err = conn.SetDeadline(time.Now().Add(c.dialTimeout)); err != nil {
+		return false, errors.Wrapf(err, "problem setting deadline for %s", c.Address)
+	}
+
+	return tls.Client(conn, conf).Handshake() == nil, nil
+}
+
+func acceptedState(accepted bool) string {
+	if accepted {
+		return "accepted"
+	}
+
+	return "rejected"
+}
+
+// tlsVersionByName returns the TLS version with a name such as
+// "TLS1.2", "TLSv1.2", or "1.2".
+func tlsVersionByName(name string) (uint16, bool) {
+	name = strings.ToUpper(strings.Replace(name, " ", "", -1))
+	name = strings.TrimPrefix(strings.TrimPrefix(name, "TLS"), "V")
+
+	for _, v := range tlsVersions {
+		if strings.TrimPrefix(v.name, "TLS") == name {
+			return v.version, true
+		}
+	}
+
+	return 0, false
+}
+
+// tlsCipherByName returns the id of a TLS 1.0-1.2 cipher suite,
+// including the insecure suites that crypto/tls only uses when they
+// are configured explicitly.
+func tlsCipherByName(name string) (uint16, bool) {
+	for _, suite := range append(tls.CipherSuites(), tls.InsecureCipherSuites()...) {
+		if suite.Name != strings.ToUpper(name) {
+			continue
+		}
+
+		for _, v := range suite.SupportedVersions {
+			if v != tls.VersionTLS13 {
+				return suite.ID, true
+			}
+		}
+	}
+
+	return 0, false
+}
@@ -0,0 +1,165 @@
+package check
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/mongodb/amboy"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type TLSPolicySuite struct {
+	strict  *httptest.Server
+	legacy  *httptest.Server
+	check   *tlsPolicy
+	require *require.Assertions
+	suite.Suite
+}
+
+func TestTLSPolicySuite(t *testing.T) {
+	suite.Run(t, new(TLSPolicySuite))
+}
+
+func (s *TLSPolicySuite) startServer(conf *tls.Config) *httptest.Server {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.TLS = conf
+	server.StartTLS()
+	return server
+}
+
+func (s *TLSPolicySuite) address(server *httptest.Server) string {
+	u, err := url.Parse(server.URL)
+	s.require.NoError(err)
+	return u.Host
+}
+
+func (s *TLSPolicySuite) SetupSuite() {
+	s.require = s.Require()
+
+	s.strict = s.startServer(&tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		CipherSuites: []uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256},
+	})
+	s.legacy = s.startServer(&tls.Config{
+		MinVersion: tls.VersionTLS10,
+		CipherSuites: []uint16{
+			tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA,
+			tls.TLS_RSA_WITH_AES_128_CBC_SHA,
+		},
+	})
+}
+
+func (s *TLSPolicySuite) TearDownSuite() {
+	s.strict.Close()
+	s.legacy.Close()
+}
+
+func (s *TLSPolicySuite) SetupTest() {
+	factory, err := GetChecker("tls-policy")
+	s.require.NoError(err)
+	s.check = factory.(amboy.Job).(*tlsPolicy)
+	s.check.dialTimeout = 10 * time.Second
+}
+
+func (s *TLSPolicySuite) TestValidation() {
+	s.Error(s.check.validate())
+
+	s.check.Address = "localhost"
+	s.Error(s.check.validate())
+
+	s.check.Address = "localhost:443"
+	s.Error(s.check.validate())
+
+	s.check.MinVersion = "TLSv1.2"
+	s.NoError(s.check.validate())
+
+	s.check.ForbiddenVersions = []string{"SSL3"}
+	s.Error(s.check.validate())
+
+	s.check.ForbiddenVersions = []string{"1.0"}
+	s.NoError(s.check.validate())
+
+	s.check.RequiredCiphers = []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"}
+	s.NoError(s.check.validate())
+
+	// tls 1.3 suites cannot be offered on their own.
+	s.check.ForbiddenCiphers = []string{"TLS_AES_128_GCM_SHA256"}
+	s.Error(s.check.validate())
+
+	s.check.ForbiddenCiphers = []string{"ROT13"}
+	s.Error(s.check.validate())
+}
+
+func (s *TLSPolicySuite) TestVersionNames() {
+	for name, version := range map[string]uint16{
+		"TLS1.0":  tls.VersionTLS10,
+		"tlsv1.1": tls.VersionTLS11,
+		"TLS 1.2": tls.VersionTLS12,
+		"1.3":     tls.VersionTLS13,
+	} {
+		v, ok := tlsVersionByName(name)
+		s.True(ok, name)
+		s.Equal(version, v, name)
+	}
+
+	_, ok := tlsVersionByName("SSLv3")
+	s.False(ok)
+}
+
+func (s *TLSPolicySuite) TestCompliantServerPasses() {
+	s.check.Address = s.address(s.strict)
+	s.check.MinVersion = "TLS1.2"
+	s.check.RequiredCiphers = []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"}
+	s.check.ForbiddenCiphers = []string{"TLS_RSA_WITH_AES_128_CBC_SHA"}
+	s.check.Run()
+
+	s.True(s.check.Output().Passed)
+	s.NoError(s.check.Error())
+	s.Equal("TLS1.0: rejected\nTLS1.1: rejected\nTLS_RSA_WITH_AES_128_CBC_SHA: rejected\n"+
+		"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256: accepted", s.check.Output().Message)
+}
+
+func (s *TLSPolicySuite) TestLegacyServerFails() {
+	s.check.Address = s.address(s.legacy)
+	s.check.MinVersion = "TLS1.2"
+	s.check.ForbiddenCiphers = []string{"TLS_RSA_WITH_AES_128_CBC_SHA"}
+	s.check.Run()
+
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "accepted forbidden protocol TLS1.0")
+	s.Contains(s.check.Error().Error(), "accepted forbidden protocol TLS1.1")
+	s.Contains(s.check.Error().Error(), "negotiated forbidden cipher TLS_RSA_WITH_AES_128_CBC_SHA")
+}
+
+func (s *TLSPolicySuite) TestMissingRequiredCipherFails() {
+	s.check.Address = s.address(s.strict)
+	s.check.ForbiddenVersions = []string{"TLS1.3"}
+	s.check.RequiredCiphers = []string{"TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256"}
+	s.check.Run()
+
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "accepted forbidden protocol TLS1.3")
+	s.Contains(s.check.Error().Error(), "does not support required cipher TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256")
+}
+
+func (s *TLSPolicySuite) TestUnreachableServerErrors() {
+	server := s.startServer(&tls.Config{})
+	address := s.address(server)
+	server.Close()
+
+	s.check.Address = address
+	s.check.MinVersion = "TLS1.2"
+	s.check.Run()
+
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "problem connecting to")
+}
@@ -0,0 +1,161 @@
+package check
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"os/user"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/registry"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	name := "unix-socket"
+	registry.AddJobType(name, func() amboy.Job {
+		return &unixSocket{
+			Base: NewBase(name, 0),
+		}
+	})
+}
+
+// unixSocket checks that a Unix domain socket exists. When connect
+// is set, the check also connects to the socket, to confirm that a
+// process is accepting connections, waiting up to timeout (a
+// duration, e.g. "5s", which defaults to 10 seconds). The optional
+// mode (octal permissions, e.g. "0660") and owner (a user name or
+// uid) must match the socket file.
+type unixSocket struct {
+	Path    string `bson:"path" json:"path" yaml:"path"`
+	Connect bool   `bson:"connect" json:"connect" yaml:"connect"`
+	Mode    string `bson:"mode" json:"mode" yaml:"mode"`
+	Owner   string `bson:"owner" json:"owner" yaml:"owner"`
+	Timeout string `bson:"timeout" json:"timeout" yaml:"timeout"`
+	*Base   `bson:"metadata" json:"metadata" yaml:"metadata"`
+
+	mode    os.FileMode
+	timeout time.Duration
+}
+
+func (c *unixSocket) validate() error {
+	if c.Path == "" {
+		return errors.Errorf("no path specified for '%s' (%s) check", c.ID(), c.Name())
+	}
+
+	if c.Mode != "" {
+		mode, err := strconv.ParseUint(c.Mode, 8, 32)
+		if err != nil || mode > 0777 {
+			return errors.Errorf("mode '%s' for '%s' check is not valid octal permissions",
+				c.Mode, c.ID())
+		}
+		c.mode = os.FileMode(mode)
+	}
+
+	c.timeout = 10 * time.Second
+	if c.Timeout != "" {
+		timeout, err := time.ParseDuration(c.Timeout)
+		if err != nil {
+			return errors.Wrapf(err, "problem parsing timeout for '%s' check", c.ID())
+		}
+		c.timeout = timeout
+	}
+
+	return nil
+}
+
+// ownerUID returns the uid for the owner, which may be a user name
+// or a numeric uid.
+func (c *unixSocket) ownerUID() (string, error) {
+	if _, err := strconv.Atoi(c.Owner); err == nil {
+		return c.Owner, nil
+	}
+
+	u, err := user.Lookup(c.Owner)
+	if err != nil {
+		return "", errors.Wrapf(err, "problem finding user '%s'", c.Owner)
+	}
+
+	return u.Uid, nil
+}
+
+func (c *unixSocket) Run() {
+	c.startTask()
+	defer c.MarkComplete()
+
+	if err := c.validate(); err != nil {
+		c.setState(false)
+		c.setReason(ReasonInvalidConfig)
+		c.AddError(err)
+		return
+	}
+
+	info, err := os.Stat(c.Path)
+	if err != nil {
+		c.setState(false)
+		c.AddError(errors.Wrapf(err, "socket '%s' does not exist", c.Path))
+		return
+	}
+
+	if info.Mode()&os.ModeSocket == 0 {
+		c.setState(false)
+		c.AddError(errors.Errorf("'%s' is not a socket (mode %s)", c.Path, info.Mode()))
+		return
+	}
+
+	msg := []string{fmt.Sprintf("mode=%#o", info.Mode().Perm())}
+	var problems []string
+
+	if c.Mode != "" && info.Mode().Perm() != c.mode {
+		c.setReason(ReasonPermMismatch)
+		problems = append(problems, fmt.Sprintf("mode is %#o, expected %#o",
+			info.Mode().Perm(), c.mode))
+	}
+
+	if c.Owner != "" {
+		uid, err := c.ownerUID()
+		if err != nil {
+			c.setState(false)
+			c.AddError(err)
+			return
+		}
+
+		actual, err := fileOwnerUID(info)
+		if err != nil {
+			c.setState(false)
+			c.AddError(errors.Wrapf(err, "problem finding owner of '%s'", c.Path))
+			return
+		}
+
+		msg = append(msg, fmt.Sprintf("uid=%s", actual))
+		if actual != uid {
+			c.setReason(ReasonPermMismatch)
+			problems = append(problems, fmt.Sprintf("owner uid is %s, expected %s (%s)",
+				actual, uid, c.Owner))
+		}
+	}
+
+	if c.Connect {
+		conn, err := net.DialTimeout("unix", c.Path, c.timeout)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("connection failed: %s", err))
+		} else {
+			conn.Close()
+			msg = append(msg, "accepting connections")
+		}
+	}
+
+	c.setMessage(fmt.Sprintf("'%s': %s", c.Path, strings.Join(msg, ", ")))
+
+	if len(problems) > 0 {
+		c.setState(false)
+		c.AddError(errors.Errorf("socket '%s' is not as expected: %s",
+			c.Path, strings.Join(problems, "; ")))
+		return
+	}
+
+	c.setState(true)
+}
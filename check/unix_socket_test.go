@@ -0,0 +1,142 @@
+package check
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type UnixSocketSuite struct {
+	tmpDir   string
+	sockPath string
+	listener net.Listener
+	check    *unixSocket
+	require  *require.Assertions
+	suite.Suite
+}
+
+func TestUnixSocketSuite(t *testing.T) {
+	suite.Run(t, new(UnixSocketSuite))
+}
+
+func (s *UnixSocketSuite) SetupSuite() {
+	s.require = s.Require()
+
+	dir, err := ioutil.TempDir("", uuid.NewV4().String())
+	s.require.NoError(err)
+	s.tmpDir = dir
+
+	s.sockPath = filepath.Join(dir, "test.sock")
+	s.listener, err = net.Listen("unix", s.sockPath)
+	s.require.NoError(err)
+	s.require.NoError(os.Chmod(s.sockPath, 0660))
+
+	go func() {
+		for {
+			conn, err := s.listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+}
+
+func (s *UnixSocketSuite) TearDownSuite() {
+	s.listener.Close()
+	s.require.NoError(os.RemoveAll(s.tmpDir))
+}
+
+func (s *UnixSocketSuite) SetupTest() {
+	factory, err := GetChecker("unix-socket")
+	s.require.NoError(err)
+	s.check = factory.(*unixSocket)
+}
+
+func (s *UnixSocketSuite) TestValidation() {
+	s.Error(s.check.validate())
+
+	s.check.Path = s.sockPath
+	s.NoError(s.check.validate())
+
+	s.check.Mode = "0999"
+	s.Error(s.check.validate())
+
+	s.check.Mode = "0660"
+	s.NoError(s.check.validate())
+
+	s.check.Timeout = "soon"
+	s.Error(s.check.validate())
+}
+
+func (s *UnixSocketSuite) TestSocketWithExpectedAttributesPasses() {
+	s.check.Path = s.sockPath
+	s.check.Connect = true
+	s.check.Mode = "0660"
+	s.check.Owner = strconv.Itoa(os.Getuid())
+	s.check.Timeout = "2s"
+	s.check.Run()
+
+	output := s.check.Output()
+	s.True(output.Passed, output.Error)
+	s.NoError(s.check.Error())
+	s.Contains(output.Message, "accepting connections")
+}
+
+func (s *UnixSocketSuite) TestRegularFileFails() {
+	fn := filepath.Join(s.tmpDir, "regular")
+	s.require.NoError(ioutil.WriteFile(fn, []byte("greenbay"), 0644))
+
+	s.check.Path = fn
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "not a socket")
+}
+
+func (s *UnixSocketSuite) TestWrongModeOrOwnerFails() {
+	s.check.Path = s.sockPath
+	s.check.Mode = "0600"
+	s.check.Owner = strconv.Itoa(os.Getuid() + 1)
+	s.check.Run()
+
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "mode is 0660, expected 0600")
+	s.Contains(s.check.Error().Error(), "owner uid is")
+}
+
+func (s *UnixSocketSuite) TestStaleSocketFailsToConnect() {
+	path := filepath.Join(s.tmpDir, "stale.sock")
+	l, err := net.Listen("unix", path)
+	s.require.NoError(err)
+	l.(*net.UnixListener).SetUnlinkOnClose(false)
+	s.require.NoError(l.Close())
+
+	s.check.Path = path
+	s.NoError(s.check.validate())
+	s.check.Run()
+	s.True(s.check.Output().Passed)
+
+	s.SetupTest()
+	s.check.Path = path
+	s.check.Connect = true
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "connection failed")
+}
+
+func (s *UnixSocketSuite) TestMissingSocketFails() {
+	s.check.Path = filepath.Join(s.tmpDir, "DOES-NOT-EXIST")
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.Error(s.check.Error())
+}
@@ -0,0 +1,21 @@
+//go:build linux || freebsd || solaris || darwin
+// +build linux freebsd solaris darwin
+
+package check
+
+import (
+	"os"
+	"strconv"
+	"syscall"
+
+	"github.com/pkg/errors"
+)
+
+func fileOwnerUID(info os.FileInfo) (string, error) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return "", errors.Errorf("cannot determine owner of '%s'", info.Name())
+	}
+
+	return strconv.FormatUint(uint64(stat.Uid), 10), nil
+}
@@ -0,0 +1,14 @@
+//go:build windows
+// +build windows
+
+package check
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+func fileOwnerUID(info os.FileInfo) (string, error) {
+	return "", errors.Errorf("cannot determine owner of '%s' on windows", info.Name())
+}
@@ -0,0 +1,169 @@
+package check
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/registry"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	name := "zombie-processes"
+	registry.AddJobType(name, func() amboy.Job {
+		return &zombieProcesses{
+			Base:    NewBase(name, 0),
+			procDir: "/proc",
+		}
+	})
+}
+
+// zombieProcesses checks that there are at most max (default 0)
+// zombie (defunct) processes, which are processes in the "Z" state in
+// /proc/<pid>/stat. Zombies remain until their parent reaps them, so
+// when the check fails it reports the parents with the most zombies,
+// which are the processes that are not reaping their children. Only
+// supported on Linux.
+type zombieProcesses struct {
+	Max   int `bson:"max" json:"max" yaml:"max"`
+	*Base `bson:"metadata" json:"metadata" yaml:"metadata"`
+	fileReadLimit
+
+	procDir string
+}
+
+// zombieProcess is a zombie process and its parent.
+type zombieProcess struct {
+	pid  string
+	comm string
+	ppid string
+}
+
+func (c *zombieProcesses) validate() error {
+	if c.Max < 0 {
+		return errors.Errorf("max (%d) for '%s' (%s) check must not be negative",
+			c.Max, c.ID(), c.Name())
+	}
+
+	return nil
+}
+
+func (c *zombieProcesses) Run() {
+	c.startTask()
+	defer c.MarkComplete()
+
+	if err := c.validate(); err != nil {
+		c.setState(false)
+		c.setReason(ReasonInvalidConfig)
+		c.AddError(err)
+		return
+	}
+
+	zombies, err := c.findZombies()
+	if err != nil {
+		c.setState(false)
+		c.AddError(err)
+		return
+	}
+
+	msg := fmt.Sprintf("found %d zombie processes", len(zombies))
+	if len(zombies) <= c.Max {
+		c.setState(true)
+		c.setMessage(msg)
+		return
+	}
+
+	c.setState(false)
+	c.setMessage(fmt.Sprintf("%s; parents with the most zombies: [%s]", msg, c.topZombieParents(zombies)))
+	c.AddError(errors.Errorf("found %d zombie processes, which is more than %d", len(zombies), c.Max))
+}
+
+func (c *zombieProcesses) findZombies() ([]zombieProcess, error) {
+	dirs, err := ioutil.ReadDir(c.procDir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem reading process table from '%s'", c.procDir)
+	}
+
+	var zombies []zombieProcess
+	for _, info := range dirs {
+		if !info.IsDir() {
+			continue
+		}
+
+		if _, err := strconv.Atoi(info.Name()); err != nil {
+			continue
+		}
+
+		// processes may exit while we're reading the process
+		// table, so we ignore processes we cannot read.
+		stat, err := c.readFile(filepath.Join(c.procDir, info.Name(), "stat"))
+		if err != nil {
+			continue
+		}
+
+		comm, state, ppid, ok := parseProcStat(string(stat))
+		if !ok || state != "Z" {
+			continue
+		}
+
+		zombies = append(zombies, zombieProcess{pid: info.Name(), comm: comm, ppid: ppid})
+	}
+
+	return zombies, nil
+}
+
+// parseProcStat returns the command name, state, and parent pid from
+// the contents of /proc/<pid>/stat. The command name is in
+// parentheses, and may contain spaces and parentheses itself, so the
+// other fields follow the last closing parenthesis.
+func parseProcStat(stat string) (string, string, string, bool) {
+	start := strings.Index(stat, "(")
+	end := strings.LastIndex(stat, ")")
+	if start < 0 || end < start {
+		return "", "", "", false
+	}
+
+	fields := strings.Fields(stat[end+1:])
+	if len(fields) < 2 {
+		return "", "", "", false
+	}
+
+	return stat[start+1 : end], fields[0], fields[1], true
+}
+
+// topZombieParents returns the parents with the most zombies, with
+// the names of the parents, and the number of zombies of each.
+func (c *zombieProcesses) topZombieParents(zombies []zombieProcess) string {
+	counts := make(map[string]int)
+	for _, z := range zombies {
+		counts[z.ppid]++
+	}
+
+	parents := make([]string, 0, len(counts))
+	for ppid := range counts {
+		parents = append(parents, ppid)
+	}
+
+	sort.Sort(&processNamesByCount{names: parents, counts: counts})
+
+	if len(parents) > processCountReportSize {
+		parents = parents[:processCountReportSize]
+	}
+
+	out := make([]string, 0, len(parents))
+	for _, ppid := range parents {
+		name := "unknown"
+		if comm, err := c.readFile(filepath.Join(c.procDir, ppid, "comm")); err == nil {
+			name = strings.TrimSpace(string(comm))
+		}
+
+		out = append(out, fmt.Sprintf("%s (pid %s)=%d", name, ppid, counts[ppid]))
+	}
+
+	return strings.Join(out, ", ")
+}
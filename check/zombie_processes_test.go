@@ -0,0 +1,107 @@
+package check
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type ZombieProcessesSuite struct {
+	tmpDir  string
+	check   *zombieProcesses
+	require *require.Assertions
+	suite.Suite
+}
+
+func TestZombieProcessesSuite(t *testing.T) {
+	suite.Run(t, new(ZombieProcessesSuite))
+}
+
+func (s *ZombieProcessesSuite) SetupSuite() {
+	s.require = s.Require()
+
+	dir, err := ioutil.TempDir("", uuid.NewV4().String())
+	s.require.NoError(err)
+	s.tmpDir = dir
+
+	procs := map[string][2]string{
+		"1":   {"systemd", "1 (systemd) S 0 1 1 0 -1"},
+		"100": {"app server", "100 (app server) S 1 100 100 0 -1"},
+		"101": {"worker", "101 (worker) Z 100 100 100 0 -1"},
+		"102": {"worker", "102 (worker) Z 100 100 100 0 -1"},
+		"103": {"sh (child)", "103 (sh (child)) Z 1 103 103 0 -1"},
+		"104": {"cron", "104 (cron) S 1 104 104 0 -1"},
+	}
+
+	for pid, proc := range procs {
+		s.require.NoError(os.MkdirAll(filepath.Join(dir, pid), 0755))
+		s.require.NoError(ioutil.WriteFile(filepath.Join(dir, pid, "comm"), []byte(proc[0]+"\n"), 0644))
+		s.require.NoError(ioutil.WriteFile(filepath.Join(dir, pid, "stat"), []byte(proc[1]+"\n"), 0644))
+	}
+
+	// not processes
+	s.require.NoError(os.MkdirAll(filepath.Join(dir, "sys"), 0755))
+	s.require.NoError(ioutil.WriteFile(filepath.Join(dir, "uptime"), []byte("1.0 1.0\n"), 0644))
+}
+
+func (s *ZombieProcessesSuite) TearDownSuite() {
+	s.require.NoError(os.RemoveAll(s.tmpDir))
+}
+
+func (s *ZombieProcessesSuite) SetupTest() {
+	s.check = &zombieProcesses{
+		Base:    NewBase("zombie-processes", 0),
+		procDir: s.tmpDir,
+	}
+}
+
+func (s *ZombieProcessesSuite) TestParseProcStat() {
+	comm, state, ppid, ok := parseProcStat("103 (sh (child)) Z 1 103 103 0 -1")
+	s.True(ok)
+	s.Equal("sh (child)", comm)
+	s.Equal("Z", state)
+	s.Equal("1", ppid)
+
+	_, _, _, ok = parseProcStat("103 sh Z 1")
+	s.False(ok)
+
+	_, _, _, ok = parseProcStat("103 (sh) Z")
+	s.False(ok)
+}
+
+func (s *ZombieProcessesSuite) TestFailsWithMoreZombiesThanMax() {
+	s.check.Run()
+
+	s.False(s.check.Output().Passed)
+	s.require.Error(s.check.Error())
+	s.Contains(s.check.Error().Error(), "found 3 zombie processes, which is more than 0")
+	s.Equal("found 3 zombie processes; parents with the most zombies: [app server (pid 100)=2, systemd (pid 1)=1]",
+		s.check.Output().Message)
+}
+
+func (s *ZombieProcessesSuite) TestPassesWithinMax() {
+	s.check.Max = 3
+	s.check.Run()
+
+	s.True(s.check.Output().Passed)
+	s.NoError(s.check.Error())
+	s.Equal("found 3 zombie processes", s.check.Output().Message)
+}
+
+func (s *ZombieProcessesSuite) TestInvalidConfiguration() {
+	s.check.Max = -1
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.Error(s.check.Error())
+
+	s.SetupTest()
+	s.check.procDir = filepath.Join(s.tmpDir, "DOES-NOT-EXIST")
+	s.check.Run()
+	s.False(s.check.Output().Passed)
+	s.Error(s.check.Error())
+}
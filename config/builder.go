@@ -62,9 +62,10 @@ func (b *Builder) AddCheck(check greenbay.Checker) error {
 
 	// build the check structure
 	t := rawTest{
-		Name:      check.ID(),
-		Suites:    check.Suites(),
-		Operation: check.Name(),
+		Name:        check.ID(),
+		Suites:      check.Suites(),
+		Operation:   check.Name(),
+		Annotations: check.Annotations(),
 	}
 
 	raw, err := json.Marshal(check)
@@ -2,9 +2,14 @@ package config
 
 import (
 	"encoding/json"
+	"fmt"
 	"runtime"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 
+	"github.com/ghodss/yaml"
 	"github.com/mongodb/amboy"
 	"github.com/pkg/errors"
 	"github.com/tychoish/grip"
@@ -13,17 +18,44 @@ import (
 // GreenbayTestConfig defines the structure for a single greenbay test
 // run, including execution behavior (options) and check definitions.
 type GreenbayTestConfig struct {
-	Options  *options             `bson:"options" json:"options" yaml:"options"`
-	RawTests []rawTest            `bson:"tests" json:"tests" yaml:"tests"`
-	tests    map[string]amboy.Job // maping of test names to test objects
-	suites   map[string][]string  // mapping of suite names to test names
-	mutex    sync.RWMutex
+	Options *options `bson:"options" json:"options" yaml:"options"`
+
+	// Values in Defaults are added to every check that does not
+	// specify a value for that key. The "order",
+	// "expected_max_duration", "retries", "retry_on", "retry_delay",
+	// and "annotations" keys set those fields of the checks, and all
+	// other keys are added to the arguments of the checks, and must
+	// be an argument of at least one check.
+	Defaults map[string]interface{} `bson:"defaults" json:"defaults" yaml:"defaults"`
+
+	// SuiteOptions maps suite names to per-suite settings.
+	SuiteOptions map[string]*suiteOptions `bson:"suite_options" json:"suite_options" yaml:"suite_options"`
+	RawTests     []rawTest                `bson:"tests" json:"tests" yaml:"tests"`
+	tests        map[string]amboy.Job     // maping of test names to test objects
+	suites       map[string][]string      // mapping of suite names to test names
+	mutex        sync.RWMutex
 }
 
 type options struct {
 	ContineOnError bool   `bson:"continue_on_error" json:"continue_on_error" yaml:"continue_on_error"`
 	ReportFormat   string `bson:"report_format" json:"report_format" yaml:"report_format"`
 	Jobs           int    `bson:"jobs" json:"jobs" yaml:"jobs"` // number of job workers.
+	PreRun         string `bson:"pre_run" json:"pre_run" yaml:"pre_run"`
+	PostRun        string `bson:"post_run" json:"post_run" yaml:"post_run"`
+}
+
+type suiteOptions struct {
+	// MinPassPercent is the percentage of checks in the suite that
+	// must pass for the suite to pass.
+	MinPassPercent *float64 `bson:"min_pass_percent" json:"min_pass_percent" yaml:"min_pass_percent"`
+
+	// WarnOnly reports the failures of checks in the suite as
+	// warnings that do not fail the run.
+	WarnOnly bool `bson:"warn_only" json:"warn_only" yaml:"warn_only"`
+
+	// Workers is the number of checks in the suite to run at once,
+	// which overrides the global number of jobs for the suite.
+	Workers int `bson:"workers" json:"workers" yaml:"workers"`
 }
 
 func newTestConfig() *GreenbayTestConfig {
@@ -39,10 +71,17 @@ func (c *GreenbayTestConfig) reset() {
 	c.tests = make(map[string]amboy.Job)
 }
 
+// StdinConfig is the config file name that refers to standard input.
+const StdinConfig = "-"
+
 // ReadConfig takes a path name to a configuration file (yaml
-// formatted,) and returns a configuration format.
-func ReadConfig(fn string) (*GreenbayTestConfig, error) {
-	data, err := getRawConfig(fn)
+// formatted,) and returns a configuration format. The format, "yaml"
+// or "json", overrides the format that the file's extension implies,
+// and is required for files without a ".yaml", ".yml", or ".json"
+// extension and for standard input (StdinConfig). An empty format
+// uses the extension.
+func ReadConfig(fn, format string) (*GreenbayTestConfig, error) {
+	data, err := getRawConfig(fn, format)
 	if err != nil {
 		return nil, errors.Wrapf(err, "problem reading config data for '%s'", fn)
 	}
@@ -57,15 +96,65 @@ func ReadConfig(fn string) (*GreenbayTestConfig, error) {
 		return nil, errors.Wrapf(err, "problem parsing config '%s'", fn)
 	}
 
+	if err = c.applyDefaults(); err != nil {
+		return nil, errors.Wrapf(err, "problem applying defaults from file '%s'", fn)
+	}
+
 	if err = c.parseTests(); err != nil {
 		return nil, errors.Wrapf(err, "problem parsing tests from file '%s'", fn)
 	}
 
+	if err = c.validateSuiteOptions(); err != nil {
+		return nil, errors.Wrapf(err, "problem validating suite options from file '%s'", fn)
+	}
+
 	grip.Infoln("loading config file:", fn)
 
 	return c, nil
 }
 
+// ValidateCheckTypes reads the config file and returns an error,
+// which lists every unknown type, if any check in the file has a type
+// that is not in the check registry. Unlike ReadConfig, it does not
+// build any checks, which makes it suitable as a fast validation pass
+// before starting a run. The format is the same as for ReadConfig.
+func ValidateCheckTypes(fn, format string) error {
+	data, err := getRawConfig(fn, format)
+	if err != nil {
+		return errors.Wrapf(err, "problem reading config data for '%s'", fn)
+	}
+
+	c := newTestConfig()
+	if err = json.Unmarshal(data, c); err != nil {
+		return errors.Wrapf(err, "problem parsing config '%s'", fn)
+	}
+
+	unknown := make(map[string][]string)
+	for _, t := range c.RawTests {
+		if _, err := t.getChecker(); err != nil {
+			unknown[t.Operation] = append(unknown[t.Operation], t.Name)
+		}
+	}
+
+	if len(unknown) == 0 {
+		return nil
+	}
+
+	types := make([]string, 0, len(unknown))
+	for name := range unknown {
+		types = append(types, name)
+	}
+	sort.Strings(types)
+
+	out := make([]string, 0, len(types))
+	for _, name := range types {
+		out = append(out, fmt.Sprintf("'%s' (used by: %s)", name, strings.Join(unknown[name], ", ")))
+	}
+
+	return errors.Errorf("config '%s' has %d unknown check type(s): %s",
+		fn, len(types), strings.Join(out, "; "))
+}
+
 // JobWithError is a type used by the test generators and contains an
 // amboy.Job and an error message.
 type JobWithError struct {
@@ -153,3 +242,199 @@ func (c *GreenbayTestConfig) TestsByName(names ...string) <-chan JobWithError {
 
 	return output
 }
+
+// CheckHash returns a hash of the definition of the named check. The
+// hash changes when the name, suites, type, or arguments of the check
+// change in the config file. Returns an error if there is no check
+// with that name.
+func (c *GreenbayTestConfig) CheckHash(name string) (string, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	for _, t := range c.RawTests {
+		if t.Name == name {
+			return t.hash()
+		}
+	}
+
+	return "", errors.Errorf("no test named %s", name)
+}
+
+// CheckDefinition returns the definition of the named check from the
+// config file, which has the paths of secret files rather than the
+// secrets. Returns an error if there is no check with that name.
+func (c *GreenbayTestConfig) CheckDefinition(name string) (json.RawMessage, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	for _, t := range c.RawTests {
+		if t.Name == name {
+			return t.definition()
+		}
+	}
+
+	return nil, errors.Errorf("no test named %s", name)
+}
+
+// HasTest reports if the config defines a check with the name.
+func (c *GreenbayTestConfig) HasTest(name string) bool {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	_, ok := c.tests[name]
+	return ok
+}
+
+// CheckExpectedMaxDuration returns the expected maximum duration of
+// the named check, which is 0 for checks that do not specify one, or
+// that do not exist.
+func (c *GreenbayTestConfig) CheckExpectedMaxDuration(name string) time.Duration {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	for _, t := range c.RawTests {
+		if t.Name == name && t.MaxDuration != "" {
+			// parseTests validates the duration.
+			dur, _ := time.ParseDuration(t.MaxDuration)
+			return dur
+		}
+	}
+
+	return 0
+}
+
+// CheckOrder returns the order hint of the named check, which is 0
+// for checks that do not specify an order, or that do not exist.
+func (c *GreenbayTestConfig) CheckOrder(name string) int {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	for _, t := range c.RawTests {
+		if t.Name == name {
+			return t.Order
+		}
+	}
+
+	return 0
+}
+
+// SuiteMinPassPercent returns the percentage of checks in the named
+// suite that must pass for the suite to pass. The second value is
+// false if the config does not set a threshold for the suite.
+func (c *GreenbayTestConfig) SuiteMinPassPercent(name string) (float64, bool) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	opts, ok := c.SuiteOptions[name]
+	if !ok || opts == nil || opts.MinPassPercent == nil {
+		return 0, false
+	}
+
+	return *opts.MinPassPercent, true
+}
+
+// PreRunHook returns the shell command to run before running
+// checks, or an empty string if the config does not set one.
+func (c *GreenbayTestConfig) PreRunHook() string {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	if c.Options == nil {
+		return ""
+	}
+
+	return c.Options.PreRun
+}
+
+// PostRunHook returns the shell command to run after running checks,
+// or an empty string if the config does not set one.
+func (c *GreenbayTestConfig) PostRunHook() string {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	if c.Options == nil {
+		return ""
+	}
+
+	return c.Options.PostRun
+}
+
+// SuiteWorkers returns the number of workers for the named suite.
+// The second value is false if the config does not set a number of
+// workers for the suite.
+func (c *GreenbayTestConfig) SuiteWorkers(name string) (int, bool) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	opts, ok := c.SuiteOptions[name]
+	if !ok || opts == nil || opts.Workers == 0 {
+		return 0, false
+	}
+
+	return opts.Workers, true
+}
+
+// SuiteWarnOnly reports if the named suite is warn-only.
+func (c *GreenbayTestConfig) SuiteWarnOnly(name string) bool {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	opts, ok := c.SuiteOptions[name]
+	return ok && opts != nil && opts.WarnOnly
+}
+
+// CheckWarnOnly reports if failures of the named check are
+// warnings, which is the case when every suite that the check
+// belongs to is warn-only. A check that is also in a suite that is
+// not warn-only fails the run as usual.
+func (c *GreenbayTestConfig) CheckWarnOnly(name string) bool {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	for _, t := range c.RawTests {
+		if t.Name != name {
+			continue
+		}
+
+		if len(t.Suites) == 0 {
+			return false
+		}
+
+		for _, suite := range t.Suites {
+			opts, ok := c.SuiteOptions[suite]
+			if !ok || opts == nil || !opts.WarnOnly {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	return false
+}
+
+// Dump returns the resolved config, after defaults are applied, as a
+// JSON or YAML document. This is the config that greenbay uses to
+// build checks.
+func (c *GreenbayTestConfig) Dump(format amboy.Format) ([]byte, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	data, err := json.MarshalIndent(c, "", "   ")
+	if err != nil {
+		return nil, errors.Wrap(err, "problem encoding config")
+	}
+
+	switch format {
+	case amboy.JSON:
+		return data, nil
+	case amboy.YAML:
+		data, err = yaml.JSONToYAML(data)
+		if err != nil {
+			return nil, errors.Wrap(err, "problem converting config to yaml")
+		}
+		return data, nil
+	default:
+		return nil, errors.Errorf("format %d is not supported", format)
+	}
+}
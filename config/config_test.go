@@ -7,10 +7,13 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/mongodb/amboy"
 	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/greenbay"
 	"github.com/mongodb/greenbay/check"
 	"github.com/satori/go.uuid"
 	"github.com/stretchr/testify/require"
@@ -73,7 +76,7 @@ func (s *ConfigSuite) TearDownSuite() {
 }
 
 func (s *ConfigSuite) TestTemporyFileConfigIsCorrect() {
-	conf, err := ReadConfig(s.confFile)
+	conf, err := ReadConfig(s.confFile, "")
 
 	s.NoError(err)
 	s.NotNil(conf)
@@ -132,7 +135,7 @@ func (s *ConfigSuite) TestAddingInvalidDocumentsToConfig() {
 }
 
 func (s *ConfigSuite) TestReadingConfigFromFileDoesntExist() {
-	conf, err := ReadConfig(filepath.Join(s.tempDir, "foo", filepath.Base(s.confFile)))
+	conf, err := ReadConfig(filepath.Join(s.tempDir, "foo", filepath.Base(s.confFile)), "")
 	s.Error(err)
 	s.Nil(conf)
 }
@@ -142,14 +145,362 @@ func (s *ConfigSuite) TestReadConfigWithInvalidFormat() {
 	err := os.Link(s.confFile, fn)
 	s.NoError(err)
 
-	conf, err := ReadConfig(fn)
+	conf, err := ReadConfig(fn, "")
 
 	s.Error(err)
 	s.Nil(conf)
 }
 
+func (s *ConfigSuite) TestReadConfigWithFormatHint() {
+	fn := strings.TrimSuffix(s.confFile, filepath.Ext(s.confFile)) + ".conf"
+	s.require.NoError(os.Link(s.confFile, fn))
+
+	conf, err := ReadConfig(fn, "json")
+	s.NoError(err)
+	s.NotNil(conf)
+
+	conf, err = ReadConfig(s.confFile, "yaml")
+	s.Error(err)
+	s.Nil(conf)
+	s.Contains(err.Error(), "but the config format is 'yaml'")
+}
+
+func (s *ConfigSuite) TestDefaultsAreAppliedToChecksWithoutOverrides() {
+	fn := filepath.Join(s.tempDir, "defaults.yaml")
+	s.require.NoError(ioutil.WriteFile(fn, []byte(`
+defaults:
+  message: from-defaults
+tests:
+  - name: uses-default
+    type: mock-shell-check
+    suites: [ "one" ]
+  - name: overrides-default
+    type: mock-shell-check
+    suites: [ "one" ]
+    args:
+      message: from-check
+`), 0644))
+
+	conf, err := ReadConfig(fn, "")
+	s.require.NoError(err)
+
+	expected := map[string]string{
+		"uses-default":      "from-defaults",
+		"overrides-default": "from-check",
+	}
+
+	for name, msg := range expected {
+		for t := range conf.TestsByName(name) {
+			s.require.NoError(t.Err)
+			c, ok := t.Job.(*mockShellCheck)
+			s.require.True(ok)
+			s.Equal(msg, c.Output().Message)
+		}
+	}
+}
+
+func (s *ConfigSuite) TestDefaultsSetFieldsOfChecks() {
+	fn := filepath.Join(s.tempDir, "field-defaults.yaml")
+	s.require.NoError(ioutil.WriteFile(fn, []byte(`
+defaults:
+  retries: 2
+  retry_delay: 1s
+  annotations: { team: "storage" }
+tests:
+  - name: uses-default
+    type: mock-shell-check
+    suites: [ "one" ]
+  - name: overrides-default
+    type: mock-shell-check
+    suites: [ "one" ]
+    retries: 5
+    annotations: { team: "network" }
+`), 0644))
+
+	conf, err := ReadConfig(fn, "")
+	s.require.NoError(err)
+
+	policy, ok := conf.CheckRetryPolicy("uses-default")
+	s.True(ok)
+	s.Equal(2, policy.Retries)
+	s.Equal(time.Second, policy.Delay)
+
+	policy, ok = conf.CheckRetryPolicy("overrides-default")
+	s.True(ok)
+	s.Equal(5, policy.Retries)
+
+	for name, team := range map[string]string{"uses-default": "storage", "overrides-default": "network"} {
+		for t := range conf.TestsByName(name) {
+			s.require.NoError(t.Err)
+			s.Equal(team, t.Job.(greenbay.Checker).Annotations()["team"], name)
+		}
+	}
+
+	for _, t := range conf.RawTests {
+		s.NotContains(string(t.RawArgs), "retries", t.Name)
+	}
+}
+
+func (s *ConfigSuite) TestDefaultsErrorWithKeysThatNoCheckUses() {
+	fn := filepath.Join(s.tempDir, "unused-defaults.yaml")
+	s.require.NoError(ioutil.WriteFile(fn, []byte(`
+defaults:
+  retires: 2
+tests:
+  - name: uses-default
+    type: mock-shell-check
+    suites: [ "one" ]
+`), 0644))
+
+	conf, err := ReadConfig(fn, "")
+	s.Error(err)
+	s.Nil(conf)
+	s.Contains(err.Error(), "retires")
+}
+
+func (s *ConfigSuite) TestDumpIncludesMergedDefaults() {
+	fn := filepath.Join(s.tempDir, "dump.yaml")
+	s.require.NoError(ioutil.WriteFile(fn, []byte(`
+defaults:
+  message: from-defaults
+tests:
+  - name: uses-default
+    type: mock-shell-check
+    suites: [ "one" ]
+`), 0644))
+
+	conf, err := ReadConfig(fn, "")
+	s.require.NoError(err)
+
+	out, err := conf.Dump(amboy.JSON)
+	s.require.NoError(err)
+
+	dumped := &GreenbayTestConfig{}
+	s.require.NoError(json.Unmarshal(out, dumped))
+	s.require.Len(dumped.RawTests, 1)
+	s.Equal("uses-default", dumped.RawTests[0].Name)
+	s.Contains(string(dumped.RawTests[0].RawArgs), "from-defaults")
+
+	out, err = conf.Dump(amboy.YAML)
+	s.require.NoError(err)
+	s.Contains(string(out), "message: from-defaults")
+
+	_, err = conf.Dump(amboy.BSON)
+	s.Error(err)
+}
+
+func (s *ConfigSuite) TestCheckOrderDefaultsToZero() {
+	fn := filepath.Join(s.tempDir, "order.yaml")
+	s.require.NoError(ioutil.WriteFile(fn, []byte(`
+tests:
+  - name: ordered
+    type: mock-shell-check
+    suites: [ "one" ]
+    args: {}
+    order: 3
+  - name: unordered
+    type: mock-shell-check
+    suites: [ "one" ]
+    args: {}
+`), 0644))
+
+	conf, err := ReadConfig(fn, "")
+	s.require.NoError(err)
+
+	s.Equal(3, conf.CheckOrder("ordered"))
+	s.Equal(0, conf.CheckOrder("unordered"))
+	s.Equal(0, conf.CheckOrder("DOES-NOT-EXIST"))
+}
+
+func (s *ConfigSuite) TestCheckExpectedMaxDuration() {
+	fn := filepath.Join(s.tempDir, "budget.yaml")
+	s.require.NoError(ioutil.WriteFile(fn, []byte(`
+tests:
+  - name: budgeted
+    type: mock-shell-check
+    suites: [ "one" ]
+    args: {}
+    expected_max_duration: 90s
+  - name: unbudgeted
+    type: mock-shell-check
+    suites: [ "one" ]
+    args: {}
+`), 0644))
+
+	conf, err := ReadConfig(fn, "")
+	s.require.NoError(err)
+
+	s.Equal(90*time.Second, conf.CheckExpectedMaxDuration("budgeted"))
+	s.Equal(time.Duration(0), conf.CheckExpectedMaxDuration("unbudgeted"))
+	s.Equal(time.Duration(0), conf.CheckExpectedMaxDuration("DOES-NOT-EXIST"))
+
+	for _, value := range []string{"soon", "-1s", "0s"} {
+		s.require.NoError(ioutil.WriteFile(fn, []byte(`
+tests:
+  - name: budgeted
+    type: mock-shell-check
+    args: {}
+    expected_max_duration: `+value+`
+`), 0644))
+
+		_, err = ReadConfig(fn, "")
+		s.Error(err, value)
+	}
+}
+
+func (s *ConfigSuite) TestSuiteMinPassPercent() {
+	fn := filepath.Join(s.tempDir, "suite-options.yaml")
+	s.require.NoError(ioutil.WriteFile(fn, []byte(`
+suite_options:
+  one:
+    min_pass_percent: 80
+tests:
+  - name: check
+    type: mock-shell-check
+    suites: [ "one", "two" ]
+    args: {}
+`), 0644))
+
+	conf, err := ReadConfig(fn, "")
+	s.require.NoError(err)
+
+	percent, ok := conf.SuiteMinPassPercent("one")
+	s.True(ok)
+	s.Equal(80.0, percent)
+
+	_, ok = conf.SuiteMinPassPercent("two")
+	s.False(ok)
+}
+
+func (s *ConfigSuite) TestSuiteWorkers() {
+	fn := filepath.Join(s.tempDir, "suite-workers.yaml")
+	s.require.NoError(ioutil.WriteFile(fn, []byte(`
+suite_options:
+  disk:
+    workers: 1
+  fast:
+    warn_only: true
+tests:
+  - name: check
+    type: mock-shell-check
+    suites: [ "disk", "fast" ]
+    args: {}
+`), 0644))
+
+	conf, err := ReadConfig(fn, "")
+	s.require.NoError(err)
+
+	workers, ok := conf.SuiteWorkers("disk")
+	s.True(ok)
+	s.Equal(1, workers)
+
+	_, ok = conf.SuiteWorkers("fast")
+	s.False(ok)
+
+	_, ok = conf.SuiteWorkers("DOES-NOT-EXIST")
+	s.False(ok)
+}
+
+func (s *ConfigSuite) TestWarnOnlySuites() {
+	fn := filepath.Join(s.tempDir, "warn-only.yaml")
+	s.require.NoError(ioutil.WriteFile(fn, []byte(`
+suite_options:
+  trial:
+    warn_only: true
+  other-trial:
+    warn_only: true
+  thresholds:
+    min_pass_percent: 50
+tests:
+  - name: trial-check
+    type: mock-shell-check
+    suites: [ "trial", "other-trial" ]
+    args: {}
+  - name: shared-check
+    type: mock-shell-check
+    suites: [ "trial", "thresholds" ]
+    args: {}
+  - name: no-suites
+    type: mock-shell-check
+    args: {}
+`), 0644))
+
+	conf, err := ReadConfig(fn, "")
+	s.require.NoError(err)
+
+	s.True(conf.SuiteWarnOnly("trial"))
+	s.False(conf.SuiteWarnOnly("thresholds"))
+	s.False(conf.SuiteWarnOnly("DOES-NOT-EXIST"))
+
+	s.True(conf.CheckWarnOnly("trial-check"))
+	s.False(conf.CheckWarnOnly("shared-check"))
+	s.False(conf.CheckWarnOnly("no-suites"))
+	s.False(conf.CheckWarnOnly("DOES-NOT-EXIST"))
+
+	// setting warn_only does not set a pass threshold.
+	_, ok := conf.SuiteMinPassPercent("trial")
+	s.False(ok)
+}
+
+func (s *ConfigSuite) TestInvalidSuiteOptionsFailValidation() {
+	for _, opts := range []string{
+		"one: { min_pass_percent: 101 }",
+		"one: { min_pass_percent: -1 }",
+		"one: { workers: -1 }",
+		"DOES-NOT-EXIST: { min_pass_percent: 50 }",
+	} {
+		fn := filepath.Join(s.tempDir, "invalid-suite-options.yaml")
+		s.require.NoError(ioutil.WriteFile(fn, []byte(`
+suite_options:
+  `+opts+`
+tests:
+  - name: check
+    type: mock-shell-check
+    suites: [ "one" ]
+    args: {}
+`), 0644))
+
+		conf, err := ReadConfig(fn, "")
+		s.Error(err, opts)
+		s.Nil(conf)
+	}
+}
+
+func (s *ConfigSuite) TestValidateCheckTypesListsAllUnknownTypes() {
+	s.NoError(ValidateCheckTypes(s.confFile, ""))
+
+	fn := filepath.Join(s.tempDir, "unknown-types.yaml")
+	s.require.NoError(ioutil.WriteFile(fn, []byte(`
+tests:
+  - name: known
+    type: mock-shell-check
+    suites: [ "one" ]
+    args: {}
+  - name: typo-one
+    type: mock-shell-chek
+    suites: [ "one" ]
+    args: {}
+  - name: typo-two
+    type: mock-shell-chek
+    suites: [ "one" ]
+    args: {}
+  - name: other
+    type: DOES-NOT-EXIST
+    suites: [ "one" ]
+    args: {}
+`), 0644))
+
+	err := ValidateCheckTypes(fn, "")
+	s.require.Error(err)
+	s.Contains(err.Error(), "2 unknown check type(s)")
+	s.Contains(err.Error(), "'mock-shell-chek' (used by: typo-one, typo-two)")
+	s.Contains(err.Error(), "'DOES-NOT-EXIST' (used by: other)")
+
+	s.Error(ValidateCheckTypes(filepath.Join(s.tempDir, "DOES-NOT-EXIST.yaml"), ""))
+}
+
 func (s *ConfigSuite) TestForSuiteGetterObject() {
-	conf, err := ReadConfig(s.confFile)
+	conf, err := ReadConfig(s.confFile, "")
 
 	s.NoError(err)
 	s.NotNil(conf)
@@ -177,7 +528,7 @@ func (s *ConfigSuite) TestForSuiteGetterGeneratorWithInvalidSuite() {
 }
 
 func (s *ConfigSuite) TestByNameGenerator() {
-	conf, err := ReadConfig(s.confFile)
+	conf, err := ReadConfig(s.confFile, "")
 
 	s.NoError(err)
 	s.NotNil(conf)
@@ -208,7 +559,7 @@ func (s *ConfigSuite) TestByNameWithInvalidGenerator() {
 }
 
 func (s *ConfigSuite) TestsBySuiteDoesNotProduceDuplicates() {
-	conf, err := ReadConfig(s.confFile)
+	conf, err := ReadConfig(s.confFile, "")
 
 	s.NoError(err)
 	s.NotNil(conf)
@@ -225,7 +576,7 @@ func (s *ConfigSuite) TestsBySuiteDoesNotProduceDuplicates() {
 }
 
 func (s *ConfigSuite) TestBySuiteWithInconsistentData() {
-	conf, err := ReadConfig(s.confFile)
+	conf, err := ReadConfig(s.confFile, "")
 
 	s.NoError(err)
 	s.NotNil(conf)
@@ -242,3 +593,62 @@ func (s *ConfigSuite) TestBySuiteWithInconsistentData() {
 	}
 
 }
+
+func (s *ConfigSuite) TestCheckRetryPolicy() {
+	fn := filepath.Join(s.tempDir, "retries.yaml")
+	s.require.NoError(ioutil.WriteFile(fn, []byte(`
+tests:
+  - name: retried
+    type: mock-shell-check
+    suites: [ "one" ]
+    args: {}
+    retries: 3
+    retry_delay: 2s
+    retry_on: [ "connection refused", "time(d)? ?out" ]
+  - name: not-retried
+    type: mock-shell-check
+    suites: [ "one" ]
+    args: {}
+`), 0644))
+
+	conf, err := ReadConfig(fn, "")
+	s.require.NoError(err)
+
+	policy, ok := conf.CheckRetryPolicy("retried")
+	s.True(ok)
+	s.Equal(3, policy.Retries)
+	s.Equal(2*time.Second, policy.Delay)
+	s.True(policy.Matches("dial tcp: connection refused"))
+	s.True(policy.Matches("i/o timeout"))
+	s.False(policy.Matches("401 unauthorized"))
+	s.True(RetryPolicy{}.Matches("anything"))
+
+	_, ok = conf.CheckRetryPolicy("not-retried")
+	s.False(ok)
+	_, ok = conf.CheckRetryPolicy("DOES-NOT-EXIST")
+	s.False(ok)
+
+	c, err := conf.NewCheck("retried")
+	s.require.NoError(err)
+	s.Equal("retried", c.ID())
+	_, err = conf.NewCheck("DOES-NOT-EXIST")
+	s.Error(err)
+
+	for _, retries := range []string{
+		"retries: -1",
+		"retry_on: [ \"refused\" ]",
+		"retries: 1\n    retry_on: [ \"([\" ]",
+		"retries: 1\n    retry_delay: soon",
+	} {
+		s.require.NoError(ioutil.WriteFile(fn, []byte(`
+tests:
+  - name: retried
+    type: mock-shell-check
+    args: {}
+    `+retries+`
+`), 0644))
+
+		_, err = ReadConfig(fn, "")
+		s.Error(err, retries)
+	}
+}
@@ -0,0 +1,38 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+)
+
+// UnreferencedChecks returns the sorted names of the checks that are
+// not in any suite. These checks never run as part of a suite,
+// including the default "all" suite, and only run when requested by
+// name.
+func (c *GreenbayTestConfig) UnreferencedChecks() []string {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	var names []string
+	for _, t := range c.RawTests {
+		if len(t.Suites) == 0 {
+			names = append(names, t.Name)
+		}
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Lint returns warnings about parts of the config that are valid,
+// but are probably mistakes, such as checks that are not in any
+// suite.
+func (c *GreenbayTestConfig) Lint() []string {
+	var warnings []string
+
+	for _, name := range c.UnreferencedChecks() {
+		warnings = append(warnings, fmt.Sprintf("check '%s' is not in any suite, and only runs when requested by name", name))
+	}
+
+	return warnings
+}
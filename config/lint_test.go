@@ -0,0 +1,38 @@
+package config
+
+import (
+	"io/ioutil"
+	"path/filepath"
+)
+
+func (s *ConfigSuite) TestLintReportsUnreferencedChecks() {
+	fn := filepath.Join(s.tempDir, "lint.yaml")
+	s.require.NoError(ioutil.WriteFile(fn, []byte(`
+tests:
+  - name: in-all
+    type: mock-shell-check
+    suites: [ "all" ]
+    args: {}
+  - name: orphan-b
+    type: mock-shell-check
+    args: {}
+  - name: in-other
+    type: mock-shell-check
+    suites: [ "other" ]
+    args: {}
+  - name: orphan-a
+    type: mock-shell-check
+    suites: []
+    args: {}
+`), 0644))
+
+	conf, err := ReadConfig(fn, "")
+	s.require.NoError(err)
+
+	s.Equal([]string{"orphan-a", "orphan-b"}, conf.UnreferencedChecks())
+
+	warnings := conf.Lint()
+	s.require.Len(warnings, 2)
+	s.Contains(warnings[0], "check 'orphan-a' is not in any suite")
+	s.Contains(warnings[1], "check 'orphan-b' is not in any suite")
+}
@@ -2,7 +2,10 @@ package config
 
 import (
 	"io/ioutil"
+	"os"
 	"path/filepath"
+	"sync"
+	"time"
 
 	"github.com/ghodss/yaml"
 	"github.com/mongodb/amboy"
@@ -25,6 +28,46 @@ func getFormat(fn string) (amboy.Format, error) {
 	return -1, errors.Errorf("greenbay does not support files with '%s' extension", ext)
 }
 
+// resolveFormat returns the format of the config file, which is the
+// format hint ("yaml" or "json"), if specified, and otherwise depends
+// on the file's extension. A hint that disagrees with a known
+// extension is an error.
+func resolveFormat(fn, hint string) (amboy.Format, error) {
+	if hint == "" {
+		if fn == StdinConfig {
+			return -1, errors.New("must specify the format of a config read from standard input")
+		}
+
+		format, err := getFormat(fn)
+		if err != nil {
+			return -1, errors.Wrap(err, "specify the config format for files without a known extension")
+		}
+
+		return format, nil
+	}
+
+	var format amboy.Format
+	switch hint {
+	case "yaml":
+		format = amboy.YAML
+	case "json":
+		format = amboy.JSON
+	default:
+		return -1, errors.Errorf("'%s' is not a supported config format, use 'yaml' or 'json'", hint)
+	}
+
+	if fn == StdinConfig {
+		return format, nil
+	}
+
+	if extFormat, err := getFormat(fn); err == nil && extFormat != format {
+		return -1, errors.Errorf("config file '%s' has a '%s' extension, but the config format is '%s'",
+			fn, filepath.Ext(fn), hint)
+	}
+
+	return format, nil
+}
+
 func getJSONFormattedConfig(format amboy.Format, data []byte) ([]byte, error) {
 	var err error
 
@@ -42,15 +85,36 @@ func getJSONFormattedConfig(format amboy.Format, data []byte) ([]byte, error) {
 	return nil, errors.Errorf("%s is not a support format", format)
 }
 
-func getRawConfig(fn string) ([]byte, error) {
-	data, err := ioutil.ReadFile(fn)
+var (
+	stdinOnce sync.Once
+	stdinData []byte
+	stdinErr  error
+)
+
+// readStdin returns the content of standard input, which it only
+// reads once, so that the config can be validated and then read.
+func readStdin() ([]byte, error) {
+	stdinOnce.Do(func() {
+		stdinData, stdinErr = ioutil.ReadAll(os.Stdin)
+	})
+
+	return stdinData, stdinErr
+}
+
+func getRawConfig(fn, hint string) ([]byte, error) {
+	format, err := resolveFormat(fn, hint)
 	if err != nil {
-		return nil, errors.Wrapf(err, "problem reading greenbay config file: %s", fn)
+		return nil, errors.Wrapf(err, "problem determining format of file %s", fn)
 	}
 
-	format, err := getFormat(fn)
+	var data []byte
+	if fn == StdinConfig {
+		data, err = readStdin()
+	} else {
+		data, err = ioutil.ReadFile(fn)
+	}
 	if err != nil {
-		return nil, errors.Wrapf(err, "problem determining format of file %s", fn)
+		return nil, errors.Wrapf(err, "problem reading greenbay config file: %s", fn)
 	}
 
 	return getJSONFormattedConfig(format, data)
@@ -62,6 +126,44 @@ func getRawConfig(fn string) ([]byte, error) {
 //
 ////////////////////////////////////////////////////////////////////////
 
+func (c *GreenbayTestConfig) applyDefaults() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if len(c.Defaults) == 0 {
+		return nil
+	}
+
+	catcher := grip.NewCatcher()
+	for key := range c.Defaults {
+		if fieldDefaults[key] {
+			continue
+		}
+
+		consumed := false
+		for idx := range c.RawTests {
+			if c.RawTests[idx].consumesArg(key) {
+				consumed = true
+				break
+			}
+		}
+
+		if !consumed {
+			catcher.Add(errors.Errorf("default '%s' is not an argument of any check", key))
+		}
+	}
+
+	if catcher.HasErrors() {
+		return catcher.Resolve()
+	}
+
+	for idx := range c.RawTests {
+		catcher.Add(c.RawTests[idx].mergeDefaults(c.Defaults))
+	}
+
+	return catcher.Resolve()
+}
+
 func (c *GreenbayTestConfig) parseTests() error {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
@@ -70,6 +172,19 @@ func (c *GreenbayTestConfig) parseTests() error {
 	for _, msg := range c.RawTests {
 		c.addSuites(msg.Name, msg.Suites)
 
+		if msg.MaxDuration != "" {
+			if dur, err := time.ParseDuration(msg.MaxDuration); err != nil || dur <= 0 {
+				catcher.Add(errors.Errorf("expected max duration '%s' for %s is not a positive duration",
+					msg.MaxDuration, msg.Name))
+				continue
+			}
+		}
+
+		if _, err := msg.retryPolicy(); err != nil {
+			catcher.Add(errors.Wrapf(err, "invalid retries for %s", msg.Name))
+			continue
+		}
+
 		testJob, err := msg.resolveCheck()
 		if err != nil {
 			catcher.Add(errors.Wrapf(err, "problem resolving %s", msg.Name))
@@ -89,6 +204,33 @@ func (c *GreenbayTestConfig) parseTests() error {
 	return catcher.Resolve()
 }
 
+func (c *GreenbayTestConfig) validateSuiteOptions() error {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	catcher := grip.NewCatcher()
+	for name, opts := range c.SuiteOptions {
+		if opts == nil {
+			continue
+		}
+
+		if _, ok := c.suites[name]; !ok {
+			catcher.Add(errors.Errorf("options specified for suite '%s', which does not exist", name))
+		}
+
+		if opts.MinPassPercent != nil && (*opts.MinPassPercent < 0 || *opts.MinPassPercent > 100) {
+			catcher.Add(errors.Errorf("minimum pass percent for suite '%s' must be between 0 and 100, not %g",
+				name, *opts.MinPassPercent))
+		}
+
+		if opts.Workers < 0 {
+			catcher.Add(errors.Errorf("number of workers for suite '%s' cannot be negative", name))
+		}
+	}
+
+	return catcher.Resolve()
+}
+
 // These methods are unsafe, and need to be used within the context a lock.
 
 func (c *GreenbayTestConfig) addSuites(name string, suites []string) {
@@ -34,6 +34,41 @@ func TestGetFormatFromFileName(t *testing.T) {
 
 }
 
+func TestResolveFormatWithHint(t *testing.T) {
+	assert := assert.New(t)
+
+	for fn, expected := range map[string]amboy.Format{
+		"greenbay.conf": amboy.YAML,
+		"greenbay.yml":  amboy.YAML,
+		StdinConfig:     amboy.YAML,
+	} {
+		frmt, err := resolveFormat(fn, "yaml")
+		assert.NoError(err, fn)
+		assert.Equal(expected, frmt, fn)
+	}
+
+	frmt, err := resolveFormat("greenbay", "json")
+	assert.NoError(err)
+	assert.Equal(amboy.JSON, frmt)
+
+	frmt, err = resolveFormat("greenbay.json", "")
+	assert.NoError(err)
+	assert.Equal(amboy.JSON, frmt)
+
+	// hints that disagree with the extension, unknown hints, and
+	// files without a hint or a known extension are errors.
+	for fn, hint := range map[string]string{
+		"greenbay.json": "yaml",
+		"greenbay.yaml": "json",
+		"greenbay.conf": "toml",
+		"greenbay.ini":  "",
+		StdinConfig:     "",
+	} {
+		_, err = resolveFormat(fn, hint)
+		assert.Error(err, fn)
+	}
+}
+
 func TestGetJsonConfig(t *testing.T) {
 	assert := assert.New(t)
 
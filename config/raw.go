@@ -1,50 +1,228 @@
 package config
 
 import (
+	"crypto/sha1"
 	"encoding/json"
+	"fmt"
+	"strings"
 
-	"github.com/mongodb/amboy/registry"
 	"github.com/mongodb/greenbay"
+	"github.com/mongodb/greenbay/check"
 	"github.com/pkg/errors"
 )
 
 type rawTest struct {
-	Name      string          `bson:"name" json:"name" yaml:"name"`
-	Suites    []string        `bson:"suites" json:"suites" yaml:"suites"`
-	Operation string          `bson:"type" json:"type" yaml:"type"`
-	RawArgs   json.RawMessage `bson:"args" json:"args" yaml:"args"`
+	Name      string   `bson:"name" json:"name" yaml:"name"`
+	Suites    []string `bson:"suites" json:"suites" yaml:"suites"`
+	Operation string   `bson:"type" json:"type" yaml:"type"`
+
+	// Order is a hint for the order in which to dispatch the
+	// check, which only takes effect with the "--ordered" option.
+	Order int `bson:"order" json:"order" yaml:"order"`
+
+	// MaxDuration is the expected maximum run time of the check (e.g.
+	// "30s"), and the output marks checks that take longer as over
+	// budget, without failing them.
+	MaxDuration string `bson:"expected_max_duration,omitempty" json:"expected_max_duration,omitempty" yaml:"expected_max_duration,omitempty"`
+
+	// Retries reruns a failed check up to that many times, waiting
+	// RetryDelay (e.g. "5s") between attempts. When RetryOn has
+	// patterns, the check only retries failures with an error or
+	// message that matches one of the patterns; see RetryPolicy.
+	Retries    int      `bson:"retries,omitempty" json:"retries,omitempty" yaml:"retries,omitempty"`
+	RetryOn    []string `bson:"retry_on,omitempty" json:"retry_on,omitempty" yaml:"retry_on,omitempty"`
+	RetryDelay string   `bson:"retry_delay,omitempty" json:"retry_delay,omitempty" yaml:"retry_delay,omitempty"`
+
+	Annotations map[string]string `bson:"annotations" json:"annotations" yaml:"annotations"`
+
+	// Any string argument in RawArgs (e.g. "password") can be read
+	// from a file, with the "_file" suffix (e.g. "password_file"), so
+	// that credentials do not need to appear in the config; see
+	// SecretRef.
+	RawArgs json.RawMessage `bson:"args" json:"args" yaml:"args"`
 }
 
 func (t *rawTest) resolveCheck() (greenbay.Checker, error) {
-	check, err := t.getChecker()
+	checker, err := t.getChecker()
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem building job %s (%s)",
+			t.Name, t.Operation)
+	}
+
+	args, err := resolveSecrets(checker, t.RawArgs)
 	if err != nil {
-		return nil, errors.Wrap(err, "problem determining job type")
+		return nil, errors.Wrapf(err, "problem resolving secrets for %s (%s)",
+			t.Name, t.Operation)
 	}
 
-	if err = json.Unmarshal(t.RawArgs, check); err != nil {
-		return nil, errors.Wrapf(err, "problem parsing argument for job %s (%s)",
+	c, err := check.NewCheckFromJSON(t.Operation, args)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem building job %s (%s)",
 			t.Name, t.Operation)
 	}
 
-	check.SetID(t.Name)
-	check.SetSuites(t.Suites)
+	c.SetID(t.Name)
+	c.SetSuites(t.Suites)
+	c.SetAnnotations(t.Annotations)
 
-	return check, nil
+	return c, nil
 }
 
 func (t *rawTest) getChecker() (greenbay.Checker, error) {
-	factory, err := registry.GetJobFactory(t.Operation)
+	return check.GetChecker(t.Operation)
+}
+
+// fieldDefaults are the keys of the defaults that set fields of the
+// check definition, rather than arguments of the check.
+var fieldDefaults = map[string]bool{
+	"order":                 true,
+	"expected_max_duration": true,
+	"retries":               true,
+	"retry_on":              true,
+	"retry_delay":           true,
+	"annotations":           true,
+}
+
+// splitDefaults separates the defaults for the fields of the check
+// definition from the defaults for the arguments of the check.
+func splitDefaults(defaults map[string]interface{}) (*rawTest, map[string]interface{}, error) {
+	fields := make(map[string]interface{})
+	args := make(map[string]interface{})
+
+	for key, value := range defaults {
+		if fieldDefaults[key] {
+			fields[key] = value
+		} else {
+			args[key] = value
+		}
+	}
+
+	t := &rawTest{}
+	if len(fields) == 0 {
+		return t, args, nil
+	}
+
+	raw, err := json.Marshal(fields)
 	if err != nil {
-		return nil, errors.Wrapf(err, "no test job named %s defined,",
-			t.Operation)
+		return nil, nil, errors.Wrap(err, "problem encoding defaults")
+	}
+
+	if err = json.Unmarshal(raw, t); err != nil {
+		return nil, nil, errors.Wrap(err, "problem parsing defaults")
 	}
 
-	j := factory()
+	return t, args, nil
+}
 
-	c, ok := j.(greenbay.Checker)
-	if !ok {
-		return nil, errors.Errorf("job %s does not implement Checker interface", t.Name)
+// consumesArg reports if the check accepts the argument, either
+// directly, or as a reference to a secret file.
+func (t *rawTest) consumesArg(key string) bool {
+	checker, err := t.getChecker()
+	if err != nil {
+		return false
 	}
 
-	return c, nil
+	fields := stringFields(checker)
+	if _, ok := fields[key]; ok {
+		return true
+	}
+
+	return strings.HasSuffix(key, secretFileSuffix) && fields[strings.TrimSuffix(key, secretFileSuffix)]
+}
+
+// mergeDefaults adds the values in the defaults to the check, for all
+// fields and arguments that the check does not already set. Default
+// annotations are merged with the annotations of the check.
+func (t *rawTest) mergeDefaults(defaults map[string]interface{}) error {
+	fields, defaults, err := splitDefaults(defaults)
+	if err != nil {
+		return errors.Wrapf(err, "problem applying defaults to %s", t.Name)
+	}
+
+	if t.Order == 0 {
+		t.Order = fields.Order
+	}
+	if t.MaxDuration == "" {
+		t.MaxDuration = fields.MaxDuration
+	}
+	if t.Retries == 0 {
+		t.Retries = fields.Retries
+	}
+	if len(t.RetryOn) == 0 {
+		t.RetryOn = fields.RetryOn
+	}
+	if t.RetryDelay == "" {
+		t.RetryDelay = fields.RetryDelay
+	}
+	for key, value := range fields.Annotations {
+		if _, ok := t.Annotations[key]; ok {
+			continue
+		}
+		if t.Annotations == nil {
+			t.Annotations = make(map[string]string)
+		}
+		t.Annotations[key] = value
+	}
+
+	args := make(map[string]interface{})
+
+	if len(t.RawArgs) > 0 {
+		if err := json.Unmarshal(t.RawArgs, &args); err != nil {
+			return errors.Wrapf(err, "problem parsing arguments for %s", t.Name)
+		}
+
+		if args == nil {
+			args = make(map[string]interface{})
+		}
+	}
+
+	for key, value := range defaults {
+		if _, ok := args[key]; !ok {
+			args[key] = value
+		}
+	}
+
+	raw, err := json.Marshal(args)
+	if err != nil {
+		return errors.Wrapf(err, "problem encoding arguments for %s", t.Name)
+	}
+
+	t.RawArgs = raw
+
+	return nil
+}
+
+// definition returns the check definition as it appears in the
+// config file, with references to secret files rather than their
+// values. The arguments are re-encoded, so that formatting and key
+// order in the config file do not impact the definition.
+func (t *rawTest) definition() ([]byte, error) {
+	var args interface{}
+	if len(t.RawArgs) > 0 {
+		if err := json.Unmarshal(t.RawArgs, &args); err != nil {
+			return nil, errors.Wrapf(err, "problem parsing arguments for %s", t.Name)
+		}
+	}
+
+	doc, err := json.Marshal(struct {
+		Name      string      `json:"name"`
+		Suites    []string    `json:"suites"`
+		Operation string      `json:"type"`
+		Args      interface{} `json:"args"`
+	}{t.Name, t.Suites, t.Operation, args})
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem encoding definition of %s", t.Name)
+	}
+
+	return doc, nil
+}
+
+// hash returns a sha1 hash of the check definition.
+func (t *rawTest) hash() (string, error) {
+	doc, err := t.definition()
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%x", sha1.Sum(doc)), nil
 }
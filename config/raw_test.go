@@ -120,3 +120,64 @@ func (s *RawCheckSuite) TestResolveCheckReturnsPopulatedChecker() {
 	s.Equal(s.check.Name, c.Name())
 	s.Equal(s.check.Suites, c.Suites())
 }
+
+func (s *RawCheckSuite) TestResolveCheckSetsAnnotations() {
+	s.check.Annotations = map[string]string{"runbook": "https://example.net/runbook"}
+
+	c, err := s.check.resolveCheck()
+	s.require.NoError(err)
+	s.Equal(s.check.Annotations, c.Annotations())
+	s.Equal(s.check.Annotations, c.Output().Annotations)
+}
+
+func (s *RawCheckSuite) TestMergeDefaultsAddsMissingKeys() {
+	s.check.RawArgs = []byte(`{"message": "check"}`)
+	s.NoError(s.check.mergeDefaults(map[string]interface{}{
+		"message": "default",
+		"passed":  true,
+	}))
+
+	args := map[string]interface{}{}
+	s.NoError(json.Unmarshal(s.check.RawArgs, &args))
+	s.Equal("check", args["message"])
+	s.Equal(true, args["passed"])
+}
+
+func (s *RawCheckSuite) TestMergeDefaultsWithEmptyArguments() {
+	for _, raw := range [][]byte{nil, []byte(`null`), []byte(`{}`)} {
+		s.check.RawArgs = raw
+		s.NoError(s.check.mergeDefaults(map[string]interface{}{"message": "default"}))
+
+		args := map[string]interface{}{}
+		s.NoError(json.Unmarshal(s.check.RawArgs, &args))
+		s.Equal("default", args["message"])
+	}
+}
+
+func (s *RawCheckSuite) TestMergeDefaultsErrorsWithMalformedJson() {
+	s.check.RawArgs = s.check.RawArgs[4:]
+	s.Error(s.check.mergeDefaults(map[string]interface{}{"message": "default"}))
+}
+
+func (s *RawCheckSuite) TestHashIgnoresFormattingButTracksChanges() {
+	s.check.RawArgs = []byte(`{"message": "one", "passed": true}`)
+	first, err := s.check.hash()
+	s.NoError(err)
+	s.NotEqual("", first)
+
+	s.check.RawArgs = []byte(`{ "passed":true,"message":"one" }`)
+	second, err := s.check.hash()
+	s.NoError(err)
+	s.Equal(first, second)
+
+	s.check.RawArgs = []byte(`{"message": "two", "passed": true}`)
+	third, err := s.check.hash()
+	s.NoError(err)
+	s.NotEqual(first, third)
+
+	s.check.Suites = append(s.check.Suites, "three")
+	s.check.RawArgs = []byte(`{"message": "one", "passed": true}`)
+	fourth, err := s.check.hash()
+	s.NoError(err)
+	s.NotEqual(first, fourth)
+}
@@ -0,0 +1,99 @@
+package config
+
+import (
+	"regexp"
+	"time"
+
+	"github.com/mongodb/greenbay"
+	"github.com/pkg/errors"
+)
+
+// RetryPolicy describes when and how often to rerun a failed check.
+// The On patterns are regular expressions, which match anywhere in
+// the text of a failure, so plain substrings (e.g. "connection
+// refused") also work. A policy without patterns retries every
+// failure.
+type RetryPolicy struct {
+	Retries int
+	Delay   time.Duration
+	On      []*regexp.Regexp
+}
+
+// Matches reports if the policy retries a failure with the text,
+// which is the error and message of the check.
+func (p RetryPolicy) Matches(text string) bool {
+	if len(p.On) == 0 {
+		return true
+	}
+
+	for _, pattern := range p.On {
+		if pattern.MatchString(text) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (t *rawTest) retryPolicy() (RetryPolicy, error) {
+	p := RetryPolicy{Retries: t.Retries}
+
+	if t.Retries < 0 {
+		return p, errors.Errorf("retries (%d) must not be negative", t.Retries)
+	}
+
+	if t.Retries == 0 && (len(t.RetryOn) > 0 || t.RetryDelay != "") {
+		return p, errors.New("retry_on and retry_delay require retries")
+	}
+
+	if t.RetryDelay != "" {
+		delay, err := time.ParseDuration(t.RetryDelay)
+		if err != nil || delay < 0 {
+			return p, errors.Errorf("retry delay '%s' is not a duration", t.RetryDelay)
+		}
+		p.Delay = delay
+	}
+
+	for _, expr := range t.RetryOn {
+		pattern, err := regexp.Compile(expr)
+		if err != nil {
+			return p, errors.Wrapf(err, "problem compiling retry pattern '%s'", expr)
+		}
+		p.On = append(p.On, pattern)
+	}
+
+	return p, nil
+}
+
+// CheckRetryPolicy returns the retry policy of the named check. The
+// second value is false for checks that do not retry, or that do not
+// exist.
+func (c *GreenbayTestConfig) CheckRetryPolicy(name string) (RetryPolicy, bool) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	for _, t := range c.RawTests {
+		if t.Name == name && t.Retries > 0 {
+			// parseTests validates the policy.
+			p, _ := t.retryPolicy()
+			return p, true
+		}
+	}
+
+	return RetryPolicy{}, false
+}
+
+// NewCheck builds a new instance of the named check from its
+// definition, which has not run, e.g. to retry the check.
+func (c *GreenbayTestConfig) NewCheck(name string) (greenbay.Checker, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	for _, t := range c.RawTests {
+		if t.Name == name {
+			return t.resolveCheck()
+		}
+	}
+
+	return nil, errors.Errorf("no test named %s", name)
+}
@@ -0,0 +1,172 @@
+package config
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/mongodb/greenbay"
+	"github.com/pkg/errors"
+)
+
+// secretFileSuffix is the suffix of check arguments that name a file
+// that holds the value of another argument.
+const secretFileSuffix = "_file"
+
+// SecretRef is a reference to a file that holds the value of a string
+// argument of a check, so that credentials do not need to appear in
+// the config file. Any string argument (e.g. "password") of any check
+// can be specified as a path with the "_file" suffix (e.g.
+// "password_file"), unless the check has its own argument with that
+// name (e.g. the "pid_file" argument of process checks). The config
+// loader reads the file when it builds the check, and the config
+// itself, including dumps and definition hashes, only has the path.
+type SecretRef struct {
+	Field string
+	Path  string
+}
+
+// Resolve returns the content of the file, without trailing newlines.
+func (r SecretRef) Resolve() (string, error) {
+	data, err := ioutil.ReadFile(r.Path)
+	if err != nil {
+		return "", errors.Wrapf(err, "problem reading secret for '%s' from '%s'", r.Field, r.Path)
+	}
+
+	value := strings.TrimRight(string(data), "\r\n")
+	if value == "" {
+		return "", errors.Errorf("secret file '%s' for '%s' is empty", r.Path, r.Field)
+	}
+
+	return value, nil
+}
+
+// findSecretRefs returns the references to secret files in the
+// arguments for the check.
+func findSecretRefs(c greenbay.Checker, args map[string]interface{}) ([]SecretRef, error) {
+	fields := stringFields(c)
+
+	keys := make([]string, 0, len(args))
+	for key := range args {
+		if strings.HasSuffix(key, secretFileSuffix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var refs []SecretRef
+	for _, key := range keys {
+
+		field := strings.TrimSuffix(key, secretFileSuffix)
+		isString, ok := fields[field]
+		if !ok {
+			continue
+		}
+		if _, ok = fields[key]; ok {
+			continue
+		}
+
+		if !isString {
+			return nil, errors.Errorf("'%s' is not a string argument, and cannot be read from a file", field)
+		}
+
+		if _, ok = args[field]; ok {
+			return nil, errors.Errorf("cannot specify both '%s' and '%s'", field, key)
+		}
+
+		path, ok := args[key].(string)
+		if !ok || path == "" {
+			return nil, errors.Errorf("'%s' must be a path", key)
+		}
+
+		refs = append(refs, SecretRef{Field: field, Path: path})
+	}
+
+	return refs, nil
+}
+
+// stringFields returns the JSON names of the exported fields of the
+// check, mapped to whether the field is a string.
+func stringFields(c greenbay.Checker) map[string]bool {
+	return typeStringFields(reflect.TypeOf(c))
+}
+
+func typeStringFields(t reflect.Type) map[string]bool {
+	fields := make(map[string]bool)
+	embedded := make(map[string]bool)
+
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return fields
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+
+		// the fields of embedded structs without a name, like
+		// fileReadLimit, are arguments of the check, unless the
+		// check has its own field with the same name.
+		if f.Anonymous && name == "" {
+			for field, isString := range typeStringFields(f.Type) {
+				embedded[field] = isString
+			}
+			continue
+		}
+
+		if f.PkgPath != "" || f.Anonymous || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		fields[name] = f.Type.Kind() == reflect.String
+	}
+
+	for field, isString := range embedded {
+		if _, ok := fields[field]; !ok {
+			fields[field] = isString
+		}
+	}
+
+	return fields
+}
+
+// resolveSecrets returns the arguments with the values of all
+// referenced secret files in place of the references.
+func resolveSecrets(c greenbay.Checker, rawArgs json.RawMessage) (json.RawMessage, error) {
+	if len(rawArgs) == 0 {
+		return rawArgs, nil
+	}
+
+	args := make(map[string]interface{})
+	if err := json.Unmarshal(rawArgs, &args); err != nil {
+		return nil, errors.Wrap(err, "problem parsing arguments")
+	}
+
+	refs, err := findSecretRefs(c, args)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(refs) == 0 {
+		return rawArgs, nil
+	}
+
+	for _, ref := range refs {
+		value, err := ref.Resolve()
+		if err != nil {
+			return nil, err
+		}
+
+		delete(args, ref.Field+secretFileSuffix)
+		args[ref.Field] = value
+	}
+
+	return json.Marshal(args)
+}
@@ -0,0 +1,115 @@
+package config
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mongodb/amboy"
+	"github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type SecretRefSuite struct {
+	tmpDir  string
+	secret  string
+	require *require.Assertions
+	suite.Suite
+}
+
+func TestSecretRefSuite(t *testing.T) {
+	suite.Run(t, new(SecretRefSuite))
+}
+
+func (s *SecretRefSuite) SetupSuite() {
+	s.require = s.Require()
+
+	dir, err := ioutil.TempDir("", uuid.NewV4().String())
+	s.require.NoError(err)
+	s.tmpDir = dir
+
+	s.secret = filepath.Join(dir, "ldap-password")
+	s.require.NoError(ioutil.WriteFile(s.secret, []byte("hunter2\n"), 0600))
+	s.require.NoError(ioutil.WriteFile(filepath.Join(dir, "empty"), []byte("\n"), 0600))
+}
+
+func (s *SecretRefSuite) TearDownSuite() {
+	s.require.NoError(os.RemoveAll(s.tmpDir))
+}
+
+func (s *SecretRefSuite) resolve(operation string, args map[string]interface{}) (map[string]interface{}, error) {
+	raw, err := json.Marshal(args)
+	s.require.NoError(err)
+
+	t := &rawTest{Name: "secret-test", Operation: operation, RawArgs: raw}
+	c, err := t.resolveCheck()
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := json.Marshal(c)
+	s.require.NoError(err)
+
+	doc := make(map[string]interface{})
+	s.require.NoError(json.Unmarshal(out, &doc))
+	return doc, nil
+}
+
+func (s *SecretRefSuite) TestSecretFileSetsField() {
+	doc, err := s.resolve("ldap-bind", map[string]interface{}{
+		"url":                "ldap://ldap.example.com",
+		"bind_dn":            "cn=greenbay,dc=example,dc=com",
+		"bind_password_file": s.secret,
+	})
+	s.require.NoError(err)
+	s.Equal("hunter2", doc["bind_password"])
+}
+
+func (s *SecretRefSuite) TestFileArgumentsOfChecksAreNotSecrets() {
+	pidFile := filepath.Join(s.tmpDir, "does-not-exist.pid")
+	doc, err := s.resolve("process-threads", map[string]interface{}{
+		"pid_file": pidFile,
+	})
+	s.require.NoError(err)
+	s.Equal(pidFile, doc["pid_file"])
+}
+
+func (s *SecretRefSuite) TestInvalidSecretsAreErrors() {
+	for name, args := range map[string]map[string]interface{}{
+		"missing": {"bind_password_file": filepath.Join(s.tmpDir, "does-not-exist")},
+		"empty":   {"bind_password_file": filepath.Join(s.tmpDir, "empty")},
+		"both":    {"bind_password_file": s.secret, "bind_password": "hunter2"},
+		"path":    {"bind_password_file": 42},
+	} {
+		_, err := s.resolve("ldap-bind", args)
+		s.Error(err, name)
+	}
+
+	_, err := s.resolve("process-threads", map[string]interface{}{"min_threads_file": s.secret})
+	s.Error(err)
+}
+
+func (s *SecretRefSuite) TestDumpDoesNotIncludeSecrets() {
+	fn := filepath.Join(s.tmpDir, "conf.yaml")
+	s.require.NoError(ioutil.WriteFile(fn, []byte(`
+tests:
+  - name: directory-bind
+    type: ldap-bind
+    suites: [all]
+    args:
+      url: ldap://ldap.example.com
+      bind_dn: cn=greenbay,dc=example,dc=com
+      bind_password_file: `+s.secret+`
+`), 0644))
+
+	conf, err := ReadConfig(fn, "")
+	s.require.NoError(err)
+
+	out, err := conf.Dump(amboy.JSON)
+	s.require.NoError(err)
+	s.NotContains(string(out), "hunter2")
+	s.Contains(string(out), s.secret)
+}
@@ -41,6 +41,11 @@ type Checker interface {
 	SetSuites([]string)
 	Suites() []string
 
+	// Annotations are arbitrary key/value pairs (e.g. links to
+	// runbooks) that are included in the output of the check.
+	SetAnnotations(map[string]string)
+	Annotations() map[string]string
+
 	// Name returns the name of the checker. Use ID(), in the
 	// amboy.Job interface to get a unique identifer for the
 	// task. This is typically the same as the
@@ -57,12 +62,50 @@ type Checker interface {
 type CheckOutput struct {
 	Completed bool
 	Passed    bool
-	Check     string
-	Name      string
-	Message   string
-	Error     string
-	Suites    []string
-	Timing    TimingInfo
+
+	// Skipped checks did not run, and are neither passed nor
+	// failed.
+	Skipped bool
+
+	// Warning is set for checks that failed, but only belong to
+	// warn-only suites, so their failure does not fail the run.
+	Warning bool
+
+	Check       string
+	Name        string
+	Message     string
+	Error       string
+	Suites      []string
+	Annotations map[string]string
+
+	// Host is the host that ran the check, in runs across several
+	// hosts, and is empty for checks that ran locally. Names are only
+	// unique together with the host, so output formats identify checks
+	// by their QualifiedName.
+	Host string `json:",omitempty"`
+
+	Timing TimingInfo
+
+	// ExpectedMaxDuration is the run time that the config allows the
+	// check, if any, and OverBudget is set when the check took longer,
+	// which does not fail the check.
+	ExpectedMaxDuration time.Duration `json:",omitempty"`
+	OverBudget          bool          `json:",omitempty"`
+
+	// ReasonCode is a stable, machine-readable code for the failure of
+	// a check that did not pass, and is empty otherwise.
+	ReasonCode string `json:",omitempty"`
+}
+
+// QualifiedName returns the name of the check, prefixed with the host
+// that ran it, as "<host>/<name>", or the name alone for checks that
+// ran locally.
+func (o CheckOutput) QualifiedName() string {
+	if o.Host == "" {
+		return o.Name
+	}
+
+	return o.Host + "/" + o.Name
 }
 
 // TimingInfo tracks the start and end time for a task.
@@ -74,5 +117,5 @@ type TimingInfo struct {
 // Duration returns a time.Duration for the timing information stored
 // in the TimingInfo object.
 func (t TimingInfo) Duration() time.Duration {
-	return t.Start.Sub(t.End)
+	return t.End.Sub(t.Start)
 }
@@ -2,14 +2,15 @@ package main
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"runtime"
-	"sort"
 	"strings"
 
-	"github.com/mongodb/amboy/registry"
+	"github.com/mongodb/amboy"
 	"github.com/mongodb/greenbay/check"
+	"github.com/mongodb/greenbay/config"
 	"github.com/mongodb/greenbay/operations"
 	"github.com/pkg/errors"
 	"github.com/tychoish/grip"
@@ -45,6 +46,8 @@ func buildApp() *cli.App {
 	app.Commands = []cli.Command{
 		list(),
 		checks(),
+		configCmd(),
+		validate(),
 	}
 
 	// need to call a function in the check package so that the
@@ -59,10 +62,24 @@ func buildApp() *cli.App {
 			Value: "info",
 			Usage: "Specify lowest visible loglevel as string: 'emergency|alert|critical|error|warning|notice|info|debug'",
 		},
+		cli.StringSliceFlag{
+			Name:  "plugin",
+			Usage: "path to a go plugin (.so) that registers additional checks. may specify multiple times",
+		},
 	}
 
 	app.Before = func(c *cli.Context) error {
 		loggingSetup(app.Name, c.String("level"))
+
+		added, err := check.LoadPlugins(c.StringSlice("plugin")...)
+		if err != nil {
+			return errors.Wrap(err, "problem loading plugins")
+		}
+
+		if len(added) > 0 {
+			grip.Infof("plugins registered %d checks: %s", len(added), strings.Join(added, ", "))
+		}
+
 		return nil
 	}
 
@@ -86,16 +103,12 @@ func list() cli.Command {
 		Name:  "list",
 		Usage: "list all available checks",
 		Action: func(c *cli.Context) error {
-			var list []string
-			for name := range registry.JobTypeNames() {
-				list = append(list, name)
-			}
+			list := check.RegisteredChecks()
 
 			if len(list) == 0 {
 				return errors.New("no jobs registered")
 			}
 
-			sort.Strings(list)
 			fmt.Printf("Registered Greenbay Checks:\n\t%s\n",
 				strings.Join(list, "\n\t"))
 
@@ -109,6 +122,7 @@ func checks() cli.Command {
 	defaultNumJobs := runtime.NumCPU()
 	cwd, _ := os.Getwd()
 	configPath := filepath.Join(cwd, "greenbay.yaml")
+	statePath := filepath.Join(cwd, ".greenbay-state.json")
 
 	return cli.Command{
 		Name:  "run",
@@ -123,14 +137,46 @@ func checks() cli.Command {
 			cli.StringFlag{
 				Name: "conf",
 				Usage: fmt.Sprintln("path to config file. '.json', '.yaml', and '.yml' extensions ",
-					"supported.", "Default path:", configPath),
+					"supported. Use '-' to read standard input.", "Default path:", configPath),
 				Value: configPath,
 			},
+			cli.StringFlag{
+				Name:  "config-format",
+				Usage: "format of the config file, 'yaml' or 'json'. Required for files without a known extension",
+			},
 			cli.StringFlag{
 				Name:  "output",
 				Usage: "path of file to write output too. Defaults to *not* writing output to a file",
 				Value: "",
 			},
+			cli.BoolFlag{
+				Name:  "mkdir-output",
+				Usage: "create the parent directory of the output file if it does not exist",
+			},
+			cli.BoolFlag{
+				Name:  "clean-output",
+				Usage: "with the 'dir' format, remove files in the output directory from checks that did not run",
+			},
+			cli.BoolFlag{
+				Name:  "timing-summary",
+				Usage: "with the 'gotest' format, end the output with percentiles of check durations",
+			},
+			cli.BoolFlag{
+				Name:  "sort-by-severity",
+				Usage: "order results with failures first, then warnings, skipped, and passed checks, then by suite and name",
+			},
+			cli.StringFlag{
+				Name: "output-mode",
+				Usage: fmt.Sprintln("'buffered' writes sorted results after all checks complete.",
+					"'streaming' writes results as checks complete, with the 'gotest' and 'evergreen-ndjson'",
+					"formats, but the order of results is not deterministic. By default, 'evergreen-ndjson'",
+					"streams and other formats buffer."),
+			},
+			cli.BoolFlag{
+				Name: "incremental-output",
+				Usage: fmt.Sprintln("write results to the --output file as checks complete, so that a crash",
+					"leaves partial results. Supported by the 'gotest', 'result', and 'evergreen-ndjson' formats"),
+			},
 			cli.BoolFlag{
 				Name:  "quiet",
 				Usage: "specify to disable printed (standard output) results",
@@ -139,9 +185,20 @@ func checks() cli.Command {
 				Name: "format",
 				Usage: fmt.Sprintln("Selects the output format, defaults to a format that mirrors gotest,",
 					"but also supports evergreen's results format.",
-					"Use 'gotest' (default), 'result', or 'log'."),
+					"Use 'gotest' (default), 'result', 'log', 'evergreen-ndjson', 'dir', or 'template'.",
+					"With 'dir', --output is a directory that holds one file per check.",
+					"With 'template', --template or --template-file sets the format."),
 				Value: "gotest",
 			},
+			cli.StringFlag{
+				Name: "template",
+				Usage: fmt.Sprintln("with the 'template' format, a Go text/template rendered for each check.",
+					"A template named \"summary\", if defined, is rendered once after all checks"),
+			},
+			cli.StringFlag{
+				Name:  "template-file",
+				Usage: "with the 'template' format, path of a file that holds the template",
+			},
 			cli.StringSliceFlag{
 				Name:  "test",
 				Usage: "specify a check, by name. may specify multiple times",
@@ -150,6 +207,106 @@ func checks() cli.Command {
 				Name:  "suite",
 				Usage: "specify a suite or suites, by name. if not specified, runs the 'all' suite",
 			},
+			cli.BoolFlag{
+				Name:  "only-changed",
+				Usage: "skip checks whose definitions have not changed since they last passed",
+			},
+			cli.StringFlag{
+				Name:  "state",
+				Usage: "path of the file that records check state between runs, used with --only-changed",
+				Value: statePath,
+			},
+			cli.IntFlag{
+				Name:  "max-failures",
+				Usage: "stop running checks after this many checks fail. 0 (default) runs all checks",
+			},
+			cli.StringFlag{
+				Name:  "since",
+				Usage: "path of a 'result' format file from a prior run. only output checks whose status changed since then",
+			},
+			cli.StringFlag{
+				Name:  "record",
+				Usage: "path of a file to record the definition and output of every check to",
+			},
+			cli.StringFlag{
+				Name:  "replay",
+				Usage: "path of a recording to produce results from, without running any checks",
+			},
+			cli.BoolFlag{
+				Name: "fail-on-empty",
+				Usage: fmt.Sprintln("fail if the selected suites and tests do not match any checks.",
+					"Enabled by default when the CI environment variable is true. Disable with --fail-on-empty=false"),
+				EnvVar: "GREENBAY_FAIL_ON_EMPTY,CI",
+			},
+			cli.StringFlag{
+				Name:  "summary-json",
+				Usage: "path of a file to write a JSON summary of the run to, with the number of checks in each state, the duration, and the exit status",
+			},
+			cli.StringFlag{
+				Name:  "retry-failed",
+				Usage: "path of a 'result' format file from a prior run. only run the checks that failed in that run",
+			},
+			cli.StringFlag{
+				Name:  "hosts",
+				Usage: "path of a file that lists hosts, one per line, to run the checks on over ssh, rather than locally",
+			},
+			cli.IntFlag{
+				Name:  "host-jobs",
+				Usage: "the number of hosts to run checks on at once, with --hosts. Defaults to all hosts",
+			},
+			cli.StringFlag{
+				Name:  "remote-command",
+				Usage: "the command that runs greenbay on remote hosts, with --hosts",
+				Value: "greenbay",
+			},
+			cli.StringSliceFlag{
+				Name:  "ssh-option",
+				Usage: "an option to pass to ssh, e.g. '-oUser=greenbay', with --hosts. Specify multiple times for multiple options",
+			},
+			cli.BoolFlag{
+				Name:  "strict-config",
+				Usage: "fail before running any checks if the config has checks of unknown types, or has warnings, such as checks that are not in any suite",
+			},
+			cli.BoolFlag{
+				Name:  "ordered",
+				Usage: "dispatch checks in ascending order of the 'order' value in their definitions",
+			},
+			cli.BoolFlag{
+				Name:  "suite-pools, parallel-suites",
+				Usage: "give each suite its own pool of --jobs workers, or the suite's 'workers' option, rather than sharing one pool",
+			},
+			cli.BoolFlag{
+				Name:  "suite-serial",
+				Usage: "run suites one at a time, in order, running the checks in each suite in parallel",
+			},
+			cli.StringFlag{
+				Name:  "mongodb-uri",
+				Usage: "connection string of a mongodb deployment to write results to, in addition to other output",
+			},
+			cli.StringFlag{
+				Name:  "mongodb-db",
+				Usage: "name of the database for results written to mongodb",
+				Value: "greenbay",
+			},
+			cli.StringFlag{
+				Name:  "mongodb-collection",
+				Usage: "name of the collection for results written to mongodb",
+				Value: "results",
+			},
+			cli.BoolFlag{
+				Name:  "syslog",
+				Usage: "send the result of each check to syslog, in addition to other output",
+			},
+			cli.StringFlag{
+				Name:  "syslog-facility",
+				Usage: "facility of the syslog messages for results, e.g. 'user' or 'local0'",
+				Value: "user",
+			},
+			cli.StringFlag{
+				Name: "syslog-address",
+				Usage: fmt.Sprintln("address of a remote syslog server, as host:port (udp) or tcp://host:port.",
+					"Defaults to the local syslog service"),
+			},
 		},
 		Action: func(c *cli.Context) error {
 			// Note: in the future in may make sense to
@@ -163,20 +320,269 @@ func checks() cli.Command {
 				suites = append(suites, "all")
 			}
 
-			app, err := operations.NewApp(
-				c.String("conf"),
-				c.String("output"),
-				c.String("format"),
-				c.Bool("quiet"),
-				c.Int("jobs"),
-				suites,
-				tests)
+			if c.Bool("strict-config") && c.String("replay") == "" {
+				if err := config.ValidateCheckTypes(c.String("conf"), c.String("config-format")); err != nil {
+					return errors.Wrap(err, "config is not valid")
+				}
+			}
+
+			var app *operations.GreenbayApp
+			var err error
+			if fn := c.String("replay"); fn != "" {
+				app, err = operations.NewReplayApp(
+					fn,
+					c.String("output"),
+					c.String("format"),
+					c.Bool("quiet"),
+					c.Int("jobs"))
+			} else {
+				app, err = operations.NewApp(
+					c.String("conf"),
+					c.String("config-format"),
+					c.String("output"),
+					c.String("format"),
+					c.Bool("quiet"),
+					c.Int("jobs"),
+					suites,
+					tests)
+			}
 
 			if err != nil {
 				return errors.Wrap(err, "problem prepping to run tests")
 			}
 
+			if app.Conf != nil {
+				if err = lintConfig(app.Conf, c.Bool("strict-config")); err != nil {
+					return errors.Wrap(err, "config is not valid")
+				}
+			}
+
+			app.OnlyChanged = c.Bool("only-changed")
+			app.StateFile = c.String("state")
+			app.Ordered = c.Bool("ordered")
+			app.MaxFailures = c.Int("max-failures")
+			app.RecordFile = c.String("record")
+			app.SuitePools = c.Bool("suite-pools")
+			app.SuiteSerial = c.Bool("suite-serial")
+			app.RetryFile = c.String("retry-failed")
+			app.SummaryFile = c.String("summary-json")
+			app.FailOnEmpty = c.Bool("fail-on-empty")
+
+			if fn := c.String("hosts"); fn != "" {
+				var hosts []string
+				hosts, err = operations.ReadHostsFile(fn)
+				if err != nil {
+					return errors.Wrap(err, "problem prepping to run tests")
+				}
+
+				app.HostRunner = &operations.HostRunner{
+					Hosts:      hosts,
+					NumHosts:   c.Int("host-jobs"),
+					Command:    c.String("remote-command"),
+					SSHOptions: c.StringSlice("ssh-option"),
+				}
+			}
+
+			if c.String("format") == "template" || c.String("template") != "" || c.String("template-file") != "" {
+				var tmpl string
+				tmpl, err = readOutputTemplate(c.String("template"), c.String("template-file"))
+				if err != nil {
+					return errors.Wrap(err, "problem configuring output")
+				}
+
+				if err = app.Output.EnableTemplate(tmpl); err != nil {
+					return errors.Wrap(err, "problem configuring output")
+				}
+			}
+
+			if mode := c.String("output-mode"); mode != "" {
+				if err = app.Output.SetMode(mode); err != nil {
+					return errors.Wrap(err, "problem configuring output")
+				}
+			}
+
+			if c.Bool("incremental-output") {
+				if err = app.Output.EnableIncrementalOutput(); err != nil {
+					return errors.Wrap(err, "problem configuring output")
+				}
+			}
+
+			if c.Bool("clean-output") {
+				app.Output.EnableCleanOutput()
+			}
+
+			if c.Bool("mkdir-output") {
+				app.Output.EnableMakeOutputDir()
+			}
+
+			if c.Bool("timing-summary") {
+				app.Output.EnableTimingSummary()
+			}
+
+			if c.Bool("sort-by-severity") {
+				app.Output.EnableSortBySeverity()
+			}
+
+			if fn := c.String("since"); fn != "" {
+				if err = app.Output.EnableChangedSince(fn); err != nil {
+					return errors.Wrap(err, "problem configuring output")
+				}
+			}
+
+			if uri := c.String("mongodb-uri"); uri != "" {
+				err = app.Output.EnableMongoDB(uri, c.String("mongodb-db"), c.String("mongodb-collection"))
+				if err != nil {
+					return errors.Wrap(err, "problem configuring mongodb output")
+				}
+			}
+
+			if c.Bool("syslog") || c.String("syslog-address") != "" {
+				err = app.Output.EnableSyslog(c.String("syslog-facility"), c.String("syslog-address"))
+				if err != nil {
+					return errors.Wrap(err, "problem configuring syslog output")
+				}
+			}
+
 			return errors.Wrap(app.Run(ctx), "problem running tests")
 		},
 	}
 }
+
+func validate() cli.Command {
+	cwd, _ := os.Getwd()
+	configPath := filepath.Join(cwd, "greenbay.yaml")
+
+	return cli.Command{
+		Name:  "validate",
+		Usage: "check a config for errors, and report warnings, without running any checks",
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "conf",
+				Usage: fmt.Sprintln("path to config file, or '-' for standard input. Default path:", configPath),
+				Value: configPath,
+			},
+			cli.StringFlag{
+				Name:  "config-format",
+				Usage: "format of the config file, 'yaml' or 'json'. Required for files without a known extension",
+			},
+			cli.BoolFlag{
+				Name:  "strict-config",
+				Usage: "treat warnings, such as checks that are not in any suite, as errors",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			fn := c.String("conf")
+
+			if err := config.ValidateCheckTypes(fn, c.String("config-format")); err != nil {
+				return errors.Wrap(err, "config is not valid")
+			}
+
+			conf, err := config.ReadConfig(fn, c.String("config-format"))
+			if err != nil {
+				return errors.Wrap(err, "config is not valid")
+			}
+
+			if err = lintConfig(conf, c.Bool("strict-config")); err != nil {
+				return errors.Wrap(err, "config is not valid")
+			}
+
+			grip.Noticef("config '%s' is valid", fn)
+			return nil
+		},
+	}
+}
+
+// lintConfig logs the warnings about a config, and returns an error
+// if there are warnings and strict is set.
+func lintConfig(conf *config.GreenbayTestConfig, strict bool) error {
+	warnings := conf.Lint()
+	if len(warnings) == 0 {
+		return nil
+	}
+
+	if strict {
+		return errors.Errorf("config has %d warning(s): %s", len(warnings), strings.Join(warnings, "; "))
+	}
+
+	for _, msg := range warnings {
+		grip.Warning(msg)
+	}
+
+	return nil
+}
+
+// readOutputTemplate returns the template for the "template" output
+// format, from the value of the --template flag or the file named by
+// the --template-file flag, which are mutually exclusive.
+func readOutputTemplate(text, fn string) (string, error) {
+	if fn == "" {
+		return text, nil
+	}
+
+	if text != "" {
+		return "", errors.New("cannot specify both --template and --template-file")
+	}
+
+	data, err := ioutil.ReadFile(fn)
+	if err != nil {
+		return "", errors.Wrapf(err, "problem reading template file %s", fn)
+	}
+
+	return string(data), nil
+}
+
+func configCmd() cli.Command {
+	cwd, _ := os.Getwd()
+	configPath := filepath.Join(cwd, "greenbay.yaml")
+
+	return cli.Command{
+		Name:  "config",
+		Usage: "inspect greenbay config files",
+		Subcommands: []cli.Command{
+			{
+				Name:  "dump",
+				Usage: "print the config after defaults are applied",
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "conf",
+						Usage: fmt.Sprintln("path to config file, or '-' for standard input. Default path:", configPath),
+						Value: configPath,
+					},
+					cli.StringFlag{
+						Name:  "config-format",
+						Usage: "format of the config file, 'yaml' or 'json'. Required for files without a known extension",
+					},
+					cli.StringFlag{
+						Name:  "format",
+						Usage: "format of the output, either 'yaml' (default) or 'json'",
+						Value: "yaml",
+					},
+				},
+				Action: func(c *cli.Context) error {
+					var format amboy.Format
+					switch c.String("format") {
+					case "yaml":
+						format = amboy.YAML
+					case "json":
+						format = amboy.JSON
+					default:
+						return errors.Errorf("'%s' is not a supported format", c.String("format"))
+					}
+
+					conf, err := config.ReadConfig(c.String("conf"), c.String("config-format"))
+					if err != nil {
+						return errors.Wrap(err, "problem loading config")
+					}
+
+					out, err := conf.Dump(format)
+					if err != nil {
+						return errors.Wrap(err, "problem rendering config")
+					}
+
+					fmt.Println(strings.TrimRight(string(out), "\n"))
+					return nil
+				},
+			},
+		},
+	}
+}
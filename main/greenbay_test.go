@@ -2,8 +2,13 @@ package main
 
 import (
 	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/mongodb/greenbay/config"
+	"github.com/satori/go.uuid"
 	"github.com/stretchr/testify/suite"
 	"github.com/tychoish/grip"
 	"github.com/tychoish/grip/level"
@@ -69,3 +74,48 @@ func (s *MainSuite) TestChecksActionFunctionReturnsErrorWithoutArguments() {
 	err := checkFunc(ctx)
 	s.Error(err)
 }
+
+func (s *MainSuite) TestLintConfigFailsWithWarningsWhenStrict() {
+	dir, err := ioutil.TempDir("", uuid.NewV4().String())
+	s.Require().NoError(err)
+	defer os.RemoveAll(dir)
+
+	fn := filepath.Join(dir, "conf.yaml")
+	s.Require().NoError(ioutil.WriteFile(fn, []byte(`
+tests:
+  - name: orphan
+    type: shell-operation
+    args: { command: "true" }
+`), 0644))
+
+	conf, err := config.ReadConfig(fn, "")
+	s.Require().NoError(err)
+
+	s.NoError(lintConfig(conf, false))
+	err = lintConfig(conf, true)
+	s.Require().Error(err)
+	s.Contains(err.Error(), "check 'orphan' is not in any suite")
+}
+
+func (s *MainSuite) TestReadOutputTemplate() {
+	dir, err := ioutil.TempDir("", uuid.NewV4().String())
+	s.Require().NoError(err)
+	defer os.RemoveAll(dir)
+
+	fn := filepath.Join(dir, "output.tmpl")
+	s.Require().NoError(ioutil.WriteFile(fn, []byte("{{.Name}}\n"), 0644))
+
+	tmpl, err := readOutputTemplate("{{.Check}}", "")
+	s.NoError(err)
+	s.Equal("{{.Check}}", tmpl)
+
+	tmpl, err = readOutputTemplate("", fn)
+	s.NoError(err)
+	s.Equal("{{.Name}}\n", tmpl)
+
+	_, err = readOutputTemplate("{{.Check}}", fn)
+	s.Error(err)
+
+	_, err = readOutputTemplate("", filepath.Join(dir, "DOES-NOT-EXIST"))
+	s.Error(err)
+}
@@ -15,10 +15,15 @@ output production, test running, and test configuration.
 package operations
 
 import (
+	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/mongodb/amboy"
 	"github.com/mongodb/amboy/queue"
+	"github.com/mongodb/greenbay"
+	"github.com/mongodb/greenbay/check"
 	"github.com/mongodb/greenbay/config"
 	"github.com/mongodb/greenbay/output"
 	"github.com/pkg/errors"
@@ -35,15 +40,102 @@ type GreenbayApp struct {
 	NumWorkers int
 	Tests      []string
 	Suites     []string
+
+	// OnlyChanged skips checks whose definitions have not changed
+	// since the last run in which they passed, as recorded in the
+	// state file at StateFile, which the run updates.
+	OnlyChanged bool
+	StateFile   string
+
+	// Ordered dispatches checks in ascending order of the "order"
+	// value in their definitions, rather than in config file order.
+	// This is a hint, not dependency resolution: with more than one
+	// worker, checks may still run concurrently, though a suite with
+	// its own pool of one worker runs its checks strictly in order.
+	Ordered bool
+
+	// MaxFailures, when greater than zero, stops the run once that
+	// many checks have failed: checks that have not started are
+	// skipped, and Run returns an error. Running checks complete, so
+	// a run with more than one worker may report more failures than
+	// the limit.
+	MaxFailures int
+
+	// RecordFile, if set, is where Run writes the definition and
+	// output of every check. ReplayFile, if set, is a recording that
+	// Run produces results from, rather than running checks. Replays
+	// do not need a config, and ignore Tests, Suites, and the options
+	// that select checks.
+	RecordFile string
+	ReplayFile string
+
+	// By default, all checks share one pool of NumWorkers workers.
+	// SuitePools gives checks requested by name, and each suite, their
+	// own pool of NumWorkers workers, so slow suites do not delay
+	// other suites, which can run up to NumWorkers times the number
+	// of suites checks at once. SuiteSerial runs these groups one
+	// after another, in the order of Suites, which is slower, but
+	// makes results easier to attribute. A check in more than one
+	// suite runs with the first of them. Suites with "workers" in
+	// their suite options have a pool of that many workers, rather
+	// than NumWorkers, which implies SuitePools, so that a suite of
+	// disk heavy checks can run one check at a time while other
+	// suites run many.
+	SuitePools  bool
+	SuiteSerial bool
+
+	// OnResult, if set, is called with the output of each check as
+	// the check completes, from a single goroutine, so it does not
+	// need to be safe for concurrent use. Run waits for the last call
+	// before producing results, and logs panics in the callback.
+	OnResult func(greenbay.CheckOutput)
+
+	// RetryFile, if set, is a results file, as written by the "result"
+	// format, and Run ignores Tests and Suites and only runs the checks
+	// that failed in those results. Run returns an error, without
+	// running any checks, if any of those checks are not in the config.
+	RetryFile string
+
+	// HostRunner, if set, runs the checks in Tests and Suites on each
+	// of its hosts, with NumWorkers workers per host, rather than on
+	// the local host, and Run reports the results of every host
+	// together. Hosts cannot be combined with OnlyChanged or
+	// ReplayFile, and ignore SuitePools, SuiteSerial, Ordered, and
+	// MaxFailures, which apply to the local queue.
+	HostRunner *HostRunner
+
+	// SummaryFile, if set, is where Run writes a JSON summary of the
+	// run before returning, whatever the output format, with the number
+	// of checks in each state, the duration of the run, and its result
+	// and exit status. Run writes the summary even when the run fails
+	// before running checks, in which case all counts are zero.
+	SummaryFile string
+
+	// FailOnEmpty makes Run return an error, which lists the selected
+	// suites and tests, if the selection does not match any checks,
+	// rather than succeeding without running anything. Retrying a
+	// results file without failures, and replaying a recording, are
+	// never empty selections.
+	FailOnEmpty bool
+
+	state    *runState
+	summary  *runSummary
+	failures *failureLimit
+	queued   map[string]struct{}
+
+	// ctx is the context of the current run, which stops checks
+	// that are waiting to retry when the run is canceled.
+	ctx context.Context
 }
 
 // NewApp configures the greenbay application and manages the
 // construction of the main config object as well as the output
 // configuration structure. Returns an error if there are problems
 // constructing either the main config or the output
-// configuration objects.
-func NewApp(confPath, outFn, format string, quiet bool, jobs int, suite, tests []string) (*GreenbayApp, error) {
-	conf, err := config.ReadConfig(confPath)
+// configuration objects. The confFormat overrides the format of the
+// config file, as in config.ReadConfig.
+func NewApp(confPath, confFormat, outFn, format string, quiet bool, jobs int, suite, tests []string) (*GreenbayApp, error) {
+	conf, err := config.ReadConfig(confPath, confFormat)
 	if err != nil {
 		return nil, errors.Wrap(err, "problem parsing config file")
 	}
@@ -64,22 +156,122 @@ func NewApp(confPath, outFn, format string, quiet bool, jobs int, suite, tests [
 	return app, nil
 }
 
+// NewReplayApp configures the greenbay application to produce results
+// from the recording at replayFn, rather than running checks from a
+// config file. Returns an error if there are problems constructing
+// the output configuration.
+func NewReplayApp(replayFn, outFn, format string, quiet bool, jobs int) (*GreenbayApp, error) {
+	out, err := output.NewOptions(outFn, format, quiet)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem generating output definition")
+	}
+
+	app := &GreenbayApp{
+		Output:     out,
+		NumWorkers: jobs,
+		ReplayFile: replayFn,
+	}
+
+	return app, nil
+}
+
 // Run executes all tasks defined in the application, and produces
 // results as described by the output configuration. Returns an error
 // if any test failed and/or if there were any problems with test
 // execution.
-func (a *GreenbayApp) Run(ctx context.Context) error {
-	if a.Conf == nil || a.Output == nil {
+func (a *GreenbayApp) Run(ctx context.Context) (err error) {
+	if a.SummaryFile != "" {
+		start := time.Now()
+		a.summary = nil
+		defer func() {
+			err = a.writeSummary(time.Since(start), err)
+		}()
+	}
+
+	if (a.Conf == nil && a.ReplayFile == "") || a.Output == nil {
 		return errors.New("GreenbayApp is not correctly constructed:" +
 			"system and output configuration must be specified.")
 	}
 
+	if a.ReplayFile != "" && a.OnlyChanged {
+		return errors.New("cannot only run changed checks when replaying a recording")
+	}
+
+	if a.RetryFile != "" {
+		if a.ReplayFile != "" {
+			return errors.New("cannot retry failed checks when replaying a recording")
+		}
+
+		if err := a.selectFailedChecks(); err != nil {
+			return errors.Wrap(err, "problem selecting failed checks to retry")
+		}
+	}
+
+	if a.HostRunner != nil {
+		if a.ReplayFile != "" {
+			return errors.New("cannot run checks on remote hosts when replaying a recording")
+		}
+
+		if a.OnlyChanged {
+			return errors.New("cannot only run changed checks on remote hosts")
+		}
+
+		if len(a.HostRunner.Hosts) == 0 {
+			return errors.New("must specify at least one host to run checks on remote hosts")
+		}
+
+		for _, host := range a.HostRunner.Hosts {
+			if err := validateHost(host); err != nil {
+				return err
+			}
+		}
+	}
+
+	if a.OnlyChanged {
+		if a.StateFile == "" {
+			return errors.New("must specify a state file to only run changed checks")
+		}
+
+		state, err := readState(a.StateFile)
+		if err != nil {
+			return errors.Wrap(err, "problem loading state of previous runs")
+		}
+		a.state = state
+	}
+
+	if a.MaxFailures > 0 {
+		a.failures = &failureLimit{max: a.MaxFailures}
+	}
+
+	a.queued = make(map[string]struct{})
+
 	// make sure we clean up after ourselves if we return early
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
+	a.ctx = ctx
+
+	// checks do not run if the pre-run hook fails.
+	if a.Conf != nil {
+		if hook := a.Conf.PreRunHook(); hook != "" {
+			if err := runHook(ctx, "pre-run", hook, nil); err != nil {
+				return errors.Wrap(err, "not running checks")
+			}
+		}
+	}
 
 	q := queue.NewLocalUnordered(a.NumWorkers)
 
+	if (a.SuitePools || a.SuiteSerial || a.suiteWorkers()) && a.ReplayFile == "" && a.HostRunner == nil {
+		r := newSuiteRunner(a.NumWorkers, a.SuiteSerial, a.checkGroups())
+		if err := r.SetQueue(q); err != nil {
+			return errors.Wrap(err, "problem configuring suite workers")
+		}
+
+		if err := q.SetRunner(r); err != nil {
+			return errors.Wrap(err, "problem configuring suite workers")
+		}
+	}
+
 	if err := q.Start(ctx); err != nil {
 		return errors.Wrap(err, "problem starting workers")
 	}
@@ -87,21 +279,124 @@ func (a *GreenbayApp) Run(ctx context.Context) error {
 	// begin "real" work
 	start := time.Now()
 
-	if err := a.addTests(q); err != nil {
-		return errors.Wrap(err, "problem processing checks from suites")
-	}
+	if a.ReplayFile != "" {
+		if err := a.addReplayedChecks(q); err != nil {
+			return errors.Wrap(err, "problem processing checks from recording")
+		}
+	} else if a.HostRunner != nil {
+		if err := a.addHostChecks(ctx, q); err != nil {
+			return errors.Wrap(err, "problem processing checks from remote hosts")
+		}
+	} else {
+		if err := a.addTests(q); err != nil {
+			return errors.Wrap(err, "problem processing checks from suites")
+		}
 
-	if err := a.addSuites(q); err != nil {
-		return errors.Wrap(err, "problem processing checks from suites")
+		if err := a.addSuites(q); err != nil {
+			return errors.Wrap(err, "problem processing checks from suites")
+		}
 	}
 
 	stats := q.Stats()
+	if a.FailOnEmpty && stats.Total == 0 && a.ReplayFile == "" && a.RetryFile == "" {
+		return errors.Errorf("no checks matched the selection [suites='%s', tests='%s']",
+			strings.Join(a.Suites, ", "), strings.Join(a.Tests, ", "))
+	}
 	grip.Noticef("registered %d jobs, running checks now", stats.Total)
-	q.Wait()
+
+	var notified chan struct{}
+	if a.OnResult != nil {
+		notified = make(chan struct{})
+		go func() {
+			defer close(notified)
+			a.notifyResults(ctx, q)
+		}()
+	}
+
+	// with suite pass thresholds, the thresholds, rather than the
+	// failed checks, determine the result of the run, and the
+	// output only reports problems writing the results.
+	thresholds := len(a.Suites) > 0 && a.ReplayFile == "" && a.hasSuiteThresholds()
+	if thresholds {
+		a.Output.DisableFailureErrors()
+	}
+
+	// in the streaming output mode, or with incremental output,
+	// formats that support it write results as checks complete,
+	// otherwise all results are written after the queue is
+	// complete.
+	var resultsErr error
+	streaming := a.Output.Streaming()
+	if streaming {
+		resultsErr = a.Output.StreamResults(ctx, q)
+	} else {
+		q.Wait()
+	}
+
+	if notified != nil {
+		<-notified
+	}
 
 	grip.Noticef("checks complete in [num=%d, runtime=%s] ", stats.Total, time.Since(start))
-	if err := a.Output.ProduceResults(q); err != nil {
-		return errors.Wrap(err, "problems encountered during tests")
+
+	if a.state != nil {
+		if err := a.saveState(q); err != nil {
+			return errors.Wrap(err, "problem saving state of run")
+		}
+	}
+
+	if a.RecordFile != "" {
+		if err := a.writeRecording(q); err != nil {
+			return errors.Wrap(err, "problem recording run")
+		}
+	}
+
+	if !streaming {
+		resultsErr = a.Output.ProduceResults(q)
+	}
+
+	if len(a.Suites) > 0 && a.ReplayFile == "" {
+		suitesErr := a.evaluateSuites(q)
+
+		if thresholds {
+			catcher := grip.NewCatcher()
+			catcher.Add(resultsErr)
+			catcher.Add(suitesErr)
+			resultsErr = catcher.Resolve()
+		}
+	}
+
+	if a.failures != nil && a.failures.numSkipped() > 0 {
+		msg := fmt.Sprintf("stopped after reaching the limit of %d failures, skipped %d checks",
+			a.MaxFailures, a.failures.numSkipped())
+		grip.Notice(msg)
+
+		if resultsErr == nil {
+			resultsErr = errors.New(msg)
+		} else {
+			resultsErr = errors.Wrap(resultsErr, msg)
+		}
+	}
+
+	summary := summarizeRun(q)
+	a.summary = &summary
+
+	if summary.overBudget > 0 {
+		grip.Noticef("%d check(s) took longer than their expected max duration", summary.overBudget)
+	}
+
+	// the post-run hook gets the result of the run, and the number
+	// of checks in each state, in GREENBAY_* environment
+	// variables. Its failures are logged, rather than returned.
+	if a.Conf != nil {
+		if hook := a.Conf.PostRunHook(); hook != "" {
+			env := summary.env(resultsErr)
+			grip.CatchError(errors.Wrap(runHook(ctx, "post-run", hook, env), "problem running post-run hook"))
+		}
+	}
+
+	if resultsErr != nil {
+		return errors.Wrap(resultsErr, "problems encountered during tests")
 	}
 
 	return nil
@@ -120,14 +415,17 @@ func (a *GreenbayApp) addSuites(q amboy.Queue) error {
 
 	catcher := grip.NewCatcher()
 
+	var jobs []amboy.Job
 	for check := range a.Conf.TestsForSuites(a.Suites...) {
 		if check.Err != nil {
 			catcher.Add(check.Err)
 			continue
 		}
-		catcher.Add(q.Put(check.Job))
+		jobs = append(jobs, a.skipUnchanged(check.Job))
 	}
 
+	catcher.Add(a.putJobs(q, jobs))
+
 	return catcher.Resolve()
 }
 
@@ -142,13 +440,133 @@ func (a *GreenbayApp) addTests(q amboy.Queue) error {
 
 	catcher := grip.NewCatcher()
 
+	var jobs []amboy.Job
 	for check := range a.Conf.TestsByName(a.Tests...) {
 		if check.Err != nil {
 			catcher.Add(check.Err)
 			continue
 		}
-		catcher.Add(q.Put(check.Job))
+		jobs = append(jobs, a.skipUnchanged(check.Job))
 	}
 
+	catcher.Add(a.putJobs(q, jobs))
+
+	return catcher.Resolve()
+}
+
+func (a *GreenbayApp) putJobs(q amboy.Queue, jobs []amboy.Job) error {
+	if a.Ordered {
+		sort.Stable(newJobsByOrder(jobs, a.Conf))
+	}
+
+	if a.queued == nil {
+		a.queued = make(map[string]struct{})
+	}
+
+	catcher := grip.NewCatcher()
+	for _, j := range jobs {
+		// checks requested by name may also be in a suite, and
+		// should only run once.
+		if _, ok := a.queued[j.ID()]; ok {
+			grip.Debugf("check '%s' is already queued", j.ID())
+			continue
+		}
+		a.queued[j.ID()] = struct{}{}
+
+		j = a.retrying(j)
+		j = a.warnOnly(j)
+		j = a.durationBudget(j)
+
+		if a.failures != nil {
+			j = a.failures.wrap(j)
+		}
+
+		catcher.Add(q.Put(j))
+	}
+
+	return catcher.Resolve()
+}
+
+// jobsByOrder sorts jobs by the order hint in the definition of each
+// check in the config. The hints are looked up once, when the sorter
+// is built, rather than in every comparison.
+type jobsByOrder struct {
+	jobs  []amboy.Job
+	order []int
+}
+
+func newJobsByOrder(jobs []amboy.Job, conf *config.GreenbayTestConfig) *jobsByOrder {
+	order := make([]int, len(jobs))
+	for idx, j := range jobs {
+		order[idx] = conf.CheckOrder(j.ID())
+	}
+
+	return &jobsByOrder{jobs: jobs, order: order}
+}
+
+func (s *jobsByOrder) Len() int { return len(s.jobs) }
+func (s *jobsByOrder) Swap(i, j int) {
+	s.jobs[i], s.jobs[j] = s.jobs[j], s.jobs[i]
+	s.order[i], s.order[j] = s.order[j], s.order[i]
+}
+func (s *jobsByOrder) Less(i, j int) bool { return s.order[i] < s.order[j] }
+
+// Helper methods for tracking check state between runs:
+
+func (a *GreenbayApp) skipUnchanged(j amboy.Job) amboy.Job {
+	if a.state == nil {
+		return j
+	}
+
+	c, ok := j.(greenbay.Checker)
+	if !ok {
+		return j
+	}
+
+	hash, err := a.Conf.CheckHash(c.ID())
+	if err != nil {
+		grip.Warning(err)
+		return j
+	}
+
+	if prev, ok := a.state.Checks[c.ID()]; ok && prev == hash {
+		return check.Skip(c, "unchanged since last run")
+	}
+
+	return j
+}
+
+func (a *GreenbayApp) saveState(q amboy.Queue) error {
+	catcher := grip.NewCatcher()
+
+	for j := range q.Results() {
+		c, ok := j.(greenbay.Checker)
+		if !ok {
+			continue
+		}
+
+		out := c.Output()
+		if out.Skipped {
+			continue
+		}
+
+		if !out.Passed {
+			// failed checks should run again, even if their
+			// definitions do not change.
+			delete(a.state.Checks, out.Name)
+			continue
+		}
+
+		hash, err := a.Conf.CheckHash(out.Name)
+		if err != nil {
+			catcher.Add(err)
+			continue
+		}
+
+		a.state.Checks[out.Name] = hash
+	}
+
+	catcher.Add(a.state.write(a.StateFile))
+
 	return catcher.Resolve()
 }
@@ -1,12 +1,23 @@
 package operations
 
 import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/mongodb/amboy"
 	"github.com/mongodb/amboy/queue"
+	"github.com/mongodb/greenbay"
 	"github.com/mongodb/greenbay/check"
 	"github.com/mongodb/greenbay/config"
 	"github.com/mongodb/greenbay/output"
+	"github.com/satori/go.uuid"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 	"golang.org/x/net/context"
@@ -67,13 +78,13 @@ func (s *AppSuite) TestRunFailsWithUninitailizedConfAndOrOutput() {
 }
 
 func (s *AppSuite) TestConsturctorFailsIfConfPathDoesNotExist() {
-	app, err := NewApp("DOES-NOT-EXIST", "", "gotest", true, 3, []string{}, []string{})
+	app, err := NewApp("DOES-NOT-EXIST", "", "", "gotest", true, 3, []string{}, []string{})
 	s.Error(err)
 	s.Nil(app)
 }
 
 func (s *AppSuite) TestConsturctorFailsWithEmptyConfPath() {
-	app, err := NewApp("", "", "gotest", true, 3, []string{}, []string{})
+	app, err := NewApp("", "", "", "gotest", true, 3, []string{}, []string{})
 	s.Error(err)
 	s.Nil(app)
 }
@@ -140,6 +151,842 @@ func (s *AppSuite) TestAddTestHelperErrorsWithoutValidTests() {
 	s.Error(s.app.addTests(q))
 }
 
+func (s *AppSuite) TestOrderedAppSortsJobsByOrderHint() {
+	dir, err := ioutil.TempDir("", uuid.NewV4().String())
+	s.require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	fn := filepath.Join(dir, "conf.yaml")
+	s.require.NoError(ioutil.WriteFile(fn, []byte(`
+tests:
+  - name: third
+    type: file-exists
+    suites: [ "all" ]
+    args: {}
+    order: 10
+  - name: first
+    type: file-exists
+    suites: [ "all" ]
+    args: {}
+    order: -1
+  - name: second-a
+    type: file-exists
+    suites: [ "all" ]
+    args: {}
+  - name: second-b
+    type: file-exists
+    suites: [ "all" ]
+    args: {}
+`), 0644))
+
+	conf, err := config.ReadConfig(fn, "")
+	s.require.NoError(err)
+
+	var jobs []amboy.Job
+	for check := range conf.TestsForSuites("all") {
+		s.require.NoError(check.Err)
+		jobs = append(jobs, check.Job)
+	}
+
+	sort.Stable(newJobsByOrder(jobs, conf))
+
+	var names []string
+	for _, j := range jobs {
+		names = append(names, j.ID())
+	}
+	s.Equal([]string{"first", "second-a", "second-b", "third"}, names)
+}
+
 // TODO: add tests that exercise successful runs and dispatch actual
 // tests and suites,but to do this we'll want to have better mock
 // tests and configs, so holding off on that until MAKE-101
+
+func (s *AppSuite) TestSuitePassThresholdDeterminesResult() {
+	dir, err := ioutil.TempDir("", uuid.NewV4().String())
+	s.require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	out := filepath.Join(dir, "results.txt")
+
+	for percent, passes := range map[int]bool{70: true, 80: false} {
+		fn := filepath.Join(dir, "conf.yaml")
+		s.require.NoError(ioutil.WriteFile(fn, []byte(fmt.Sprintf(`
+suite_options:
+  one:
+    min_pass_percent: %d
+tests:
+  - name: pass-a
+    type: shell-operation
+    suites: [ "one" ]
+    args: { command: "true" }
+  - name: pass-b
+    type: shell-operation
+    suites: [ "one" ]
+    args: { command: "true" }
+  - name: pass-c
+    type: shell-operation
+    suites: [ "one" ]
+    args: { command: "true" }
+  - name: fail
+    type: shell-operation
+    suites: [ "one", "two" ]
+    args: { command: "false" }
+`, percent)), 0644))
+
+		app, err := NewApp(fn, "", out, "gotest", true, 2, []string{"one"}, []string{})
+		s.require.NoError(err)
+
+		err = app.Run(context.Background())
+		if passes {
+			s.NoError(err, fmt.Sprint(percent))
+		} else {
+			s.Error(err, fmt.Sprint(percent))
+		}
+
+		// thresholds do not hide problems writing the results.
+		app, err = NewApp(fn, "", filepath.Join(dir, "DOES-NOT-EXIST", "results.txt"), "gotest", true, 2, []string{"one"}, []string{})
+		s.require.NoError(err)
+		err = app.Run(context.Background())
+		s.Error(err, fmt.Sprint(percent))
+		s.Contains(err.Error(), "problem writing output", fmt.Sprint(percent))
+
+		// suites without a threshold require every check to pass.
+		app, err = NewApp(fn, "", out, "gotest", true, 2, []string{"two"}, []string{})
+		s.require.NoError(err)
+		s.Error(app.Run(context.Background()))
+
+		// checks requested by name are not subject to thresholds.
+		app, err = NewApp(fn, "", out, "gotest", true, 2, []string{"one"}, []string{"fail"})
+		s.require.NoError(err)
+		s.Error(app.Run(context.Background()))
+	}
+}
+
+func (s *AppSuite) TestMaxFailuresSkipsRemainingChecks() {
+	dir, err := ioutil.TempDir("", uuid.NewV4().String())
+	s.require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	fn := filepath.Join(dir, "conf.yaml")
+	conf := "tests:\n"
+	for i := 0; i < 5; i++ {
+		conf += fmt.Sprintf(`  - name: fail-%d
+    type: shell-operation
+    suites: [ "all" ]
+    order: %d
+    args: { command: "false" }
+`, i, i)
+	}
+	s.require.NoError(ioutil.WriteFile(fn, []byte(conf), 0644))
+
+	out := filepath.Join(dir, "results")
+	app, err := NewApp(fn, "", out, "dir", true, 1, []string{"all"}, []string{})
+	s.require.NoError(err)
+	app.Ordered = true
+	app.MaxFailures = 2
+
+	err = app.Run(context.Background())
+	s.require.Error(err)
+	s.Contains(err.Error(), "limit of 2 failures, skipped 3 checks")
+
+	var failed, skipped int
+	for i := 0; i < 5; i++ {
+		data, err := ioutil.ReadFile(filepath.Join(out, fmt.Sprintf("fail-%d.json", i)))
+		s.require.NoError(err)
+
+		result := greenbay.CheckOutput{}
+		s.require.NoError(json.Unmarshal(data, &result))
+		if result.Skipped {
+			skipped++
+		} else if !result.Passed {
+			failed++
+		}
+	}
+	s.Equal(2, failed)
+	s.Equal(3, skipped)
+}
+
+func (s *AppSuite) TestReplayingRecordingReproducesResults() {
+	dir, err := ioutil.TempDir("", uuid.NewV4().String())
+	s.require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	fn := filepath.Join(dir, "conf.yaml")
+	s.require.NoError(ioutil.WriteFile(fn, []byte(`
+tests:
+  - name: passes
+    type: shell-operation
+    suites: [ "all" ]
+    annotations: { runbook: "https://example.net/runbook" }
+    args: { command: "true" }
+  - name: fails
+    type: shell-operation
+    suites: [ "all" ]
+    args: { command: "echo broken; false" }
+`), 0644))
+
+	recording := filepath.Join(dir, "recording.json")
+	recorded := filepath.Join(dir, "recorded")
+	app, err := NewApp(fn, "", recorded, "dir", true, 2, []string{"all"}, []string{})
+	s.require.NoError(err)
+	app.RecordFile = recording
+	s.Error(app.Run(context.Background()))
+
+	replayed := filepath.Join(dir, "replayed")
+	app, err = NewReplayApp(recording, replayed, "dir", true, 2)
+	s.require.NoError(err)
+	s.Nil(app.Conf)
+	s.Error(app.Run(context.Background()))
+
+	for _, name := range []string{"passes.json", "fails.json"} {
+		expected, err := ioutil.ReadFile(filepath.Join(recorded, name))
+		s.require.NoError(err)
+		actual, err := ioutil.ReadFile(filepath.Join(replayed, name))
+		s.require.NoError(err)
+		s.JSONEq(string(expected), string(actual), name)
+	}
+
+	app, err = NewReplayApp(filepath.Join(dir, "DOES-NOT-EXIST"), replayed, "dir", true, 2)
+	s.require.NoError(err)
+	s.Error(app.Run(context.Background()))
+}
+
+func (s *AppSuite) TestRecordingDoesNotIncludeSecrets() {
+	dir, err := ioutil.TempDir("", uuid.NewV4().String())
+	s.require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	secret := filepath.Join(dir, "secret")
+	s.require.NoError(ioutil.WriteFile(secret, []byte("test -n s3cr3t-t0ken\n"), 0600))
+
+	fn := filepath.Join(dir, "conf.yaml")
+	s.require.NoError(ioutil.WriteFile(fn, []byte(fmt.Sprintf(`
+tests:
+  - name: secret
+    type: shell-operation
+    suites: [ "all" ]
+    args: { command_file: %s }
+`, secret)), 0644))
+
+	recording := filepath.Join(dir, "recording.json")
+	app, err := NewApp(fn, "", filepath.Join(dir, "results"), "dir", true, 2, []string{"all"}, []string{})
+	s.require.NoError(err)
+	app.RecordFile = recording
+	s.NoError(app.Run(context.Background()))
+
+	data, err := ioutil.ReadFile(recording)
+	s.require.NoError(err)
+	s.Contains(string(data), secret)
+	s.NotContains(string(data), "s3cr3t-t0ken")
+}
+
+func (s *AppSuite) TestSuiteWorkerPools() {
+	dir, err := ioutil.TempDir("", uuid.NewV4().String())
+	s.require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	log := filepath.Join(dir, "log")
+	fn := filepath.Join(dir, "conf.yaml")
+	s.require.NoError(ioutil.WriteFile(fn, []byte(fmt.Sprintf(`
+tests:
+  - name: slow-a
+    type: shell-operation
+    suites: [ "slow" ]
+    args: { command: "sleep 0.2; echo slow >> %[1]s" }
+  - name: slow-b
+    type: shell-operation
+    suites: [ "slow", "fast" ]
+    args: { command: "sleep 0.2; echo slow >> %[1]s" }
+  - name: fast
+    type: shell-operation
+    suites: [ "fast" ]
+    args: { command: "echo fast >> %[1]s" }
+`, log)), 0644))
+
+	out := filepath.Join(dir, "results")
+
+	for serial, expected := range map[bool]string{
+		false: "fast\nslow\nslow\n",
+		true:  "slow\nslow\nfast\n",
+	} {
+		s.require.NoError(os.RemoveAll(log))
+
+		app, err := NewApp(fn, "", out, "dir", true, 1, []string{"slow", "fast"}, []string{})
+		s.require.NoError(err)
+		app.SuitePools = !serial
+		app.SuiteSerial = serial
+
+		groups := app.checkGroups()
+		s.require.Len(groups, 2)
+		s.Equal(checkGroup{name: "slow", checks: []string{"slow-a", "slow-b"}}, groups[0])
+		s.Equal(checkGroup{name: "fast", checks: []string{"fast"}}, groups[1])
+
+		s.NoError(app.Run(context.Background()))
+
+		data, err := ioutil.ReadFile(log)
+		s.require.NoError(err)
+		s.Equal(expected, string(data), fmt.Sprint(serial))
+	}
+}
+
+func (s *AppSuite) TestSuiteWorkersOverrideJobs() {
+	dir, err := ioutil.TempDir("", uuid.NewV4().String())
+	s.require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	log := filepath.Join(dir, "log")
+	fn := filepath.Join(dir, "conf.yaml")
+	s.require.NoError(ioutil.WriteFile(fn, []byte(fmt.Sprintf(`
+suite_options:
+  disk:
+    workers: 1
+tests:
+  - name: disk-a
+    type: shell-operation
+    suites: [ "disk" ]
+    args: { command: "echo start >> %[1]s; sleep 0.1; echo end >> %[1]s" }
+  - name: disk-b
+    type: shell-operation
+    suites: [ "disk" ]
+    args: { command: "echo start >> %[1]s; sleep 0.1; echo end >> %[1]s" }
+  - name: disk-c
+    type: shell-operation
+    suites: [ "disk" ]
+    args: { command: "echo start >> %[1]s; sleep 0.1; echo end >> %[1]s" }
+  - name: fast
+    type: shell-operation
+    suites: [ "fast" ]
+    args: { command: "true" }
+`, log)), 0644))
+
+	out := filepath.Join(dir, "results")
+	app, err := NewApp(fn, "", out, "dir", true, 4, []string{"disk", "fast"}, []string{})
+	s.require.NoError(err)
+	s.True(app.suiteWorkers())
+
+	groups := app.checkGroups()
+	s.require.Len(groups, 2)
+	s.Equal(checkGroup{name: "disk", checks: []string{"disk-a", "disk-b", "disk-c"}, workers: 1}, groups[0])
+	s.Equal(checkGroup{name: "fast", checks: []string{"fast"}}, groups[1])
+
+	s.NoError(app.Run(context.Background()))
+
+	data, err := ioutil.ReadFile(log)
+	s.require.NoError(err)
+	s.Equal(strings.Repeat("start\nend\n", 3), string(data))
+
+	app.Suites = []string{"fast"}
+	s.False(app.suiteWorkers())
+}
+
+func (s *AppSuite) TestFailOnEmptySelection() {
+	dir, err := ioutil.TempDir("", uuid.NewV4().String())
+	s.require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	fn := filepath.Join(dir, "conf.yaml")
+	s.require.NoError(ioutil.WriteFile(fn, []byte(`
+tests:
+  - name: passes
+    type: shell-operation
+    suites: [ "stable" ]
+    args: { command: "true" }
+`), 0644))
+
+	out := filepath.Join(dir, "results")
+	app, err := NewApp(fn, "", out, "dir", true, 2, []string{}, []string{})
+	s.require.NoError(err)
+	s.NoError(app.Run(context.Background()))
+
+	app.FailOnEmpty = true
+	err = app.Run(context.Background())
+	s.require.Error(err)
+	s.Contains(err.Error(), "no checks matched the selection [suites='', tests='']")
+
+	app.Suites = []string{"stable"}
+	s.NoError(app.Run(context.Background()))
+}
+
+func (s *AppSuite) TestWarnOnlySuitesDoNotFailTheRun() {
+	dir, err := ioutil.TempDir("", uuid.NewV4().String())
+	s.require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	fn := filepath.Join(dir, "conf.yaml")
+	s.require.NoError(ioutil.WriteFile(fn, []byte(`
+suite_options:
+  trial:
+    warn_only: true
+tests:
+  - name: passes
+    type: shell-operation
+    suites: [ "stable" ]
+    args: { command: "true" }
+  - name: trial-fails
+    type: shell-operation
+    suites: [ "trial" ]
+    args: { command: "false" }
+  - name: shared-fails
+    type: shell-operation
+    suites: [ "trial", "stable" ]
+    args: { command: "false" }
+`), 0644))
+
+	out := filepath.Join(dir, "results")
+	app, err := NewApp(fn, "", out, "dir", true, 2, []string{"trial"}, []string{"passes"})
+	s.require.NoError(err)
+	s.Error(app.Run(context.Background()))
+
+	for name, warning := range map[string]bool{"trial-fails": true, "shared-fails": false} {
+		data, err := ioutil.ReadFile(filepath.Join(out, name+".json"))
+		s.require.NoError(err)
+
+		result := greenbay.CheckOutput{}
+		s.require.NoError(json.Unmarshal(data, &result))
+		s.False(result.Passed, name)
+		s.Equal(warning, result.Warning, name)
+	}
+
+	out = filepath.Join(dir, "results.txt")
+	app, err = NewApp(fn, "", out, "gotest", true, 2, []string{"stable"}, []string{"trial-fails"})
+	s.require.NoError(err)
+	s.Error(app.Run(context.Background()))
+
+	// warnings do not count towards the failure limit.
+	app, err = NewApp(fn, "", out, "gotest", true, 2, []string{}, []string{"passes", "trial-fails"})
+	s.require.NoError(err)
+	app.MaxFailures = 1
+	s.NoError(app.Run(context.Background()))
+
+	data, err := ioutil.ReadFile(out)
+	s.require.NoError(err)
+	s.Contains(string(data), "--- WARN: trial-fails")
+}
+
+func (s *AppSuite) TestOnResultIsCalledForEachCheck() {
+	dir, err := ioutil.TempDir("", uuid.NewV4().String())
+	s.require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	fn := filepath.Join(dir, "conf.yaml")
+	conf := "tests:\n"
+	for i := 0; i < 6; i++ {
+		conf += fmt.Sprintf(`  - name: check-%d
+    type: shell-operation
+    suites: [ "all" ]
+    args: { command: "test %d -lt 4" }
+`, i, i)
+	}
+	s.require.NoError(ioutil.WriteFile(fn, []byte(conf), 0644))
+
+	app, err := NewApp(fn, "", filepath.Join(dir, "results.txt"), "gotest", true, 3, []string{"all"}, []string{})
+	s.require.NoError(err)
+
+	// the callback does not use a lock, because calls are
+	// serialized.
+	results := map[string]bool{}
+	app.OnResult = func(out greenbay.CheckOutput) {
+		results[out.Name] = out.Passed
+		if out.Name == "check-0" {
+			panic("callbacks may panic")
+		}
+	}
+
+	s.Error(app.Run(context.Background()))
+	s.Len(results, 6)
+	for i := 0; i < 6; i++ {
+		s.Equal(i < 4, results[fmt.Sprintf("check-%d", i)], i)
+	}
+}
+
+func (s *AppSuite) TestPreAndPostRunHooks() {
+	dir, err := ioutil.TempDir("", uuid.NewV4().String())
+	s.require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	marker := filepath.Join(dir, "marker")
+	summary := filepath.Join(dir, "summary")
+	conf := func(preRun, postRun string) string {
+		fn := filepath.Join(dir, "conf.yaml")
+		s.require.NoError(ioutil.WriteFile(fn, []byte(fmt.Sprintf(`
+options:
+  pre_run: '%s'
+  post_run: '%s'
+tests:
+  - name: needs-marker
+    type: shell-operation
+    suites: [ "all" ]
+    args: { command: "test -f %s" }
+  - name: fails
+    type: shell-operation
+    suites: [ "all" ]
+    args: { command: "false" }
+`, preRun, postRun, marker)), 0644))
+		return fn
+	}
+
+	out := filepath.Join(dir, "results.txt")
+	fn := conf("touch "+marker,
+		"echo $GREENBAY_RESULT $GREENBAY_TOTAL $GREENBAY_PASSED $GREENBAY_FAILED > "+summary)
+	app, err := NewApp(fn, "", out, "gotest", true, 2, []string{"all"}, []string{})
+	s.require.NoError(err)
+	s.Error(app.Run(context.Background()))
+
+	data, err := ioutil.ReadFile(summary)
+	s.require.NoError(err)
+	s.Equal("fail 2 1 1\n", string(data))
+
+	// failed pre-run hooks abort the run.
+	s.require.NoError(os.Remove(summary))
+	fn = conf("exit 1", "touch "+summary)
+	app, err = NewApp(fn, "", out, "gotest", true, 2, []string{"all"}, []string{})
+	s.require.NoError(err)
+	err = app.Run(context.Background())
+	s.require.Error(err)
+	s.Contains(err.Error(), "pre-run hook")
+	_, err = os.Stat(summary)
+	s.True(os.IsNotExist(err))
+
+	// failed post-run hooks do not change the result.
+	app, err = NewApp(conf("true", "exit 1"), "", out, "gotest", true, 2, []string{}, []string{"needs-marker"})
+	s.require.NoError(err)
+	s.NoError(app.Run(context.Background()))
+}
+
+func (s *AppSuite) TestChecksInSeveralSuitesRunOnce() {
+	dir, err := ioutil.TempDir("", uuid.NewV4().String())
+	s.require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	counter := filepath.Join(dir, "counter")
+	fn := filepath.Join(dir, "conf.yaml")
+	s.require.NoError(ioutil.WriteFile(fn, []byte(fmt.Sprintf(`
+tests:
+  - name: shared
+    type: shell-operation
+    suites: [ "one", "two" ]
+    args: { command: "echo run >> %s" }
+  - name: other
+    type: shell-operation
+    suites: [ "two" ]
+    args: { command: "true" }
+`, counter)), 0644))
+
+	out := filepath.Join(dir, "results")
+	app, err := NewApp(fn, "", out, "dir", true, 2, []string{"one", "two"}, []string{"shared"})
+	s.require.NoError(err)
+	s.NoError(app.Run(context.Background()))
+
+	data, err := ioutil.ReadFile(counter)
+	s.require.NoError(err)
+	s.Equal("run\n", string(data))
+
+	data, err = ioutil.ReadFile(filepath.Join(out, "shared.json"))
+	s.require.NoError(err)
+	result := greenbay.CheckOutput{}
+	s.require.NoError(json.Unmarshal(data, &result))
+	s.Equal([]string{"one", "two"}, result.Suites)
+}
+
+func (s *AppSuite) TestRetryFailedOnlyRunsFailedChecks() {
+	dir, err := ioutil.TempDir("", uuid.NewV4().String())
+	s.require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	fn := filepath.Join(dir, "conf.yaml")
+	s.require.NoError(ioutil.WriteFile(fn, []byte(`
+tests:
+  - name: passes
+    type: shell-operation
+    suites: [ "all" ]
+    args: { command: "true" }
+  - name: fails
+    type: shell-operation
+    suites: [ "all" ]
+    args: { command: "false" }
+`), 0644))
+
+	prior := filepath.Join(dir, "prior.json")
+	app, err := NewApp(fn, "", prior, "result", true, 2, []string{"all"}, []string{})
+	s.require.NoError(err)
+	s.Error(app.Run(context.Background()))
+
+	retried := filepath.Join(dir, "retried")
+	app, err = NewApp(fn, "", retried, "dir", true, 2, []string{"all"}, []string{})
+	s.require.NoError(err)
+	app.RetryFile = prior
+	s.Error(app.Run(context.Background()))
+
+	s.Equal([]string{"fails"}, app.Tests)
+	s.Len(app.Suites, 0)
+
+	files, err := filepath.Glob(filepath.Join(retried, "*.json"))
+	s.require.NoError(err)
+	s.Equal([]string{filepath.Join(retried, "fails.json")}, files)
+
+	// checks from the prior results must still be in the config
+	s.require.NoError(ioutil.WriteFile(fn, []byte(`
+tests:
+  - name: passes
+    type: shell-operation
+    suites: [ "all" ]
+    args: { command: "true" }
+`), 0644))
+
+	app, err = NewApp(fn, "", retried, "dir", true, 2, []string{"all"}, []string{})
+	s.require.NoError(err)
+	app.RetryFile = prior
+	err = app.Run(context.Background())
+	s.require.Error(err)
+	s.Contains(err.Error(), "failed check 'fails'")
+
+	app.RetryFile = filepath.Join(dir, "DOES-NOT-EXIST")
+	s.Error(app.Run(context.Background()))
+}
+
+func (s *AppSuite) TestSummaryFileRecordsRun() {
+	dir, err := ioutil.TempDir("", uuid.NewV4().String())
+	s.require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	fn := filepath.Join(dir, "conf.yaml")
+	s.require.NoError(ioutil.WriteFile(fn, []byte(`
+tests:
+  - name: passes
+    type: shell-operation
+    suites: [ "all" ]
+    args: { command: "true" }
+  - name: fails
+    type: shell-operation
+    suites: [ "all" ]
+    args: { command: "false" }
+`), 0644))
+
+	out := filepath.Join(dir, "results.txt")
+	summaryFn := filepath.Join(dir, "summary.json")
+	readSummary := func() *summaryDocument {
+		data, err := ioutil.ReadFile(summaryFn)
+		s.require.NoError(err)
+
+		doc := &summaryDocument{}
+		s.require.NoError(json.Unmarshal(data, doc))
+		return doc
+	}
+
+	app, err := NewApp(fn, "", out, "gotest", true, 2, []string{"all"}, []string{})
+	s.require.NoError(err)
+	app.SummaryFile = summaryFn
+	s.Error(app.Run(context.Background()))
+
+	doc := readSummary()
+	s.Equal("fail", doc.Result)
+	s.Equal(1, doc.ExitCode)
+	s.NotEqual("", doc.Error)
+	s.Equal(2, doc.Total)
+	s.Equal(1, doc.Passed)
+	s.Equal(1, doc.Failed)
+	s.Equal(0, doc.Skipped)
+	s.True(doc.DurationSeconds > 0)
+
+	app, err = NewApp(fn, "", out, "gotest", true, 2, []string{}, []string{"passes"})
+	s.require.NoError(err)
+	app.SummaryFile = summaryFn
+	s.NoError(app.Run(context.Background()))
+
+	doc = readSummary()
+	s.Equal("pass", doc.Result)
+	s.Equal(0, doc.ExitCode)
+	s.Equal("", doc.Error)
+	s.Equal(1, doc.Total)
+	s.Equal(1, doc.Passed)
+
+	// runs that fail before running checks still write a summary
+	app, err = NewApp(fn, "", out, "gotest", true, 2, []string{}, []string{"passes"})
+	s.require.NoError(err)
+	app.SummaryFile = summaryFn
+	app.OnlyChanged = true
+	s.Error(app.Run(context.Background()))
+
+	doc = readSummary()
+	s.Equal("fail", doc.Result)
+	s.Equal(0, doc.Total)
+
+	app, err = NewApp(fn, "", out, "gotest", true, 2, []string{}, []string{"passes"})
+	s.require.NoError(err)
+	app.SummaryFile = filepath.Join(dir, "DOES-NOT-EXIST", "summary.json")
+	s.Error(app.Run(context.Background()))
+}
+
+func (s *AppSuite) TestChecksOverTheirExpectedDurationAreFlagged() {
+	dir, err := ioutil.TempDir("", uuid.NewV4().String())
+	s.require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	fn := filepath.Join(dir, "conf.yaml")
+	s.require.NoError(ioutil.WriteFile(fn, []byte(`
+tests:
+  - name: slow
+    type: shell-operation
+    suites: [ "all" ]
+    args: { command: "sleep 0.2" }
+    expected_max_duration: 10ms
+  - name: fast
+    type: shell-operation
+    suites: [ "all" ]
+    args: { command: "true" }
+    expected_max_duration: 1m
+  - name: unbudgeted
+    type: shell-operation
+    suites: [ "all" ]
+    args: { command: "sleep 0.2" }
+`), 0644))
+
+	out := filepath.Join(dir, "results")
+	app, err := NewApp(fn, "", out, "dir", true, 2, []string{"all"}, []string{})
+	s.require.NoError(err)
+
+	// being over budget does not fail the run.
+	s.NoError(app.Run(context.Background()))
+
+	for name, overBudget := range map[string]bool{"slow": true, "fast": false, "unbudgeted": false} {
+		data, err := ioutil.ReadFile(filepath.Join(out, name+".json"))
+		s.require.NoError(err)
+
+		result := greenbay.CheckOutput{}
+		s.require.NoError(json.Unmarshal(data, &result))
+		s.True(result.Passed, name)
+		s.Equal(overBudget, result.OverBudget, name)
+	}
+
+	s.Equal(1, app.summary.overBudget)
+}
+
+func (s *AppSuite) TestChecksRetryMatchingFailures() {
+	dir, err := ioutil.TempDir("", uuid.NewV4().String())
+	s.require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	marker := filepath.Join(dir, "marker")
+	fn := filepath.Join(dir, "conf.yaml")
+	s.require.NoError(ioutil.WriteFile(fn, []byte(fmt.Sprintf(`
+tests:
+  - name: flaky
+    type: shell-operation
+    suites: [ "all" ]
+    args: { command: "test -f %s || { touch %s; echo connection refused; false; }" }
+    retries: 3
+    retry_on: [ "connection refused", "timed? out" ]
+  - name: unauthorized
+    type: shell-operation
+    suites: [ "all" ]
+    args: { command: "echo 401 unauthorized; false" }
+    retries: 3
+    retry_on: [ "connection refused" ]
+  - name: down
+    type: shell-operation
+    suites: [ "all" ]
+    args: { command: "echo connection refused; false" }
+    retries: 2
+    retry_delay: 10ms
+  - name: stable
+    type: shell-operation
+    suites: [ "all" ]
+    args: { command: "true" }
+    retries: 2
+`, marker, marker)), 0644))
+
+	out := filepath.Join(dir, "results")
+	app, err := NewApp(fn, "", out, "dir", true, 2, []string{"all"}, []string{})
+	s.require.NoError(err)
+	s.Error(app.Run(context.Background()))
+
+	results := map[string]greenbay.CheckOutput{}
+	for _, name := range []string{"flaky", "unauthorized", "down", "stable"} {
+		data, err := ioutil.ReadFile(filepath.Join(out, name+".json"))
+		s.require.NoError(err)
+
+		result := greenbay.CheckOutput{}
+		s.require.NoError(json.Unmarshal(data, &result))
+		results[name] = result
+	}
+
+	s.True(results["flaky"].Passed)
+	s.Contains(results["flaky"].Message, "retried 1 time(s), stopped: passed")
+
+	s.False(results["unauthorized"].Passed)
+	s.Contains(results["unauthorized"].Message, "401 unauthorized")
+	s.Contains(results["unauthorized"].Message, "retried 0 time(s), stopped: failure does not match retry_on")
+
+	s.False(results["down"].Passed)
+	s.Contains(results["down"].Message, "retried 2 time(s), stopped: no retries left")
+
+	s.True(results["stable"].Passed)
+	s.NotContains(results["stable"].Message, "retried")
+}
+
+func (s *AppSuite) TestRetriesStopWhenTheRunIsCanceled() {
+	newCheck := func() (greenbay.Checker, error) {
+		return check.NewCheckFromJSON("shell-operation", []byte(`{"command": "false"}`))
+	}
+
+	c, err := newCheck()
+	s.require.NoError(err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	retrying := &retryingCheck{
+		Checker: c,
+		current: c,
+		ctx:     ctx,
+		policy:  config.RetryPolicy{Retries: 3, Delay: time.Hour},
+		rebuild: newCheck,
+	}
+
+	time.AfterFunc(50*time.Millisecond, cancel)
+	start := time.Now()
+	retrying.Run()
+
+	s.True(time.Since(start) < time.Minute)
+	s.True(retrying.Completed())
+	s.False(retrying.Output().Passed)
+	s.Contains(retrying.Output().Message, "retried 0 time(s), stopped: canceled")
+}
+
+func (s *AppSuite) TestRetriedChecksReportTheFinalAttempt() {
+	dir, err := ioutil.TempDir("", uuid.NewV4().String())
+	s.require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	marker := filepath.Join(dir, "marker")
+	fn := filepath.Join(dir, "conf.yaml")
+	s.require.NoError(ioutil.WriteFile(fn, []byte(fmt.Sprintf(`
+tests:
+  - name: flaky
+    type: shell-operation
+    suites: [ "all" ]
+    args: { command: "test -f %s || { touch %s; echo connection refused; false; }" }
+    retries: 1
+    retry_delay: 200ms
+`, marker, marker)), 0644))
+
+	out := filepath.Join(dir, "results.txt")
+	app, err := NewApp(fn, "", out, "gotest", true, 1, []string{"all"}, []string{})
+	s.require.NoError(err)
+	s.require.NoError(app.Output.SetMode(output.StreamingMode))
+	s.require.NoError(app.Output.EnableIncrementalOutput())
+
+	var results []greenbay.CheckOutput
+	app.OnResult = func(out greenbay.CheckOutput) {
+		results = append(results, out)
+	}
+
+	s.NoError(app.Run(context.Background()))
+
+	s.require.Len(results, 1)
+	s.True(results[0].Passed)
+	s.Contains(results[0].Message, "retried 1 time(s), stopped: passed")
+
+	data, err := ioutil.ReadFile(out)
+	s.require.NoError(err)
+	s.Contains(string(data), "--- PASS: flaky")
+	s.NotContains(string(data), "--- FAIL: flaky")
+}
@@ -0,0 +1,44 @@
+package operations
+
+import (
+	"time"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/greenbay"
+)
+
+// durationBudget wraps checks that have an expected maximum duration
+// in the config, so that their output records the budget, and
+// whether the check exceeded it. Being over budget does not change
+// the result of the check, or of the run.
+func (a *GreenbayApp) durationBudget(j amboy.Job) amboy.Job {
+	c, ok := j.(greenbay.Checker)
+	if !ok || a.Conf == nil {
+		return j
+	}
+
+	budget := a.Conf.CheckExpectedMaxDuration(c.ID())
+	if budget <= 0 {
+		return j
+	}
+
+	return &budgetedCheck{Checker: c, budget: budget}
+}
+
+type budgetedCheck struct {
+	greenbay.Checker
+	budget time.Duration
+}
+
+func (c *budgetedCheck) Output() greenbay.CheckOutput {
+	out := c.Checker.Output()
+	out.ExpectedMaxDuration = c.budget
+
+	// skipped checks, and checks that have not finished, do not
+	// have a meaningful duration.
+	if !out.Skipped && !out.Timing.End.IsZero() && out.Timing.Duration() > c.budget {
+		out.OverBudget = true
+	}
+
+	return out
+}
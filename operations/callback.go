@@ -0,0 +1,67 @@
+package operations
+
+import (
+	"time"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/greenbay"
+	"github.com/pkg/errors"
+	"github.com/tychoish/grip"
+	"golang.org/x/net/context"
+)
+
+// resultInterval is the interval at which the run checks the queue
+// for newly completed checks to pass to OnResult.
+const resultInterval = 50 * time.Millisecond
+
+// notifyResults calls OnResult, from a single goroutine, once for
+// each check in the queue as it completes, and returns once all
+// checks are complete or the context is canceled.
+func (a *GreenbayApp) notifyResults(ctx context.Context, q amboy.Queue) {
+	seen := make(map[string]struct{})
+
+	notify := func() {
+		for j := range q.Results() {
+			if _, ok := seen[j.ID()]; ok {
+				continue
+			}
+			seen[j.ID()] = struct{}{}
+
+			c, ok := j.(greenbay.Checker)
+			if !ok {
+				continue
+			}
+
+			a.callOnResult(c.Output())
+		}
+	}
+
+	for {
+		notify()
+
+		stats := q.Stats()
+		if stats.Completed >= stats.Total {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(resultInterval):
+		}
+	}
+	notify()
+}
+
+// callOnResult calls OnResult, and logs, rather than propagates,
+// panics in the callback, so that problems in the callback do not
+// abort the run.
+func (a *GreenbayApp) callOnResult(out greenbay.CheckOutput) {
+	defer func() {
+		if r := recover(); r != nil {
+			grip.Alert(errors.Errorf("result callback panicked for check '%s': %v", out.QualifiedName(), r))
+		}
+	}()
+
+	a.OnResult(out)
+}
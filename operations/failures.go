@@ -0,0 +1,102 @@
+package operations
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/greenbay"
+	"github.com/mongodb/greenbay/check"
+)
+
+// failureLimit counts the failed checks in a run, so that the run
+// can skip the checks that have not started once the number of
+// failures reaches the limit.
+type failureLimit struct {
+	max     int
+	failed  int
+	skipped int
+	mutex   sync.Mutex
+}
+
+func (l *failureLimit) reached() bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	return l.failed >= l.max
+}
+
+func (l *failureLimit) recordFailure() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.failed++
+}
+
+func (l *failureLimit) recordSkip() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.skipped++
+}
+
+func (l *failureLimit) numSkipped() int {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	return l.skipped
+}
+
+// wrap returns a job that runs the check, unless the limit is
+// reached before the check starts, in which case the check reports
+// that it was skipped. Jobs that are not checks are not wrapped.
+func (l *failureLimit) wrap(j amboy.Job) amboy.Job {
+	c, ok := j.(greenbay.Checker)
+	if !ok {
+		return j
+	}
+
+	return &limitedCheck{Checker: c, limit: l}
+}
+
+type limitedCheck struct {
+	greenbay.Checker
+	limit   *failureLimit
+	skipped greenbay.Checker
+	mutex   sync.RWMutex
+}
+
+func (c *limitedCheck) Run() {
+	if c.limit.reached() {
+		skipped := check.Skip(c.Checker, fmt.Sprintf("not run: the run stopped after %d failures",
+			c.limit.max))
+		skipped.Run()
+		c.limit.recordSkip()
+
+		c.mutex.Lock()
+		c.skipped = skipped
+		c.mutex.Unlock()
+		return
+	}
+
+	c.Checker.Run()
+
+	if out := c.Checker.Output(); !out.Passed && !out.Skipped && !out.Warning {
+		c.limit.recordFailure()
+	}
+}
+
+func (c *limitedCheck) active() greenbay.Checker {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	if c.skipped != nil {
+		return c.skipped
+	}
+
+	return c.Checker
+}
+
+func (c *limitedCheck) Completed() bool              { return c.active().Completed() }
+func (c *limitedCheck) Error() error                 { return c.active().Error() }
+func (c *limitedCheck) Output() greenbay.CheckOutput { return c.active().Output() }
@@ -0,0 +1,91 @@
+package operations
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/greenbay"
+	"github.com/pkg/errors"
+	"github.com/tychoish/grip"
+	"golang.org/x/net/context"
+)
+
+// runSummary counts the outcomes of the checks in a run, which the
+// post-run hook receives in its environment, and the number of checks
+// that exceeded their expected duration.
+type runSummary struct {
+	total      int
+	passed     int
+	failed     int
+	warnings   int
+	skipped    int
+	overBudget int
+}
+
+func summarizeRun(q amboy.Queue) runSummary {
+	s := runSummary{}
+
+	for j := range q.Results() {
+		c, ok := j.(greenbay.Checker)
+		if !ok {
+			continue
+		}
+
+		out := c.Output()
+		s.total++
+
+		if out.OverBudget {
+			s.overBudget++
+		}
+
+		switch {
+		case out.Skipped:
+			s.skipped++
+		case out.Passed:
+			s.passed++
+		case out.Warning:
+			s.warnings++
+		default:
+			s.failed++
+		}
+	}
+
+	return s
+}
+
+// env returns the summary, and the result of the run, as environment
+// variables.
+func (s runSummary) env(runErr error) []string {
+	result := "pass"
+	if runErr != nil {
+		result = "fail"
+	}
+
+	return []string{
+		fmt.Sprintf("GREENBAY_RESULT=%s", result),
+		fmt.Sprintf("GREENBAY_TOTAL=%d", s.total),
+		fmt.Sprintf("GREENBAY_PASSED=%d", s.passed),
+		fmt.Sprintf("GREENBAY_FAILED=%d", s.failed),
+		fmt.Sprintf("GREENBAY_WARNINGS=%d", s.warnings),
+		fmt.Sprintf("GREENBAY_SKIPPED=%d", s.skipped),
+	}
+}
+
+// runHook runs a hook command with the shell, with the environment
+// of greenbay and the additional variables in env, and logs its
+// output.
+func runHook(ctx context.Context, name, command string, env []string) error {
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Env = append(os.Environ(), env...)
+
+	out, err := cmd.CombinedOutput()
+	grip.Infof("%s hook output: %s", name, strings.TrimSpace(string(out)))
+	if err != nil {
+		return errors.Wrapf(err, "%s hook '%s' failed: %s", name, command, strings.TrimSpace(string(out)))
+	}
+
+	return nil
+}
@@ -0,0 +1,274 @@
+package operations
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/greenbay"
+	"github.com/mongodb/greenbay/check"
+	"github.com/pkg/errors"
+	"github.com/tychoish/grip"
+	"golang.org/x/net/context"
+)
+
+// defaultRemoteCommand is the greenbay command that a HostRunner runs
+// on each host, unless it specifies another.
+const defaultRemoteCommand = "greenbay"
+
+// HostRunner runs the checks of a greenbay run on each of a list of
+// remote hosts, rather than on the local host. The runner connects
+// to each host with ssh, and runs greenbay there, with the config of
+// the local run on standard input, so the remote hosts need a
+// greenbay binary, but not the config. Each host runs its checks in
+// its own queue, and the runner connects to at most NumHosts hosts at
+// once, or every host when NumHosts is not greater than zero.
+//
+// The runner reports the output of each check with the Host set, so
+// the results of all hosts appear in one report, where output formats
+// name each check "<host>/<check>". Hosts that greenbay could not run
+// on report a single failed "<host>/greenbay-run" check, which
+// belongs to all of the suites in the run.
+type HostRunner struct {
+	Hosts    []string
+	NumHosts int
+
+	// Command is the shell command that runs greenbay on the
+	// remote hosts, which defaults to "greenbay". SSHOptions are
+	// passed to ssh before the host name.
+	Command    string
+	SSHOptions []string
+
+	// run executes the remote command on a host, and is a hook
+	// for testing.
+	run func(ctx context.Context, host, command string, stdin []byte) ([]byte, error)
+}
+
+// validateHost returns an error for host names that ssh would parse
+// as options.
+func validateHost(host string) error {
+	if strings.HasPrefix(host, "-") {
+		return errors.Errorf("host '%s' is not valid, host names cannot start with '-'", host)
+	}
+
+	return nil
+}
+
+// ReadHostsFile reads a list of hosts from a file, with one host per
+// line. Blank lines, and lines that start with "#", are ignored.
+func ReadHostsFile(fn string) ([]string, error) {
+	f, err := os.Open(fn)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem opening hosts file '%s'", fn)
+	}
+	defer f.Close()
+
+	var hosts []string
+	seen := make(map[string]struct{})
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if _, ok := seen[line]; ok {
+			continue
+		}
+		seen[line] = struct{}{}
+
+		if err = validateHost(line); err != nil {
+			return nil, errors.Wrapf(err, "problem reading hosts file '%s'", fn)
+		}
+
+		hosts = append(hosts, line)
+	}
+
+	if err = scanner.Err(); err != nil {
+		return nil, errors.Wrapf(err, "problem reading hosts file '%s'", fn)
+	}
+
+	if len(hosts) == 0 {
+		return nil, errors.Errorf("hosts file '%s' does not list any hosts", fn)
+	}
+
+	return hosts, nil
+}
+
+// hostRun is the request that a HostRunner sends to every host.
+type hostRun struct {
+	conf   []byte
+	jobs   int
+	tests  []string
+	suites []string
+}
+
+// remoteCommand returns the shell command that runs greenbay on a
+// host and prints the recording of the run. The output of greenbay
+// itself is discarded, since the recording has the output of every
+// check.
+func (r *HostRunner) remoteCommand(req hostRun) string {
+	command := r.Command
+	if command == "" {
+		command = defaultRemoteCommand
+	}
+
+	args := []string{command, "run", "--conf", "-", "--config-format", "json",
+		"--jobs", strconv.Itoa(req.jobs), "--quiet", "--record", `"$f"`}
+	for _, t := range req.tests {
+		args = append(args, "--test", shellQuote(t))
+	}
+	for _, s := range req.suites {
+		args = append(args, "--suite", shellQuote(s))
+	}
+
+	return fmt.Sprintf(`f=$(mktemp) || exit 1; %s >/dev/null 2>&1; cat "$f"; rm -f "$f"`,
+		strings.Join(args, " "))
+}
+
+// shellQuote quotes a string as a single word for a POSIX shell.
+func shellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
+
+func (r *HostRunner) runSSH(ctx context.Context, host, command string, stdin []byte) ([]byte, error) {
+	args := append([]string{"-o", "BatchMode=yes"}, r.SSHOptions...)
+	args = append(args, "--", host, command)
+
+	cmd := exec.CommandContext(ctx, "ssh", args...)
+	cmd.Stdin = bytes.NewReader(stdin)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, errors.Wrap(err, msg)
+		}
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// runHost runs the checks on a single host, and returns the output
+// of every check, or of a single failed check if greenbay did not
+// run.
+func (r *HostRunner) runHost(ctx context.Context, host string, req hostRun) []greenbay.CheckOutput {
+	run := r.run
+	if run == nil {
+		run = r.runSSH
+	}
+
+	start := time.Now()
+	data, err := run(ctx, host, r.remoteCommand(req), req.conf)
+	if err == nil && len(bytes.TrimSpace(data)) == 0 {
+		err = errors.New("greenbay did not produce a recording")
+	}
+
+	rec := &recording{}
+	if err == nil {
+		err = errors.Wrap(json.Unmarshal(data, rec), "problem parsing recording")
+	}
+
+	if err != nil {
+		grip.Warningf("problem running checks on '%s': %+v", host, err)
+
+		return []greenbay.CheckOutput{{
+			Completed:  true,
+			Check:      "remote-run",
+			Name:       "greenbay-run",
+			Host:       host,
+			Suites:     req.suites,
+			Error:      errors.Wrapf(err, "problem running greenbay on '%s'", host).Error(),
+			ReasonCode: check.ReasonRemoteRunFailed,
+			Timing:     greenbay.TimingInfo{Start: start, End: time.Now()},
+		}}
+	}
+
+	out := make([]greenbay.CheckOutput, 0, len(rec.Checks))
+	for _, c := range rec.Checks {
+		c.Output.Host = host
+		out = append(out, c.Output)
+	}
+	grip.Infof("collected the output of %d checks from '%s'", len(out), host)
+
+	return out
+}
+
+// runHosts runs the checks on every host, and returns the output of
+// the checks on all hosts, in the order of the hosts.
+func (r *HostRunner) runHosts(ctx context.Context, req hostRun) []greenbay.CheckOutput {
+	limit := r.NumHosts
+	if limit <= 0 || limit > len(r.Hosts) {
+		limit = len(r.Hosts)
+	}
+
+	sem := make(chan struct{}, limit)
+	results := make([][]greenbay.CheckOutput, len(r.Hosts))
+
+	wg := &sync.WaitGroup{}
+	for idx, host := range r.Hosts {
+		wg.Add(1)
+		go func(idx int, host string) {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				return
+			}
+
+			results[idx] = r.runHost(ctx, host, req)
+		}(idx, host)
+	}
+	wg.Wait()
+
+	var out []greenbay.CheckOutput
+	for _, hostResults := range results {
+		out = append(out, hostResults...)
+	}
+
+	return out
+}
+
+func (a *GreenbayApp) addHostChecks(ctx context.Context, q amboy.Queue) error {
+	conf, err := a.Conf.Dump(amboy.JSON)
+	if err != nil {
+		return errors.Wrap(err, "problem encoding config for remote hosts")
+	}
+
+	jobs := a.NumWorkers
+	if jobs <= 0 {
+		jobs = 1
+	}
+
+	grip.Noticef("running checks on %d hosts", len(a.HostRunner.Hosts))
+	outputs := a.HostRunner.runHosts(ctx, hostRun{
+		conf:   conf,
+		jobs:   jobs,
+		tests:  a.Tests,
+		suites: a.Suites,
+	})
+
+	if err = ctx.Err(); err != nil {
+		return errors.Wrap(err, "problem running checks on remote hosts")
+	}
+
+	catcher := grip.NewCatcher()
+	for _, out := range outputs {
+		catcher.Add(q.Put(check.Replay(out)))
+	}
+
+	return catcher.Resolve()
+}
@@ -0,0 +1,233 @@
+package operations
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/greenbay"
+	"github.com/mongodb/greenbay/config"
+	"github.com/mongodb/greenbay/output"
+	"github.com/pkg/errors"
+	"github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/net/context"
+)
+
+type HostRunnerSuite struct {
+	tmpDir  string
+	require *require.Assertions
+	suite.Suite
+}
+
+func TestHostRunnerSuite(t *testing.T) {
+	suite.Run(t, new(HostRunnerSuite))
+}
+
+func (s *HostRunnerSuite) SetupSuite() {
+	s.require = s.Require()
+
+	dir, err := ioutil.TempDir("", uuid.NewV4().String())
+	s.require.NoError(err)
+	s.tmpDir = dir
+}
+
+func (s *HostRunnerSuite) TearDownSuite() {
+	s.require.NoError(os.RemoveAll(s.tmpDir))
+}
+
+func (s *HostRunnerSuite) recording(outputs ...greenbay.CheckOutput) []byte {
+	rec := &recording{}
+	for _, out := range outputs {
+		rec.Checks = append(rec.Checks, recordedCheck{Definition: []byte("{}"), Output: out})
+	}
+
+	data, err := json.Marshal(rec)
+	s.require.NoError(err)
+
+	return data
+}
+
+func (s *HostRunnerSuite) TestReadHostsFile() {
+	fn := filepath.Join(s.tmpDir, "hosts.txt")
+	s.require.NoError(ioutil.WriteFile(fn, []byte("# fleet\nweb1.example.net\n\n  web2.example.net  \nweb1.example.net\n"), 0644))
+
+	hosts, err := ReadHostsFile(fn)
+	s.NoError(err)
+	s.Equal([]string{"web1.example.net", "web2.example.net"}, hosts)
+
+	empty := filepath.Join(s.tmpDir, "empty.txt")
+	s.require.NoError(ioutil.WriteFile(empty, []byte("# nothing here\n"), 0644))
+	_, err = ReadHostsFile(empty)
+	s.Error(err)
+
+	_, err = ReadHostsFile(filepath.Join(s.tmpDir, "DOES-NOT-EXIST"))
+	s.Error(err)
+
+	option := filepath.Join(s.tmpDir, "option.txt")
+	s.require.NoError(ioutil.WriteFile(option, []byte("web1.example.net\n-oProxyCommand=false\n"), 0644))
+	_, err = ReadHostsFile(option)
+	s.Error(err)
+}
+
+func (s *HostRunnerSuite) TestRemoteCommandQuotesArguments() {
+	r := &HostRunner{}
+	cmd := r.remoteCommand(hostRun{jobs: 4, tests: []string{"it's"}, suites: []string{"all"}})
+
+	s.True(strings.Contains(cmd, "greenbay run --conf - --config-format json --jobs 4"))
+	s.True(strings.Contains(cmd, `--test 'it'\''s'`))
+	s.True(strings.Contains(cmd, "--suite 'all'"))
+	s.True(strings.Contains(cmd, `--record "$f"`))
+
+	r.Command = "/opt/greenbay/bin/greenbay"
+	s.True(strings.HasPrefix(r.remoteCommand(hostRun{jobs: 1}), "f=$(mktemp) || exit 1; /opt/greenbay/bin/greenbay run"))
+}
+
+func (s *HostRunnerSuite) TestRunLimitsConcurrentHosts() {
+	mutex := &sync.Mutex{}
+	running, maxRunning := 0, 0
+
+	r := &HostRunner{
+		Hosts:    []string{"a", "b", "c", "d", "e"},
+		NumHosts: 2,
+		run: func(ctx context.Context, host, command string, stdin []byte) ([]byte, error) {
+			mutex.Lock()
+			running++
+			if running > maxRunning {
+				maxRunning = running
+			}
+			mutex.Unlock()
+
+			time.Sleep(20 * time.Millisecond)
+
+			mutex.Lock()
+			running--
+			mutex.Unlock()
+
+			return s.recording(greenbay.CheckOutput{Name: "check", Passed: true, Completed: true}), nil
+		},
+	}
+
+	out := r.runHosts(context.Background(), hostRun{jobs: 1})
+	s.Len(out, 5)
+	s.Equal(2, maxRunning)
+
+	for idx, host := range r.Hosts {
+		s.Equal(host, out[idx].Host)
+		s.Equal("check", out[idx].Name)
+		s.Equal(host+"/check", out[idx].QualifiedName())
+	}
+}
+
+func (s *HostRunnerSuite) TestUnreachableHostReportsFailedCheck() {
+	r := &HostRunner{
+		Hosts: []string{"up", "down", "broken"},
+		run: func(ctx context.Context, host, command string, stdin []byte) ([]byte, error) {
+			switch host {
+			case "down":
+				return nil, errors.New("ssh: connect to host down port 22: Connection refused")
+			case "broken":
+				return []byte("\n"), nil
+			default:
+				return s.recording(greenbay.CheckOutput{Name: "check", Passed: true, Completed: true}), nil
+			}
+		},
+	}
+
+	out := r.runHosts(context.Background(), hostRun{jobs: 1, suites: []string{"all"}})
+	s.require.Len(out, 3)
+
+	s.Equal("up/check", out[0].QualifiedName())
+	s.True(out[0].Passed)
+
+	s.Equal("down/greenbay-run", out[1].QualifiedName())
+	s.False(out[1].Passed)
+	s.Equal([]string{"all"}, out[1].Suites)
+	s.Contains(out[1].Error, "Connection refused")
+
+	s.Equal("broken/greenbay-run", out[2].QualifiedName())
+	s.False(out[2].Passed)
+	s.Contains(out[2].Error, "did not produce a recording")
+}
+
+func (s *HostRunnerSuite) TestAppProducesCombinedReport() {
+	fn := filepath.Join(s.tmpDir, "conf.yaml")
+	s.require.NoError(ioutil.WriteFile(fn, []byte(`
+tests:
+  - name: passes
+    type: shell-operation
+    suites: [ "all" ]
+    args: { command: "true" }
+`), 0644))
+
+	outDir := filepath.Join(s.tmpDir, "results")
+	app, err := NewApp(fn, "", outDir, "dir", true, 2, []string{"all"}, []string{})
+	s.require.NoError(err)
+
+	mutex := &sync.Mutex{}
+	var sentConf []byte
+	app.HostRunner = &HostRunner{
+		Hosts: []string{"web1", "web2"},
+		run: func(ctx context.Context, host, command string, stdin []byte) ([]byte, error) {
+			mutex.Lock()
+			sentConf = stdin
+			mutex.Unlock()
+
+			return s.recording(greenbay.CheckOutput{
+				Name:      "passes",
+				Check:     "shell-operation",
+				Suites:    []string{"all"},
+				Passed:    host == "web1",
+				Completed: true,
+			}), nil
+		},
+	}
+
+	s.Error(app.Run(context.Background()))
+
+	conf, err := config.ReadConfig(fn, "")
+	s.require.NoError(err)
+	expected, err := conf.Dump(amboy.JSON)
+	s.require.NoError(err)
+	s.JSONEq(string(expected), string(sentConf))
+
+	for _, host := range []string{"web1", "web2"} {
+		data, err := ioutil.ReadFile(filepath.Join(outDir, host+"_passes.json"))
+		s.require.NoError(err, host)
+
+		out := greenbay.CheckOutput{}
+		s.require.NoError(json.Unmarshal(data, &out))
+		s.Equal(host, out.Host)
+		s.Equal(host == "web1", out.Passed)
+	}
+}
+
+func (s *HostRunnerSuite) TestAppRejectsIncompatibleOptions() {
+	out, err := output.NewOptions("", "gotest", true)
+	s.require.NoError(err)
+
+	app := &GreenbayApp{
+		Conf:        &config.GreenbayTestConfig{},
+		Output:      out,
+		OnlyChanged: true,
+		StateFile:   filepath.Join(s.tmpDir, "state.json"),
+		HostRunner:  &HostRunner{Hosts: []string{"web1"}},
+	}
+	s.Error(app.Run(context.Background()))
+
+	app.OnlyChanged = false
+	app.HostRunner.Hosts = nil
+	s.Error(app.Run(context.Background()))
+
+	app.HostRunner.Hosts = []string{"web1", "-oProxyCommand=false"}
+	err = app.Run(context.Background())
+	s.Error(err)
+	s.Contains(err.Error(), "cannot start with '-'")
+}
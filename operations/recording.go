@@ -0,0 +1,96 @@
+package operations
+
+import (
+	"encoding/json"
+	"io/ioutil"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/greenbay"
+	"github.com/mongodb/greenbay/check"
+	"github.com/pkg/errors"
+	"github.com/tychoish/grip"
+)
+
+// recording is the format of the files written with the RecordFile
+// option, and read with the ReplayFile option. Each entry has the
+// definition of the check, for reference, and its output. Definitions
+// come from the config, so that secrets read from files are not
+// recorded.
+type recording struct {
+	Checks []recordedCheck `bson:"checks" json:"checks" yaml:"checks"`
+}
+
+type recordedCheck struct {
+	Definition json.RawMessage      `bson:"definition" json:"definition" yaml:"definition"`
+	Output     greenbay.CheckOutput `bson:"output" json:"output" yaml:"output"`
+}
+
+func (a *GreenbayApp) writeRecording(q amboy.Queue) error {
+	rec := &recording{}
+	catcher := grip.NewCatcher()
+
+	for j := range q.Results() {
+		c, ok := j.(greenbay.Checker)
+		if !ok {
+			continue
+		}
+
+		def, err := a.recordedDefinition(c)
+		if err != nil {
+			catcher.Add(errors.Wrapf(err, "problem encoding definition of '%s'", c.ID()))
+			continue
+		}
+
+		rec.Checks = append(rec.Checks, recordedCheck{
+			Definition: def,
+			Output:     c.Output(),
+		})
+	}
+
+	data, err := json.MarshalIndent(rec, "", "   ")
+	if err != nil {
+		catcher.Add(errors.Wrap(err, "problem encoding recording"))
+		return catcher.Resolve()
+	}
+
+	if err = ioutil.WriteFile(a.RecordFile, data, 0644); err != nil {
+		catcher.Add(errors.Wrapf(err, "problem writing recording to '%s'", a.RecordFile))
+	}
+
+	return catcher.Resolve()
+}
+
+// recordedDefinition returns the definition of the check from the
+// config, or the encoded check, for checks that are not in the config.
+func (a *GreenbayApp) recordedDefinition(c greenbay.Checker) (json.RawMessage, error) {
+	if a.Conf != nil && a.Conf.HasTest(c.ID()) {
+		return a.Conf.CheckDefinition(c.ID())
+	}
+
+	return json.Marshal(c)
+}
+
+func (a *GreenbayApp) addReplayedChecks(q amboy.Queue) error {
+	data, err := ioutil.ReadFile(a.ReplayFile)
+	if err != nil {
+		return errors.Wrapf(err, "problem reading recording '%s'", a.ReplayFile)
+	}
+
+	rec := &recording{}
+	if err = json.Unmarshal(data, rec); err != nil {
+		return errors.Wrapf(err, "problem parsing recording '%s'", a.ReplayFile)
+	}
+
+	jobs := make([]amboy.Job, 0, len(rec.Checks))
+	for _, c := range rec.Checks {
+		jobs = append(jobs, check.Replay(c.Output))
+	}
+	grip.Infof("replaying %d checks from '%s'", len(jobs), a.ReplayFile)
+
+	catcher := grip.NewCatcher()
+	for _, j := range jobs {
+		catcher.Add(q.Put(j))
+	}
+
+	return catcher.Resolve()
+}
@@ -0,0 +1,40 @@
+package operations
+
+import (
+	"github.com/mongodb/greenbay/output"
+	"github.com/pkg/errors"
+	"github.com/tychoish/grip"
+)
+
+// selectFailedChecks replaces the checks and suites of the run with
+// the checks that failed in the results at RetryFile. Returns an
+// error if any of the failed checks are not in the config.
+func (a *GreenbayApp) selectFailedChecks() error {
+	failed, err := output.FailedChecks(a.RetryFile)
+	if err != nil {
+		return errors.Wrap(err, "problem loading prior results")
+	}
+
+	catcher := grip.NewCatcher()
+	for _, name := range failed {
+		if !a.Conf.HasTest(name) {
+			catcher.Add(errors.Errorf("failed check '%s' from '%s' is not in the config",
+				name, a.RetryFile))
+		}
+	}
+
+	if catcher.HasErrors() {
+		return catcher.Resolve()
+	}
+
+	if len(failed) == 0 {
+		grip.Noticef("no checks failed in '%s', there is nothing to retry", a.RetryFile)
+	} else {
+		grip.Noticef("retrying %d failed checks from '%s'", len(failed), a.RetryFile)
+	}
+
+	a.Tests = failed
+	a.Suites = nil
+
+	return nil
+}
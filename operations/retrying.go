@@ -0,0 +1,146 @@
+package operations
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/greenbay"
+	"github.com/mongodb/greenbay/config"
+	"github.com/tychoish/grip"
+	"golang.org/x/net/context"
+)
+
+// retrying wraps checks that have a retry policy in the config, so
+// that failures that match the policy rerun the check.
+func (a *GreenbayApp) retrying(j amboy.Job) amboy.Job {
+	c, ok := j.(greenbay.Checker)
+	if !ok || a.Conf == nil {
+		return j
+	}
+
+	policy, ok := a.Conf.CheckRetryPolicy(c.ID())
+	if !ok {
+		return j
+	}
+
+	ctx := a.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	return &retryingCheck{
+		Checker: c,
+		current: c,
+		ctx:     ctx,
+		policy:  policy,
+		rebuild: func() (greenbay.Checker, error) { return a.Conf.NewCheck(c.ID()) },
+	}
+}
+
+// retryingCheck runs a check, and then runs new instances of the
+// check while the latest attempt fails with an error that the policy
+// retries, up to the number of retries in the policy. The output is
+// the output of the last attempt, with the time of all attempts, and
+// a note of the number of retries, and why they stopped. Retries stop
+// when the context of the run is canceled.
+type retryingCheck struct {
+	greenbay.Checker
+	ctx     context.Context
+	policy  config.RetryPolicy
+	rebuild func() (greenbay.Checker, error)
+
+	current  greenbay.Checker
+	attempts int
+	stopped  string
+	mutex    sync.RWMutex
+}
+
+func (c *retryingCheck) Run() {
+	c.Checker.Run()
+
+	for {
+		c.mutex.RLock()
+		out := c.current.Output()
+		attempts := c.attempts
+		c.mutex.RUnlock()
+
+		reason := ""
+		switch {
+		case out.Passed || out.Skipped:
+			reason = "passed"
+		case attempts >= c.policy.Retries:
+			reason = "no retries left"
+		case c.ctx.Err() != nil:
+			reason = "canceled"
+		case !c.policy.Matches(out.Error + "\n" + out.Message):
+			reason = "failure does not match retry_on"
+		}
+
+		if reason != "" {
+			c.mutex.Lock()
+			c.stopped = reason
+			c.mutex.Unlock()
+			return
+		}
+
+		grip.Infof("retrying check '%s' (%d/%d): %s", c.ID(), attempts+1, c.policy.Retries, out.Error)
+		select {
+		case <-c.ctx.Done():
+			continue
+		case <-time.After(c.policy.Delay):
+		}
+
+		next, err := c.rebuild()
+		if err != nil {
+			c.mutex.Lock()
+			c.stopped = fmt.Sprintf("could not rebuild check: %s", err)
+			c.mutex.Unlock()
+			return
+		}
+		next.Run()
+
+		c.mutex.Lock()
+		c.current = next
+		c.attempts++
+		c.mutex.Unlock()
+	}
+}
+
+// Completed reports whether the check has finished retrying, rather
+// than whether the latest attempt is complete, so that results are
+// not collected between attempts.
+func (c *retryingCheck) Completed() bool {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return c.stopped != ""
+}
+
+func (c *retryingCheck) Error() error {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return c.current.Error()
+}
+
+func (c *retryingCheck) Output() greenbay.CheckOutput {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	out := c.current.Output()
+	out.Timing.Start = c.Checker.Output().Timing.Start
+
+	// checks that passed on the first attempt do not note retries.
+	if c.attempts > 0 || (c.stopped != "" && c.stopped != "passed") {
+		note := fmt.Sprintf("retried %d time(s), stopped: %s", c.attempts, c.stopped)
+		if out.Message == "" {
+			out.Message = note
+		} else {
+			out.Message = out.Message + "\n" + note
+		}
+	}
+
+	return out
+}
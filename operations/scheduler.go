@@ -0,0 +1,247 @@
+package operations
+
+import (
+	"sync"
+	"time"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/greenbay/config"
+	"github.com/pkg/errors"
+	"github.com/tychoish/grip"
+	"golang.org/x/net/context"
+)
+
+// checkGroup is a set of checks, identified by name, that the
+// suiteRunner dispatches to its own pool of workers. When workers is
+// zero, the group has as many workers as the runner's size.
+type checkGroup struct {
+	name    string
+	checks  []string
+	workers int
+}
+
+// checkGroups partitions the checks in the run by suite. Checks
+// requested by name form their own group, ahead of the suites, and a
+// check in more than one suite belongs to the first of them, which
+// mirrors how the flat queue deduplicates checks. Suite groups have
+// the number of workers that the config sets for the suite, if any.
+// Errors, like missing suites, are ignored here and reported when
+// the checks are added to the queue.
+func (a *GreenbayApp) checkGroups() []checkGroup {
+	seen := make(map[string]struct{})
+	groups := []checkGroup{}
+
+	addGroup := func(name string, workers int, jobs <-chan config.JobWithError) {
+		g := checkGroup{name: name, workers: workers}
+		for check := range jobs {
+			if check.Err != nil {
+				continue
+			}
+
+			id := check.Job.ID()
+			if _, ok := seen[id]; ok {
+				continue
+			}
+
+			seen[id] = struct{}{}
+			g.checks = append(g.checks, id)
+		}
+
+		if len(g.checks) > 0 {
+			groups = append(groups, g)
+		}
+	}
+
+	if len(a.Tests) > 0 {
+		addGroup("tests", 0, a.Conf.TestsByName(a.Tests...))
+	}
+
+	for _, suite := range a.Suites {
+		workers, _ := a.Conf.SuiteWorkers(suite)
+		addGroup(suite, workers, a.Conf.TestsForSuites(suite))
+	}
+
+	return groups
+}
+
+// suiteWorkers reports if the config sets the number of workers for
+// any suite in the run, in which case the run gives each suite its
+// own pool, as with SuitePools.
+func (a *GreenbayApp) suiteWorkers() bool {
+	if a.Conf == nil {
+		return false
+	}
+
+	for _, suite := range a.Suites {
+		if _, ok := a.Conf.SuiteWorkers(suite); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// suiteRunner is an amboy.Runner that gives each group of checks an
+// independent pool of workers, so that a slow suite does not hold up
+// the checks in a fast one. When serial is set, the runner runs
+// groups one at a time, in order, and runs the checks within each
+// group in parallel.
+type suiteRunner struct {
+	size     int
+	serial   bool
+	groups   []checkGroup
+	queue    amboy.Queue
+	started  bool
+	canceler context.CancelFunc
+}
+
+func newSuiteRunner(size int, serial bool, groups []checkGroup) *suiteRunner {
+	if size <= 0 {
+		size = 1
+	}
+
+	return &suiteRunner{
+		size:   size,
+		serial: serial,
+		groups: groups,
+	}
+}
+
+func (r *suiteRunner) Started() bool { return r.started }
+
+func (r *suiteRunner) SetQueue(q amboy.Queue) error {
+	if r.started {
+		return errors.New("cannot add new queue after starting a runner")
+	}
+
+	r.queue = q
+	return nil
+}
+
+func (r *suiteRunner) Start(ctx context.Context) error {
+	if r.started {
+		return nil
+	}
+
+	if r.queue == nil {
+		return errors.New("runner must have an embedded queue")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	r.canceler = cancel
+
+	// each group's channel holds all of its checks, so dispatching
+	// never blocks on a group that has not started.
+	channels := make([]chan amboy.Job, len(r.groups))
+	assignments := make(map[string]int)
+	for idx, g := range r.groups {
+		channels[idx] = make(chan amboy.Job, len(g.checks))
+		for _, id := range g.checks {
+			assignments[id] = idx
+		}
+	}
+
+	go r.dispatch(ctx, channels, assignments)
+
+	if r.serial {
+		go func() {
+			for idx := range r.groups {
+				r.runGroup(ctx, r.groups[idx], channels[idx]).Wait()
+			}
+		}()
+	} else {
+		for idx := range r.groups {
+			r.runGroup(ctx, r.groups[idx], channels[idx])
+		}
+	}
+
+	r.started = true
+	grip.Debugf("running up to %d workers for each of %d suites, unless the suite sets its own", r.size, len(r.groups))
+
+	return nil
+}
+
+func (r *suiteRunner) Close() {
+	if r.canceler != nil {
+		r.canceler()
+	}
+}
+
+// dispatch routes jobs from the queue to the channel of their group,
+// and closes each channel once all of the group's jobs have arrived.
+func (r *suiteRunner) dispatch(ctx context.Context, channels []chan amboy.Job, assignments map[string]int) {
+	remaining := make([]int, len(r.groups))
+	for idx, g := range r.groups {
+		remaining[idx] = len(g.checks)
+	}
+
+	for {
+		job := r.queue.Next(ctx)
+		if job == nil {
+			if ctx.Err() != nil {
+				return
+			}
+			continue
+		}
+
+		idx, ok := assignments[job.ID()]
+		if !ok {
+			grip.Warningf("check '%s' is not in a suite, running it outside of the suite pools", job.ID())
+			go func(j amboy.Job) {
+				j.Run()
+				r.queue.Complete(ctx, j)
+			}(job)
+			continue
+		}
+
+		channels[idx] <- job
+		remaining[idx]--
+		if remaining[idx] == 0 {
+			close(channels[idx])
+		}
+	}
+}
+
+// runGroup starts the workers for a group, and returns a wait group
+// that is done when the group's channel is closed and drained.
+func (r *suiteRunner) runGroup(ctx context.Context, g checkGroup, jobs <-chan amboy.Job) *sync.WaitGroup {
+	wg := &sync.WaitGroup{}
+
+	workers := r.size
+	if g.workers > 0 {
+		workers = g.workers
+	}
+
+	if workers > len(g.checks) {
+		workers = len(g.checks)
+	}
+
+	start := time.Now()
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case job, ok := <-jobs:
+					if !ok {
+						return
+					}
+
+					job.Run()
+					r.queue.Complete(ctx, job)
+				}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		grip.Infof("suite '%s' complete in [num=%d, workers=%d, runtime=%s]",
+			g.name, len(g.checks), workers, time.Since(start))
+	}()
+
+	return wg
+}
@@ -0,0 +1,52 @@
+package operations
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+// runState records the definition hashes of checks that passed in
+// previous runs, and supports the "only changed" mode, which skips
+// checks whose definitions have not changed since they last passed.
+type runState struct {
+	Checks map[string]string `bson:"checks" json:"checks" yaml:"checks"`
+}
+
+// readState loads a state file. If the file does not exist, returns
+// an empty state document.
+func readState(fn string) (*runState, error) {
+	s := &runState{}
+
+	data, err := ioutil.ReadFile(fn)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, errors.Wrapf(err, "problem reading state file '%s'", fn)
+	}
+
+	if len(data) > 0 {
+		if err = json.Unmarshal(data, s); err != nil {
+			return nil, errors.Wrapf(err, "problem parsing state file '%s'", fn)
+		}
+	}
+
+	if s.Checks == nil {
+		s.Checks = make(map[string]string)
+	}
+
+	return s, nil
+}
+
+func (s *runState) write(fn string) error {
+	data, err := json.MarshalIndent(s, "", "   ")
+	if err != nil {
+		return errors.Wrap(err, "problem encoding state")
+	}
+
+	if err = ioutil.WriteFile(fn, data, 0644); err != nil {
+		return errors.Wrapf(err, "problem writing state file '%s'", fn)
+	}
+
+	return nil
+}
@@ -0,0 +1,104 @@
+package operations
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mongodb/greenbay/config"
+	"github.com/mongodb/greenbay/output"
+	"github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/net/context"
+)
+
+type StateSuite struct {
+	tmpDir  string
+	require *require.Assertions
+	suite.Suite
+}
+
+func TestStateSuite(t *testing.T) {
+	suite.Run(t, new(StateSuite))
+}
+
+func (s *StateSuite) SetupSuite() {
+	s.require = s.Require()
+
+	dir, err := ioutil.TempDir("", uuid.NewV4().String())
+	s.require.NoError(err)
+	s.tmpDir = dir
+}
+
+func (s *StateSuite) TearDownSuite() {
+	s.require.NoError(os.RemoveAll(s.tmpDir))
+}
+
+func (s *StateSuite) TestReadingMissingStateFileProducesEmptyState() {
+	state, err := readState(filepath.Join(s.tmpDir, "DOES-NOT-EXIST"))
+	s.NoError(err)
+	s.require.NotNil(state)
+	s.Len(state.Checks, 0)
+}
+
+func (s *StateSuite) TestReadingMalformedStateFileErrors() {
+	fn := filepath.Join(s.tmpDir, "malformed")
+	s.require.NoError(ioutil.WriteFile(fn, []byte("{a:1"), 0644))
+
+	state, err := readState(fn)
+	s.Error(err)
+	s.Nil(state)
+}
+
+func (s *StateSuite) TestStateRoundTrips() {
+	fn := filepath.Join(s.tmpDir, "round-trip")
+	state := &runState{Checks: map[string]string{"foo": "abc", "bar": "def"}}
+	s.NoError(state.write(fn))
+
+	loaded, err := readState(fn)
+	s.NoError(err)
+	s.Equal(state.Checks, loaded.Checks)
+}
+
+func (s *StateSuite) TestOnlyChangedRequiresStateFile() {
+	app := &GreenbayApp{
+		OnlyChanged: true,
+		Conf:        &config.GreenbayTestConfig{},
+		Output:      &output.Options{},
+	}
+	s.Error(app.Run(context.Background()))
+}
+
+func (s *StateSuite) TestUnchangedChecksAreSkippedOnSubsequentRuns() {
+	confFn := filepath.Join(s.tmpDir, "conf.yaml")
+	s.require.NoError(ioutil.WriteFile(confFn, []byte(`
+tests:
+  - name: makefile-exists
+    type: file-exists
+    suites: [ "all" ]
+    args:
+      name: ../makefile
+`), 0644))
+
+	stateFn := filepath.Join(s.tmpDir, "state.json")
+	for idx, expected := range []string{"--- PASS", "--- SKIP"} {
+		outFn := filepath.Join(s.tmpDir, fmt.Sprintf("output-%d", idx))
+		app, err := NewApp(confFn, "", outFn, "gotest", true, 2, []string{"all"}, []string{})
+		s.require.NoError(err)
+		app.OnlyChanged = true
+		app.StateFile = stateFn
+
+		s.NoError(app.Run(context.Background()))
+
+		out, err := ioutil.ReadFile(outFn)
+		s.NoError(err)
+		s.Contains(string(out), expected)
+	}
+
+	state, err := readState(stateFn)
+	s.NoError(err)
+	s.Len(state.Checks, 1)
+}
@@ -0,0 +1,150 @@
+package operations
+
+import (
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/greenbay"
+	"github.com/pkg/errors"
+	"github.com/tychoish/grip"
+)
+
+// suiteResult records the number of checks in a suite that passed
+// and failed, and the percentage of checks that must pass for the
+// suite to pass. Skipped checks are not counted.
+type suiteResult struct {
+	name           string
+	passed         int
+	failed         int
+	minPassPercent float64
+}
+
+func (r *suiteResult) passPercent() float64 {
+	total := r.passed + r.failed
+	if total == 0 {
+		return 100
+	}
+
+	return 100 * float64(r.passed) / float64(total)
+}
+
+func (r *suiteResult) ok() bool {
+	return r.passPercent() >= r.minPassPercent
+}
+
+// hasSuiteThresholds reports if the outcome of the run depends on
+// the pass thresholds of the suites, rather than on individual
+// checks, which is the case when the run only includes suites, and
+// at least one of them has a threshold. Such runs succeed when every
+// suite meets its threshold, even if some checks fail. Suites without
+// a threshold require all of their checks to pass.
+func (a *GreenbayApp) hasSuiteThresholds() bool {
+	if len(a.Tests) > 0 {
+		return false
+	}
+
+	for _, name := range a.Suites {
+		if _, ok := a.Conf.SuiteMinPassPercent(name); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (a *GreenbayApp) suiteResults(q amboy.Queue) []*suiteResult {
+	results := make([]*suiteResult, 0, len(a.Suites))
+	bySuite := make(map[string]*suiteResult)
+
+	for _, name := range a.Suites {
+		if _, ok := bySuite[name]; ok {
+			continue
+		}
+
+		r := &suiteResult{name: name, minPassPercent: 100}
+		if percent, ok := a.Conf.SuiteMinPassPercent(name); ok {
+			r.minPassPercent = percent
+		}
+
+		bySuite[name] = r
+		results = append(results, r)
+	}
+
+	for j := range q.Results() {
+		c, ok := j.(greenbay.Checker)
+		if !ok {
+			continue
+		}
+
+		out := c.Output()
+		if out.Skipped {
+			continue
+		}
+
+		for _, name := range out.Suites {
+			r, ok := bySuite[name]
+			if !ok {
+				continue
+			}
+
+			if out.Passed {
+				r.passed++
+			} else {
+				r.failed++
+			}
+		}
+	}
+
+	return results
+}
+
+// evaluateSuites logs the pass ratio of each suite in the run, and
+// returns an error if any suite did not meet its pass threshold.
+// Warn-only suites never fail.
+func (a *GreenbayApp) evaluateSuites(q amboy.Queue) error {
+	numFailed := 0
+
+	for _, r := range a.suiteResults(q) {
+		status := "PASSED"
+		if !r.ok() {
+			if a.Conf.SuiteWarnOnly(r.name) {
+				status = "WARNING"
+			} else {
+				status = "FAILED"
+				numFailed++
+			}
+		}
+
+		grip.Noticef("%s: suite '%s' [passed=%d/%d (%.1f%%), required=%.1f%%]",
+			status, r.name, r.passed, r.passed+r.failed, r.passPercent(), r.minPassPercent)
+	}
+
+	if numFailed > 0 {
+		return errors.Errorf("%d suite(s) did not meet their pass threshold", numFailed)
+	}
+
+	return nil
+}
+
+// warnOnly wraps checks that only belong to warn-only suites, so that
+// their failures are reported as warnings, which appear in the
+// output, but do not fail the run.
+func (a *GreenbayApp) warnOnly(j amboy.Job) amboy.Job {
+	c, ok := j.(greenbay.Checker)
+	if !ok || !a.Conf.CheckWarnOnly(c.ID()) {
+		return j
+	}
+
+	return &warnOnlyCheck{Checker: c}
+}
+
+type warnOnlyCheck struct {
+	greenbay.Checker
+}
+
+func (c *warnOnlyCheck) Output() greenbay.CheckOutput {
+	out := c.Checker.Output()
+	if !out.Passed && !out.Skipped {
+		out.Warning = true
+	}
+
+	return out
+}
@@ -0,0 +1,72 @@
+package operations
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/tychoish/grip"
+)
+
+// summaryDocument is the format of the file written with the
+// SummaryFile option, which is a stable format for scripts, unlike
+// the output formats.
+type summaryDocument struct {
+	Result          string  `bson:"result" json:"result" yaml:"result"`
+	ExitCode        int     `bson:"exit_code" json:"exit_code" yaml:"exit_code"`
+	Error           string  `bson:"error,omitempty" json:"error,omitempty" yaml:"error,omitempty"`
+	Total           int     `bson:"total" json:"total" yaml:"total"`
+	Passed          int     `bson:"passed" json:"passed" yaml:"passed"`
+	Failed          int     `bson:"failed" json:"failed" yaml:"failed"`
+	Warnings        int     `bson:"warnings" json:"warnings" yaml:"warnings"`
+	Skipped         int     `bson:"skipped" json:"skipped" yaml:"skipped"`
+	DurationSeconds float64 `bson:"duration_secs" json:"duration_secs" yaml:"duration_secs"`
+}
+
+func newSummaryDocument(s runSummary, dur time.Duration, runErr error) *summaryDocument {
+	doc := &summaryDocument{
+		Result:          "pass",
+		Total:           s.total,
+		Passed:          s.passed,
+		Failed:          s.failed,
+		Warnings:        s.warnings,
+		Skipped:         s.skipped,
+		DurationSeconds: dur.Seconds(),
+	}
+
+	if runErr != nil {
+		doc.Result = "fail"
+		doc.ExitCode = 1
+		doc.Error = runErr.Error()
+	}
+
+	return doc
+}
+
+// writeSummary writes the summary of the run to the SummaryFile, and
+// returns the error of the run, or the error writing the summary if
+// the run succeeded.
+func (a *GreenbayApp) writeSummary(dur time.Duration, runErr error) error {
+	s := runSummary{}
+	if a.summary != nil {
+		s = *a.summary
+	}
+
+	data, err := json.MarshalIndent(newSummaryDocument(s, dur, runErr), "", "   ")
+	if err == nil {
+		err = ioutil.WriteFile(a.SummaryFile, append(data, '\n'), 0644)
+	}
+
+	if err != nil {
+		err = errors.Wrapf(err, "problem writing run summary to '%s'", a.SummaryFile)
+		if runErr != nil {
+			grip.Error(err)
+			return runErr
+		}
+
+		return err
+	}
+
+	return runErr
+}
@@ -1,6 +1,7 @@
 package output
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/mongodb/amboy"
@@ -54,3 +55,52 @@ func TestJobToCheckGenerator(t *testing.T) {
 
 	close(input)
 }
+
+func TestAnnotationsAreRenderedInOutput(t *testing.T) {
+	assert := assert.New(t)
+
+	out := greenbay.CheckOutput{
+		Name: "annotated",
+		Annotations: map[string]string{
+			"runbook":   "https://example.net/runbook",
+			"dashboard": "https://example.net/dashboard",
+		},
+	}
+
+	assert.Equal("dashboard=https://example.net/dashboard, runbook=https://example.net/runbook",
+		formatAnnotations(out.Annotations))
+	assert.Equal("", formatAnnotations(nil))
+
+	buf := &bytes.Buffer{}
+	printTestResult(buf, out)
+	assert.Contains(buf.String(), "    see: https://example.net/runbook (runbook)\n")
+
+	buf.Reset()
+	assert.NoError((&EvergreenNDJSON{}).Stream(buf, out))
+	assert.Contains(buf.String(), `"annotations":{"dashboard":`)
+}
+
+func TestWarningsAreRenderedInOutput(t *testing.T) {
+	assert := assert.New(t)
+
+	out := greenbay.CheckOutput{
+		Name:    "trial",
+		Warning: true,
+		Error:   "broken",
+	}
+
+	buf := &bytes.Buffer{}
+	assert.True(printTestResult(buf, out))
+	assert.Contains(buf.String(), "--- WARN: trial")
+
+	buf.Reset()
+	assert.NoError((&EvergreenNDJSON{}).Stream(buf, out))
+	assert.Contains(buf.String(), `"severity":"warning"`)
+	assert.Contains(buf.String(), "WARNING: 'trial'")
+
+	doc := &resultsDocument{}
+	doc.addItem(out)
+	assert.False(doc.failed)
+	assert.Equal("silentfail", doc.Results[0].Status)
+	assert.Equal(0, doc.Results[0].Code)
+}
@@ -1,6 +1,10 @@
 package output
 
 import (
+	"fmt"
+	"sort"
+	"strings"
+
 	"github.com/mongodb/amboy"
 	"github.com/mongodb/greenbay"
 	"github.com/pkg/errors"
@@ -39,6 +43,27 @@ func jobsToCheck(jobs <-chan amboy.Job) <-chan workUnit {
 	return output
 }
 
+// annotationKeys returns the keys of the annotations map in sorted
+// order, so that output is consistent between runs.
+func annotationKeys(annotations map[string]string) []string {
+	keys := make([]string, 0, len(annotations))
+	for key := range annotations {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+func formatAnnotations(annotations map[string]string) string {
+	out := make([]string, 0, len(annotations))
+	for _, key := range annotationKeys(annotations) {
+		out = append(out, fmt.Sprintf("%s=%s", key, annotations[key]))
+	}
+
+	return strings.Join(out, ", ")
+}
+
 func convert(j amboy.Job) (greenbay.Checker, error) {
 	c, ok := j.(greenbay.Checker)
 	if ok {
@@ -0,0 +1,164 @@
+package output
+
+import (
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/greenbay"
+	"github.com/pkg/errors"
+	"github.com/tychoish/grip"
+)
+
+// Directory provides a ResultsProducer implementation that writes
+// the full output of each check to its own JSON file, named for the
+// ID of the check. The argument to ToFile is the path of the
+// directory, which Directory creates if it does not exist.
+//
+// When Clean is set, ToFile removes files in the directory left by
+// checks that are not part of the current run.
+type Directory struct {
+	Clean     bool
+	numFailed int
+	results   []greenbay.CheckOutput
+}
+
+// Populate generates output, based on the content (via the Results()
+// method) of an amboy.Queue instance. All jobs processed by that
+// queue must also implement the greenbay.Checker interface.
+func (r *Directory) Populate(queue amboy.Queue) error {
+	if queue == nil {
+		return errors.New("cannot populate results with a nil queue")
+	}
+
+	catcher := grip.NewCatcher()
+	for wu := range jobsToCheck(queue.Results()) {
+		if wu.err != nil {
+			catcher.Add(wu.err)
+			continue
+		}
+
+		if !wu.output.Passed && !wu.output.Skipped && !wu.output.Warning {
+			r.numFailed++
+		}
+
+		r.results = append(r.results, wu.output)
+	}
+
+	return catcher.Resolve()
+}
+
+// ToFile writes one file per check to the directory. If any tasks
+// failed, this operation returns an error.
+func (r *Directory) ToFile(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return errors.Wrapf(err, "problem creating output directory %s", dir)
+	}
+
+	names := checkOutputFileNames(r.results)
+	written := make(map[string]struct{})
+	catcher := grip.NewCatcher()
+	for idx, check := range r.results {
+		data, err := json.MarshalIndent(check, "", "   ")
+		if err != nil {
+			catcher.Add(errors.Wrapf(err, "problem encoding result for '%s'", check.QualifiedName()))
+			continue
+		}
+
+		fn := names[idx]
+		written[fn] = struct{}{}
+
+		if err = ioutil.WriteFile(filepath.Join(dir, fn), append(data, '\n'), 0644); err != nil {
+			catcher.Add(errors.Wrapf(err, "problem writing result for '%s'", check.QualifiedName()))
+		}
+	}
+
+	if r.Clean {
+		catcher.Add(removeStaleOutput(dir, written))
+	}
+
+	if catcher.HasErrors() {
+		return catcher.Resolve()
+	}
+
+	grip.Infof("wrote results for %d checks to: %s", len(r.results), dir)
+
+	if r.numFailed > 0 {
+		return failedChecks(r.numFailed)
+	}
+
+	return nil
+}
+
+// Print writes, to standard output, the output documents for all
+// checks.
+func (r *Directory) Print() error {
+	for _, check := range r.results {
+		data, err := json.MarshalIndent(check, "", "   ")
+		if err != nil {
+			return errors.Wrapf(err, "problem encoding result for '%s'", check.QualifiedName())
+		}
+
+		fmt.Println(string(data))
+	}
+
+	if r.numFailed > 0 {
+		return failedChecks(r.numFailed)
+	}
+
+	return nil
+}
+
+func checkOutputFileName(id string) string {
+	return strings.NewReplacer("/", "_", string(filepath.Separator), "_").Replace(id) + ".json"
+}
+
+// checkOutputFileNames returns the name of the file for each result.
+// When replacing path separators makes names collide (e.g. "web/a"
+// and "web_a"), the names that had separators end with a short hash
+// of the check name, so that results do not overwrite each other,
+// and each check has the same file in every run.
+func checkOutputFileNames(results []greenbay.CheckOutput) []string {
+	names := make([]string, len(results))
+	counts := make(map[string]int)
+	for idx, check := range results {
+		names[idx] = checkOutputFileName(check.QualifiedName())
+		counts[names[idx]]++
+	}
+
+	for idx, check := range results {
+		id := check.QualifiedName()
+		if counts[names[idx]] > 1 && names[idx] != id+".json" {
+			hash := fmt.Sprintf("%x", sha1.Sum([]byte(id)))
+			names[idx] = fmt.Sprintf("%s-%s.json", strings.TrimSuffix(names[idx], ".json"), hash[:8])
+		}
+	}
+
+	return names
+}
+
+// removeStaleOutput removes the JSON files in the directory that are
+// not in the set of files written by the current run.
+func removeStaleOutput(dir string, written map[string]struct{}) error {
+	existing, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return errors.Wrapf(err, "problem listing files in %s", dir)
+	}
+
+	catcher := grip.NewCatcher()
+	for _, fn := range existing {
+		if _, ok := written[filepath.Base(fn)]; ok {
+			continue
+		}
+
+		grip.Debugln("removing stale result file:", fn)
+		catcher.Add(os.Remove(fn))
+	}
+
+	return catcher.Resolve()
+}
@@ -0,0 +1,87 @@
+package output
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/mongodb/greenbay"
+)
+
+func (s *OptionsSuite) TestDirectoryFormatWritesOneFilePerCheck() {
+	dir := filepath.Join(s.tmpDir, "dir-output", "results")
+	opts, err := NewOptions(dir, "dir", true)
+	s.require.NoError(err)
+
+	s.NoError(opts.ProduceResults(s.queue))
+
+	files, err := ioutil.ReadDir(dir)
+	s.require.NoError(err)
+	s.Len(files, s.queue.Stats().Total)
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, "mock-check-0.json"))
+	s.require.NoError(err)
+
+	out := greenbay.CheckOutput{}
+	s.NoError(json.Unmarshal(data, &out))
+	s.Equal("mock-check-0", out.Name)
+	s.True(out.Passed)
+}
+
+func (s *OptionsSuite) TestDirectoryFormatOnlyRemovesStaleFilesWhenClean() {
+	dir := filepath.Join(s.tmpDir, "dir-clean")
+	s.require.NoError(os.MkdirAll(dir, 0755))
+
+	stale := filepath.Join(dir, "removed-check.json")
+	other := filepath.Join(dir, "notes.txt")
+	s.require.NoError(ioutil.WriteFile(stale, []byte("{}"), 0644))
+	s.require.NoError(ioutil.WriteFile(other, []byte("notes"), 0644))
+
+	opts, err := NewOptions(dir, "dir", true)
+	s.require.NoError(err)
+	s.NoError(opts.ProduceResults(s.queue))
+
+	_, err = os.Stat(stale)
+	s.NoError(err)
+
+	opts.EnableCleanOutput()
+	s.NoError(opts.ProduceResults(s.queue))
+
+	_, err = os.Stat(stale)
+	s.True(os.IsNotExist(err))
+	_, err = os.Stat(other)
+	s.NoError(err)
+	_, err = os.Stat(filepath.Join(dir, "mock-check-4.json"))
+	s.NoError(err)
+}
+
+func (s *OptionsSuite) TestCheckOutputFileNamesDoNotContainPaths() {
+	s.Equal("foo.json", checkOutputFileName("foo"))
+	s.Equal("foo_bar.json", checkOutputFileName("foo/bar"))
+}
+
+func (s *OptionsSuite) TestCollidingCheckOutputFileNamesAreDistinct() {
+	results := []greenbay.CheckOutput{
+		{Name: "a", Host: "web", Passed: true},
+		{Name: "web_a", Passed: true},
+		{Name: "b", Host: "web", Passed: true},
+	}
+
+	names := checkOutputFileNames(results)
+	s.Equal("web_a.json", names[1])
+	s.Equal("web_b.json", names[2])
+	s.NotEqual(names[0], names[1])
+	s.Regexp(`^web_a-[0-9a-f]{8}\.json$`, names[0])
+
+	// names do not depend on the order of the results.
+	s.Equal(names[0], checkOutputFileNames([]greenbay.CheckOutput{results[1], results[0]})[1])
+
+	dir := filepath.Join(s.tmpDir, "dir-collisions")
+	r := &Directory{results: results}
+	s.NoError(r.ToFile(dir))
+
+	files, err := ioutil.ReadDir(dir)
+	s.require.NoError(err)
+	s.Len(files, len(results))
+}
@@ -14,10 +14,14 @@ import (
 )
 
 // GoTest defines a ResultsProducer implementation that generates
-// output in the format of "go test -v"
+// output in the format of "go test -v". When TimingSummary is set,
+// the output ends with a line that reports the distribution of check
+// durations. GoTest also implements IncrementalResultsProducer.
 type GoTest struct {
-	numFailed int
-	buf       *bytes.Buffer
+	TimingSummary bool
+	numFailed     int
+	buf           *bytes.Buffer
+	streamed      durations
 }
 
 // Populate generates output, based on the content (via the Results()
@@ -28,13 +32,17 @@ func (r *GoTest) Populate(queue amboy.Queue) error {
 		return errors.New("cannot populate results with a nil queue")
 	}
 
-	numFailed, err := produceResults(r.buf, jobsToCheck(queue.Results()))
+	numFailed, times, err := produceResults(r.buf, jobsToCheck(queue.Results()))
 	if err != nil {
 		return errors.Wrap(err, "problem generating gotest results")
 	}
 
 	r.numFailed = numFailed
 
+	if r.TimingSummary {
+		fmt.Fprintln(r.buf, "timing:", newTimingSummary(times))
+	}
+
 	return nil
 }
 
@@ -45,7 +53,7 @@ func (r *GoTest) ToFile(fn string) error {
 	}
 
 	if r.numFailed > 0 {
-		return errors.Errorf("%d test(s) failed", r.numFailed)
+		return failedChecks(r.numFailed)
 	}
 
 	return nil
@@ -56,7 +64,32 @@ func (r *GoTest) Print() error {
 	fmt.Println(strings.TrimRight(r.buf.String(), "\n"))
 
 	if r.numFailed > 0 {
-		return errors.Errorf("%d test(s) failed", r.numFailed)
+		return failedChecks(r.numFailed)
+	}
+
+	return nil
+}
+
+// Begin is a no-op: "go test -v" output does not have a header.
+func (r *GoTest) Begin(w io.Writer) error { return nil }
+
+// Stream writes the "go test -v" output for a single check to the
+// writer.
+func (r *GoTest) Stream(w io.Writer, check greenbay.CheckOutput) error {
+	if dur, ok := checkDuration(check); ok {
+		r.streamed = append(r.streamed, dur)
+	}
+
+	printTestResult(w, check)
+	return nil
+}
+
+// Finish writes the timing summary of the streamed checks, when
+// TimingSummary is set.
+func (r *GoTest) Finish(w io.Writer) error {
+	if r.TimingSummary {
+		_, err := fmt.Fprintln(w, "timing:", newTimingSummary(r.streamed))
+		return err
 	}
 
 	return nil
@@ -68,10 +101,11 @@ func (r *GoTest) Print() error {
 //
 ////////////////////////////////////////////////////////////////////////
 
-func produceResults(w io.Writer, checks <-chan workUnit) (int, error) {
+func produceResults(w io.Writer, checks <-chan workUnit) (int, durations, error) {
 	catcher := grip.NewCatcher()
 
 	var failedCount int
+	var times durations
 
 	for wu := range checks {
 		if wu.err != nil {
@@ -79,16 +113,21 @@ func produceResults(w io.Writer, checks <-chan workUnit) (int, error) {
 			continue
 		}
 
+		if dur, ok := checkDuration(wu.output); ok {
+			times = append(times, dur)
+		}
+
 		if !printTestResult(w, wu.output) {
 			failedCount++
 		}
 	}
 
-	return failedCount, catcher.Resolve()
+	return failedCount, times, catcher.Resolve()
 }
 
 func printTestResult(w io.Writer, check greenbay.CheckOutput) bool {
-	fmt.Fprintln(w, "=== RUN", check.Name)
+	name := check.QualifiedName()
+	fmt.Fprintln(w, "=== RUN", name)
 	if check.Message != "" {
 		fmt.Fprintln(w, "    message:", check.Message)
 	}
@@ -97,12 +136,34 @@ func printTestResult(w io.Writer, check greenbay.CheckOutput) bool {
 		fmt.Fprintln(w, "    error:", check.Error)
 	}
 
-	dur := check.Timing.Start.Sub(check.Timing.End)
+	if check.ReasonCode != "" {
+		fmt.Fprintln(w, "    reason:", check.ReasonCode)
+	}
+
+	for _, key := range annotationKeys(check.Annotations) {
+		fmt.Fprintf(w, "    see: %s (%s)\n", check.Annotations[key], key)
+	}
+
+	if msg, ok := overBudget(check); ok {
+		fmt.Fprintln(w, "    over budget:", msg)
+	}
+
+	dur := check.Timing.Duration()
+
+	if check.Skipped {
+		fmt.Fprintf(w, "--- SKIP: %s (%s)\n", name, dur)
+		return true
+	}
+
+	if check.Warning {
+		fmt.Fprintf(w, "--- WARN: %s (%s)\n", name, dur)
+		return true
+	}
 
 	if check.Passed {
-		fmt.Fprintf(w, "--- PASS: %s (%s)\n", check.Name, dur)
+		fmt.Fprintf(w, "--- PASS: %s (%s)\n", name, dur)
 	} else {
-		fmt.Fprintf(w, "--- FAIL: %s (%s)\n", check.Name, dur)
+		fmt.Fprintf(w, "--- FAIL: %s (%s)\n", name, dur)
 	}
 
 	return check.Passed
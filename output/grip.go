@@ -11,10 +11,13 @@ import (
 
 // GripOutput provides a ResultsProducer implementation that writes
 // the results of a greenbay run to logging using the grip logging
-// package.
+// package. Checks that took longer than their expected max duration
+// also log a warning.
 type GripOutput struct {
-	passedMsgs []message.Composer
-	failedMsgs []message.Composer
+	passedMsgs  []message.Composer
+	failedMsgs  []message.Composer
+	warnedMsgs  []message.Composer
+	skippedMsgs []message.Composer
 }
 
 // Populate generates output messages based on the content (via the
@@ -33,15 +36,30 @@ func (r *GripOutput) Populate(queue amboy.Queue) error {
 			continue
 		}
 
-		dur := wu.output.Timing.Start.Sub(wu.output.Timing.End)
-		if wu.output.Passed {
+		dur := wu.output.Timing.Duration()
+		if wu.output.Skipped {
+			r.skippedMsgs = append(r.skippedMsgs,
+				message.NewFormatted("SKIPPED: '%s' [time='%s', msg='%s']",
+					wu.output.QualifiedName(), dur, wu.output.Message))
+		} else if wu.output.Warning {
+			r.warnedMsgs = append(r.warnedMsgs,
+				message.NewFormatted("WARNING: '%s' [time='%s', msg='%s', error='%s', reason='%s', see='%s']",
+					wu.output.QualifiedName(), dur, wu.output.Message, wu.output.Error, wu.output.ReasonCode,
+					formatAnnotations(wu.output.Annotations)))
+		} else if wu.output.Passed {
 			r.passedMsgs = append(r.passedMsgs,
 				message.NewFormatted("PASSED: '%s' [time='%s', msg='%s', error='%s']",
-					wu.output.Name, dur, wu.output.Message, wu.output.Error))
+					wu.output.QualifiedName(), dur, wu.output.Message, wu.output.Error))
 		} else {
 			r.failedMsgs = append(r.passedMsgs,
-				message.NewFormatted("FAILED: '%s' [time='%s', msg='%s', error='%s']",
-					wu.output.Name, dur, wu.output.Message, wu.output.Error))
+				message.NewFormatted("FAILED: '%s' [time='%s', msg='%s', error='%s', reason='%s', see='%s']",
+					wu.output.QualifiedName(), dur, wu.output.Message, wu.output.Error, wu.output.ReasonCode,
+					formatAnnotations(wu.output.Annotations)))
+		}
+
+		if msg, ok := overBudget(wu.output); ok {
+			r.warnedMsgs = append(r.warnedMsgs,
+				message.NewFormatted("OVER BUDGET: '%s' [%s]", wu.output.QualifiedName(), msg))
 		}
 	}
 
@@ -62,7 +80,7 @@ func (r *GripOutput) ToFile(fn string) error {
 
 	numFailed := len(r.failedMsgs)
 	if numFailed > 0 {
-		return errors.Errorf("%d test(s) failed", numFailed)
+		return failedChecks(numFailed)
 	}
 
 	return nil
@@ -82,17 +100,25 @@ func (r *GripOutput) Print() error {
 
 	numFailed := len(r.failedMsgs)
 	if numFailed > 0 {
-		return errors.Errorf("%d test(s) failed", numFailed)
+		return failedChecks(numFailed)
 	}
 
 	return nil
 }
 
 func (r *GripOutput) logResults(logger grip.Journaler) {
+	for _, msg := range r.skippedMsgs {
+		logger.Info(msg)
+	}
+
 	for _, msg := range r.passedMsgs {
 		logger.Notice(msg)
 	}
 
+	for _, msg := range r.warnedMsgs {
+		logger.Warning(msg)
+	}
+
 	for _, msg := range r.failedMsgs {
 		logger.Alert(msg)
 	}
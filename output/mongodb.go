@@ -0,0 +1,115 @@
+package output
+
+import (
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mongodb/greenbay"
+	"github.com/pkg/errors"
+	"github.com/satori/go.uuid"
+	"github.com/tychoish/grip"
+	mgo "gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// mongodbResults writes the output of every check in a run to a
+// MongoDB collection, as one document per check. All documents from
+// a single run share a run id.
+type mongodbResults struct {
+	uri        string
+	database   string
+	collection string
+	timeout    time.Duration
+}
+
+type mongodbResultDocument struct {
+	ID       bson.ObjectId        `bson:"_id"`
+	RunID    string               `bson:"run_id"`
+	Hostname string               `bson:"hostname"`
+	Created  time.Time            `bson:"created_at"`
+	Result   greenbay.CheckOutput `bson:"result"`
+}
+
+func (m *mongodbResults) validate() error {
+	catcher := grip.NewCatcher()
+
+	if m.uri == "" {
+		catcher.Add(errors.New("must specify a mongodb connection string"))
+	}
+
+	if m.database == "" {
+		catcher.Add(errors.New("must specify a mongodb database"))
+	}
+
+	if m.collection == "" {
+		catcher.Add(errors.New("must specify a mongodb collection"))
+	}
+
+	return catcher.Resolve()
+}
+
+func (m *mongodbResults) write(results []greenbay.CheckOutput) error {
+	if len(results) == 0 {
+		return nil
+	}
+
+	info, err := mgo.ParseURL(m.uri)
+	if err != nil {
+		return errors.Wrap(err, "problem parsing mongodb connection string")
+	}
+	info.Timeout = m.timeout
+
+	session, err := mgo.DialWithInfo(info)
+	if err != nil {
+		return errors.Wrapf(err, "problem connecting to mongodb at '%s'", redactedAddress(info))
+	}
+	defer session.Close()
+
+	hostname, err := os.Hostname()
+	grip.CatchWarning(errors.Wrap(err, "problem finding hostname for results"))
+
+	runID := uuid.NewV4().String()
+	created := time.Now()
+
+	docs := make([]interface{}, 0, len(results))
+	for _, r := range results {
+		// checks that ran on remote hosts belong to those hosts,
+		// rather than the host that collected the results.
+		host := hostname
+		if r.Host != "" {
+			host = r.Host
+		}
+
+		docs = append(docs, &mongodbResultDocument{
+			ID:       bson.NewObjectId(),
+			RunID:    runID,
+			Hostname: host,
+			Created:  created,
+			Result:   r,
+		})
+	}
+
+	coll := session.DB(m.database).C(m.collection)
+	if err = coll.Insert(docs...); err != nil {
+		return errors.Wrapf(err, "problem writing %d results to %s.%s",
+			len(docs), m.database, m.collection)
+	}
+
+	grip.Infof("wrote %d results to %s.%s [run_id=%s]", len(docs),
+		m.database, m.collection, runID)
+
+	return nil
+}
+
+// redactedAddress describes the servers and database of a connection,
+// without the credentials from the connection string, for use in
+// errors and logs.
+func redactedAddress(info *mgo.DialInfo) string {
+	addr := strings.Join(info.Addrs, ",")
+	if info.Database != "" {
+		addr += "/" + info.Database
+	}
+
+	return addr
+}
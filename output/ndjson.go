@@ -0,0 +1,136 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/greenbay"
+	"github.com/pkg/errors"
+	"github.com/tychoish/grip"
+)
+
+// EvergreenNDJSON provides a ResultsProducer implementation that
+// writes one newline-delimited JSON document per check, with the
+// timestamp, severity, and message fields that Evergreen task logs
+// ingest. EvergreenNDJSON also implements StreamingResultsProducer,
+// and writes the result of each check as it completes, unless the
+// output mode is buffered. Passing
+// checks that took longer than their expected max duration have the
+// "warning" severity.
+type EvergreenNDJSON struct {
+	numFailed int
+	buf       *bytes.Buffer
+}
+
+type ndjsonLine struct {
+	Timestamp   time.Time         `bson:"timestamp" json:"timestamp" yaml:"timestamp"`
+	Severity    string            `bson:"severity" json:"severity" yaml:"severity"`
+	Message     string            `bson:"message" json:"message" yaml:"message"`
+	Host        string            `bson:"host,omitempty" json:"host,omitempty" yaml:"host,omitempty"`
+	OverBudget  bool              `bson:"over_budget,omitempty" json:"over_budget,omitempty" yaml:"over_budget,omitempty"`
+	ReasonCode  string            `bson:"reason_code,omitempty" json:"reason_code,omitempty" yaml:"reason_code,omitempty"`
+	Annotations map[string]string `bson:"annotations,omitempty" json:"annotations,omitempty" yaml:"annotations,omitempty"`
+}
+
+// streamsByDefault marks EvergreenNDJSON as a format that streams
+// unless the output mode is buffered.
+func (r *EvergreenNDJSON) streamsByDefault() {}
+
+// Populate generates output, based on the content (via the Results()
+// method) of an amboy.Queue instance. All jobs processed by that
+// queue must also implement the greenbay.Checker interface.
+func (r *EvergreenNDJSON) Populate(queue amboy.Queue) error {
+	if queue == nil {
+		return errors.New("cannot populate results with a nil queue")
+	}
+
+	catcher := grip.NewCatcher()
+	for wu := range jobsToCheck(queue.Results()) {
+		if wu.err != nil {
+			catcher.Add(wu.err)
+			continue
+		}
+
+		if !wu.output.Passed && !wu.output.Skipped && !wu.output.Warning {
+			r.numFailed++
+		}
+
+		catcher.Add(r.Stream(r.buf, wu.output))
+	}
+
+	return catcher.Resolve()
+}
+
+// Stream writes a single NDJSON line for the check to the writer.
+func (r *EvergreenNDJSON) Stream(w io.Writer, check greenbay.CheckOutput) error {
+	line := ndjsonLine{
+		Timestamp:   check.Timing.End,
+		Severity:    checkSeverity(check),
+		Annotations: check.Annotations,
+		Host:        check.Host,
+		ReasonCode:  check.ReasonCode,
+		Message: fmt.Sprintf("PASSED: '%s' (%s) [time='%s', msg='%s']",
+			check.QualifiedName(), check.Check, check.Timing.Duration(), check.Message),
+	}
+
+	if line.Timestamp.IsZero() {
+		line.Timestamp = time.Now()
+	}
+
+	if check.Skipped {
+		line.Message = fmt.Sprintf("SKIPPED: '%s' (%s) [msg='%s']",
+			check.QualifiedName(), check.Check, check.Message)
+	} else if check.Warning {
+		line.Message = fmt.Sprintf("WARNING: '%s' (%s) [time='%s', msg='%s', error='%s']",
+			check.QualifiedName(), check.Check, check.Timing.Duration(), check.Message, check.Error)
+	} else if !check.Passed {
+		line.Message = fmt.Sprintf("FAILED: '%s' (%s) [time='%s', msg='%s', error='%s']",
+			check.QualifiedName(), check.Check, check.Timing.Duration(), check.Message, check.Error)
+	}
+
+	if msg, ok := overBudget(check); ok {
+		line.OverBudget = true
+		line.Message = fmt.Sprintf("%s [over budget: %s]", line.Message, msg)
+	}
+
+	out, err := json.Marshal(line)
+	if err != nil {
+		return errors.Wrapf(err, "problem encoding result for '%s'", check.QualifiedName())
+	}
+
+	if _, err = w.Write(append(out, '\n')); err != nil {
+		return errors.Wrapf(err, "problem writing result for '%s'", check.QualifiedName())
+	}
+
+	return nil
+}
+
+// ToFile writes the NDJSON output to a file.
+func (r *EvergreenNDJSON) ToFile(fn string) error {
+	if err := ioutil.WriteFile(fn, r.buf.Bytes(), 0644); err != nil {
+		return errors.Wrapf(err, "problem writing output to %s", fn)
+	}
+
+	if r.numFailed > 0 {
+		return failedChecks(r.numFailed)
+	}
+
+	return nil
+}
+
+// Print writes the NDJSON output to standard output.
+func (r *EvergreenNDJSON) Print() error {
+	fmt.Println(strings.TrimRight(r.buf.String(), "\n"))
+
+	if r.numFailed > 0 {
+		return failedChecks(r.numFailed)
+	}
+
+	return nil
+}
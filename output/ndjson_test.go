@@ -0,0 +1,163 @@
+package output
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mongodb/greenbay"
+	"golang.org/x/net/context"
+)
+
+func (s *OptionsSuite) TestOnlyNDJSONFormatSupportsStreaming() {
+	for format, expected := range map[string]bool{
+		"gotest":           false,
+		"result":           false,
+		"log":              false,
+		"dir":              false,
+		"evergreen-ndjson": true,
+	} {
+		opts, err := NewOptions("", format, true)
+		s.require.NoError(err)
+		s.Equal(expected, opts.Streaming(), format)
+	}
+
+	s.False(s.opts.Streaming())
+}
+
+func (s *OptionsSuite) TestStreamingModeOnlyAppliesToStreamingFormats() {
+	for format, expected := range map[string]bool{
+		"gotest":           true,
+		"result":           true,
+		"log":              false,
+		"dir":              false,
+		"evergreen-ndjson": true,
+	} {
+		opts, err := NewOptions("", format, true)
+		s.require.NoError(err)
+
+		s.NoError(opts.SetMode(StreamingMode))
+		s.Equal(expected, opts.Streaming(), format)
+
+		s.NoError(opts.SetMode(BufferedMode))
+		s.False(opts.Streaming(), format)
+	}
+
+	s.False(s.opts.Streaming())
+	s.Error(s.opts.SetMode("eventually"))
+}
+
+func (s *OptionsSuite) TestStreamResultsErrorsWithNonStreamingFormat() {
+	opts, err := NewOptions("", "log", true)
+	s.require.NoError(err)
+	s.Error(opts.StreamResults(context.Background(), s.queue))
+}
+
+func (s *OptionsSuite) TestStreamResultsWritesOneLinePerCheck() {
+	fn := filepath.Join(s.tmpDir, "stream.ndjson")
+	opts, err := NewOptions(fn, "evergreen-ndjson", true)
+	s.require.NoError(err)
+
+	s.NoError(opts.StreamResults(context.Background(), s.queue))
+
+	f, err := os.Open(fn)
+	s.require.NoError(err)
+	defer f.Close()
+
+	count := 0
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := ndjsonLine{}
+		s.NoError(json.Unmarshal(scanner.Bytes(), &line))
+		s.Equal("info", line.Severity)
+		s.Contains(line.Message, "PASSED: 'mock-check-")
+		s.False(line.Timestamp.IsZero())
+		count++
+	}
+	s.NoError(scanner.Err())
+	s.Equal(s.queue.Stats().Total, count)
+}
+
+func (s *OptionsSuite) TestStreamResultsWithGoTestFormat() {
+	fn := filepath.Join(s.tmpDir, "stream.gotest")
+	opts, err := NewOptions(fn, "gotest", true)
+	s.require.NoError(err)
+
+	s.NoError(opts.StreamResults(context.Background(), s.queue))
+
+	data, err := ioutil.ReadFile(fn)
+	s.require.NoError(err)
+	s.Equal(s.queue.Stats().Total, strings.Count(string(data), "--- PASS: mock-check-"))
+}
+
+func (s *OptionsSuite) TestStreamedResultsIncludeHost() {
+	check := greenbay.CheckOutput{
+		Name:      "disk-space",
+		Check:     "disk-space",
+		Host:      "web1.example.net",
+		Passed:    true,
+		Completed: true,
+	}
+
+	buf := &bytes.Buffer{}
+	s.NoError((&EvergreenNDJSON{}).Stream(buf, check))
+	line := ndjsonLine{}
+	s.NoError(json.Unmarshal(buf.Bytes(), &line))
+	s.Equal("web1.example.net", line.Host)
+	s.Contains(line.Message, "PASSED: 'web1.example.net/disk-space'")
+
+	buf.Reset()
+	s.NoError((&GoTest{}).Stream(buf, check))
+	s.Contains(buf.String(), "=== RUN web1.example.net/disk-space")
+	s.Contains(buf.String(), "--- PASS: web1.example.net/disk-space")
+
+	buf.Reset()
+	results := &Results{}
+	s.NoError(results.Begin(buf))
+	s.NoError(results.Stream(buf, check))
+	s.NoError(results.Finish(buf))
+	doc := &resultsDocument{}
+	s.NoError(json.Unmarshal(buf.Bytes(), doc))
+	s.require.Len(doc.Results, 1)
+	s.Equal("web1.example.net/disk-space", doc.Results[0].Test)
+	s.Equal("web1.example.net", doc.Results[0].Host)
+
+	check.Host = ""
+	buf.Reset()
+	s.NoError((&EvergreenNDJSON{}).Stream(buf, check))
+	s.NotContains(buf.String(), `"host"`)
+}
+
+func (s *OptionsSuite) TestFailedResultsIncludeReasonCode() {
+	check := greenbay.CheckOutput{
+		Name:       "config-file",
+		Check:      "file-exists",
+		Error:      "file existence check did not detect expected state",
+		ReasonCode: "FILE_MISSING",
+		Completed:  true,
+	}
+
+	buf := &bytes.Buffer{}
+	s.NoError((&EvergreenNDJSON{}).Stream(buf, check))
+	line := ndjsonLine{}
+	s.NoError(json.Unmarshal(buf.Bytes(), &line))
+	s.Equal("FILE_MISSING", line.ReasonCode)
+
+	buf.Reset()
+	s.NoError((&GoTest{}).Stream(buf, check))
+	s.Contains(buf.String(), "    reason: FILE_MISSING\n")
+
+	buf.Reset()
+	results := &Results{}
+	s.NoError(results.Begin(buf))
+	s.NoError(results.Stream(buf, check))
+	s.NoError(results.Finish(buf))
+	doc := &resultsDocument{}
+	s.NoError(json.Unmarshal(buf.Bytes(), doc))
+	s.require.Len(doc.Results, 1)
+	s.Equal("FILE_MISSING", doc.Results[0].ReasonCode)
+}
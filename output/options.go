@@ -1,9 +1,35 @@
 package output
 
 import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"text/template"
+	"time"
+
 	"github.com/mongodb/amboy"
+	"github.com/mongodb/greenbay"
 	"github.com/pkg/errors"
 	"github.com/tychoish/grip"
+	"golang.org/x/net/context"
+)
+
+// streamInterval is the interval at which StreamResults checks the
+// queue for newly completed checks.
+const streamInterval = 50 * time.Millisecond
+
+// Output modes select when results are written. In the buffered mode
+// results are written after all checks complete, in a sorted and
+// deterministic order. In the streaming mode, formats that support it
+// write the result of each check as it completes, which gives early
+// feedback on long runs at the cost of a deterministic order: the
+// order of results depends on the order in which checks finish.
+// Formats that do not support streaming always buffer. Without a
+// mode, "evergreen-ndjson" streams and other formats buffer.
+const (
+	BufferedMode  = "buffered"
+	StreamingMode = "streaming"
 )
 
 // Options represents all operations for output generation, and
@@ -14,6 +40,17 @@ type Options struct {
 	writeStdOut bool
 	fn          string
 	format      string
+	cleanOutput bool
+	timing      bool
+	mode        string
+	incremental bool
+	mkdir       bool
+	bySeverity  bool
+	noFailures  bool
+	template    *template.Template
+	prior       priorResults
+	mongodb     *mongodbResults
+	syslog      *syslogResults
 }
 
 // NewOptions provides a constructor to generate a valid Options
@@ -37,6 +74,192 @@ func NewOptions(fn, format string, quiet bool) (*Options, error) {
 	return o, nil
 }
 
+// EnableMongoDB configures the output to also write the results of
+// every check to a MongoDB collection, in addition to the configured
+// output format. Failures to write to MongoDB are logged, but do not
+// impact other output or the result of the operation.
+func (o *Options) EnableMongoDB(uri, database, collection string) error {
+	m := &mongodbResults{
+		uri:        uri,
+		database:   database,
+		collection: collection,
+		timeout:    10 * time.Second,
+	}
+
+	if err := m.validate(); err != nil {
+		return errors.Wrap(err, "invalid mongodb output configuration")
+	}
+
+	o.mongodb = m
+
+	return nil
+}
+
+// EnableSyslog configures the output to also send the result of
+// every check to syslog, as a message with the facility (e.g. "user"
+// or "local0") and a severity that reflects the result of the check:
+// "err" for failures, "warning" for warnings and checks over budget,
+// "notice" for skipped checks, and "info" for passing checks. The
+// address is empty, for the local syslog service, or the "host:port"
+// of a remote syslog server, which receives messages over UDP unless
+// the address starts with "tcp://". Like MongoDB output, failures to
+// send messages are logged, but do not impact other output or the
+// result of the operation.
+func (o *Options) EnableSyslog(facility, address string) error {
+	s, err := newSyslogResults(facility, address)
+	if err != nil {
+		return errors.Wrap(err, "invalid syslog output configuration")
+	}
+
+	o.syslog = s
+
+	return nil
+}
+
+// EnableCleanOutput configures formats that write output to a
+// directory (e.g. "dir") to remove files from previous runs that the
+// current run does not write.
+func (o *Options) EnableCleanOutput() {
+	o.cleanOutput = true
+}
+
+// EnableMakeOutputDir configures the output to create the parent
+// directory of the output file, and any missing parents of that
+// directory, before writing the file. By default, writing output to
+// a file in a directory that does not exist fails.
+func (o *Options) EnableMakeOutputDir() {
+	o.mkdir = true
+}
+
+// makeOutputDir creates the parent directory of the output file, if
+// configured to do so.
+func (o *Options) makeOutputDir() error {
+	if !o.mkdir || !o.writeFile {
+		return nil
+	}
+
+	dir := filepath.Dir(o.fn)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return errors.Wrapf(err, "problem creating output directory %s", dir)
+	}
+
+	return nil
+}
+
+// EnableSortBySeverity configures the output to order results by
+// severity, so that failures come first, followed by warnings,
+// skipped checks, and passing checks, and then by suite and name.
+// By default, results are in the order of the queue. Results written
+// as checks complete, in the streaming mode or to an incremental
+// output file, are always in the order that checks finish.
+func (o *Options) EnableSortBySeverity() {
+	o.bySeverity = true
+}
+
+// EnableTemplate configures the "template" format to render results
+// with the text/template in text, and returns an error if the format
+// is not "template" or the template is not valid, so that a bad
+// template fails before any checks run. See Template for the values
+// that the template renders.
+func (o *Options) EnableTemplate(text string) error {
+	if o.format != "template" {
+		return errors.Errorf("output templates require the 'template' format, not '%s'", o.format)
+	}
+
+	tmpl, err := parseOutputTemplate(text)
+	if err != nil {
+		return errors.Wrap(err, "invalid output template")
+	}
+
+	o.template = tmpl
+
+	return nil
+}
+
+// EnableTimingSummary configures the "gotest" format to end with a
+// summary of the distribution of check durations. The "result"
+// format always includes this summary.
+func (o *Options) EnableTimingSummary() {
+	o.timing = true
+}
+
+// DisableFailureErrors stops ProduceResults and StreamResults from
+// returning an error when checks fail, so that they only return
+// problems producing the results, for callers that determine the
+// result of the run another way, like suite pass thresholds.
+func (o *Options) DisableFailureErrors() {
+	o.noFailures = true
+}
+
+// resultsError returns the error, unless it reports failed checks and
+// failure errors are disabled.
+func (o *Options) resultsError(err error) error {
+	if _, ok := err.(failedChecksError); ok && o.noFailures {
+		return nil
+	}
+
+	return err
+}
+
+// SetMode selects the buffered or streaming output mode, and returns
+// an error for any other mode.
+func (o *Options) SetMode(mode string) error {
+	switch mode {
+	case BufferedMode, StreamingMode:
+		o.mode = mode
+	default:
+		return errors.Errorf("'%s' is not a valid output mode, use '%s' or '%s'",
+			mode, BufferedMode, StreamingMode)
+	}
+
+	return nil
+}
+
+// EnableIncrementalOutput configures the output file to be written
+// as checks complete, rather than after all checks complete, so that
+// a run that crashes or times out still leaves the results of the
+// completed checks on disk. Output to standard output is still
+// buffered, unless the output mode is streaming. The format must
+// implement StreamingResultsProducer: "gotest", "result",
+// "evergreen-ndjson", and "template" support incremental output.
+// Formats that implement IncrementalResultsProducer (e.g. "result")
+// write a complete document only when the run finishes.
+func (o *Options) EnableIncrementalOutput() error {
+	if !o.writeFile {
+		return errors.New("incremental output requires an output file")
+	}
+
+	rp, err := o.GetResultsProducer()
+	if err != nil {
+		return errors.Wrap(err, "problem fetching results producer")
+	}
+
+	if _, ok := rp.(StreamingResultsProducer); !ok {
+		return errors.Errorf("results format '%s' does not support incremental output", o.format)
+	}
+
+	o.incremental = true
+
+	return nil
+}
+
+// EnableChangedSince configures the output to only include checks
+// whose status differs from their status in the results document
+// (as written by the "result" format) at fn, and to log the number
+// of unchanged checks. This only filters the output: unchanged
+// checks that fail still cause ProduceResults and StreamResults to
+// return an error.
+func (o *Options) EnableChangedSince(fn string) error {
+	prior, err := readPriorResults(fn)
+	if err != nil {
+		return errors.Wrap(err, "problem loading prior results")
+	}
+
+	o.prior = prior
+
+	return nil
+}
+
 // GetResultsProducer returns the ResultsProducer implementation
 // specified in the Options structure, and returns an error if the
 // format specified in the structure does not refer to a registered
@@ -49,6 +272,18 @@ func (o *Options) GetResultsProducer() (ResultsProducer, error) {
 
 	rp := factory()
 
+	switch p := rp.(type) {
+	case *Directory:
+		p.Clean = o.cleanOutput
+	case *GoTest:
+		p.TimingSummary = o.timing
+	case *Template:
+		if o.template == nil {
+			return nil, errors.New("the 'template' format requires a template")
+		}
+		p.tmpl = o.template
+	}
+
 	return rp, nil
 }
 
@@ -62,20 +297,267 @@ func (o *Options) ProduceResults(q amboy.Queue) error {
 		return errors.Wrap(err, "problem fetching results producer")
 	}
 
-	if err := rp.Populate(q); err != nil {
-		return errors.Wrap(err, "problem generating results content")
-	}
-
 	// Actually write output to respective streems
 	catcher := grip.NewCatcher()
 
+	var rendered amboy.Queue = q
+	if o.prior != nil && q != nil {
+		rendered = &changedQueue{Queue: q, prior: o.prior}
+		if numFailed := o.prior.unchangedSummary(q); numFailed > 0 {
+			catcher.Add(o.resultsError(failedChecksError(fmt.Sprintf("%d unchanged test(s) failed", numFailed))))
+		}
+	}
+
+	if o.bySeverity && q != nil {
+		rendered = &severityQueue{Queue: rendered}
+	}
+
+	if err := rp.Populate(rendered); err != nil {
+		return errors.Wrap(err, "problem generating results content")
+	}
+
 	if o.writeStdOut {
-		catcher.Add(rp.Print())
+		catcher.Add(o.resultsError(rp.Print()))
+	}
+
+	if o.writeFile {
+		if err = o.makeOutputDir(); err != nil {
+			catcher.Add(err)
+		} else {
+			catcher.Add(o.resultsError(rp.ToFile(o.fn)))
+		}
+	}
+
+	o.writeExternal(q, true)
+
+	return catcher.Resolve()
+}
+
+// streamingMode reports if the output is in the streaming mode,
+// which is the default for formats that always stream.
+func (o *Options) streamingMode() bool {
+	if o.mode != "" {
+		return o.mode == StreamingMode
+	}
+
+	rp, err := o.GetResultsProducer()
+	if err != nil {
+		return false
+	}
+
+	_, ok := rp.(defaultStreamingResultsProducer)
+	return ok
+}
+
+// Streaming reports if the output is in the streaming mode, or writes
+// the output file incrementally, and the configured output format
+// supports writing the result of each check as it completes.
+func (o *Options) Streaming() bool {
+	if !o.streamingMode() && !o.incremental {
+		return false
+	}
+
+	rp, err := o.GetResultsProducer()
+	if err != nil {
+		return false
+	}
+
+	_, ok := rp.(StreamingResultsProducer)
+	return ok
+}
+
+// StreamResults writes the result of each check in the queue as it
+// completes, and blocks until all jobs in the queue are complete or
+// the context is canceled. The format must implement
+// StreamingResultsProducer. With incremental output, but not the
+// streaming mode, only the output file is written as checks complete,
+// and standard output is written, in order, at the end. Like
+// ProduceResults, StreamResults returns an error if any of the tests
+// failed.
+func (o *Options) StreamResults(ctx context.Context, q amboy.Queue) error {
+	rp, err := o.GetResultsProducer()
+	if err != nil {
+		return errors.Wrap(err, "problem fetching results producer")
+	}
+
+	sp, ok := rp.(StreamingResultsProducer)
+	if !ok {
+		return errors.Errorf("results format '%s' does not support streaming", o.format)
+	}
+
+	if q == nil {
+		return errors.New("cannot stream results from a nil queue")
+	}
+
+	// without the streaming mode, only the output file is written
+	// incrementally.
+	bufferStdOut := o.writeStdOut && !o.streamingMode()
+
+	var writers []io.Writer
+	if o.writeStdOut && !bufferStdOut {
+		writers = append(writers, os.Stdout)
 	}
 
 	if o.writeFile {
-		catcher.Add(rp.ToFile(o.fn))
+		if err = o.makeOutputDir(); err != nil {
+			return err
+		}
+
+		f, err := os.Create(o.fn)
+		if err != nil {
+			return errors.Wrapf(err, "problem opening output file %s", o.fn)
+		}
+		defer f.Close()
+
+		writers = append(writers, f)
+	}
+
+	w := io.MultiWriter(writers...)
+	catcher := grip.NewCatcher()
+
+	ip, incremental := sp.(IncrementalResultsProducer)
+	if incremental {
+		catcher.Add(ip.Begin(w))
+	}
+	// syslog messages go out as checks complete, over one
+	// connection for the run.
+	var syslogOut *syslogConn
+	if o.syslog != nil {
+		syslogOut, err = o.syslog.dial()
+		if err != nil {
+			grip.Alert(errors.Wrap(err, "problem writing results to syslog"))
+		} else {
+			defer syslogOut.Close()
+		}
+	}
+
+	seen := make(map[string]struct{})
+	numFailed := 0
+
+	emit := func() {
+		for j := range q.Results() {
+			if _, ok := seen[j.ID()]; ok {
+				continue
+			}
+			seen[j.ID()] = struct{}{}
+
+			c, err := convert(j)
+			if err != nil {
+				catcher.Add(err)
+				continue
+			}
+
+			out := c.Output()
+			if !out.Passed && !out.Skipped && !out.Warning {
+				numFailed++
+			}
+
+			if syslogOut != nil {
+				grip.CatchAlert(syslogOut.send(out))
+			}
+
+			if o.prior != nil && !o.prior.changed(out) {
+				continue
+			}
+
+			catcher.Add(sp.Stream(w, out))
+		}
+	}
+
+waitLoop:
+	for {
+		emit()
+
+		stats := q.Stats()
+		if stats.Completed >= stats.Total {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			catcher.Add(errors.New("operation canceled before all checks completed"))
+			break waitLoop
+		case <-time.After(streamInterval):
+		}
+	}
+	emit()
+
+	if incremental {
+		catcher.Add(ip.Finish(w))
+	}
+
+	if bufferStdOut {
+		catcher.Add(o.printBuffered(q, numFailed))
+	}
+
+	if o.prior != nil {
+		o.prior.unchangedSummary(q)
+	}
+
+	o.writeExternal(q, false)
+
+	if numFailed > 0 {
+		catcher.Add(o.resultsError(failedChecks(numFailed)))
 	}
 
 	return catcher.Resolve()
 }
+
+// printBuffered writes the results in the queue to standard output,
+// after all checks complete.
+func (o *Options) printBuffered(q amboy.Queue, numFailed int) error {
+	rp, err := o.GetResultsProducer()
+	if err != nil {
+		return errors.Wrap(err, "problem fetching results producer")
+	}
+
+	var rendered amboy.Queue = q
+	if o.prior != nil {
+		rendered = &changedQueue{Queue: q, prior: o.prior}
+	}
+
+	if o.bySeverity {
+		rendered = &severityQueue{Queue: rendered}
+	}
+
+	if err = rp.Populate(rendered); err != nil {
+		return errors.Wrap(err, "problem generating results content")
+	}
+
+	// Print reports failed checks as an error, which the caller
+	// already counts.
+	if err = rp.Print(); err != nil && numFailed == 0 {
+		return err
+	}
+
+	return nil
+}
+
+// writeExternal writes the results in the queue to the configured
+// destinations other than the output format, MongoDB and, unless
+// StreamResults already sent the results, syslog, which log
+// failures rather than returning them.
+func (o *Options) writeExternal(q amboy.Queue, withSyslog bool) {
+	withSyslog = withSyslog && o.syslog != nil
+	if o.mongodb == nil && !withSyslog {
+		return
+	}
+
+	var results []greenbay.CheckOutput
+
+	for wu := range jobsToCheck(q.Results()) {
+		if wu.err != nil {
+			continue
+		}
+
+		results = append(results, wu.output)
+	}
+
+	if o.mongodb != nil {
+		grip.CatchAlert(errors.Wrap(o.mongodb.write(results), "problem writing results to mongodb"))
+	}
+
+	if withSyslog {
+		grip.CatchAlert(errors.Wrap(o.syslog.write(results), "problem writing results to syslog"))
+	}
+}
@@ -1,15 +1,19 @@
 package output
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/mongodb/amboy"
 	"github.com/mongodb/amboy/job"
 	"github.com/mongodb/amboy/queue"
+	"github.com/mongodb/greenbay"
 	"github.com/mongodb/greenbay/check"
 	"github.com/satori/go.uuid"
 	"github.com/stretchr/testify/require"
@@ -150,6 +154,34 @@ func (s *OptionsSuite) TestResultsToFileOnly() {
 	}
 }
 
+func (s *OptionsSuite) TestMakeOutputDirCreatesParentDirectories() {
+	for idx, format := range []string{"gotest", "result", "log", "dir"} {
+		fn := filepath.Join(s.tmpDir, fmt.Sprintf("nested-%d", idx), "a", "b", "results")
+
+		opt, err := NewOptions(fn, format, true)
+		s.require.NoError(err)
+		if format != "dir" {
+			// the directory format always creates its directory.
+			s.Error(opt.ProduceResults(s.queue), format)
+		}
+
+		opt.EnableMakeOutputDir()
+		s.NoError(opt.ProduceResults(s.queue), format)
+		_, err = os.Stat(fn)
+		s.NoError(err, format)
+	}
+
+	fn := filepath.Join(s.tmpDir, "nested-stream", "results.ndjson")
+	opt, err := NewOptions(fn, "evergreen-ndjson", true)
+	s.require.NoError(err)
+	s.Error(opt.StreamResults(context.Background(), s.queue))
+
+	opt.EnableMakeOutputDir()
+	s.NoError(opt.StreamResults(context.Background(), s.queue))
+	_, err = os.Stat(fn)
+	s.NoError(err)
+}
+
 func (s *OptionsSuite) TestResultsToFileAndOutput() {
 	for idx, format := range []string{"gotest", "result", "log"} {
 		fn := filepath.Join(s.tmpDir, fmt.Sprintf("enabled-three-%d", idx))
@@ -159,3 +191,206 @@ func (s *OptionsSuite) TestResultsToFileAndOutput() {
 		s.NoError(opt.ProduceResults(s.queue))
 	}
 }
+
+func (s *OptionsSuite) TestChangedSinceOnlyOutputsChangedChecks() {
+	prior := filepath.Join(s.tmpDir, "prior.json")
+	s.require.NoError(ioutil.WriteFile(prior, []byte(`{"results": [
+		{"test_file": "mock-check-0", "status": "pass"},
+		{"test_file": "mock-check-1", "status": "fail"},
+		{"test_file": "mock-check-2", "status": "pass"},
+		{"test_file": "mock-check-3", "status": "skip"}
+	]}`), 0644))
+
+	for idx, format := range []string{"gotest", "result", "log", "evergreen-ndjson"} {
+		fn := filepath.Join(s.tmpDir, fmt.Sprintf("changed-since-%d", idx))
+		opt, err := NewOptions(fn, format, true)
+		s.require.NoError(err)
+		s.require.NoError(opt.EnableChangedSince(prior))
+
+		if opt.Streaming() {
+			s.NoError(opt.StreamResults(context.Background(), s.queue), format)
+		} else {
+			s.NoError(opt.ProduceResults(s.queue), format)
+		}
+
+		data, err := ioutil.ReadFile(fn)
+		s.require.NoError(err)
+		out := string(data)
+		for _, changed := range []string{"mock-check-1", "mock-check-3", "mock-check-4"} {
+			s.Contains(out, changed, format)
+		}
+		for _, unchanged := range []string{"mock-check-0", "mock-check-2"} {
+			s.NotContains(out, unchanged, format)
+		}
+	}
+
+	s.Error(s.opts.EnableChangedSince(filepath.Join(s.tmpDir, "DOES-NOT-EXIST")))
+}
+
+func (s *OptionsSuite) TestChangedSinceFailsForUnchangedFailures() {
+	checks := queue.NewLocalUnordered(1)
+	s.require.NoError(checks.Start(context.Background()))
+	c := &mockCheck{Base: check.Base{Base: &job.Base{}}}
+	c.SetID("failing-check")
+	s.require.NoError(checks.Put(c))
+	checks.Wait()
+	c.Base.WasSuccessful = false
+
+	prior := filepath.Join(s.tmpDir, "prior-fail.json")
+	s.require.NoError(ioutil.WriteFile(prior, []byte(`{"results": [
+		{"test_file": "failing-check", "status": "fail"}
+	]}`), 0644))
+
+	fn := filepath.Join(s.tmpDir, "changed-since-fail")
+	opt, err := NewOptions(fn, "gotest", true)
+	s.require.NoError(err)
+	s.require.NoError(opt.EnableChangedSince(prior))
+
+	err = opt.ProduceResults(checks)
+	s.require.Error(err)
+	s.Contains(err.Error(), "1 unchanged test(s) failed")
+
+	data, err := ioutil.ReadFile(fn)
+	s.require.NoError(err)
+	s.NotContains(string(data), "failing-check")
+}
+
+func (s *OptionsSuite) TestIncrementalOutputRequiresFileAndStreamingFormat() {
+	opt, err := NewOptions("", "result", true)
+	s.require.NoError(err)
+	s.Error(opt.EnableIncrementalOutput())
+	s.False(opt.Streaming())
+
+	for _, format := range []string{"log", "dir"} {
+		opt, err = NewOptions(filepath.Join(s.tmpDir, "incremental"), format, true)
+		s.require.NoError(err)
+		s.Error(opt.EnableIncrementalOutput(), format)
+		s.False(opt.Streaming(), format)
+	}
+
+	for _, format := range []string{"gotest", "result", "evergreen-ndjson"} {
+		opt, err = NewOptions(filepath.Join(s.tmpDir, "incremental"), format, true)
+		s.require.NoError(err)
+		s.NoError(opt.EnableIncrementalOutput(), format)
+		s.True(opt.Streaming(), format)
+	}
+}
+
+func (s *OptionsSuite) TestIncrementalResultsDocumentIsComplete() {
+	fn := filepath.Join(s.tmpDir, "incremental-results.json")
+	opt, err := NewOptions(fn, "result", true)
+	s.require.NoError(err)
+	s.require.NoError(opt.EnableIncrementalOutput())
+
+	s.NoError(opt.StreamResults(context.Background(), s.queue))
+
+	data, err := ioutil.ReadFile(fn)
+	s.require.NoError(err)
+
+	doc := resultsDocument{}
+	s.require.NoError(json.Unmarshal(data, &doc))
+	s.Len(doc.Results, s.queue.Stats().Total)
+	s.NotNil(doc.Timing)
+	for _, item := range doc.Results {
+		s.Equal("pass", item.Status)
+	}
+}
+
+func (s *OptionsSuite) TestPartialIncrementalResultsHaveCompletedChecks() {
+	r := &Results{}
+	buf := &bytes.Buffer{}
+
+	s.require.NoError(r.Begin(buf))
+	for i := 0; i < 2; i++ {
+		s.require.NoError(r.Stream(buf, greenbay.CheckOutput{Name: fmt.Sprintf("partial-%d", i), Passed: true}))
+	}
+
+	// a run that stops here leaves both results in the file.
+	s.Contains(buf.String(), `"test_file": "partial-0"`)
+	s.Contains(buf.String(), `"test_file": "partial-1"`)
+	s.Error(json.Unmarshal(buf.Bytes(), &resultsDocument{}))
+
+	s.require.NoError(r.Finish(buf))
+	doc := resultsDocument{}
+	s.require.NoError(json.Unmarshal(buf.Bytes(), &doc))
+	s.Len(doc.Results, 2)
+}
+
+func (s *OptionsSuite) TestIncrementalGoTestOutputEndsWithTimingSummary() {
+	fn := filepath.Join(s.tmpDir, "incremental-gotest")
+	opt, err := NewOptions(fn, "gotest", true)
+	s.require.NoError(err)
+	opt.EnableTimingSummary()
+	s.require.NoError(opt.EnableIncrementalOutput())
+
+	s.NoError(opt.StreamResults(context.Background(), s.queue))
+
+	data, err := ioutil.ReadFile(fn)
+	s.require.NoError(err)
+	s.Equal(s.queue.Stats().Total, strings.Count(string(data), "--- PASS: "))
+	s.Contains(string(data), "timing:")
+}
+
+func (s *OptionsSuite) TestSortBySeverityPutsFailuresFirst() {
+	checks := queue.NewLocalUnordered(2)
+	s.require.NoError(checks.Start(context.Background()))
+
+	mocks := map[string]*mockCheck{}
+	for _, name := range []string{"a-pass", "b-fail", "c-skip", "d-fail", "e-pass"} {
+		c := &mockCheck{Base: check.Base{Base: &job.Base{}}}
+		c.SetID(name)
+		s.require.NoError(checks.Put(c))
+		mocks[name] = c
+	}
+	checks.Wait()
+
+	mocks["b-fail"].WasSuccessful = false
+	mocks["b-fail"].SetSuites([]string{"web"})
+	mocks["d-fail"].WasSuccessful = false
+	mocks["d-fail"].SetSuites([]string{"db", "web"})
+	mocks["c-skip"].WasSkipped = true
+
+	fn := filepath.Join(s.tmpDir, "by-severity")
+	opt, err := NewOptions(fn, "gotest", true)
+	s.require.NoError(err)
+	opt.EnableSortBySeverity()
+	s.Error(opt.ProduceResults(checks))
+
+	data, err := ioutil.ReadFile(fn)
+	s.require.NoError(err)
+
+	var order []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.HasPrefix(line, "=== RUN ") {
+			order = append(order, strings.TrimPrefix(line, "=== RUN "))
+		}
+	}
+	s.Equal([]string{"d-fail", "b-fail", "c-skip", "a-pass", "e-pass"}, order)
+}
+
+func (s *OptionsSuite) TestTemplateFormatRequiresValidTemplate() {
+	opt, err := NewOptions("", "gotest", true)
+	s.require.NoError(err)
+	s.Error(opt.EnableTemplate("{{.Name}}"))
+
+	opt, err = NewOptions("", "template", true)
+	s.require.NoError(err)
+	_, err = opt.GetResultsProducer()
+	s.Error(err)
+	s.Error(opt.EnableTemplate(""))
+	s.Error(opt.EnableTemplate("{{.Name"))
+	s.NoError(opt.EnableTemplate("{{.Name}}"))
+}
+
+func (s *OptionsSuite) TestTemplateFormatRendersEachCheck() {
+	fn := filepath.Join(s.tmpDir, "template")
+	opt, err := NewOptions(fn, "template", true)
+	s.require.NoError(err)
+	s.require.NoError(opt.EnableTemplate(`{{.Name}}: {{if .Passed}}ok{{else}}not ok{{end}}{{define "summary"}}total={{.Total}}{{end}}`))
+	s.NoError(opt.ProduceResults(s.queue))
+
+	data, err := ioutil.ReadFile(fn)
+	s.require.NoError(err)
+	s.Equal(s.queue.Stats().Total, strings.Count(string(data), ": ok\n"))
+	s.True(strings.HasSuffix(string(data), fmt.Sprintf("total=%d", s.queue.Stats().Total)))
+}
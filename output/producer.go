@@ -1,6 +1,12 @@
 package output
 
-import "github.com/mongodb/amboy"
+import (
+	"fmt"
+	"io"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/greenbay"
+)
 
 // ResultsProducer defines a common interface for generating results
 // in different formats.
@@ -25,3 +31,46 @@ type ResultsProducer interface {
 	// any failed checks.
 	Print() error
 }
+
+// StreamingResultsProducer is implemented by ResultsProducers that
+// can write the result of each check as it completes, rather than
+// after all checks have run.
+type StreamingResultsProducer interface {
+	ResultsProducer
+
+	// Stream writes the output for a single completed check to
+	// the writer.
+	Stream(io.Writer, greenbay.CheckOutput) error
+}
+
+// defaultStreamingResultsProducer is implemented by
+// StreamingResultsProducers, like EvergreenNDJSON, that stream their
+// results unless the output mode is buffered.
+type defaultStreamingResultsProducer interface {
+	StreamingResultsProducer
+	streamsByDefault()
+}
+
+// IncrementalResultsProducer is implemented by
+// StreamingResultsProducers with output that needs content before
+// the first result or after the last, for example to open and close
+// a document, or to summarize the run.
+type IncrementalResultsProducer interface {
+	StreamingResultsProducer
+
+	// Begin writes the content that precedes the first result.
+	Begin(io.Writer) error
+
+	// Finish writes the content that follows the last result.
+	Finish(io.Writer) error
+}
+
+// failedChecksError is the error that results producers return when
+// checks failed, as opposed to problems producing the results.
+type failedChecksError string
+
+func (e failedChecksError) Error() string { return string(e) }
+
+func failedChecks(numFailed int) error {
+	return failedChecksError(fmt.Sprintf("%d test(s) failed", numFailed))
+}
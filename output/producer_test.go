@@ -61,6 +61,17 @@ func TestGripProducerSuite(t *testing.T) {
 	suite.Run(t, s)
 }
 
+func TestEvergreenNDJSONProducerSuite(t *testing.T) {
+	s := new(ProducerSuite)
+	s.factory = func() ResultsProducer {
+		return &EvergreenNDJSON{
+			buf: bytes.NewBuffer([]byte{}),
+		}
+	}
+
+	suite.Run(t, s)
+}
+
 // Fixtures for suite:
 
 func (s *ProducerSuite) SetupSuite() {
@@ -36,6 +36,22 @@ func init() {
 	AddFactory("log", func() ResultsProducer {
 		return &GripOutput{}
 	})
+
+	AddFactory("dir", func() ResultsProducer {
+		return &Directory{}
+	})
+
+	AddFactory("evergreen-ndjson", func() ResultsProducer {
+		return &EvergreenNDJSON{
+			buf: bytes.NewBuffer([]byte{}),
+		}
+	})
+
+	AddFactory("template", func() ResultsProducer {
+		return &Template{
+			buf: bytes.NewBuffer([]byte{}),
+		}
+	})
 }
 
 func (r *resultsFactoryRegistry) add(name string, factory ResultsFactory) {
@@ -3,6 +3,7 @@ package output
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
@@ -21,9 +22,18 @@ import (
 ////////////////////////////////////////////////////////////////////////
 
 // Results defines a ResultsProducer implementation for the Evergreen
-// results.json output format.
+// results.json output format. Failed checks that are warnings have
+// the "silentfail" status, which does not fail the run. Checks that
+// took longer than their expected max duration are "over_budget",
+// with their "expected_max_duration".
+//
+// Results also implements IncrementalResultsProducer: Begin opens the
+// document, Stream writes each result, and Finish writes the timing
+// summary and closes the document. Output that ends before Finish is
+// not a complete JSON document, but has every result written so far.
 type Results struct {
-	out *resultsDocument
+	out      *resultsDocument
+	streamed *resultsDocument
 }
 
 // Populate generates output, based on the content (via the Results()
@@ -47,7 +57,7 @@ func (r *Results) ToFile(fn string) error {
 	}
 
 	if r.out.failed {
-		return errors.New("tests failed")
+		return failedChecksError("tests failed")
 	}
 
 	return nil
@@ -60,12 +70,59 @@ func (r *Results) Print() error {
 	}
 
 	if r.out.failed {
-		return errors.New("tests failed")
+		return failedChecksError("tests failed")
 	}
 
 	return nil
 }
 
+// Begin writes the start of the results document.
+func (r *Results) Begin(w io.Writer) error {
+	r.streamed = &resultsDocument{}
+
+	_, err := io.WriteString(w, "{\n   \"results\": [")
+	return errors.Wrap(err, "problem writing results")
+}
+
+// Stream writes a single result to the results document.
+func (r *Results) Stream(w io.Writer, check greenbay.CheckOutput) error {
+	if r.streamed == nil {
+		return errors.New("cannot stream results before beginning the document")
+	}
+
+	r.streamed.addItem(check)
+	item := r.streamed.Results[len(r.streamed.Results)-1]
+
+	out, err := json.MarshalIndent(item, "      ", "   ")
+	if err != nil {
+		return errors.Wrap(err, "problem converting result to json")
+	}
+
+	sep := ","
+	if len(r.streamed.Results) == 1 {
+		sep = ""
+	}
+
+	_, err = fmt.Fprintf(w, "%s\n      %s", sep, out)
+	return errors.Wrap(err, "problem writing results")
+}
+
+// Finish writes the timing summary and the end of the results
+// document.
+func (r *Results) Finish(w io.Writer) error {
+	if r.streamed == nil {
+		return errors.New("cannot finish results before beginning the document")
+	}
+
+	timing, err := json.MarshalIndent(newTimingSummary(r.streamed.durations), "   ", "   ")
+	if err != nil {
+		return errors.Wrap(err, "problem converting timing summary to json")
+	}
+
+	_, err = fmt.Fprintf(w, "\n   ],\n   \"timing\": %s\n}\n", timing)
+	return errors.Wrap(err, "problem writing results")
+}
+
 ////////////////////////////////////////////////////////////////////////
 //
 // Implementation for construction and generation of resultsDocument structure.
@@ -75,17 +132,25 @@ func (r *Results) Print() error {
 // type definition and constructors
 
 type resultsDocument struct {
-	failed  bool
-	Results []*resultsItem `bson:"results" json:"results" yaml:"results"`
+	failed    bool
+	durations durations
+	Results   []*resultsItem `bson:"results" json:"results" yaml:"results"`
+	Timing    *timingSummary `bson:"timing" json:"timing" yaml:"timing"`
 }
 
 type resultsItem struct {
-	Status  string        `bson:"status" json:"status" yaml:"status"`
-	Test    string        `bson:"test_file" json:"test_file" yaml:"test_file"`
-	Code    int           `bson:"exit_code" json:"exit_code" yaml:"exit_code"`
-	Elapsed time.Duration `bson:"elapsed" json:"elapsed" yaml:"elapsed"`
-	Start   time.Time     `bson:"start" json:"start" yaml:"start"`
-	End     time.Time     `bson:"end" json:"end" yaml:"end"`
+	Status      string            `bson:"status" json:"status" yaml:"status"`
+	Test        string            `bson:"test_file" json:"test_file" yaml:"test_file"`
+	Host        string            `bson:"host,omitempty" json:"host,omitempty" yaml:"host,omitempty"`
+	Code        int               `bson:"exit_code" json:"exit_code" yaml:"exit_code"`
+	Elapsed     time.Duration     `bson:"elapsed" json:"elapsed" yaml:"elapsed"`
+	Start       time.Time         `bson:"start" json:"start" yaml:"start"`
+	End         time.Time         `bson:"end" json:"end" yaml:"end"`
+	Annotations map[string]string `bson:"annotations,omitempty" json:"annotations,omitempty" yaml:"annotations,omitempty"`
+	ReasonCode  string            `bson:"reason_code,omitempty" json:"reason_code,omitempty" yaml:"reason_code,omitempty"`
+
+	OverBudget          bool          `bson:"over_budget,omitempty" json:"over_budget,omitempty" yaml:"over_budget,omitempty"`
+	ExpectedMaxDuration time.Duration `bson:"expected_max_duration,omitempty" json:"expected_max_duration,omitempty" yaml:"expected_max_duration,omitempty"`
 }
 
 func newResultsDocument(queue amboy.Queue) (*resultsDocument, error) {
@@ -115,22 +180,33 @@ func (r *resultsDocument) populate(checks <-chan workUnit) error {
 		r.addItem(wu.output)
 	}
 
+	r.Timing = newTimingSummary(r.durations)
+
 	return catcher.Resolve()
 }
 
 func (r *resultsDocument) addItem(check greenbay.CheckOutput) {
 	item := &resultsItem{
-		Test:    check.Name,
-		Elapsed: check.Timing.Duration(),
-		Start:   check.Timing.Start,
-		End:     check.Timing.End,
+		Test:        check.QualifiedName(),
+		Host:        check.Host,
+		Elapsed:     check.Timing.Duration(),
+		Start:       check.Timing.Start,
+		End:         check.Timing.End,
+		Annotations: check.Annotations,
+		ReasonCode:  check.ReasonCode,
+
+		OverBudget:          check.OverBudget,
+		ExpectedMaxDuration: check.ExpectedMaxDuration,
 	}
 	r.Results = append(r.Results, item)
 
-	item.Status = "pass"
+	if dur, ok := checkDuration(check); ok {
+		r.durations = append(r.durations, dur)
+	}
+
+	item.Status = checkStatus(check)
 
-	if !check.Passed {
-		item.Status = "fail"
+	if item.Status == "fail" {
 		item.Code = 1
 		r.failed = true
 	}
@@ -0,0 +1,110 @@
+package output
+
+import (
+	"sort"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/greenbay"
+)
+
+// Severities of check results, from most to least severe. Failed
+// checks are errors; warnings and passing checks that took longer
+// than their expected max duration are warnings; skipped checks are
+// notices, and passing checks are info.
+const (
+	severityError   = "error"
+	severityWarning = "warning"
+	severityNotice  = "notice"
+	severityInfo    = "info"
+)
+
+var severityRanks = map[string]int{
+	severityError:   0,
+	severityWarning: 1,
+	severityNotice:  2,
+	severityInfo:    3,
+}
+
+// checkSeverity returns the severity of the result of a check.
+func checkSeverity(check greenbay.CheckOutput) string {
+	switch {
+	case check.Skipped:
+		return severityNotice
+	case check.Warning:
+		return severityWarning
+	case !check.Passed:
+		return severityError
+	case check.OverBudget:
+		return severityWarning
+	default:
+		return severityInfo
+	}
+}
+
+// severityQueue wraps a queue so that Results returns checks ordered
+// by severity, then by suite, then by name, so that all formats put
+// the most severe results first without any changes to the formats.
+// Checks are ordered by the first of their suites, in sorted order.
+type severityQueue struct {
+	amboy.Queue
+}
+
+type severityItem struct {
+	job      amboy.Job
+	severity int
+	suite    string
+	name     string
+}
+
+// bySeverity orders items by severity, then suite, then name.
+type bySeverity []severityItem
+
+func (s bySeverity) Len() int      { return len(s) }
+func (s bySeverity) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s bySeverity) Less(i, j int) bool {
+	if s[i].severity != s[j].severity {
+		return s[i].severity < s[j].severity
+	}
+
+	if s[i].suite != s[j].suite {
+		return s[i].suite < s[j].suite
+	}
+
+	return s[i].name < s[j].name
+}
+
+func (q *severityQueue) Results() <-chan amboy.Job {
+	var items []severityItem
+
+	for j := range q.Queue.Results() {
+		item := severityItem{job: j, name: j.ID()}
+
+		// jobs that are not checks sort first, so the formats
+		// report their errors.
+		if c, ok := j.(greenbay.Checker); ok {
+			out := c.Output()
+			item.severity = severityRanks[checkSeverity(out)]
+			item.name = out.QualifiedName()
+
+			suites := append([]string{}, out.Suites...)
+			sort.Strings(suites)
+			if len(suites) > 0 {
+				item.suite = suites[0]
+			}
+		} else {
+			item.severity = -1
+		}
+
+		items = append(items, item)
+	}
+
+	sort.Stable(bySeverity(items))
+
+	output := make(chan amboy.Job, len(items))
+	for _, item := range items {
+		output <- item.job
+	}
+	close(output)
+
+	return output
+}
@@ -0,0 +1,19 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/mongodb/greenbay"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckSeverity(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("info", checkSeverity(greenbay.CheckOutput{Passed: true}))
+	assert.Equal("warning", checkSeverity(greenbay.CheckOutput{Passed: true, OverBudget: true}))
+	assert.Equal("warning", checkSeverity(greenbay.CheckOutput{Warning: true}))
+	assert.Equal("notice", checkSeverity(greenbay.CheckOutput{Skipped: true}))
+	assert.Equal("error", checkSeverity(greenbay.CheckOutput{}))
+	assert.Equal("error", checkSeverity(greenbay.CheckOutput{OverBudget: true}))
+}
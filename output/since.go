@@ -0,0 +1,132 @@
+package output
+
+import (
+	"encoding/json"
+	"io/ioutil"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/greenbay"
+	"github.com/pkg/errors"
+	"github.com/tychoish/grip"
+)
+
+// priorResults maps the name of each check in a previous run to its
+// status ("pass", "fail", "silentfail", or "skip").
+type priorResults map[string]string
+
+// readResultsDocument reads a file written by the "result" format.
+func readResultsDocument(fn string) (*resultsDocument, error) {
+	data, err := ioutil.ReadFile(fn)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem reading prior results '%s'", fn)
+	}
+
+	doc := &resultsDocument{}
+	if err = json.Unmarshal(data, doc); err != nil {
+		return nil, errors.Wrapf(err, "problem parsing prior results '%s'", fn)
+	}
+
+	return doc, nil
+}
+
+// readPriorResults reads the status of every check in a file written
+// by the "result" format.
+func readPriorResults(fn string) (priorResults, error) {
+	doc, err := readResultsDocument(fn)
+	if err != nil {
+		return nil, err
+	}
+
+	prior := priorResults{}
+	for _, item := range doc.Results {
+		prior[item.Test] = item.Status
+	}
+
+	return prior, nil
+}
+
+// FailedChecks returns the names of the checks that failed in the
+// results document at fn, as written by the "result" format, in the
+// order of the document. Checks that only failed with a warning, and
+// skipped checks, are not included.
+func FailedChecks(fn string) ([]string, error) {
+	doc, err := readResultsDocument(fn)
+	if err != nil {
+		return nil, err
+	}
+
+	var failed []string
+	for _, item := range doc.Results {
+		if item.Status == "fail" {
+			failed = append(failed, item.Test)
+		}
+	}
+
+	return failed, nil
+}
+
+// changed reports if the status of a check differs from its status
+// in the prior run. Checks that were not in the prior run have
+// changed.
+func (p priorResults) changed(out greenbay.CheckOutput) bool {
+	prev, ok := p[out.QualifiedName()]
+	return !ok || prev != checkStatus(out)
+}
+
+func checkStatus(out greenbay.CheckOutput) string {
+	switch {
+	case out.Skipped:
+		return "skip"
+	case out.Warning:
+		return "silentfail"
+	case out.Passed:
+		return "pass"
+	default:
+		return "fail"
+	}
+}
+
+// changedQueue wraps a queue so that Results only returns the checks
+// whose status changed since the prior run, which lets all formats
+// filter their output without any changes to the formats.
+type changedQueue struct {
+	amboy.Queue
+	prior priorResults
+}
+
+func (q *changedQueue) Results() <-chan amboy.Job {
+	output := make(chan amboy.Job)
+
+	go func() {
+		for j := range q.Queue.Results() {
+			if c, ok := j.(greenbay.Checker); ok && !q.prior.changed(c.Output()) {
+				continue
+			}
+
+			output <- j
+		}
+		close(output)
+	}()
+
+	return output
+}
+
+// unchangedSummary logs the number of checks, by status, that are not
+// in the output because their status did not change, and returns
+// the number of those checks that failed.
+func (p priorResults) unchangedSummary(q amboy.Queue) int {
+	counts := map[string]int{}
+	for wu := range jobsToCheck(q.Results()) {
+		if wu.err != nil || p.changed(wu.output) {
+			continue
+		}
+
+		counts[checkStatus(wu.output)]++
+	}
+
+	grip.Noticef("%d check(s) unchanged since the prior run [passed=%d, failed=%d, warnings=%d, skipped=%d]",
+		counts["pass"]+counts["fail"]+counts["silentfail"]+counts["skip"],
+		counts["pass"], counts["fail"], counts["silentfail"], counts["skip"])
+
+	return counts["fail"]
+}
@@ -0,0 +1,135 @@
+package output
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/mongodb/greenbay"
+	"github.com/pkg/errors"
+	"github.com/tychoish/grip"
+)
+
+// syslogTag is the tag, or program name, of the syslog messages for
+// results.
+const syslogTag = "greenbay"
+
+// syslogFacilities maps the names of syslog facilities to their
+// codes.
+var syslogFacilities = map[string]int{
+	"kern":     0,
+	"user":     1,
+	"mail":     2,
+	"daemon":   3,
+	"auth":     4,
+	"syslog":   5,
+	"lpr":      6,
+	"news":     7,
+	"uucp":     8,
+	"cron":     9,
+	"authpriv": 10,
+	"ftp":      11,
+	"local0":   16,
+	"local1":   17,
+	"local2":   18,
+	"local3":   19,
+	"local4":   20,
+	"local5":   21,
+	"local6":   22,
+	"local7":   23,
+}
+
+// syslogResults sends the output of every check in a run to syslog,
+// as one message per check, with the severity of the check (see
+// checkSeverity) as the syslog severity. Messages go to the local
+// syslog service, or, when address is set, to a remote syslog
+// server. When results are streamed, each message is sent as the
+// check completes.
+type syslogResults struct {
+	facility string
+	network  string
+	address  string
+}
+
+// newSyslogResults returns the configuration for syslog output to
+// the facility (e.g. "user" or "local0"). The address is empty, for
+// the local syslog service, "host:port", for a server that receives
+// messages over UDP, or "tcp://host:port" or "udp://host:port".
+func newSyslogResults(facility, address string) (*syslogResults, error) {
+	s := &syslogResults{facility: facility}
+
+	if _, ok := syslogFacilities[facility]; !ok {
+		return nil, errors.Errorf("'%s' is not a syslog facility", facility)
+	}
+
+	if address == "" {
+		return s, nil
+	}
+
+	s.network = "udp"
+	s.address = address
+	if parts := strings.SplitN(address, "://", 2); len(parts) == 2 {
+		s.network = parts[0]
+		s.address = parts[1]
+	}
+
+	if s.network != "udp" && s.network != "tcp" {
+		return nil, errors.Errorf("syslog network '%s' is not supported, use 'udp' or 'tcp'", s.network)
+	}
+
+	if _, _, err := net.SplitHostPort(s.address); err != nil {
+		return nil, errors.Wrapf(err, "syslog address '%s' is not valid", address)
+	}
+
+	return s, nil
+}
+
+// syslogMessage returns the text of the syslog message for a check.
+func syslogMessage(check greenbay.CheckOutput) string {
+	status := "PASSED"
+	switch {
+	case check.Skipped:
+		status = "SKIPPED"
+	case check.Warning:
+		status = "WARNING"
+	case !check.Passed:
+		status = "FAILED"
+	}
+
+	msg := fmt.Sprintf("%s: '%s' (%s) [time='%s', msg='%s'", status, check.QualifiedName(),
+		check.Check, check.Timing.Duration(), check.Message)
+	if check.Error != "" {
+		msg += fmt.Sprintf(", error='%s'", check.Error)
+	}
+
+	if check.ReasonCode != "" {
+		msg += fmt.Sprintf(", reason='%s'", check.ReasonCode)
+	}
+
+	if over, ok := overBudget(check); ok {
+		msg += fmt.Sprintf(", over budget='%s'", over)
+	}
+
+	return msg + "]"
+}
+
+// write sends a message for each result to syslog, and returns an
+// error if it cannot connect to syslog or send any of the messages.
+func (s *syslogResults) write(results []greenbay.CheckOutput) error {
+	if len(results) == 0 {
+		return nil
+	}
+
+	conn, err := s.dial()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	catcher := grip.NewCatcher()
+	for _, r := range results {
+		catcher.Add(conn.send(r))
+	}
+
+	return catcher.Resolve()
+}
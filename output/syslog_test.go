@@ -0,0 +1,119 @@
+package output
+
+import (
+	"net"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/mongodb/greenbay"
+	"golang.org/x/net/context"
+)
+
+func (s *OptionsSuite) TestEnableSyslogValidatesConfiguration() {
+	for _, c := range [][]string{
+		{"", ""},
+		{"local9", ""},
+		{"user", "localhost"},
+		{"user", "unix:///dev/log"},
+	} {
+		s.Error(s.opts.EnableSyslog(c[0], c[1]))
+		s.Nil(s.opts.syslog)
+	}
+
+	s.NoError(s.opts.EnableSyslog("local0", ""))
+	s.Equal("", s.opts.syslog.network)
+
+	s.NoError(s.opts.EnableSyslog("user", "logs.example.net:514"))
+	s.Equal("udp", s.opts.syslog.network)
+	s.Equal("logs.example.net:514", s.opts.syslog.address)
+
+	s.NoError(s.opts.EnableSyslog("auth", "tcp://logs.example.net:601"))
+	s.Equal("tcp", s.opts.syslog.network)
+	s.Equal("logs.example.net:601", s.opts.syslog.address)
+}
+
+func (s *OptionsSuite) TestSyslogMessage() {
+	s.Equal("PASSED: 'one' (file-exists) [time='0s', msg='ok']",
+		syslogMessage(greenbay.CheckOutput{Name: "one", Check: "file-exists", Passed: true, Message: "ok"}))
+	s.Equal("FAILED: 'web-1/two' (file-exists) [time='0s', msg='', error='missing', reason='FILE_MISSING']",
+		syslogMessage(greenbay.CheckOutput{Name: "two", Check: "file-exists", Host: "web-1",
+			Error: "missing", ReasonCode: "FILE_MISSING"}))
+}
+
+func (s *OptionsSuite) TestSyslogWriteSendsMessagesWithSeverity() {
+	if runtime.GOOS == "windows" {
+		s.T().Skip("syslog output is not supported on windows")
+	}
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	s.require.NoError(err)
+	defer conn.Close()
+
+	out, err := newSyslogResults("local0", conn.LocalAddr().String())
+	s.require.NoError(err)
+
+	s.require.NoError(out.write([]greenbay.CheckOutput{
+		{Name: "passed", Passed: true},
+		{Name: "failed", Error: "boom"},
+		{Name: "skipped", Skipped: true},
+	}))
+
+	var msgs []string
+	buf := make([]byte, 2048)
+	for i := 0; i < 3; i++ {
+		s.require.NoError(conn.SetReadDeadline(time.Now().Add(5 * time.Second)))
+		n, _, err := conn.ReadFrom(buf)
+		s.require.NoError(err)
+		msgs = append(msgs, string(buf[:n]))
+	}
+
+	// local0 is facility 16: the priority is 16*8 plus the
+	// severity, which is 6 for info, 3 for err, and 5 for notice.
+	s.True(strings.HasPrefix(msgs[0], "<134>"), msgs[0])
+	s.Contains(msgs[0], "greenbay")
+	s.Contains(msgs[0], "PASSED: 'passed'")
+	s.True(strings.HasPrefix(msgs[1], "<131>"), msgs[1])
+	s.Contains(msgs[1], "FAILED: 'failed'")
+	s.True(strings.HasPrefix(msgs[2], "<133>"), msgs[2])
+}
+
+func (s *OptionsSuite) TestSyslogWriteFailuresDoNotImpactOtherOutput() {
+	opt, err := NewOptions("", "gotest", true)
+	s.NoError(err)
+	s.NoError(opt.EnableSyslog("user", "tcp://127.0.0.1:1"))
+	s.Error(opt.syslog.write([]greenbay.CheckOutput{{Name: "foo"}}))
+
+	s.NoError(opt.ProduceResults(s.queue))
+}
+
+func (s *OptionsSuite) TestStreamResultsSendsEachResultToSyslogOnce() {
+	if runtime.GOOS == "windows" {
+		s.T().Skip("syslog output is not supported on windows")
+	}
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	s.require.NoError(err)
+	defer conn.Close()
+
+	opts, err := NewOptions(filepath.Join(s.tmpDir, "syslog.ndjson"), "evergreen-ndjson", true)
+	s.require.NoError(err)
+	s.require.NoError(opts.EnableSyslog("local0", conn.LocalAddr().String()))
+	s.require.True(opts.Streaming())
+
+	s.NoError(opts.StreamResults(context.Background(), s.queue))
+
+	count := 0
+	buf := make([]byte, 2048)
+	for {
+		s.require.NoError(conn.SetReadDeadline(time.Now().Add(500 * time.Millisecond)))
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			break
+		}
+		s.Contains(string(buf[:n]), "PASSED: 'mock-check-")
+		count++
+	}
+	s.Equal(s.queue.Stats().Total, count)
+}
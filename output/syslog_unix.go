@@ -0,0 +1,50 @@
+//go:build linux || freebsd || solaris || darwin
+// +build linux freebsd solaris darwin
+
+package output
+
+import (
+	"log/syslog"
+
+	"github.com/mongodb/greenbay"
+	"github.com/pkg/errors"
+)
+
+// syslogConn is a connection to syslog, which sends the result of
+// each check as one message.
+type syslogConn struct {
+	w *syslog.Writer
+}
+
+// dial connects to the syslog service or server.
+func (s *syslogResults) dial() (*syslogConn, error) {
+	facility := syslog.Priority(syslogFacilities[s.facility] << 3)
+	w, err := syslog.Dial(s.network, s.address, facility|syslog.LOG_INFO, syslogTag)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem connecting to syslog")
+	}
+
+	return &syslogConn{w: w}, nil
+}
+
+// send writes the message for the result, with the severity of the
+// check as the syslog severity.
+func (c *syslogConn) send(r greenbay.CheckOutput) error {
+	var err error
+	msg := syslogMessage(r)
+
+	switch checkSeverity(r) {
+	case severityError:
+		err = c.w.Err(msg)
+	case severityWarning:
+		err = c.w.Warning(msg)
+	case severityNotice:
+		err = c.w.Notice(msg)
+	default:
+		err = c.w.Info(msg)
+	}
+
+	return errors.Wrapf(err, "problem sending result for '%s' to syslog", r.QualifiedName())
+}
+
+func (c *syslogConn) Close() error { return c.w.Close() }
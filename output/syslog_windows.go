@@ -0,0 +1,21 @@
+//go:build windows
+// +build windows
+
+package output
+
+import (
+	"github.com/mongodb/greenbay"
+	"github.com/pkg/errors"
+)
+
+// syslogConn is not supported on Windows.
+type syslogConn struct{}
+
+// dial returns an error: syslog output is not supported on Windows.
+func (s *syslogResults) dial() (*syslogConn, error) {
+	return nil, errors.New("syslog output is not supported on windows")
+}
+
+func (c *syslogConn) send(r greenbay.CheckOutput) error { return nil }
+
+func (c *syslogConn) Close() error { return nil }
@@ -0,0 +1,185 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"text/template"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/greenbay"
+	"github.com/pkg/errors"
+	"github.com/tychoish/grip"
+)
+
+// summaryTemplateName is the name of the template, defined with
+// {{define "summary"}}, that a Template executes once, after the
+// results of all checks.
+const summaryTemplateName = "summary"
+
+// TemplateSummary is the value that the "summary" template of the
+// "template" format renders.
+type TemplateSummary struct {
+	Total    int
+	Passed   int
+	Failed   int
+	Skipped  int
+	Warnings int
+}
+
+func (s *TemplateSummary) add(check greenbay.CheckOutput) {
+	s.Total++
+
+	switch {
+	case check.Skipped:
+		s.Skipped++
+	case check.Warning:
+		s.Warnings++
+	case check.Passed:
+		s.Passed++
+	default:
+		s.Failed++
+	}
+}
+
+// parseOutputTemplate parses the text of a template for the
+// "template" format. Templates can use the "severity" function,
+// which returns the severity of a check as in the "evergreen-ndjson"
+// format.
+func parseOutputTemplate(text string) (*template.Template, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, errors.New("the output template is empty")
+	}
+
+	tmpl, err := template.New("check").Funcs(template.FuncMap{
+		"severity": checkSeverity,
+	}).Parse(text)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem parsing output template")
+	}
+
+	return tmpl, nil
+}
+
+// Template provides a ResultsProducer implementation that renders
+// results with a user-defined text/template. The template is
+// executed once for each check, with the greenbay.CheckOutput of the
+// check, and the output of each check, if any, ends with a newline. If the
+// template defines a "summary" template, with {{define "summary"}},
+// that template is executed once, after all checks, with a
+// TemplateSummary. Template also implements
+// IncrementalResultsProducer.
+type Template struct {
+	tmpl     *template.Template
+	summary  TemplateSummary
+	buf      *bytes.Buffer
+	streamed TemplateSummary
+}
+
+// Populate generates output, based on the content (via the Results()
+// method) of an amboy.Queue instance. All jobs processed by that
+// queue must also implement the greenbay.Checker interface.
+func (r *Template) Populate(queue amboy.Queue) error {
+	if queue == nil {
+		return errors.New("cannot populate results with a nil queue")
+	}
+
+	if r.tmpl == nil {
+		return errors.New("the 'template' format requires a template")
+	}
+
+	r.buf = bytes.NewBuffer([]byte{})
+	r.summary = TemplateSummary{}
+
+	catcher := grip.NewCatcher()
+	for wu := range jobsToCheck(queue.Results()) {
+		if wu.err != nil {
+			catcher.Add(wu.err)
+			continue
+		}
+
+		r.summary.add(wu.output)
+		catcher.Add(r.execute(r.buf, wu.output))
+	}
+
+	catcher.Add(r.executeSummary(r.buf, r.summary))
+
+	return catcher.Resolve()
+}
+
+// ToFile writes the rendered output to a file.
+func (r *Template) ToFile(fn string) error {
+	if err := ioutil.WriteFile(fn, r.buf.Bytes(), 0644); err != nil {
+		return errors.Wrapf(err, "problem writing output to %s", fn)
+	}
+
+	if r.summary.Failed > 0 {
+		return failedChecks(r.summary.Failed)
+	}
+
+	return nil
+}
+
+// Print writes the rendered output to standard output.
+func (r *Template) Print() error {
+	fmt.Println(strings.TrimRight(r.buf.String(), "\n"))
+
+	if r.summary.Failed > 0 {
+		return failedChecks(r.summary.Failed)
+	}
+
+	return nil
+}
+
+// Begin is a no-op: only the results and the summary are rendered.
+func (r *Template) Begin(w io.Writer) error {
+	r.streamed = TemplateSummary{}
+
+	return nil
+}
+
+// Stream renders the template for a single check to the writer.
+func (r *Template) Stream(w io.Writer, check greenbay.CheckOutput) error {
+	if r.tmpl == nil {
+		return errors.New("the 'template' format requires a template")
+	}
+
+	r.streamed.add(check)
+
+	return r.execute(w, check)
+}
+
+// Finish renders the summary of the streamed checks, if the template
+// defines one.
+func (r *Template) Finish(w io.Writer) error {
+	return r.executeSummary(w, r.streamed)
+}
+
+func (r *Template) execute(w io.Writer, check greenbay.CheckOutput) error {
+	out := bytes.NewBuffer([]byte{})
+	if err := r.tmpl.Execute(out, check); err != nil {
+		return errors.Wrapf(err, "problem rendering output template for '%s'", check.QualifiedName())
+	}
+
+	if out.Len() == 0 {
+		return nil
+	}
+
+	if !bytes.HasSuffix(out.Bytes(), []byte("\n")) {
+		out.WriteString("\n")
+	}
+
+	_, err := w.Write(out.Bytes())
+	return errors.Wrapf(err, "problem writing result for '%s'", check.QualifiedName())
+}
+
+func (r *Template) executeSummary(w io.Writer, summary TemplateSummary) error {
+	if r.tmpl == nil || r.tmpl.Lookup(summaryTemplateName) == nil {
+		return nil
+	}
+
+	return errors.Wrap(r.tmpl.ExecuteTemplate(w, summaryTemplateName, summary),
+		"problem rendering summary template")
+}
@@ -0,0 +1,70 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/mongodb/greenbay"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOutputTemplate(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := parseOutputTemplate("")
+	assert.Error(err)
+
+	_, err = parseOutputTemplate("{{.Name")
+	assert.Error(err)
+
+	tmpl, err := parseOutputTemplate(`{{.Name}}`)
+	assert.NoError(err)
+	assert.NotNil(tmpl)
+}
+
+func TestTemplateRendersChecksAndSummary(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	tmpl, err := parseOutputTemplate(`{{.Name}} {{severity .}}{{if .ReasonCode}} ({{.ReasonCode}}){{end}}` +
+		`{{define "summary"}}{{.Passed}}/{{.Total}} passed, {{.Failed}} failed, {{.Skipped}} skipped{{end}}`)
+	require.NoError(err)
+
+	r := &Template{tmpl: tmpl}
+	buf := &bytes.Buffer{}
+
+	require.NoError(r.Begin(buf))
+	require.NoError(r.Stream(buf, greenbay.CheckOutput{Name: "one", Passed: true}))
+	require.NoError(r.Stream(buf, greenbay.CheckOutput{Name: "two", ReasonCode: "FILE_MISSING"}))
+	require.NoError(r.Stream(buf, greenbay.CheckOutput{Name: "three", Skipped: true}))
+	require.NoError(r.Finish(buf))
+
+	assert.Equal("one info\ntwo error (FILE_MISSING)\nthree notice\n1/3 passed, 1 failed, 1 skipped", buf.String())
+}
+
+func TestTemplateWithoutSummary(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	tmpl, err := parseOutputTemplate("{{.Name}}\n")
+	require.NoError(err)
+
+	r := &Template{tmpl: tmpl}
+	buf := &bytes.Buffer{}
+
+	require.NoError(r.Begin(buf))
+	require.NoError(r.Stream(buf, greenbay.CheckOutput{Name: "one", Passed: true}))
+	require.NoError(r.Finish(buf))
+
+	assert.Equal("one\n", buf.String())
+}
+
+func TestTemplateExecutionErrors(t *testing.T) {
+	tmpl, err := parseOutputTemplate(`{{.DoesNotExist}}`)
+	require.NoError(t, err)
+
+	r := &Template{tmpl: tmpl}
+	assert.Error(t, r.Stream(&bytes.Buffer{}, greenbay.CheckOutput{Name: "one"}))
+	assert.Error(t, (&Template{}).Stream(&bytes.Buffer{}, greenbay.CheckOutput{Name: "one"}))
+}
@@ -0,0 +1,84 @@
+package output
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/mongodb/greenbay"
+)
+
+// timingSummary describes the distribution of check durations in a
+// run. Skipped checks are not included.
+type timingSummary struct {
+	Count int           `bson:"count" json:"count" yaml:"count"`
+	Total time.Duration `bson:"total" json:"total" yaml:"total"`
+	P50   time.Duration `bson:"p50" json:"p50" yaml:"p50"`
+	P90   time.Duration `bson:"p90" json:"p90" yaml:"p90"`
+	P99   time.Duration `bson:"p99" json:"p99" yaml:"p99"`
+}
+
+type durations []time.Duration
+
+func (d durations) Len() int           { return len(d) }
+func (d durations) Swap(i, j int)      { d[i], d[j] = d[j], d[i] }
+func (d durations) Less(i, j int) bool { return d[i] < d[j] }
+
+// checkDuration returns the run time of a check, or false if the
+// check did not run.
+func checkDuration(check greenbay.CheckOutput) (time.Duration, bool) {
+	if check.Skipped || check.Timing.End.IsZero() {
+		return 0, false
+	}
+
+	return check.Timing.End.Sub(check.Timing.Start), true
+}
+
+func newTimingSummary(times durations) *timingSummary {
+	summary := &timingSummary{Count: len(times)}
+	if len(times) == 0 {
+		return summary
+	}
+
+	sorted := make(durations, len(times))
+	copy(sorted, times)
+	sort.Sort(sorted)
+
+	for _, d := range sorted {
+		summary.Total += d
+	}
+
+	summary.P50 = percentile(sorted, 50)
+	summary.P90 = percentile(sorted, 90)
+	summary.P99 = percentile(sorted, 99)
+
+	return summary
+}
+
+// percentile returns the nearest-rank percentile of a sorted,
+// non-empty list of durations.
+func percentile(sorted durations, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+
+	return sorted[rank-1]
+}
+
+func (s *timingSummary) String() string {
+	return fmt.Sprintf("checks=%d total=%s p50=%s p90=%s p99=%s",
+		s.Count, s.Total, s.P50, s.P90, s.P99)
+}
+
+// overBudget describes the expected and actual durations of a check
+// that took longer than its expected max duration, or returns false
+// for checks that were within their budget, or did not have one.
+func overBudget(check greenbay.CheckOutput) (string, bool) {
+	if !check.OverBudget {
+		return "", false
+	}
+
+	dur, _ := checkDuration(check)
+	return fmt.Sprintf("took %s, expected at most %s", dur, check.ExpectedMaxDuration), true
+}
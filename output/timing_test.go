@@ -0,0 +1,163 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mongodb/amboy/queue"
+	amboyRegistry "github.com/mongodb/amboy/registry"
+	"github.com/mongodb/greenbay"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+)
+
+func TestTimingSummaryPercentiles(t *testing.T) {
+	assert := assert.New(t)
+
+	var times durations
+	for i := 100; i > 0; i-- {
+		times = append(times, time.Duration(i)*time.Millisecond)
+	}
+
+	summary := newTimingSummary(times)
+	assert.Equal(100, summary.Count)
+	assert.Equal(5050*time.Millisecond, summary.Total)
+	assert.Equal(50*time.Millisecond, summary.P50)
+	assert.Equal(90*time.Millisecond, summary.P90)
+	assert.Equal(99*time.Millisecond, summary.P99)
+
+	// the summary should not reorder the input.
+	assert.Equal(100*time.Millisecond, times[0])
+
+	summary = newTimingSummary(durations{time.Second})
+	assert.Equal(time.Second, summary.P50)
+	assert.Equal(time.Second, summary.P99)
+
+	summary = newTimingSummary(nil)
+	assert.Equal(0, summary.Count)
+	assert.Equal(time.Duration(0), summary.P99)
+}
+
+func TestCheckDurationExcludesChecksThatDidNotRun(t *testing.T) {
+	assert := assert.New(t)
+
+	start := time.Now()
+	check := greenbay.CheckOutput{
+		Timing: greenbay.TimingInfo{Start: start, End: start.Add(time.Second)},
+	}
+
+	dur, ok := checkDuration(check)
+	assert.True(ok)
+	assert.Equal(time.Second, dur)
+
+	check.Skipped = true
+	_, ok = checkDuration(check)
+	assert.False(ok)
+
+	_, ok = checkDuration(greenbay.CheckOutput{Timing: greenbay.TimingInfo{Start: start}})
+	assert.False(ok)
+}
+
+func (s *OptionsSuite) TestTimingSummaryUsesCheckRunTimes() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	q := queue.NewLocalUnordered(2)
+	s.require.NoError(q.Start(ctx))
+
+	factory, err := amboyRegistry.GetJobFactory("shell-operation")
+	s.require.NoError(err)
+
+	num := 3
+	for i := 0; i < num; i++ {
+		j := factory()
+		s.require.NoError(json.Unmarshal([]byte(`{"command": "sleep 0.05"}`), j))
+		j.(interface {
+			SetID(string)
+		}).SetID(fmt.Sprintf("sleep-%d", i))
+		s.require.NoError(q.Put(j))
+	}
+	q.Wait()
+
+	for j := range q.Results() {
+		out := j.(greenbay.Checker).Output()
+		s.True(out.Passed, out.Name)
+		s.True(out.Timing.Duration() >= 50*time.Millisecond, out.Timing.Duration().String())
+	}
+
+	doc, err := newResultsDocument(q)
+	s.require.NoError(err)
+	s.require.NotNil(doc.Timing)
+	s.Equal(num, doc.Timing.Count)
+	s.True(doc.Timing.P50 >= 50*time.Millisecond, doc.Timing.P50.String())
+	s.True(doc.Timing.P99 < time.Minute, doc.Timing.P99.String())
+	s.True(doc.Timing.Total >= time.Duration(num)*50*time.Millisecond, doc.Timing.Total.String())
+}
+
+func (s *OptionsSuite) TestTimingSummaryIsOptionalForGoTest() {
+	opts, err := NewOptions("", "gotest", true)
+	s.require.NoError(err)
+
+	for _, enabled := range []bool{false, true} {
+		if enabled {
+			opts.EnableTimingSummary()
+		}
+
+		rp, err := opts.GetResultsProducer()
+		s.require.NoError(err)
+		s.require.NoError(rp.Populate(s.queue))
+
+		out := rp.(*GoTest).buf.String()
+		s.Equal(enabled, bytes.Contains([]byte(out), []byte("timing: checks=")), out)
+	}
+}
+
+func (s *OptionsSuite) TestResultsDocumentIncludesTimingSummary() {
+	doc, err := newResultsDocument(s.queue)
+	s.require.NoError(err)
+	s.require.NotNil(doc.Timing)
+
+	// the mock checks do not record timing information.
+	s.Equal(0, doc.Timing.Count)
+}
+
+func (s *OptionsSuite) TestOverBudgetChecksReportDurations() {
+	start := time.Now()
+	check := greenbay.CheckOutput{
+		Name:                "slow-check",
+		Check:               "shell-operation",
+		Passed:              true,
+		Completed:           true,
+		OverBudget:          true,
+		ExpectedMaxDuration: time.Second,
+		Timing:              greenbay.TimingInfo{Start: start, End: start.Add(3 * time.Second)},
+	}
+
+	buf := &bytes.Buffer{}
+	s.NoError((&GoTest{}).Stream(buf, check))
+	s.Contains(buf.String(), "    over budget: took 3s, expected at most 1s")
+	s.Contains(buf.String(), "--- PASS: slow-check")
+
+	buf.Reset()
+	s.NoError((&EvergreenNDJSON{}).Stream(buf, check))
+	line := ndjsonLine{}
+	s.NoError(json.Unmarshal(buf.Bytes(), &line))
+	s.True(line.OverBudget)
+	s.Equal("warning", line.Severity)
+	s.Contains(line.Message, "[over budget: took 3s, expected at most 1s]")
+
+	doc := &resultsDocument{}
+	doc.addItem(check)
+	s.require.Len(doc.Results, 1)
+	s.True(doc.Results[0].OverBudget)
+	s.Equal(time.Second, doc.Results[0].ExpectedMaxDuration)
+	s.Equal("pass", doc.Results[0].Status)
+
+	check.OverBudget = false
+	buf.Reset()
+	s.NoError((&GoTest{}).Stream(buf, check))
+	s.NotContains(buf.String(), "over budget")
+}